@@ -0,0 +1,368 @@
+// Package genericortb implements an adapter for bidders speaking plain OpenRTB 2.x. Its behavior is
+// declared in the genericOrtb section of the bidder info, so a bidder is onboarded with a bidder info
+// file aliasing genericortb:
+//
+//	aliasOf: genericortb
+//	disabled: false
+//	endpoint: "https://{{.Host}}.ssp.com/openrtb2?publisher={{.PublisherID}}"
+//	genericOrtb:
+//	  endpointMacros:
+//	    Host: region
+//	    PublisherID: publisherId
+//	  impParams:
+//	    placementId: tagid
+//	  mediaType: mtype
+//	  currency: EUR
+//	  headers:
+//	    Authorization: "Bearer token"
+//
+// The bidder params are passed through unchecked: the genericortb params schema only requires an object,
+// whatever the params the alias declares. An imp missing a param of the endpoint macros is rejected when the
+// requests are built, the other params are copied to the imp when present and ignored otherwise.
+package genericortb
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	mediaTypeAuto  = ""
+	mediaTypeMType = "mtype"
+	mediaTypeImp   = "imp"
+)
+
+type adapter struct {
+	endpoint       *template.Template
+	endpointMacros map[int]string
+	impParams      map[string]string
+	mediaType      string
+	currency       string
+	headers        map[string]string
+}
+
+// Builder builds a new instance of the generic ORTB adapter for the given bidder with the given config.
+func Builder(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+	endpoint, err := template.New("endpointTemplate").Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse endpoint url template: %v", err)
+	}
+
+	bidder := &adapter{endpoint: endpoint}
+	if config.GenericORTB == nil {
+		return bidder, nil
+	}
+
+	endpointMacros := make(map[int]string, len(config.GenericORTB.EndpointMacros))
+	for macro, param := range config.GenericORTB.EndpointMacros {
+		field, ok := findEndpointMacro(macro)
+		if !ok {
+			return nil, fmt.Errorf("unknown endpoint macro %s", macro)
+		}
+		if param == "" {
+			return nil, fmt.Errorf("missing bidder param for endpoint macro %s", macro)
+		}
+		endpointMacros[field] = param
+	}
+	for param, path := range config.GenericORTB.ImpParams {
+		if path == "" {
+			return nil, fmt.Errorf("missing imp path for bidder param %s", param)
+		}
+	}
+	switch config.GenericORTB.MediaType {
+	case mediaTypeAuto, mediaTypeMType, mediaTypeImp:
+	default:
+		return nil, fmt.Errorf("unknown media type detection %s, expected mtype or imp", config.GenericORTB.MediaType)
+	}
+
+	bidder.endpointMacros = endpointMacros
+	bidder.impParams = config.GenericORTB.ImpParams
+	bidder.mediaType = config.GenericORTB.MediaType
+	bidder.currency = config.GenericORTB.Currency
+	bidder.headers = config.GenericORTB.Headers
+	return bidder, nil
+}
+
+// findEndpointMacro returns the index of the endpoint template param field of the macro. The name is
+// matched case insensitively since viper lowercases the keys of the host config.
+func findEndpointMacro(macro string) (int, bool) {
+	paramsType := reflect.TypeOf(macros.EndpointTemplateParams{})
+	if field, ok := paramsType.FieldByName(macro); ok && field.Type.Kind() == reflect.String {
+		return field.Index[0], true
+	}
+	for i := 0; i < paramsType.NumField(); i++ {
+		field := paramsType.Field(i)
+		if strings.EqualFold(field.Name, macro) && field.Type.Kind() == reflect.String {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (a *adapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	var errs []error
+	var endpoints []string
+	impsByEndpoint := make(map[string][]openrtb2.Imp)
+
+	for _, imp := range request.Imp {
+		endpoint, err := a.buildEndpoint(imp)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		imp, err = a.mapImpParams(imp)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := a.convertBidFloor(&imp, reqInfo); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if _, ok := impsByEndpoint[endpoint]; !ok {
+			endpoints = append(endpoints, endpoint)
+		}
+		impsByEndpoint[endpoint] = append(impsByEndpoint[endpoint], imp)
+	}
+
+	requests := make([]*adapters.RequestData, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		requestCopy := *request
+		requestCopy.Imp = impsByEndpoint[endpoint]
+		if a.currency != "" {
+			requestCopy.Cur = []string{a.currency}
+		}
+
+		body, err := jsonutil.Marshal(&requestCopy)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		requests = append(requests, &adapters.RequestData{
+			Method:  http.MethodPost,
+			Uri:     endpoint,
+			Body:    body,
+			Headers: a.buildHeaders(request),
+			ImpIDs:  openrtb_ext.GetImpIDs(requestCopy.Imp),
+		})
+	}
+	return requests, errs
+}
+
+func (a *adapter) buildEndpoint(imp openrtb2.Imp) (string, error) {
+	var params macros.EndpointTemplateParams
+	values := reflect.ValueOf(&params).Elem()
+	for field, param := range a.endpointMacros {
+		value, err := getBidderParam(imp, param)
+		if err != nil {
+			return "", err
+		}
+		values.Field(field).SetString(value)
+	}
+	return macros.ResolveMacros(a.endpoint, params)
+}
+
+// mapImpParams copies the bidder params to the configured imp paths. The imp ext is left untouched.
+func (a *adapter) mapImpParams(imp openrtb2.Imp) (openrtb2.Imp, error) {
+	if len(a.impParams) == 0 {
+		return imp, nil
+	}
+
+	impJSON, err := jsonutil.Marshal(imp)
+	if err != nil {
+		return imp, err
+	}
+	for param, path := range a.impParams {
+		value, dataType, _, err := jsonparser.Get(imp.Ext, "bidder", param)
+		if dataType == jsonparser.NotExist {
+			continue
+		}
+		if err != nil {
+			return imp, &errortypes.BadInput{Message: fmt.Sprintf("invalid bidder param %s for imp %s: %v", param, imp.ID, err)}
+		}
+		if dataType == jsonparser.String {
+			// jsonparser strips the quotes of strings
+			value = []byte(`"` + string(value) + `"`)
+		}
+		if impJSON, err = jsonparser.Set(impJSON, value, strings.Split(path, ".")...); err != nil {
+			return imp, &errortypes.BadInput{Message: fmt.Sprintf("unable to set %s of imp %s: %v", path, imp.ID, err)}
+		}
+	}
+
+	var mapped openrtb2.Imp
+	if err := jsonutil.Unmarshal(impJSON, &mapped); err != nil {
+		return imp, &errortypes.BadInput{Message: fmt.Sprintf("unable to map the bidder params of imp %s: %v", imp.ID, err)}
+	}
+	return mapped, nil
+}
+
+func (a *adapter) convertBidFloor(imp *openrtb2.Imp, reqInfo *adapters.ExtraRequestInfo) error {
+	if a.currency == "" || imp.BidFloor <= 0 {
+		return nil
+	}
+
+	floorCur := imp.BidFloorCur
+	if floorCur == "" {
+		floorCur = "USD"
+	}
+	if strings.EqualFold(floorCur, a.currency) {
+		return nil
+	}
+
+	floor, err := reqInfo.ConvertCurrency(imp.BidFloor, floorCur, a.currency)
+	if err != nil {
+		return err
+	}
+	imp.BidFloor = floor
+	imp.BidFloorCur = a.currency
+	return nil
+}
+
+func (a *adapter) buildHeaders(request *openrtb2.BidRequest) http.Header {
+	headers := http.Header{}
+	headers.Add("Content-Type", "application/json;charset=utf-8")
+	headers.Add("Accept", "application/json")
+
+	if request.Device != nil {
+		if len(request.Device.UA) > 0 {
+			headers.Add("User-Agent", request.Device.UA)
+		}
+		if len(request.Device.IPv6) > 0 {
+			headers.Add("X-Forwarded-For", request.Device.IPv6)
+		}
+		if len(request.Device.IP) > 0 {
+			headers.Add("X-Forwarded-For", request.Device.IP)
+		}
+	}
+
+	for name, value := range a.headers {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+func getBidderParam(imp openrtb2.Imp, param string) (string, error) {
+	value, dataType, _, err := jsonparser.Get(imp.Ext, "bidder", param)
+	if err != nil || dataType == jsonparser.Null {
+		return "", &errortypes.BadInput{Message: fmt.Sprintf("missing bidder param %s for imp %s", param, imp.ID)}
+	}
+	if dataType == jsonparser.String {
+		return jsonparser.ParseString(value)
+	}
+	return string(value), nil
+}
+
+func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.RequestData, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	if adapters.IsResponseStatusCodeNoContent(responseData) {
+		return nil, nil
+	}
+	if err := adapters.CheckResponseStatusCodeForErrors(responseData); err != nil {
+		return nil, []error{err}
+	}
+
+	var response openrtb2.BidResponse
+	if err := jsonutil.Unmarshal(responseData.Body, &response); err != nil {
+		return nil, []error{err}
+	}
+
+	bidResponse := adapters.NewBidderResponseWithBidsCapacity(len(request.Imp))
+	if response.Cur != "" {
+		bidResponse.Currency = response.Cur
+	} else if a.currency != "" {
+		bidResponse.Currency = a.currency
+	}
+
+	var errs []error
+	for _, seatBid := range response.SeatBid {
+		for i := range seatBid.Bid {
+			bidType, err := a.getBidType(seatBid.Bid[i], request.Imp)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
+				Bid:     &seatBid.Bid[i],
+				BidType: bidType,
+			})
+		}
+	}
+	return bidResponse, errs
+}
+
+func (a *adapter) getBidType(bid openrtb2.Bid, imps []openrtb2.Imp) (openrtb_ext.BidType, error) {
+	switch a.mediaType {
+	case mediaTypeMType:
+		return getBidTypeFromMType(bid)
+	case mediaTypeImp:
+		return getBidTypeFromImp(bid, imps)
+	}
+
+	if bid.MType != 0 {
+		return getBidTypeFromMType(bid)
+	}
+	return getBidTypeFromImp(bid, imps)
+}
+
+func getBidTypeFromMType(bid openrtb2.Bid) (openrtb_ext.BidType, error) {
+	switch bid.MType {
+	case openrtb2.MarkupBanner:
+		return openrtb_ext.BidTypeBanner, nil
+	case openrtb2.MarkupVideo:
+		return openrtb_ext.BidTypeVideo, nil
+	case openrtb2.MarkupAudio:
+		return openrtb_ext.BidTypeAudio, nil
+	case openrtb2.MarkupNative:
+		return openrtb_ext.BidTypeNative, nil
+	}
+
+	return "", &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Unsupported mtype %d for bid %s", bid.MType, bid.ID),
+	}
+}
+
+// getBidTypeFromImp returns the media type of the imp of the bid, which must be unambiguous.
+func getBidTypeFromImp(bid openrtb2.Bid, imps []openrtb2.Imp) (openrtb_ext.BidType, error) {
+	for _, imp := range imps {
+		if imp.ID != bid.ImpID {
+			continue
+		}
+
+		var bidTypes []openrtb_ext.BidType
+		if imp.Banner != nil {
+			bidTypes = append(bidTypes, openrtb_ext.BidTypeBanner)
+		}
+		if imp.Video != nil {
+			bidTypes = append(bidTypes, openrtb_ext.BidTypeVideo)
+		}
+		if imp.Audio != nil {
+			bidTypes = append(bidTypes, openrtb_ext.BidTypeAudio)
+		}
+		if imp.Native != nil {
+			bidTypes = append(bidTypes, openrtb_ext.BidTypeNative)
+		}
+		if len(bidTypes) != 1 {
+			return "", &errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Unable to detect the media type of bid %s for multi-format imp %s without mtype", bid.ID, imp.ID),
+			}
+		}
+		return bidTypes[0], nil
+	}
+
+	return "", &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Failed to find impression \"%s\" for bid %s", bid.ImpID, bid.ID),
+	}
+}
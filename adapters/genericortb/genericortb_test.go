@@ -0,0 +1,144 @@
+package genericortb
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters/adapterstest"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonSamples(t *testing.T) {
+	bidder, buildErr := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{
+		Endpoint: "https://{{.Host}}.ssp.example.com/openrtb2?publisher={{.PublisherID}}",
+		GenericORTB: &config.GenericORTBInfo{
+			EndpointMacros: map[string]string{"Host": "region", "publisherid": "publisherId"},
+			ImpParams:      map[string]string{"placementId": "tagid", "zone": "ext.zone"},
+			Currency:       "EUR",
+			Headers:        map[string]string{"Authorization": "Bearer test-token"},
+		}}, config.Server{ExternalUrl: "http://hosturl.com", GvlID: 1, DataCenter: "2"})
+
+	if buildErr != nil {
+		t.Fatalf("Builder returned unexpected error %v", buildErr)
+	}
+
+	adapterstest.RunJSONBidderTest(t, "genericortbtest", bidder)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		endpoint    string
+		genericORTB *config.GenericORTBInfo
+		expectedErr string
+	}{
+		{
+			name:        "malformed-endpoint",
+			endpoint:    "{{Malformed}}",
+			expectedErr: `unable to parse endpoint url template: template: endpointTemplate:1: function "Malformed" not defined`,
+		},
+		{
+			name:        "unknown-endpoint-macro",
+			endpoint:    "https://ssp.example.com",
+			genericORTB: &config.GenericORTBInfo{EndpointMacros: map[string]string{"Unknown": "param"}},
+			expectedErr: "unknown endpoint macro Unknown",
+		},
+		{
+			name:        "missing-endpoint-macro-param",
+			endpoint:    "https://ssp.example.com",
+			genericORTB: &config.GenericORTBInfo{EndpointMacros: map[string]string{"Host": ""}},
+			expectedErr: "missing bidder param for endpoint macro Host",
+		},
+		{
+			name:        "missing-imp-path",
+			endpoint:    "https://ssp.example.com",
+			genericORTB: &config.GenericORTBInfo{ImpParams: map[string]string{"placementId": ""}},
+			expectedErr: "missing imp path for bidder param placementId",
+		},
+		{
+			name:        "unknown-media-type",
+			endpoint:    "https://ssp.example.com",
+			genericORTB: &config.GenericORTBInfo{MediaType: "adm"},
+			expectedErr: "unknown media type detection adm, expected mtype or imp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{Endpoint: tc.endpoint, GenericORTB: tc.genericORTB}, config.Server{})
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGetBidType(t *testing.T) {
+	imps := []openrtb2.Imp{
+		{ID: "banner", Banner: &openrtb2.Banner{}},
+		{ID: "multi-format", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}},
+	}
+
+	testCases := []struct {
+		name         string
+		mediaType    string
+		bid          openrtb2.Bid
+		expectedType openrtb_ext.BidType
+		expectErr    bool
+	}{
+		{
+			name:         "auto-mtype",
+			bid:          openrtb2.Bid{ImpID: "banner", MType: openrtb2.MarkupVideo},
+			expectedType: openrtb_ext.BidTypeVideo,
+		},
+		{
+			name:         "auto-imp-fallback",
+			bid:          openrtb2.Bid{ImpID: "banner"},
+			expectedType: openrtb_ext.BidTypeBanner,
+		},
+		{
+			name:      "auto-multi-format-imp",
+			bid:       openrtb2.Bid{ImpID: "multi-format"},
+			expectErr: true,
+		},
+		{
+			name:         "mtype",
+			mediaType:    mediaTypeMType,
+			bid:          openrtb2.Bid{ImpID: "multi-format", MType: openrtb2.MarkupAudio},
+			expectedType: openrtb_ext.BidTypeAudio,
+		},
+		{
+			name:      "mtype-missing",
+			mediaType: mediaTypeMType,
+			bid:       openrtb2.Bid{ImpID: "banner"},
+			expectErr: true,
+		},
+		{
+			name:         "imp-ignores-mtype",
+			mediaType:    mediaTypeImp,
+			bid:          openrtb2.Bid{ImpID: "banner", MType: openrtb2.MarkupNative},
+			expectedType: openrtb_ext.BidTypeBanner,
+		},
+		{
+			name:      "imp-not-found",
+			mediaType: mediaTypeImp,
+			bid:       openrtb2.Bid{ImpID: "unknown"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &adapter{mediaType: tc.mediaType}
+
+			bidType, err := a.getBidType(tc.bid, imps)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedType, bidType)
+		})
+	}
+}
@@ -70,6 +70,15 @@ func newAgmaLogger(cfg config.AgmaAnalytics, sender httpSender, clock clock.Cloc
 	}, nil
 }
 
+// Builder builds the agma module from the analytics.agma config
+func Builder(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+	var conf config.AgmaAnalytics
+	if err := analytics.DecodeConfig(cfg, &conf); err != nil {
+		return nil, err
+	}
+	return NewModule(deps.HTTPClient, conf, deps.Clock)
+}
+
 func NewModule(httpClient *http.Client, cfg config.AgmaAnalytics, clock clock.Clock) (analytics.Module, error) {
 	sender, err := createHttpSender(httpClient, cfg.Endpoint)
 	if err != nil {
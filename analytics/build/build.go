@@ -2,88 +2,134 @@ package build
 
 import (
 	"encoding/json"
+	"math/rand"
 
 	"github.com/benbjohnson/clock"
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v3/analytics"
-	"github.com/prebid/prebid-server/v3/analytics/agma"
 	"github.com/prebid/prebid-server/v3/analytics/clients"
-	"github.com/prebid/prebid-server/v3/analytics/filesystem"
-	"github.com/prebid/prebid-server/v3/analytics/pubstack"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // Modules that need to be logged to need to be initialized here
-func New(analytics *config.Analytics) analytics.Runner {
+func New(analytics *config.Analytics, me metrics.MetricsEngine) analytics.Runner {
+	return build(analytics, builders(), analyticsDeps(), me)
+}
+
+func analyticsDeps() analytics.ModuleDeps {
+	return analytics.ModuleDeps{
+		HTTPClient: clients.GetDefaultHttpInstance(),
+		Clock:      clock.New(),
+	}
+}
+
+// build initializes the enabled modules among the registered ones, passing them their JSON config.
+// The modules failing to initialize are left out, and the other ones are queued if a queue size is set.
+func build(cfg *config.Analytics, builders analytics.ModuleBuilders, deps analytics.ModuleDeps, me metrics.MetricsEngine) enabledAnalytics {
 	modules := make(enabledAnalytics, 0)
-	if len(analytics.File.Filename) > 0 {
-		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
-			modules["filelogger"] = mod
-		} else {
-			glog.Fatalf("Could not initialize FileLogger for file %v :%v", analytics.File.Filename, err)
+	for name, builder := range builders {
+		conf, isEnabled, err := moduleConfig(cfg, name)
+		if err != nil {
+			glog.Errorf("Could not read the config of the %s analytics module: %v", name, err)
+			continue
+		}
+		if !isEnabled {
+			continue
 		}
-	}
 
-	if analytics.Pubstack.Enabled {
-		pubstackModule, err := pubstack.NewModule(
-			clients.GetDefaultHttpInstance(),
-			analytics.Pubstack.ScopeId,
-			analytics.Pubstack.IntakeUrl,
-			analytics.Pubstack.ConfRefresh,
-			analytics.Pubstack.Buffers.EventCount,
-			analytics.Pubstack.Buffers.BufferSize,
-			analytics.Pubstack.Buffers.Timeout,
-			clock.New())
-		if err == nil {
-			modules["pubstack"] = pubstackModule
-		} else {
-			glog.Errorf("Could not initialize PubstackModule: %v", err)
+		module, err := builder(conf, deps)
+		if err != nil {
+			// the host asks for the transactions to be logged to the file, PBS doesn't start without it
+			if name == "filelogger" {
+				glog.Fatalf("Could not initialize FileLogger for file %v :%v", cfg.File.Filename, err)
+			}
+			glog.Errorf("Could not initialize the %s analytics module: %v", name, err)
+			continue
+		}
+		if cfg.Dispatch.QueueSize > 0 {
+			module = newQueuedModule(name, module, cfg.Dispatch, me)
 		}
+		modules[name] = module
 	}
 
-	if analytics.Agma.Enabled {
-		agmaModule, err := agma.NewModule(
-			clients.GetDefaultHttpInstance(),
-			analytics.Agma,
-			clock.New())
-		if err == nil {
-			modules["agma"] = agmaModule
-		} else {
-			glog.Errorf("Could not initialize Agma Anayltics: %v", err)
+	for name := range cfg.Modules {
+		if _, ok := builders[name]; !ok {
+			glog.Warningf("The analytics module %s is configured but not registered", name)
 		}
 	}
-
 	return modules
 }
 
+// registeredModuleConfig is the part of an analytics.modules entry read to tell whether the module is built
+type registeredModuleConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// moduleConfig returns the config of the module and whether it is enabled.
+// The modules having a dedicated block in config.Analytics are configured by it, the other ones by analytics.modules.
+func moduleConfig(cfg *config.Analytics, name string) (interface{}, bool, error) {
+	switch name {
+	case "filelogger":
+		return cfg.File, len(cfg.File.Filename) > 0, nil
+	case "pubstack":
+		return cfg.Pubstack, cfg.Pubstack.Enabled, nil
+	case "agma":
+		return cfg.Agma, cfg.Agma.Enabled, nil
+	case "stream":
+		return cfg.Stream, cfg.Stream.Enabled, nil
+	}
+
+	values, ok := cfg.Modules[name]
+	if !ok {
+		return nil, false, nil
+	}
+	var registered registeredModuleConfig
+	if err := analytics.DecodeConfig(values, &registered); err != nil {
+		return nil, false, err
+	}
+	return values, registered.Enabled, nil
+}
+
 // Collection of all the correctly configured analytics modules - implements the PBSAnalyticsModule interface
 type enabledAnalytics map[string]analytics.Module
 
 func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject, ac privacy.ActivityControl) {
+	accountConfigs := parseAccountModuleConfigs(ao.Account)
 	for name, module := range ea {
+		if !isEnabledForAccount(accountConfigs, name, rand.Float64) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
 			}
 			cloneReq := updateReqWrapperForAnalytics(ao.RequestWrapper, name, cloneBidderReq != nil)
+			ao.AccountConfig = accountModuleConfig(ao.Account, name)
 			module.LogAuctionObject(ao)
 			if cloneReq != nil {
 				ao.RequestWrapper = cloneReq
 			}
 		}
 	}
+	ao.AccountConfig = nil
 }
 
 func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject, ac privacy.ActivityControl) {
+	accountConfigs := parseAccountModuleConfigs(vo.Account)
 	for name, module := range ea {
+		if !isEnabledForAccount(accountConfigs, name, rand.Float64) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(vo.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				vo.RequestWrapper = cloneBidderReq
 			}
 			cloneReq := updateReqWrapperForAnalytics(vo.RequestWrapper, name, cloneBidderReq != nil)
+			vo.AccountConfig = accountModuleConfig(vo.Account, name)
 			module.LogVideoObject(vo)
 			if cloneReq != nil {
 				vo.RequestWrapper = cloneReq
@@ -91,6 +137,7 @@ func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject, ac privacy.
 		}
 
 	}
+	vo.AccountConfig = nil
 }
 
 func (ea enabledAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
@@ -106,27 +153,39 @@ func (ea enabledAnalytics) LogSetUIDObject(so *analytics.SetUIDObject) {
 }
 
 func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject, ac privacy.ActivityControl) {
+	accountConfigs := parseAccountModuleConfigs(ao.Account)
 	for name, module := range ea {
+		if !isEnabledForAccount(accountConfigs, name, rand.Float64) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
 			}
 			cloneReq := updateReqWrapperForAnalytics(ao.RequestWrapper, name, cloneBidderReq != nil)
+			ao.AccountConfig = accountModuleConfig(ao.Account, name)
 			module.LogAmpObject(ao)
 			if cloneReq != nil {
 				ao.RequestWrapper = cloneReq
 			}
 		}
 	}
+	ao.AccountConfig = nil
 }
 
 func (ea enabledAnalytics) LogNotificationEventObject(ne *analytics.NotificationEvent, ac privacy.ActivityControl) {
+	accountConfigs := parseAccountModuleConfigs(ne.Account)
 	for name, module := range ea {
+		if !isEnabledForAccount(accountConfigs, name, rand.Float64) {
+			continue
+		}
 		component := privacy.Component{Type: privacy.ComponentTypeAnalytics, Name: name}
 		if ac.Allow(privacy.ActivityReportAnalytics, component, privacy.ActivityRequest{}) {
+			ne.AccountConfig = accountModuleConfig(ne.Account, name)
 			module.LogNotificationEventObject(ne)
 		}
 	}
+	ne.AccountConfig = nil
 }
 
 // Shutdown - correctly shutdown all analytics modules and wait for them to finish
@@ -136,6 +195,57 @@ func (ea enabledAnalytics) Shutdown() {
 	}
 }
 
+// accountModuleConfigs holds the account-level config of the analytics modules configured by an account, nil for
+// the modules with an invalid config.
+type accountModuleConfigs map[string]*config.AccountAnalyticsModule
+
+// parseAccountModuleConfigs parses the account-level config of the analytics modules, once for all the modules
+// logging a transaction of the account.
+func parseAccountModuleConfigs(account *config.Account) accountModuleConfigs {
+	if account == nil || len(account.Analytics.Modules) == 0 {
+		return nil
+	}
+
+	configs := make(accountModuleConfigs, len(account.Analytics.Modules))
+	for name := range account.Analytics.Modules {
+		if cfg, err := account.Analytics.Modules.ModuleConfig(name); err == nil {
+			configs[name] = &cfg
+		} else {
+			configs[name] = nil
+		}
+	}
+	return configs
+}
+
+// accountModuleConfig returns the account-level config of the module, handed to the module with the objects it logs
+// so it can read its own settings next to the ones of AccountAnalyticsModule.
+func accountModuleConfig(account *config.Account, name string) json.RawMessage {
+	if account == nil {
+		return nil
+	}
+	return account.Analytics.Modules[name]
+}
+
+// isEnabledForAccount tells whether the module logs a transaction of the account according to the account-level
+// config of the module. A module disabled by the account is skipped, and a sampled one logs the share of the
+// transactions set by its sampling rate. The modules with an invalid account-level config are skipped.
+func isEnabledForAccount(configs accountModuleConfigs, name string, random func() float64) bool {
+	cfg, ok := configs[name]
+	if !ok {
+		return true
+	}
+	if cfg == nil {
+		return false
+	}
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return false
+	}
+	if cfg.SamplingRate != nil {
+		return random() < *cfg.SamplingRate
+	}
+	return true
+}
+
 func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityControl, componentName string) (bool, *openrtb_ext.RequestWrapper) {
 	// returned nil request wrapper means that request wrapper was not modified by activities and doesn't have to be changed in analytics object
 	// it is needed in order to use one function for all analytics objects with RequestWrapper
@@ -4,6 +4,8 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 
+	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -88,7 +91,7 @@ func initAnalytics(count *int) analytics.Runner {
 }
 
 func TestNewPBSAnalytics(t *testing.T) {
-	pbsAnalytics := New(&config.Analytics{})
+	pbsAnalytics := New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{})
 	instance := pbsAnalytics.(enabledAnalytics)
 
 	assert.Equal(t, len(instance), 0)
@@ -114,7 +117,7 @@ func TestNewPBSAnalytics_FileLogger(t *testing.T) {
 		}
 	}
 	defer os.RemoveAll(TEST_DIR)
-	mod := New(&config.Analytics{File: config.FileLogs{Filename: TEST_DIR + "/test"}})
+	mod := New(&config.Analytics{File: config.FileLogs{Filename: TEST_DIR + "/test"}}, &metricsConfig.NilMetricsEngine{})
 	switch modType := mod.(type) {
 	case enabledAnalytics:
 		if len(enabledAnalytics(modType)) != 1 {
@@ -124,7 +127,7 @@ func TestNewPBSAnalytics_FileLogger(t *testing.T) {
 		t.Fatalf("Failed to initialize analytics module")
 	}
 
-	pbsAnalytics := New(&config.Analytics{File: config.FileLogs{Filename: TEST_DIR + "/test"}})
+	pbsAnalytics := New(&config.Analytics{File: config.FileLogs{Filename: TEST_DIR + "/test"}}, &metricsConfig.NilMetricsEngine{})
 	instance := pbsAnalytics.(enabledAnalytics)
 
 	assert.Equal(t, len(instance), 1)
@@ -143,7 +146,7 @@ func TestNewPBSAnalytics_Pubstack(t *testing.T) {
 			},
 			ConfRefresh: "2h",
 		},
-	})
+	}, &metricsConfig.NilMetricsEngine{})
 	instanceWithoutError := pbsAnalyticsWithoutError.(enabledAnalytics)
 
 	assert.Equal(t, len(instanceWithoutError), 1)
@@ -152,7 +155,7 @@ func TestNewPBSAnalytics_Pubstack(t *testing.T) {
 		Pubstack: config.Pubstack{
 			Enabled: true,
 		},
-	})
+	}, &metricsConfig.NilMetricsEngine{})
 	instanceWithError := pbsAnalyticsWithError.(enabledAnalytics)
 	assert.Equal(t, len(instanceWithError), 0)
 }
@@ -177,7 +180,7 @@ func TestNewModuleHttp(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, &metricsConfig.NilMetricsEngine{})
 	instanceWithoutError := agmaAnalyticsWithoutError.(enabledAnalytics)
 
 	assert.Equal(t, len(instanceWithoutError), 1)
@@ -186,11 +189,228 @@ func TestNewModuleHttp(t *testing.T) {
 		Agma: config.AgmaAnalytics{
 			Enabled: true,
 		},
-	})
+	}, &metricsConfig.NilMetricsEngine{})
 	instanceWithError := agmaAnalyticsWithError.(enabledAnalytics)
 	assert.Equal(t, len(instanceWithError), 0)
 }
 
+func TestNewPBSAnalytics_Stream(t *testing.T) {
+	streamAnalyticsWithoutError := New(&config.Analytics{
+		Stream: config.StreamAnalytics{
+			Enabled:     true,
+			Format:      "json",
+			Compression: "gzip",
+			Buffers: config.StreamAnalyticsBuffer{
+				BufferSize: "100KB",
+				EventCount: 50,
+				Timeout:    "30s",
+			},
+			Sink: config.StreamAnalyticsSink{
+				Type: "http",
+				HTTP: config.StreamAnalyticsHTTPSink{
+					Url:     "http://localhost:8080",
+					Timeout: "1s",
+				},
+			},
+		},
+	}, &metricsConfig.NilMetricsEngine{})
+	instanceWithoutError := streamAnalyticsWithoutError.(enabledAnalytics)
+	defer instanceWithoutError.Shutdown()
+
+	assert.Equal(t, len(instanceWithoutError), 1)
+
+	streamAnalyticsWithError := New(&config.Analytics{
+		Stream: config.StreamAnalytics{
+			Enabled: true,
+		},
+	}, &metricsConfig.NilMetricsEngine{})
+	instanceWithError := streamAnalyticsWithError.(enabledAnalytics)
+	assert.Equal(t, len(instanceWithError), 0)
+}
+
+func TestBuildRegisteredModules(t *testing.T) {
+	type customConfig struct {
+		Enabled     bool   `mapstructure:"enabled"`
+		ScopeID     string `mapstructure:"scope_id"`
+		RefreshRate int    `mapstructure:"refresh_rate"`
+	}
+
+	var count int
+	var decodedConfig customConfig
+	newModule := func(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+		return &sampleModule{count: &count}, nil
+	}
+	builders := analytics.ModuleBuilders{
+		"custom": func(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+			if err := analytics.DecodeConfig(cfg, &decodedConfig); err != nil {
+				return nil, err
+			}
+			return &sampleModule{count: &count}, nil
+		},
+		"disabled":       newModule,
+		"enabled-string": newModule,
+		"invalid":        newModule,
+		"failing": func(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+			return nil, errors.New("invalid config")
+		},
+	}
+
+	modules := build(&config.Analytics{
+		Modules: config.AnalyticsModules{
+			"custom":         {"enabled": true, "scope_id": "scope", "refresh_rate": "10"},
+			"disabled":       {"enabled": false},
+			"enabled-string": {"enabled": "true"},
+			"invalid":        {"enabled": []string{"yes"}},
+			"failing":        {"enabled": true},
+			"unknown":        {"enabled": true},
+		},
+	}, builders, analytics.ModuleDeps{}, &metricsConfig.NilMetricsEngine{})
+
+	assert.Len(t, modules, 2)
+	assert.Contains(t, modules, "custom")
+	assert.Contains(t, modules, "enabled-string")
+	assert.Equal(t, customConfig{Enabled: true, ScopeID: "scope", RefreshRate: 10}, decodedConfig)
+}
+
+func TestModuleConfigDedicatedBlock(t *testing.T) {
+	cfg := &config.Analytics{
+		Pubstack: config.Pubstack{
+			Enabled:     true,
+			ScopeId:     "scopeId",
+			ConfRefresh: "2h",
+			Buffers:     config.PubstackBuffer{EventCount: 10},
+		},
+	}
+
+	conf, isEnabled, err := moduleConfig(cfg, "pubstack")
+	assert.NoError(t, err)
+	assert.True(t, isEnabled)
+
+	var decoded config.Pubstack
+	assert.NoError(t, analytics.DecodeConfig(conf, &decoded))
+	assert.Equal(t, cfg.Pubstack, decoded)
+
+	_, isEnabled, err = moduleConfig(cfg, "filelogger")
+	assert.NoError(t, err)
+	assert.False(t, isEnabled)
+}
+
+func TestIsEnabledForAccount(t *testing.T) {
+	random := func() float64 { return 0.5 }
+
+	testCases := []struct {
+		description string
+		account     *config.Account
+		expected    bool
+	}{
+		{
+			description: "no-account",
+			account:     nil,
+			expected:    true,
+		},
+		{
+			description: "module-not-configured",
+			account:     &config.Account{Analytics: config.AccountAnalytics{Modules: config.AccountAnalyticsModules{"other": json.RawMessage(`{"enabled":false}`)}}},
+			expected:    true,
+		},
+		{
+			description: "enabled",
+			account:     getAccountWithAnalytics(`{"enabled":true,"scopeid":"scope"}`),
+			expected:    true,
+		},
+		{
+			description: "disabled",
+			account:     getAccountWithAnalytics(`{"enabled":false}`),
+			expected:    false,
+		},
+		{
+			description: "sampled-in",
+			account:     getAccountWithAnalytics(`{"sampling_rate":0.6}`),
+			expected:    true,
+		},
+		{
+			description: "sampled-out",
+			account:     getAccountWithAnalytics(`{"sampling_rate":0.5}`),
+			expected:    false,
+		},
+		{
+			description: "disabled-with-sampling-rate",
+			account:     getAccountWithAnalytics(`{"enabled":false,"sampling_rate":1}`),
+			expected:    false,
+		},
+		{
+			description: "invalid-config",
+			account:     getAccountWithAnalytics(`{"sampling_rate":2}`),
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, isEnabledForAccount(parseAccountModuleConfigs(test.account), "sampleModule", random))
+		})
+	}
+}
+
+func TestSampleModuleDisabledForAccount(t *testing.T) {
+	var count int
+	am := initAnalytics(&count)
+	account := getAccountWithAnalytics(`{"enabled":false}`)
+
+	am.LogAuctionObject(&analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}, Account: account}, privacy.ActivityControl{})
+	am.LogAmpObject(&analytics.AmpObject{RequestWrapper: &openrtb_ext.RequestWrapper{}, Account: account}, privacy.ActivityControl{})
+	am.LogVideoObject(&analytics.VideoObject{RequestWrapper: &openrtb_ext.RequestWrapper{}, Account: account}, privacy.ActivityControl{})
+	am.LogNotificationEventObject(&analytics.NotificationEvent{Account: account}, privacy.ActivityControl{})
+	assert.Equal(t, 0, count, "the module disabled by the account should not log its transactions")
+
+	otherAccount := getAccountWithAnalytics(`{"enabled":true}`)
+	am.LogAuctionObject(&analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}, Account: otherAccount}, privacy.ActivityControl{})
+	am.LogNotificationEventObject(&analytics.NotificationEvent{Account: otherAccount}, privacy.ActivityControl{})
+	assert.Equal(t, 2, count)
+}
+
+// accountConfigModule records the account-level config of the objects it logs
+type accountConfigModule struct {
+	sampleModule
+	accountConfigs []json.RawMessage
+}
+
+func (m *accountConfigModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	m.accountConfigs = append(m.accountConfigs, ao.AccountConfig)
+}
+
+func (m *accountConfigModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	m.accountConfigs = append(m.accountConfigs, ne.AccountConfig)
+}
+
+func TestModuleAccountConfig(t *testing.T) {
+	module := &accountConfigModule{}
+	am := enabledAnalytics{"sampleModule": module}
+	account := getAccountWithAnalytics(`{"enabled":true,"scope_id":"some-scope"}`)
+
+	ao := &analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}, Account: account}
+	am.LogAuctionObject(ao, privacy.ActivityControl{})
+	ne := &analytics.NotificationEvent{Account: account}
+	am.LogNotificationEventObject(ne, privacy.ActivityControl{})
+	am.LogAuctionObject(&analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}, Account: &config.Account{}}, privacy.ActivityControl{})
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"enabled":true,"scope_id":"some-scope"}`),
+		json.RawMessage(`{"enabled":true,"scope_id":"some-scope"}`),
+		nil,
+	}, module.accountConfigs, "the module should get its account-level config")
+	assert.Nil(t, ao.AccountConfig, "the account-level config of the last module should not be left on the object")
+	assert.Nil(t, ne.AccountConfig)
+}
+
+func getAccountWithAnalytics(moduleConfig string) *config.Account {
+	return &config.Account{
+		Analytics: config.AccountAnalytics{
+			Modules: config.AccountAnalyticsModules{"sampleModule": json.RawMessage(moduleConfig)},
+		},
+	}
+}
+
 func TestSampleModuleActivitiesAllowed(t *testing.T) {
 	var count int
 	am := initAnalytics(&count)
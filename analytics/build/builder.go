@@ -0,0 +1,20 @@
+package build
+
+import (
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/analytics/agma"
+	"github.com/prebid/prebid-server/v3/analytics/filesystem"
+	"github.com/prebid/prebid-server/v3/analytics/pubstack"
+	"github.com/prebid/prebid-server/v3/analytics/stream"
+)
+
+// builders returns mapping between analytics module name and its builder.
+// The module name is the key of its host and account-level config, and the name of its activity control component.
+func builders() analytics.ModuleBuilders {
+	return analytics.ModuleBuilders{
+		"agma":       agma.Builder,
+		"filelogger": filesystem.Builder,
+		"pubstack":   pubstack.Builder,
+		"stream":     stream.Builder,
+	}
+}
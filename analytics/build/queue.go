@@ -0,0 +1,178 @@
+package build
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// queuedModule hands the analytics objects to the module through a bounded queue consumed on its own goroutine,
+// so a slow module doesn't delay the requests. The runner evaluates the privacy activities and prepares the
+// RequestWrapper of the module before the object is queued, so the objects are copied when queued as the runner
+// keeps on updating the originals for the next modules.
+type queuedModule struct {
+	name         string
+	module       analytics.Module
+	queue        chan func()
+	dropPolicy   string
+	blockTimeout time.Duration
+	metrics      metrics.MetricsEngine
+	done         chan struct{}
+	stopped      chan struct{}
+	shutdownOnce sync.Once
+}
+
+func newQueuedModule(name string, module analytics.Module, cfg config.AnalyticsDispatch, me metrics.MetricsEngine) *queuedModule {
+	q := &queuedModule{
+		name:         name,
+		module:       module,
+		queue:        make(chan func(), cfg.QueueSize),
+		dropPolicy:   cfg.DropPolicy,
+		blockTimeout: time.Duration(cfg.BlockTimeoutMs) * time.Millisecond,
+		metrics:      me,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *queuedModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	queued := *ao
+	queued.RequestWrapper = detachRequestWrapper(ao.RequestWrapper)
+	q.enqueue(func() { q.module.LogAuctionObject(&queued) })
+}
+
+func (q *queuedModule) LogVideoObject(vo *analytics.VideoObject) {
+	queued := *vo
+	queued.RequestWrapper = detachRequestWrapper(vo.RequestWrapper)
+	q.enqueue(func() { q.module.LogVideoObject(&queued) })
+}
+
+func (q *queuedModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	queued := *cso
+	q.enqueue(func() { q.module.LogCookieSyncObject(&queued) })
+}
+
+func (q *queuedModule) LogSetUIDObject(so *analytics.SetUIDObject) {
+	queued := *so
+	q.enqueue(func() { q.module.LogSetUIDObject(&queued) })
+}
+
+func (q *queuedModule) LogAmpObject(ao *analytics.AmpObject) {
+	queued := *ao
+	queued.RequestWrapper = detachRequestWrapper(ao.RequestWrapper)
+	q.enqueue(func() { q.module.LogAmpObject(&queued) })
+}
+
+func (q *queuedModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	queued := *ne
+	q.enqueue(func() { q.module.LogNotificationEventObject(&queued) })
+}
+
+// Shutdown logs the objects still queued then shuts the module down
+func (q *queuedModule) Shutdown() {
+	q.shutdownOnce.Do(func() {
+		close(q.done)
+		<-q.stopped
+		q.module.Shutdown()
+	})
+}
+
+func (q *queuedModule) run() {
+	defer close(q.stopped)
+	for {
+		select {
+		case log := <-q.queue:
+			q.metrics.RecordAnalyticsQueueDepth(q.name, len(q.queue))
+			log()
+		case <-q.done:
+			q.drain()
+			return
+		}
+	}
+}
+
+func (q *queuedModule) drain() {
+	for {
+		select {
+		case log := <-q.queue:
+			log()
+		default:
+			q.metrics.RecordAnalyticsQueueDepth(q.name, 0)
+			return
+		}
+	}
+}
+
+func (q *queuedModule) enqueue(log func()) {
+	select {
+	case <-q.done:
+		q.metrics.RecordAnalyticsObjectDropped(q.name)
+		return
+	default:
+	}
+
+	if q.push(log) {
+		q.metrics.RecordAnalyticsQueueDepth(q.name, len(q.queue))
+	} else {
+		q.metrics.RecordAnalyticsObjectDropped(q.name)
+	}
+}
+
+// push queues the object according to the drop policy, returning false if the object itself is dropped
+func (q *queuedModule) push(log func()) bool {
+	select {
+	case q.queue <- log:
+		return true
+	default:
+	}
+
+	switch q.dropPolicy {
+	case config.AnalyticsDropNewest:
+		return false
+	case config.AnalyticsBlock:
+		timer := time.NewTimer(q.blockTimeout)
+		defer timer.Stop()
+		select {
+		case q.queue <- log:
+			return true
+		case <-timer.C:
+			return false
+		case <-q.done:
+			return false
+		}
+	default:
+		for {
+			select {
+			case <-q.queue:
+				q.metrics.RecordAnalyticsObjectDropped(q.name)
+			default:
+			}
+			select {
+			case q.queue <- log:
+				return true
+			default:
+			}
+		}
+	}
+}
+
+// detachRequestWrapper returns a RequestWrapper of its own for the queued object. The runner rebuilds and replaces the
+// fields of the bid request of the analytics object rather than updating them in place, so a copy of the rebuilt bid
+// request is enough to keep the queued object unaffected.
+func detachRequestWrapper(rw *openrtb_ext.RequestWrapper) *openrtb_ext.RequestWrapper {
+	if rw == nil {
+		return nil
+	}
+	if rw.BidRequest == nil {
+		return &openrtb_ext.RequestWrapper{}
+	}
+	rw.RebuildRequest()
+	bidRequest := *rw.BidRequest
+	return &openrtb_ext.RequestWrapper{BidRequest: &bidRequest}
+}
@@ -0,0 +1,184 @@
+package build
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// blockingModule records the status of the auction objects logged, after the release channel is closed
+type blockingModule struct {
+	mutex    sync.Mutex
+	started  chan struct{}
+	release  chan struct{}
+	statuses []int
+	requests []*openrtb2.BidRequest
+	shutdown bool
+}
+
+func newBlockingModule() *blockingModule {
+	return &blockingModule{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (m *blockingModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	m.started <- struct{}{}
+	<-m.release
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.statuses = append(m.statuses, ao.Status)
+	if ao.RequestWrapper != nil {
+		m.requests = append(m.requests, ao.RequestWrapper.BidRequest)
+	}
+}
+
+func (m *blockingModule) LogVideoObject(vo *analytics.VideoObject) {}
+
+func (m *blockingModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {}
+
+func (m *blockingModule) LogSetUIDObject(so *analytics.SetUIDObject) {}
+
+func (m *blockingModule) LogAmpObject(ao *analytics.AmpObject) {}
+
+func (m *blockingModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {}
+
+func (m *blockingModule) Shutdown() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.shutdown = true
+}
+
+func newMetricsMockForQueue() *metrics.MetricsEngineMock {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAnalyticsQueueDepth", "test", mock.Anything)
+	me.On("RecordAnalyticsObjectDropped", "test")
+	return me
+}
+
+func TestQueuedModuleLogsAsynchronously(t *testing.T) {
+	module := newBlockingModule()
+	me := newMetricsMockForQueue()
+	queued := newQueuedModule("test", module, config.AnalyticsDispatch{QueueSize: 10, DropPolicy: config.AnalyticsDropOldest}, me)
+
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 1})
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 2})
+	<-module.started
+
+	close(module.release)
+	queued.Shutdown()
+
+	assert.Equal(t, []int{1, 2}, module.statuses, "the queued objects should be logged before the shutdown")
+	assert.True(t, module.shutdown)
+	me.AssertNotCalled(t, "RecordAnalyticsObjectDropped", "test")
+
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 3})
+	me.AssertNumberOfCalls(t, "RecordAnalyticsObjectDropped", 1)
+}
+
+func TestQueuedModuleDropPolicies(t *testing.T) {
+	testCases := []struct {
+		description      string
+		dispatch         config.AnalyticsDispatch
+		expectedStatuses []int
+	}{
+		{
+			description:      "drop_oldest",
+			dispatch:         config.AnalyticsDispatch{QueueSize: 1, DropPolicy: config.AnalyticsDropOldest},
+			expectedStatuses: []int{1, 3},
+		},
+		{
+			description:      "drop_newest",
+			dispatch:         config.AnalyticsDispatch{QueueSize: 1, DropPolicy: config.AnalyticsDropNewest},
+			expectedStatuses: []int{1, 2},
+		},
+		{
+			description:      "block",
+			dispatch:         config.AnalyticsDispatch{QueueSize: 1, DropPolicy: config.AnalyticsBlock, BlockTimeoutMs: 5},
+			expectedStatuses: []int{1, 2},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module := newBlockingModule()
+			me := newMetricsMockForQueue()
+			queued := newQueuedModule("test", module, test.dispatch, me)
+
+			queued.LogAuctionObject(&analytics.AuctionObject{Status: 1})
+			<-module.started
+			queued.LogAuctionObject(&analytics.AuctionObject{Status: 2})
+
+			start := time.Now()
+			queued.LogAuctionObject(&analytics.AuctionObject{Status: 3})
+			assert.Less(t, time.Since(start), time.Second, "a full queue should not block past the block timeout")
+
+			close(module.release)
+			queued.Shutdown()
+
+			assert.Equal(t, test.expectedStatuses, module.statuses)
+			me.AssertNumberOfCalls(t, "RecordAnalyticsObjectDropped", 1)
+		})
+	}
+}
+
+func TestQueuedModuleBlockWaitsForRoom(t *testing.T) {
+	module := newBlockingModule()
+	queued := newQueuedModule("test", module, config.AnalyticsDispatch{QueueSize: 1, DropPolicy: config.AnalyticsBlock, BlockTimeoutMs: 5000}, newMetricsMockForQueue())
+
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 1})
+	<-module.started
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 2})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(module.release)
+	}()
+	queued.LogAuctionObject(&analytics.AuctionObject{Status: 3})
+	queued.Shutdown()
+
+	assert.Equal(t, []int{1, 2, 3}, module.statuses, "the object should be queued once the module catches up")
+}
+
+func TestQueuedModuleWithRunner(t *testing.T) {
+	module := newBlockingModule()
+	close(module.release)
+	runner := enabledAnalytics{
+		"test": newQueuedModule("test", module, config.AnalyticsDispatch{QueueSize: 10, DropPolicy: config.AnalyticsDropOldest}, newMetricsMockForQueue()),
+	}
+
+	bidRequest := &openrtb2.BidRequest{ID: "req-id", Ext: []byte(`{"prebid":{"analytics":{"test":{"key":"value"},"other":{"key":"value"}}}}`)}
+	ao := &analytics.AuctionObject{Status: 1, RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: bidRequest}}
+	runner.LogAuctionObject(ao, privacy.ActivityControl{})
+
+	ao.RequestWrapper.BidRequest.ID = "updated-id"
+	ao.Status = 2
+	runner.Shutdown()
+
+	assert.Equal(t, []int{1}, module.statuses)
+	if assert.Len(t, module.requests, 1) {
+		assert.Equal(t, "req-id", module.requests[0].ID, "the queued object should not change after being queued")
+		assert.JSONEq(t, `{"prebid":{"analytics":{"test":{"key":"value"}}}}`, string(module.requests[0].Ext), "the module should only get its own analytics settings")
+	}
+	assert.Equal(t, "updated-id", ao.RequestWrapper.BidRequest.ID)
+}
+
+func TestDetachRequestWrapper(t *testing.T) {
+	assert.Nil(t, detachRequestWrapper(nil))
+	assert.Equal(t, &openrtb_ext.RequestWrapper{}, detachRequestWrapper(&openrtb_ext.RequestWrapper{}))
+
+	rw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req-id"}}
+	detached := detachRequestWrapper(rw)
+	rw.BidRequest.ID = "updated-id"
+	assert.Equal(t, "req-id", detached.BidRequest.ID)
+}
@@ -0,0 +1,35 @@
+package analytics
+
+import (
+	"net/http"
+
+	"github.com/benbjohnson/clock"
+	"github.com/mitchellh/mapstructure"
+)
+
+// ModuleDeps provides the dependencies shared by the analytics modules
+type ModuleDeps struct {
+	HTTPClient *http.Client
+	Clock      clock.Clock
+}
+
+type (
+	// ModuleBuilders mapping between analytics module name and its builder
+	ModuleBuilders map[string]ModuleBuilderFn
+	// ModuleBuilderFn returns the analytics module initialized with its host config, either its dedicated
+	// block of config.Analytics or its entry of analytics.modules. The builders read it with DecodeConfig.
+	ModuleBuilderFn func(cfg interface{}, deps ModuleDeps) (Module, error)
+)
+
+// DecodeConfig decodes the host config of an analytics module into the config struct of the module, following
+// its mapstructure tags as the config is loaded from the PBS config.
+func DecodeConfig(cfg interface{}, result interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           result,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(cfg)
+}
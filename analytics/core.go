@@ -1,6 +1,7 @@
 package analytics
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -32,6 +33,9 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	FloorsOutcome        *FloorsOutcome
+	// AccountConfig is the account-level config of the module logging the object, nil if the account doesn't configure it
+	AccountConfig json.RawMessage
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -41,10 +45,49 @@ type AmpObject struct {
 	AuctionResponse      *openrtb2.BidResponse
 	AmpTargetingValues   map[string]string
 	Origin               string
+	Account              *config.Account
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	FloorsOutcome        *FloorsOutcome
+	// AccountConfig is the account-level config of the module logging the object, nil if the account doesn't configure it
+	AccountConfig json.RawMessage
+}
+
+// FloorsOutcome is the outcome of the price floors of an auction, with a record per bid of the floor it was
+// checked against. It's nil if price floors are disabled for the auction.
+type FloorsOutcome struct {
+	// Location of the floor data: noData, request or fetch
+	Location      string `json:"location,omitempty"`
+	FetchStatus   string `json:"fetchStatus,omitempty"`
+	FloorProvider string `json:"floorProvider,omitempty"`
+	// ModelVersion is the version of the model group selected
+	ModelVersion string `json:"modelVersion,omitempty"`
+	// Skipped is true if the floors weren't signaled to the bidders due to the skip rate
+	Skipped bool `json:"skipped"`
+	// Enforced is true if the bids below the floors were rejected, unless exempted as deals
+	Enforced bool               `json:"enforced"`
+	Bids     []FloorsBidOutcome `json:"bids,omitempty"`
+}
+
+// FloorsBidOutcome is the floor a bid was checked against. The price of the bid and the margin, the price minus
+// the floor, are in the floor currency.
+type FloorsBidOutcome struct {
+	Seat           string  `json:"seat"`
+	BidID          string  `json:"bidId"`
+	ImpID          string  `json:"impId"`
+	Deal           bool    `json:"deal,omitempty"`
+	FloorValue     float64 `json:"floorValue"`
+	FloorCurrency  string  `json:"floorCurrency,omitempty"`
+	FloorRule      string  `json:"floorRule,omitempty"`
+	FloorRuleValue float64 `json:"floorRuleValue,omitempty"`
+	ModelVersion   string  `json:"modelVersion,omitempty"`
+	BidPrice       float64 `json:"bidPrice"`
+	Margin         float64 `json:"margin"`
+	// Enforced is false if the enforcement was skipped for the bid, by the enforce rate or as a deal
+	Enforced bool `json:"enforced"`
+	Rejected bool `json:"rejected"`
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
@@ -54,9 +97,12 @@ type VideoObject struct {
 	Response       *openrtb2.BidResponse
 	VideoRequest   *openrtb_ext.BidRequestVideo
 	VideoResponse  *openrtb_ext.BidResponseVideo
+	Account        *config.Account
 	StartTime      time.Time
 	SeatNonBid     []openrtb_ext.SeatNonBid
 	RequestWrapper *openrtb_ext.RequestWrapper
+	// AccountConfig is the account-level config of the module logging the object, nil if the account doesn't configure it
+	AccountConfig json.RawMessage
 }
 
 // Loggable object of a transaction at /setuid
@@ -91,4 +137,6 @@ type UsersyncInfo struct {
 type NotificationEvent struct {
 	Request *EventRequest   `json:"request"`
 	Account *config.Account `json:"account"`
+	// AccountConfig is the account-level config of the module logging the object, nil if the account doesn't configure it
+	AccountConfig json.RawMessage `json:"-"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
@@ -98,6 +99,15 @@ func (f *FileLogger) Shutdown() {
 	f.Logger.Flush()
 }
 
+// Builder builds the file logger from the analytics.file config
+func Builder(cfg interface{}, _ analytics.ModuleDeps) (analytics.Module, error) {
+	var conf config.FileLogs
+	if err := analytics.DecodeConfig(cfg, &conf); err != nil {
+		return nil, err
+	}
+	return NewFileLogger(conf.Filename)
+}
+
 // Method to initialize the analytic module
 func NewFileLogger(filename string) (analytics.Module, error) {
 	options := cglog.LogOptions{
@@ -15,6 +15,7 @@ import (
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/analytics/pubstack/eventchannel"
 	"github.com/prebid/prebid-server/v3/analytics/pubstack/helpers"
+	"github.com/prebid/prebid-server/v3/config"
 )
 
 type Configuration struct {
@@ -50,6 +51,23 @@ type PubstackModule struct {
 	clock         clock.Clock
 }
 
+// Builder builds the pubstack module from the analytics.pubstack config
+func Builder(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+	var conf config.Pubstack
+	if err := analytics.DecodeConfig(cfg, &conf); err != nil {
+		return nil, err
+	}
+	return NewModule(
+		deps.HTTPClient,
+		conf.ScopeId,
+		conf.IntakeUrl,
+		conf.ConfRefresh,
+		conf.Buffers.EventCount,
+		conf.Buffers.BufferSize,
+		conf.Buffers.Timeout,
+		deps.Clock)
+}
+
 func NewModule(client *http.Client, scope, endpoint, configRefreshDelay string, maxEventCount int, maxByteSize, maxTime string, clock clock.Clock) (analytics.Module, error) {
 	configUpdateTask, err := NewConfigUpdateHttpTask(
 		client,
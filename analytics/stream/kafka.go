@@ -0,0 +1,444 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// The kafka sink speaks the Kafka wire protocol directly. It only implements what a producer of a
+// single topic needs: Metadata v1 to find the partition leaders and Produce v3 with record batches
+// (magic 2), which every broker since 0.11 accepts. See https://kafka.apache.org/protocol
+
+const (
+	kafkaAPIKeyProduce  int16 = 0
+	kafkaAPIKeyMetadata int16 = 3
+
+	kafkaProduceVersion  int16 = 3
+	kafkaMetadataVersion int16 = 1
+
+	kafkaRecordBatchMagic  int8  = 2
+	kafkaCompressionGzip   int16 = 1
+	kafkaMaxResponseLength       = 64 * 1024 * 1024
+
+	kafkaErrLeaderNotAvailable int16 = 5
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+type kafkaSink struct {
+	bootstrap []string
+	topic     string
+	clientID  string
+	acks      int16
+	timeout   time.Duration
+	gzipped   bool
+	now       func() time.Time
+
+	mu            sync.Mutex
+	correlationID int32
+	conns         map[string]net.Conn
+	leaders       map[int32]string
+	partitions    []int32
+	next          int
+}
+
+func newKafkaSink(cfg config.StreamAnalyticsKafkaSink, gzipped bool) (*kafkaSink, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka sink requires at least one broker")
+	}
+	if cfg.Topic == "" {
+		return nil, errors.New("kafka sink topic is empty")
+	}
+	if cfg.Acks < -1 || cfg.Acks > 1 {
+		return nil, fmt.Errorf("kafka sink acks must be -1, 0 or 1. Got %d", cfg.Acks)
+	}
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaSink{
+		bootstrap: cfg.Brokers,
+		topic:     cfg.Topic,
+		clientID:  cfg.ClientID,
+		acks:      int16(cfg.Acks),
+		timeout:   timeout,
+		gzipped:   gzipped,
+		now:       time.Now,
+		conns:     make(map[string]net.Conn),
+	}, nil
+}
+
+// Send produces the batch to the next partition in round robin order. On failure the partition
+// metadata is refreshed and the batch is produced once more.
+func (s *kafkaSink) Send(records [][]byte) error {
+	batch, err := encodeRecordBatch(records, s.now(), s.gzipped)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.produce(batch); err != nil {
+		s.leaders = nil
+		err = s.produce(batch)
+	}
+	return err
+}
+
+func (s *kafkaSink) produce(batch []byte) error {
+	if len(s.leaders) == 0 {
+		if err := s.refreshMetadata(); err != nil {
+			return err
+		}
+	}
+
+	partition := s.partitions[s.next%len(s.partitions)]
+	s.next++
+
+	var body kafkaEncoder
+	body.putInt16(-1) // transactional_id
+	body.putInt16(s.acks)
+	body.putInt32(int32(s.timeout.Milliseconds()))
+	body.putInt32(1)
+	body.putString(s.topic)
+	body.putInt32(1)
+	body.putInt32(partition)
+	body.putBytes(batch)
+
+	addr := s.leaders[partition]
+	resp, err := s.request(addr, kafkaAPIKeyProduce, kafkaProduceVersion, body.bytes(), s.acks != 0)
+	if err != nil || s.acks == 0 {
+		return err
+	}
+
+	d := kafkaDecoder{b: resp}
+	for topics := d.getInt32(); topics > 0 && d.err == nil; topics-- {
+		d.getString()
+		for partitions := d.getInt32(); partitions > 0 && d.err == nil; partitions-- {
+			index := d.getInt32()
+			errorCode := d.getInt16()
+			d.getInt64() // base_offset
+			d.getInt64() // log_append_time_ms
+			if d.err == nil && errorCode != 0 {
+				return fmt.Errorf("kafka broker %s rejected batch for %s/%d with error code %d", addr, s.topic, index, errorCode)
+			}
+		}
+	}
+	return d.err
+}
+
+func (s *kafkaSink) refreshMetadata() error {
+	var body kafkaEncoder
+	body.putInt32(1)
+	body.putString(s.topic)
+
+	var lastErr error
+	for _, broker := range s.bootstrap {
+		resp, err := s.request(broker, kafkaAPIKeyMetadata, kafkaMetadataVersion, body.bytes(), true)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if lastErr = s.parseMetadata(resp); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to fetch kafka metadata for topic %s: %v", s.topic, lastErr)
+}
+
+func (s *kafkaSink) parseMetadata(resp []byte) error {
+	d := kafkaDecoder{b: resp}
+
+	brokers := make(map[int32]string)
+	for count := d.getInt32(); count > 0 && d.err == nil; count-- {
+		nodeID := d.getInt32()
+		host := d.getString()
+		port := d.getInt32()
+		d.getNullableString() // rack
+		brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.getInt32() // controller_id
+
+	leaders := make(map[int32]string)
+	partitions := make([]int32, 0)
+	for topics := d.getInt32(); topics > 0 && d.err == nil; topics-- {
+		topicErr := d.getInt16()
+		name := d.getString()
+		d.getInt8() // is_internal
+		if d.err == nil && name == s.topic && topicErr != 0 {
+			return fmt.Errorf("topic error code %d", topicErr)
+		}
+		for count := d.getInt32(); count > 0 && d.err == nil; count-- {
+			partitionErr := d.getInt16()
+			index := d.getInt32()
+			leader := d.getInt32()
+			d.skipInt32Array() // replica_nodes
+			d.skipInt32Array() // isr_nodes
+			addr, ok := brokers[leader]
+			if name == s.topic && ok && partitionErr != kafkaErrLeaderNotAvailable {
+				leaders[index] = addr
+				partitions = append(partitions, index)
+			}
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(partitions) == 0 {
+		return errors.New("no partition with an available leader")
+	}
+
+	s.leaders = leaders
+	s.partitions = partitions
+	return nil
+}
+
+// request sends one request to the broker and returns the response body following the correlation id.
+func (s *kafkaSink) request(addr string, apiKey, apiVersion int16, body []byte, expectResponse bool) ([]byte, error) {
+	conn, err := s.conn(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.roundTrip(conn, apiKey, apiVersion, body, expectResponse)
+	if err != nil {
+		conn.Close()
+		delete(s.conns, addr)
+	}
+	return resp, err
+}
+
+func (s *kafkaSink) roundTrip(conn net.Conn, apiKey, apiVersion int16, body []byte, expectResponse bool) ([]byte, error) {
+	s.correlationID++
+	correlationID := s.correlationID
+
+	var req kafkaEncoder
+	req.putInt32(0) // size placeholder
+	req.putInt16(apiKey)
+	req.putInt16(apiVersion)
+	req.putInt32(correlationID)
+	req.putString(s.clientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+
+	conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := conn.Write(req.buf); err != nil {
+		return nil, err
+	}
+	if !expectResponse {
+		return nil, nil
+	}
+
+	var header [8]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		return nil, err
+	}
+	length := int32(binary.BigEndian.Uint32(header[:4]))
+	if length < 4 || length > kafkaMaxResponseLength {
+		return nil, fmt.Errorf("invalid kafka response length %d", length)
+	}
+	if got := int32(binary.BigEndian.Uint32(header[4:])); got != correlationID {
+		return nil, fmt.Errorf("kafka correlation id mismatch: expected %d, got %d", correlationID, got)
+	}
+
+	resp := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *kafkaSink) conn(addr string) (net.Conn, error) {
+	if conn, ok := s.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", addr, s.timeout)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[addr] = conn
+	return conn, nil
+}
+
+func (s *kafkaSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for addr, conn := range s.conns {
+		conn.Close()
+		delete(s.conns, addr)
+	}
+	return nil
+}
+
+// encodeRecordBatch builds a v2 record batch holding one record per event, without keys.
+func encodeRecordBatch(records [][]byte, now time.Time, gzipped bool) ([]byte, error) {
+	var recordsData kafkaEncoder
+	for i, value := range records {
+		var record kafkaEncoder
+		record.putInt8(0)          // attributes
+		record.putVarint(0)        // timestamp_delta
+		record.putVarint(int64(i)) // offset_delta
+		record.putVarint(-1)       // key length, null key
+		record.putVarint(int64(len(value)))
+		record.buf = append(record.buf, value...)
+		record.putVarint(0) // headers count
+
+		recordsData.putVarint(int64(len(record.buf)))
+		recordsData.buf = append(recordsData.buf, record.buf...)
+	}
+
+	var attributes int16
+	data := recordsData.bytes()
+	if gzipped {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(data); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		data = buf.Bytes()
+		attributes = kafkaCompressionGzip
+	}
+
+	timestamp := now.UnixMilli()
+	var tail kafkaEncoder
+	tail.putInt16(attributes)
+	tail.putInt32(int32(len(records) - 1)) // last_offset_delta
+	tail.putInt64(timestamp)               // base_timestamp
+	tail.putInt64(timestamp)               // max_timestamp
+	tail.putInt64(-1)                      // producer_id
+	tail.putInt16(-1)                      // producer_epoch
+	tail.putInt32(-1)                      // base_sequence
+	tail.putInt32(int32(len(records)))
+	tail.buf = append(tail.buf, data...)
+
+	var batch kafkaEncoder
+	batch.putInt64(0)                                // base_offset
+	batch.putInt32(int32(4 + 1 + 4 + len(tail.buf))) // batch_length
+	batch.putInt32(-1)                               // partition_leader_epoch
+	batch.putInt8(kafkaRecordBatchMagic)
+	batch.putInt32(int32(crc32.Checksum(tail.buf, castagnoliTable)))
+	batch.buf = append(batch.buf, tail.buf...)
+	return batch.bytes(), nil
+}
+
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) bytes() []byte {
+	return e.buf
+}
+
+func (e *kafkaEncoder) putInt8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *kafkaEncoder) putInt16(v int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+}
+
+func (e *kafkaEncoder) putInt32(v int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+}
+
+func (e *kafkaEncoder) putInt64(v int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+}
+
+func (e *kafkaEncoder) putVarint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *kafkaEncoder) putString(v string) {
+	e.putInt16(int16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *kafkaEncoder) putBytes(v []byte) {
+	e.putInt32(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// kafkaDecoder reads big endian fields and remembers the first error, so that callers
+// can check it once after reading a whole structure.
+type kafkaDecoder struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.b) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	v := d.b[d.off : d.off+n]
+	d.off += n
+	return v
+}
+
+func (d *kafkaDecoder) getInt8() int8 {
+	if v := d.next(1); v != nil {
+		return int8(v[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) getInt16() int16 {
+	if v := d.next(2); v != nil {
+		return int16(binary.BigEndian.Uint16(v))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) getInt32() int32 {
+	if v := d.next(4); v != nil {
+		return int32(binary.BigEndian.Uint32(v))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) getInt64() int64 {
+	if v := d.next(8); v != nil {
+		return int64(binary.BigEndian.Uint64(v))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) getString() string {
+	length := d.getInt16()
+	return string(d.next(int(length)))
+}
+
+func (d *kafkaDecoder) getNullableString() string {
+	length := d.getInt16()
+	if length < 0 {
+		return ""
+	}
+	return string(d.next(int(length)))
+}
+
+func (d *kafkaDecoder) skipInt32Array() {
+	count := d.getInt32()
+	d.next(int(count) * 4)
+}
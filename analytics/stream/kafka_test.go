@@ -0,0 +1,303 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type producedBatch struct {
+	partition int32
+	acks      int16
+	records   []string
+}
+
+// brokerStandIn answers Metadata and Produce requests like a single node Kafka cluster
+// leading every partition of one topic.
+type brokerStandIn struct {
+	t          *testing.T
+	listener   net.Listener
+	topic      string
+	partitions int32
+	errorCode  int16
+
+	mu       sync.Mutex
+	batches  []producedBatch
+	clientID string
+}
+
+func newBrokerStandIn(t *testing.T, topic string, partitions int32) *brokerStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &brokerStandIn{t: t, listener: listener, topic: topic, partitions: partitions}
+	go b.serve()
+	t.Cleanup(func() { listener.Close() })
+	return b
+}
+
+func (b *brokerStandIn) addr() string {
+	return b.listener.Addr().String()
+}
+
+func (b *brokerStandIn) produced() []producedBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches
+}
+
+func (b *brokerStandIn) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *brokerStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		d := kafkaDecoder{b: req}
+		apiKey := d.getInt16()
+		d.getInt16() // api_version
+		correlationID := d.getInt32()
+		clientID := d.getString()
+		b.mu.Lock()
+		b.clientID = clientID
+		b.mu.Unlock()
+
+		var resp kafkaEncoder
+		resp.putInt32(correlationID)
+		switch apiKey {
+		case kafkaAPIKeyMetadata:
+			b.writeMetadata(&resp)
+		case kafkaAPIKeyProduce:
+			if !b.readProduce(&d, &resp) {
+				continue
+			}
+		default:
+			return
+		}
+
+		var frame kafkaEncoder
+		frame.putBytes(resp.bytes())
+		if _, err := conn.Write(frame.bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (b *brokerStandIn) writeMetadata(resp *kafkaEncoder) {
+	host, port, _ := net.SplitHostPort(b.addr())
+	portNumber, _ := strconv.Atoi(port)
+
+	resp.putInt32(1)
+	resp.putInt32(7) // node_id
+	resp.putString(host)
+	resp.putInt32(int32(portNumber))
+	resp.putInt16(-1) // rack
+	resp.putInt32(7)  // controller_id
+	resp.putInt32(1)
+	resp.putInt16(0)
+	resp.putString(b.topic)
+	resp.putInt8(0)
+	resp.putInt32(b.partitions)
+	for i := int32(0); i < b.partitions; i++ {
+		resp.putInt16(0)
+		resp.putInt32(i)
+		resp.putInt32(7) // leader
+		resp.putInt32(1) // replicas
+		resp.putInt32(7)
+		resp.putInt32(1) // isr
+		resp.putInt32(7)
+	}
+}
+
+// readProduce records the produced batch and reports whether a response is expected.
+func (b *brokerStandIn) readProduce(d *kafkaDecoder, resp *kafkaEncoder) bool {
+	d.getInt16() // transactional_id
+	acks := d.getInt16()
+	d.getInt32() // timeout
+	d.getInt32() // topics
+	topic := d.getString()
+	d.getInt32() // partitions
+	partition := d.getInt32()
+	batch := d.next(int(d.getInt32()))
+	require.NoError(b.t, d.err)
+
+	records, err := decodeRecordBatch(batch)
+	assert.NoError(b.t, err)
+
+	b.mu.Lock()
+	b.batches = append(b.batches, producedBatch{partition: partition, acks: acks, records: records})
+	errorCode := b.errorCode
+	b.mu.Unlock()
+
+	resp.putInt32(1)
+	resp.putString(topic)
+	resp.putInt32(1)
+	resp.putInt32(partition)
+	resp.putInt16(errorCode)
+	resp.putInt64(0)  // base_offset
+	resp.putInt64(-1) // log_append_time_ms
+	resp.putInt32(0)  // throttle_time_ms
+	return acks != 0
+}
+
+func decodeRecordBatch(batch []byte) ([]string, error) {
+	d := kafkaDecoder{b: batch}
+	d.getInt64() // base_offset
+	length := d.getInt32()
+	d.getInt32() // partition_leader_epoch
+	if magic := d.getInt8(); magic != kafkaRecordBatchMagic {
+		return nil, errors.New("unexpected magic")
+	}
+	crc := uint32(d.getInt32())
+	if d.err != nil || int(length) != len(batch)-12 {
+		return nil, errors.New("invalid batch length")
+	}
+	if crc32.Checksum(batch[d.off:], castagnoliTable) != crc {
+		return nil, errors.New("crc mismatch")
+	}
+
+	attributes := d.getInt16()
+	d.next(4 + 8 + 8 + 8 + 2 + 4)
+	count := d.getInt32()
+	data := batch[d.off:]
+	if attributes&0x7 == kafkaCompressionGzip {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]string, 0, count)
+	r := bytes.NewReader(data)
+	for i := int32(0); i < count; i++ {
+		if _, err := binary.ReadVarint(r); err != nil { // length
+			return nil, err
+		}
+		r.ReadByte()         // attributes
+		binary.ReadVarint(r) // timestamp_delta
+		offsetDelta, _ := binary.ReadVarint(r)
+		if offsetDelta != int64(i) {
+			return nil, errors.New("unexpected offset delta")
+		}
+		if keyLength, _ := binary.ReadVarint(r); keyLength != -1 {
+			return nil, errors.New("unexpected key")
+		}
+		valueLength, _ := binary.ReadVarint(r)
+		value := make([]byte, valueLength)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		binary.ReadVarint(r) // headers
+		records = append(records, string(value))
+	}
+	return records, nil
+}
+
+func TestKafkaSinkSend(t *testing.T) {
+	testCases := []struct {
+		description string
+		gzipped     bool
+		acks        int
+	}{
+		{description: "uncompressed", gzipped: false, acks: 1},
+		{description: "gzip", gzipped: true, acks: -1},
+		{description: "no_acks", gzipped: false, acks: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			broker := newBrokerStandIn(t, "pbs-events", 2)
+			sink, err := newKafkaSink(config.StreamAnalyticsKafkaSink{
+				Brokers:  []string{broker.addr()},
+				Topic:    "pbs-events",
+				ClientID: "pbs-test",
+				Acks:     tc.acks,
+				Timeout:  "1s",
+			}, tc.gzipped)
+			require.NoError(t, err)
+			defer sink.Close()
+
+			require.NoError(t, sink.Send([][]byte{[]byte(`{"n":1}`), []byte(`{"n":2}`)}))
+			require.NoError(t, sink.Send([][]byte{[]byte(`{"n":3}`)}))
+
+			var batches []producedBatch
+			require.Eventually(t, func() bool {
+				batches = broker.produced()
+				return len(batches) == 2
+			}, time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, producedBatch{partition: 0, acks: int16(tc.acks), records: []string{`{"n":1}`, `{"n":2}`}}, batches[0])
+			assert.Equal(t, producedBatch{partition: 1, acks: int16(tc.acks), records: []string{`{"n":3}`}}, batches[1], "partitions are used in round robin")
+			broker.mu.Lock()
+			assert.Equal(t, "pbs-test", broker.clientID)
+			broker.mu.Unlock()
+		})
+	}
+}
+
+func TestKafkaSinkSendRejected(t *testing.T) {
+	broker := newBrokerStandIn(t, "pbs-events", 1)
+	broker.errorCode = 2
+	sink, err := newKafkaSink(config.StreamAnalyticsKafkaSink{
+		Brokers: []string{broker.addr()},
+		Topic:   "pbs-events",
+		Acks:    1,
+		Timeout: "1s",
+	}, false)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Send([][]byte{[]byte(`{}`)})
+
+	assert.EqualError(t, err, "kafka broker "+broker.addr()+" rejected batch for pbs-events/0 with error code 2")
+	assert.Len(t, broker.produced(), 2, "the batch is retried once after refreshing metadata")
+}
+
+func TestKafkaSinkUnreachableBroker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	sink, err := newKafkaSink(config.StreamAnalyticsKafkaSink{
+		Brokers: []string{addr},
+		Topic:   "pbs-events",
+		Acks:    1,
+		Timeout: "100ms",
+	}, false)
+	require.NoError(t, err)
+
+	err = sink.Send([][]byte{[]byte(`{}`)})
+
+	assert.ErrorContains(t, err, "failed to fetch kafka metadata for topic pbs-events")
+}
@@ -0,0 +1,216 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// SchemaVersion is incremented whenever a field is renamed or removed, or its meaning changes.
+// Adding a field does not change the version, so consumers must ignore fields they don't know.
+const SchemaVersion = 1
+
+type EventType string
+
+const (
+	EventTypeAuction      EventType = "auction"
+	EventTypeAmp          EventType = "amp"
+	EventTypeVideo        EventType = "video"
+	EventTypeSetUID       EventType = "setuid"
+	EventTypeCookieSync   EventType = "cookie_sync"
+	EventTypeNotification EventType = "notification"
+)
+
+// Event is the envelope of every record sent through a sink. Exactly one of the payload fields
+// is set, matching Type.
+type Event struct {
+	Version      int                `json:"version"`
+	Type         EventType          `json:"type"`
+	Timestamp    int64              `json:"timestamp"`
+	Auction      *AuctionEvent      `json:"auction,omitempty"`
+	Amp          *AmpEvent          `json:"amp,omitempty"`
+	Video        *VideoEvent        `json:"video,omitempty"`
+	SetUID       *SetUIDEvent       `json:"setuid,omitempty"`
+	CookieSync   *CookieSyncEvent   `json:"cookie_sync,omitempty"`
+	Notification *NotificationEvent `json:"notification,omitempty"`
+}
+
+type AuctionEvent struct {
+	Status       int                          `json:"status"`
+	Errors       []string                     `json:"errors,omitempty"`
+	AccountID    string                       `json:"account_id,omitempty"`
+	StartTime    int64                        `json:"start_time"`
+	Request      *openrtb2.BidRequest         `json:"request,omitempty"`
+	Response     *openrtb2.BidResponse        `json:"response,omitempty"`
+	SeatNonBid   []openrtb_ext.SeatNonBid     `json:"seat_non_bid,omitempty"`
+	HookOutcomes []hookexecution.StageOutcome `json:"hook_outcomes,omitempty"`
+}
+
+type AmpEvent struct {
+	Status          int                          `json:"status"`
+	Errors          []string                     `json:"errors,omitempty"`
+	Origin          string                       `json:"origin,omitempty"`
+	StartTime       int64                        `json:"start_time"`
+	Request         *openrtb2.BidRequest         `json:"request,omitempty"`
+	Response        *openrtb2.BidResponse        `json:"response,omitempty"`
+	TargetingValues map[string]string            `json:"targeting,omitempty"`
+	SeatNonBid      []openrtb_ext.SeatNonBid     `json:"seat_non_bid,omitempty"`
+	HookOutcomes    []hookexecution.StageOutcome `json:"hook_outcomes,omitempty"`
+}
+
+type VideoEvent struct {
+	Status        int                           `json:"status"`
+	Errors        []string                      `json:"errors,omitempty"`
+	StartTime     int64                         `json:"start_time"`
+	Request       *openrtb2.BidRequest          `json:"request,omitempty"`
+	Response      *openrtb2.BidResponse         `json:"response,omitempty"`
+	VideoRequest  *openrtb_ext.BidRequestVideo  `json:"video_request,omitempty"`
+	VideoResponse *openrtb_ext.BidResponseVideo `json:"video_response,omitempty"`
+	SeatNonBid    []openrtb_ext.SeatNonBid      `json:"seat_non_bid,omitempty"`
+}
+
+type SetUIDEvent struct {
+	Status  int      `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+	Bidder  string   `json:"bidder,omitempty"`
+	UID     string   `json:"uid,omitempty"`
+	Success bool     `json:"success"`
+}
+
+type CookieSyncEvent struct {
+	Status       int                           `json:"status"`
+	Errors       []string                      `json:"errors,omitempty"`
+	BidderStatus []*analytics.CookieSyncBidder `json:"bidder_status,omitempty"`
+}
+
+type NotificationEvent struct {
+	Request   *analytics.EventRequest `json:"request,omitempty"`
+	AccountID string                  `json:"account_id,omitempty"`
+}
+
+func serializeAuctionObject(ao *analytics.AuctionObject, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeAuction,
+		Timestamp: now.UnixMilli(),
+		Auction: &AuctionEvent{
+			Status:       ao.Status,
+			Errors:       errorsToStrings(ao.Errors),
+			AccountID:    accountID(ao.Account),
+			StartTime:    ao.StartTime.UnixMilli(),
+			Request:      bidRequest(ao.RequestWrapper),
+			Response:     ao.Response,
+			SeatNonBid:   ao.SeatNonBid,
+			HookOutcomes: ao.HookExecutionOutcome,
+		},
+	})
+}
+
+func serializeAmpObject(ao *analytics.AmpObject, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeAmp,
+		Timestamp: now.UnixMilli(),
+		Amp: &AmpEvent{
+			Status:          ao.Status,
+			Errors:          errorsToStrings(ao.Errors),
+			Origin:          ao.Origin,
+			StartTime:       ao.StartTime.UnixMilli(),
+			Request:         bidRequest(ao.RequestWrapper),
+			Response:        ao.AuctionResponse,
+			TargetingValues: ao.AmpTargetingValues,
+			SeatNonBid:      ao.SeatNonBid,
+			HookOutcomes:    ao.HookExecutionOutcome,
+		},
+	})
+}
+
+func serializeVideoObject(vo *analytics.VideoObject, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeVideo,
+		Timestamp: now.UnixMilli(),
+		Video: &VideoEvent{
+			Status:        vo.Status,
+			Errors:        errorsToStrings(vo.Errors),
+			StartTime:     vo.StartTime.UnixMilli(),
+			Request:       bidRequest(vo.RequestWrapper),
+			Response:      vo.Response,
+			VideoRequest:  vo.VideoRequest,
+			VideoResponse: vo.VideoResponse,
+			SeatNonBid:    vo.SeatNonBid,
+		},
+	})
+}
+
+func serializeSetUIDObject(so *analytics.SetUIDObject, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeSetUID,
+		Timestamp: now.UnixMilli(),
+		SetUID: &SetUIDEvent{
+			Status:  so.Status,
+			Errors:  errorsToStrings(so.Errors),
+			Bidder:  so.Bidder,
+			UID:     so.UID,
+			Success: so.Success,
+		},
+	})
+}
+
+func serializeCookieSyncObject(cso *analytics.CookieSyncObject, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeCookieSync,
+		Timestamp: now.UnixMilli(),
+		CookieSync: &CookieSyncEvent{
+			Status:       cso.Status,
+			Errors:       errorsToStrings(cso.Errors),
+			BidderStatus: cso.BidderStatus,
+		},
+	})
+}
+
+func serializeNotificationEvent(ne *analytics.NotificationEvent, now time.Time) ([]byte, error) {
+	return jsonutil.Marshal(Event{
+		Version:   SchemaVersion,
+		Type:      EventTypeNotification,
+		Timestamp: now.UnixMilli(),
+		Notification: &NotificationEvent{
+			Request:   ne.Request,
+			AccountID: accountID(ne.Account),
+		},
+	})
+}
+
+func errorsToStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return messages
+}
+
+func accountID(account *config.Account) string {
+	if account == nil {
+		return ""
+	}
+	return account.ID
+}
+
+func bidRequest(rw *openrtb_ext.RequestWrapper) *openrtb2.BidRequest {
+	if rw == nil {
+		return nil
+	}
+	return rw.BidRequest
+}
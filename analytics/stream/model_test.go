@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSerializeAuctionObject(t *testing.T) {
+	ao := &analytics.AuctionObject{
+		Status:    200,
+		Errors:    []error{errors.New("first"), nil, errors.New("second")},
+		Account:   &config.Account{ID: "acct"},
+		StartTime: testNow.Add(-time.Second),
+		RequestWrapper: &openrtb_ext.RequestWrapper{
+			BidRequest: &openrtb2.BidRequest{ID: "req"},
+		},
+		Response: &openrtb2.BidResponse{ID: "resp"},
+	}
+
+	data, err := serializeAuctionObject(ao, testNow)
+	require.NoError(t, err)
+
+	expected := `{"version":1,"type":"auction","timestamp":1717243200000,"auction":{"status":200,"errors":["first","second"],"account_id":"acct","start_time":1717243199000,"request":{"id":"req","imp":null},"response":{"id":"resp"}}}`
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestSerializeEvents(t *testing.T) {
+	testCases := []struct {
+		description string
+		serialize   func() ([]byte, error)
+		expected    string
+	}{
+		{
+			description: "amp",
+			serialize: func() ([]byte, error) {
+				return serializeAmpObject(&analytics.AmpObject{
+					Status:             200,
+					Origin:             "https://example.com",
+					StartTime:          testNow,
+					AmpTargetingValues: map[string]string{"hb_pb": "1.00"},
+				}, testNow)
+			},
+			expected: `{"version":1,"type":"amp","timestamp":1717243200000,"amp":{"status":200,"origin":"https://example.com","start_time":1717243200000,"targeting":{"hb_pb":"1.00"}}}`,
+		},
+		{
+			description: "video",
+			serialize: func() ([]byte, error) {
+				return serializeVideoObject(&analytics.VideoObject{Status: 400, StartTime: testNow}, testNow)
+			},
+			expected: `{"version":1,"type":"video","timestamp":1717243200000,"video":{"status":400,"start_time":1717243200000}}`,
+		},
+		{
+			description: "setuid",
+			serialize: func() ([]byte, error) {
+				return serializeSetUIDObject(&analytics.SetUIDObject{Status: 200, Bidder: "appnexus", UID: "uid", Success: true}, testNow)
+			},
+			expected: `{"version":1,"type":"setuid","timestamp":1717243200000,"setuid":{"status":200,"bidder":"appnexus","uid":"uid","success":true}}`,
+		},
+		{
+			description: "cookie_sync",
+			serialize: func() ([]byte, error) {
+				return serializeCookieSyncObject(&analytics.CookieSyncObject{
+					Status:       200,
+					BidderStatus: []*analytics.CookieSyncBidder{{BidderCode: "appnexus", NoCookie: true}},
+				}, testNow)
+			},
+			expected: `{"version":1,"type":"cookie_sync","timestamp":1717243200000,"cookie_sync":{"status":200,"bidder_status":[{"bidder":"appnexus","no_cookie":true}]}}`,
+		},
+		{
+			description: "notification",
+			serialize: func() ([]byte, error) {
+				return serializeNotificationEvent(&analytics.NotificationEvent{
+					Request: &analytics.EventRequest{Type: analytics.Win, BidID: "bid"},
+					Account: &config.Account{ID: "acct"},
+				}, testNow)
+			},
+			expected: `{"version":1,"type":"notification","timestamp":1717243200000,"notification":{"request":{"type":"win","bidid":"bid"},"account_id":"acct"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			data, err := tc.serialize()
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/version"
+)
+
+const (
+	SinkTypeHTTP  = "http"
+	SinkTypeFile  = "file"
+	SinkTypeKafka = "kafka"
+
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+// Sink delivers batches of serialized events. Each record is one serialized Event.
+// Implementations must be safe for concurrent use.
+type Sink interface {
+	Send(records [][]byte) error
+	Close() error
+}
+
+func newSink(httpClient *http.Client, cfg config.StreamAnalytics) (Sink, error) {
+	gzipped := cfg.Compression == CompressionGzip
+	switch cfg.Sink.Type {
+	case SinkTypeHTTP:
+		return newHTTPSink(httpClient, cfg.Sink.HTTP, gzipped)
+	case SinkTypeFile:
+		return newFileSink(cfg.Sink.File, gzipped)
+	case SinkTypeKafka:
+		return newKafkaSink(cfg.Sink.Kafka, gzipped)
+	}
+	return nil, fmt.Errorf("unknown sink type %q", cfg.Sink.Type)
+}
+
+// encodeNDJSON joins the records into newline delimited JSON, optionally as a gzip stream.
+func encodeNDJSON(records [][]byte, gzipped bool) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if gzipped {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	for _, record := range records {
+		if _, err := w.Write(record); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte{'\n'}); err != nil {
+			return nil, err
+		}
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// httpSink posts every batch as newline delimited JSON.
+type httpSink struct {
+	client  *http.Client
+	url     string
+	timeout time.Duration
+	headers map[string]string
+	gzipped bool
+}
+
+func newHTTPSink(client *http.Client, cfg config.StreamAnalyticsHTTPSink, gzipped bool) (*httpSink, error) {
+	if _, err := url.ParseRequestURI(cfg.Url); err != nil {
+		return nil, fmt.Errorf("invalid http sink url %q: %v", cfg.Url, err)
+	}
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpSink{
+		client:  client,
+		url:     cfg.Url,
+		timeout: timeout,
+		headers: cfg.Headers,
+		gzipped: gzipped,
+	}, nil
+}
+
+func (s *httpSink) Send(records [][]byte) error {
+	body, err := encodeNDJSON(records, s.gzipped)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("wrong code received %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
+
+// fileSink appends every batch to a local file and rotates it once it grows past the
+// configured size. Rotated files are suffixed with their rotation time.
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	gzipped    bool
+	now        func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newFileSink(cfg config.StreamAnalyticsFileSink, gzipped bool) (*fileSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file sink path is empty")
+	}
+	maxSize, err := units.FromHumanSize(cfg.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fileSink{
+		path:       cfg.Path,
+		maxSize:    maxSize,
+		maxBackups: cfg.MaxBackups,
+		gzipped:    gzipped,
+		now:        time.Now,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// Send writes the batch in one call. With compression enabled each batch is a separate gzip
+// member, and a file of concatenated members is itself a valid gzip file.
+func (s *fileSink) Send(records [][]byte) error {
+	data, err := encodeNDJSON(records, s.gzipped)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("file sink is closed")
+	}
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	rotated := s.path + "." + s.now().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(s.path, rotated); err != nil {
+		return err
+	}
+	s.removeOldBackups()
+	return s.open()
+}
+
+func (s *fileSink) removeOldBackups() {
+	backups, err := filepath.Glob(s.path + ".*")
+	if err != nil || len(backups) <= s.maxBackups {
+		return
+	}
+
+	// the timestamp suffix sorts chronologically
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-s.maxBackups] {
+		if strings.HasPrefix(backup, s.path+".") {
+			os.Remove(backup)
+		}
+	}
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,168 @@
+package stream
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSink(t *testing.T) {
+	testCases := []struct {
+		description   string
+		cfg           config.StreamAnalytics
+		expectedError string
+	}{
+		{
+			description: "http",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type: SinkTypeHTTP,
+				HTTP: config.StreamAnalyticsHTTPSink{Url: "http://localhost:8080", Timeout: "1s"},
+			}},
+		},
+		{
+			description: "http_invalid_url",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type: SinkTypeHTTP,
+				HTTP: config.StreamAnalyticsHTTPSink{Url: "::", Timeout: "1s"},
+			}},
+			expectedError: `invalid http sink url "::": parse "::": missing protocol scheme`,
+		},
+		{
+			description: "file_without_path",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type: SinkTypeFile,
+				File: config.StreamAnalyticsFileSink{MaxSize: "1MB"},
+			}},
+			expectedError: "file sink path is empty",
+		},
+		{
+			description: "kafka",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type:  SinkTypeKafka,
+				Kafka: config.StreamAnalyticsKafkaSink{Brokers: []string{"localhost:9092"}, Topic: "pbs", Acks: 1, Timeout: "1s"},
+			}},
+		},
+		{
+			description: "kafka_without_topic",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type:  SinkTypeKafka,
+				Kafka: config.StreamAnalyticsKafkaSink{Brokers: []string{"localhost:9092"}, Timeout: "1s"},
+			}},
+			expectedError: "kafka sink topic is empty",
+		},
+		{
+			description: "kafka_invalid_acks",
+			cfg: config.StreamAnalytics{Sink: config.StreamAnalyticsSink{
+				Type:  SinkTypeKafka,
+				Kafka: config.StreamAnalyticsKafkaSink{Brokers: []string{"localhost:9092"}, Topic: "pbs", Acks: 2, Timeout: "1s"},
+			}},
+			expectedError: "kafka sink acks must be -1, 0 or 1. Got 2",
+		},
+		{
+			description:   "unknown",
+			cfg:           config.StreamAnalytics{Sink: config.StreamAnalyticsSink{Type: "s3"}},
+			expectedError: `unknown sink type "s3"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			sink, err := newSink(http.DefaultClient, tc.cfg)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, sink.Close())
+		})
+	}
+}
+
+func TestHTTPSinkSend(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ = io.ReadAll(reader)
+	}))
+	defer server.Close()
+
+	sink, err := newHTTPSink(server.Client(), config.StreamAnalyticsHTTPSink{
+		Url:     server.URL,
+		Timeout: "1s",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}, true)
+	require.NoError(t, err)
+
+	err = sink.Send([][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", string(body))
+	assert.Equal(t, "application/x-ndjson", header.Get("Content-Type"))
+	assert.Equal(t, "gzip", header.Get("Content-Encoding"))
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.NotEmpty(t, header.Get("X-Prebid"))
+}
+
+func TestHTTPSinkSendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink, err := newHTTPSink(server.Client(), config.StreamAnalyticsHTTPSink{Url: server.URL, Timeout: "1s"}, false)
+	require.NoError(t, err)
+
+	assert.EqualError(t, sink.Send([][]byte{[]byte(`{}`)}), "wrong code received 500")
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := newFileSink(config.StreamAnalyticsFileSink{Path: path, MaxSize: "20B", MaxBackups: 2}, false)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, record := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`, `{"n":4}`, `{"n":5}`, `{"n":6}`, `{"n":7}`} {
+		require.NoError(t, sink.Send([][]byte{[]byte(record)}))
+	}
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"n\":7}\n", string(current))
+
+	backups, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Len(t, backups, 2, "only the newest backups are kept")
+
+	newest, err := os.ReadFile(backups[1])
+	require.NoError(t, err)
+	assert.Equal(t, "{\"n\":5}\n{\"n\":6}\n", string(newest))
+}
+
+func TestFileSinkSendAfterClose(t *testing.T) {
+	sink, err := newFileSink(config.StreamAnalyticsFileSink{Path: filepath.Join(t.TempDir(), "events"), MaxSize: "1MB"}, false)
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	assert.EqualError(t, sink.Send([][]byte{[]byte(`{}`)}), "file sink is closed")
+}
@@ -0,0 +1,236 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+const FormatJSON = "json"
+
+// maxPendingBatches bounds the batches waiting for the sink, the batches flushed while it's full are dropped so a
+// slow sink can't pile up the events in memory.
+const maxPendingBatches = 16
+
+// StreamLogger serializes every analytics object into a versioned Event and delivers them in
+// batches through a Sink. A batch is sent once it holds the configured number of events or bytes,
+// or when the buffer timeout elapses, whichever comes first.
+type StreamLogger struct {
+	sink              Sink
+	clock             clock.Clock
+	maxEventCount     int
+	maxBufferByteSize int64
+	maxDuration       time.Duration
+
+	bufferCh   chan []byte
+	batchCh    chan [][]byte
+	shutdownCh chan struct{}
+	stoppedCh  chan struct{}
+	sentCh     chan struct{}
+	once       sync.Once
+
+	records        [][]byte
+	bufferBytes    int64
+	droppedBatches atomic.Int64
+}
+
+// Builder builds the stream module from the analytics.stream config
+func Builder(cfg interface{}, deps analytics.ModuleDeps) (analytics.Module, error) {
+	var conf config.StreamAnalytics
+	if err := analytics.DecodeConfig(cfg, &conf); err != nil {
+		return nil, err
+	}
+	return NewModule(deps.HTTPClient, conf, deps.Clock)
+}
+
+func NewModule(httpClient *http.Client, cfg config.StreamAnalytics, clock clock.Clock) (analytics.Module, error) {
+	if cfg.Format != FormatJSON {
+		return nil, fmt.Errorf("unsupported stream analytics format %q", cfg.Format)
+	}
+	if cfg.Compression != CompressionNone && cfg.Compression != CompressionGzip {
+		return nil, fmt.Errorf("unsupported stream analytics compression %q", cfg.Compression)
+	}
+
+	sink, err := newSink(httpClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := newStreamLogger(cfg.Buffers, sink, clock)
+	if err != nil {
+		sink.Close()
+		return nil, err
+	}
+
+	go l.start()
+
+	return l, nil
+}
+
+func newStreamLogger(cfg config.StreamAnalyticsBuffer, sink Sink, clock clock.Clock) (*StreamLogger, error) {
+	pSize, err := units.FromHumanSize(cfg.BufferSize)
+	if err != nil {
+		return nil, err
+	}
+	pDuration, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EventCount <= 0 {
+		return nil, fmt.Errorf("stream analytics buffer count must be positive. Got %d", cfg.EventCount)
+	}
+
+	return &StreamLogger{
+		sink:              sink,
+		clock:             clock,
+		maxEventCount:     cfg.EventCount,
+		maxBufferByteSize: pSize,
+		maxDuration:       pDuration,
+		bufferCh:          make(chan []byte),
+		batchCh:           make(chan [][]byte, maxPendingBatches),
+		shutdownCh:        make(chan struct{}),
+		stoppedCh:         make(chan struct{}),
+		sentCh:            make(chan struct{}),
+	}, nil
+}
+
+func (l *StreamLogger) start() {
+	defer close(l.stoppedCh)
+	go l.send()
+
+	ticker := l.clock.Ticker(l.maxDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.shutdownCh:
+			// the last batch waits for room since the shutdown waits for the sink anyway
+			if len(l.records) > 0 {
+				l.batchCh <- l.takeRecords()
+			}
+			close(l.batchCh)
+			return
+		case record := <-l.bufferCh:
+			l.records = append(l.records, record)
+			l.bufferBytes += int64(len(record))
+			if len(l.records) >= l.maxEventCount || l.bufferBytes >= l.maxBufferByteSize {
+				l.flush()
+			}
+		case <-ticker.C:
+			l.flush()
+		}
+	}
+}
+
+// flush hands the buffered records over to the sender without blocking the buffering loop, the records are
+// dropped when maxPendingBatches batches already wait for the sink.
+func (l *StreamLogger) flush() {
+	if len(l.records) == 0 {
+		return
+	}
+	records := l.takeRecords()
+
+	select {
+	case l.batchCh <- records:
+	default:
+		dropped := l.droppedBatches.Add(1)
+		glog.Errorf("[StreamAnalytics] Sink is behind, dropped %d events (%d batches dropped so far)", len(records), dropped)
+	}
+}
+
+func (l *StreamLogger) takeRecords() [][]byte {
+	records := l.records
+	l.records = nil
+	l.bufferBytes = 0
+	return records
+}
+
+// send delivers the batches to the sink one at a time until the batch channel is closed.
+func (l *StreamLogger) send() {
+	defer close(l.sentCh)
+
+	for records := range l.batchCh {
+		if err := l.sink.Send(records); err != nil {
+			glog.Errorf("[StreamAnalytics] Failed to send %d events: %v", len(records), err)
+		}
+	}
+}
+
+func (l *StreamLogger) buffer(data []byte, err error, eventType EventType) {
+	if err != nil {
+		glog.Errorf("[StreamAnalytics] Error serializing %s event: %v", eventType, err)
+		return
+	}
+	select {
+	case l.bufferCh <- data:
+	case <-l.shutdownCh:
+	}
+}
+
+func (l *StreamLogger) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	data, err := serializeAuctionObject(ao, l.clock.Now())
+	l.buffer(data, err, EventTypeAuction)
+}
+
+func (l *StreamLogger) LogAmpObject(ao *analytics.AmpObject) {
+	if ao == nil {
+		return
+	}
+	data, err := serializeAmpObject(ao, l.clock.Now())
+	l.buffer(data, err, EventTypeAmp)
+}
+
+func (l *StreamLogger) LogVideoObject(vo *analytics.VideoObject) {
+	if vo == nil {
+		return
+	}
+	data, err := serializeVideoObject(vo, l.clock.Now())
+	l.buffer(data, err, EventTypeVideo)
+}
+
+func (l *StreamLogger) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if so == nil {
+		return
+	}
+	data, err := serializeSetUIDObject(so, l.clock.Now())
+	l.buffer(data, err, EventTypeSetUID)
+}
+
+func (l *StreamLogger) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if cso == nil {
+		return
+	}
+	data, err := serializeCookieSyncObject(cso, l.clock.Now())
+	l.buffer(data, err, EventTypeCookieSync)
+}
+
+func (l *StreamLogger) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	if ne == nil {
+		return
+	}
+	data, err := serializeNotificationEvent(ne, l.clock.Now())
+	l.buffer(data, err, EventTypeNotification)
+}
+
+// Shutdown flushes the buffer, waits for the pending batches and closes the sink.
+func (l *StreamLogger) Shutdown() {
+	l.once.Do(func() {
+		glog.Info("[StreamAnalytics] Shutdown, trying to flush buffer")
+		close(l.shutdownCh)
+		<-l.stoppedCh
+		<-l.sentCh
+		if err := l.sink.Close(); err != nil {
+			glog.Errorf("[StreamAnalytics] Failed to close sink: %v", err)
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package stream
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSink struct {
+	mu      sync.Mutex
+	batches [][][]byte
+	closed  bool
+	err     error
+}
+
+func (s *mockSink) Send(records [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, records)
+	return s.err
+}
+
+func (s *mockSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *mockSink) sent() [][][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func newTestStreamLogger(t *testing.T, buffers config.StreamAnalyticsBuffer, sink Sink, clk clock.Clock) *StreamLogger {
+	l, err := newStreamLogger(buffers, sink, clk)
+	require.NoError(t, err)
+	go l.start()
+	return l
+}
+
+func TestNewModule(t *testing.T) {
+	validBuffers := config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 10, Timeout: "1m"}
+	validSink := config.StreamAnalyticsSink{
+		Type: SinkTypeHTTP,
+		HTTP: config.StreamAnalyticsHTTPSink{Url: "http://localhost:8080", Timeout: "1s"},
+	}
+
+	testCases := []struct {
+		description   string
+		cfg           config.StreamAnalytics
+		expectedError string
+	}{
+		{
+			description: "valid",
+			cfg:         config.StreamAnalytics{Format: FormatJSON, Compression: CompressionGzip, Buffers: validBuffers, Sink: validSink},
+		},
+		{
+			description:   "unsupported_format",
+			cfg:           config.StreamAnalytics{Format: "avro", Compression: CompressionGzip, Buffers: validBuffers, Sink: validSink},
+			expectedError: `unsupported stream analytics format "avro"`,
+		},
+		{
+			description:   "unsupported_compression",
+			cfg:           config.StreamAnalytics{Format: FormatJSON, Compression: "zstd", Buffers: validBuffers, Sink: validSink},
+			expectedError: `unsupported stream analytics compression "zstd"`,
+		},
+		{
+			description: "invalid_buffer_size",
+			cfg: config.StreamAnalytics{Format: FormatJSON, Compression: CompressionNone, Sink: validSink,
+				Buffers: config.StreamAnalyticsBuffer{BufferSize: "big", EventCount: 10, Timeout: "1m"}},
+			expectedError: "invalid size: 'big'",
+		},
+		{
+			description: "invalid_event_count",
+			cfg: config.StreamAnalytics{Format: FormatJSON, Compression: CompressionNone, Sink: validSink,
+				Buffers: config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 0, Timeout: "1m"}},
+			expectedError: "stream analytics buffer count must be positive. Got 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			module, err := NewModule(http.DefaultClient, tc.cfg, clock.NewMock())
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			module.Shutdown()
+		})
+	}
+}
+
+func TestStreamLoggerFlushOnEventCount(t *testing.T) {
+	sink := &mockSink{}
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 2, Timeout: "1m"}, sink, clock.NewMock())
+	defer l.Shutdown()
+
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200, Bidder: "appnexus"})
+	l.LogCookieSyncObject(&analytics.CookieSyncObject{Status: 200})
+
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Len(t, sink.sent()[0], 2)
+}
+
+func TestStreamLoggerFlushOnBufferSize(t *testing.T) {
+	sink := &mockSink{}
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "10B", EventCount: 100, Timeout: "1m"}, sink, clock.NewMock())
+	defer l.Shutdown()
+
+	l.LogVideoObject(&analytics.VideoObject{Status: 200})
+
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamLoggerFlushOnTimeout(t *testing.T) {
+	sink := &mockSink{}
+	clk := clock.NewMock()
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 100, Timeout: "5m"}, sink, clk)
+	defer l.Shutdown()
+
+	l.LogAuctionObject(&analytics.AuctionObject{Status: 200})
+	clk.Add(time.Minute)
+	assert.Empty(t, sink.sent())
+
+	clk.Add(5 * time.Minute)
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamLoggerShutdown(t *testing.T) {
+	sink := &mockSink{err: errors.New("unavailable")}
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 100, Timeout: "1m"}, sink, clock.NewMock())
+
+	l.LogAmpObject(&analytics.AmpObject{Status: 200})
+	l.LogNotificationEventObject(&analytics.NotificationEvent{})
+	l.Shutdown()
+	l.Shutdown()
+
+	require.Len(t, sink.sent(), 1, "the buffer is flushed on shutdown")
+	assert.Len(t, sink.sent()[0], 2)
+	assert.True(t, sink.closed)
+
+	// events logged after shutdown are dropped instead of blocking the caller
+	l.LogAuctionObject(&analytics.AuctionObject{})
+	assert.Len(t, sink.sent(), 1)
+}
+
+// blockingSink holds every batch until released
+type blockingSink struct {
+	mockSink
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSink) Send(records [][]byte) error {
+	s.sending <- struct{}{}
+	<-s.release
+	return s.mockSink.Send(records)
+}
+
+func TestStreamLoggerDropsBatchesWhenSinkIsBehind(t *testing.T) {
+	sink := &blockingSink{sending: make(chan struct{}), release: make(chan struct{})}
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 1, Timeout: "1m"}, sink, clock.NewMock())
+
+	// the first batch holds the sender, the next ones fill the pending batches and the last one is dropped
+	l.LogNotificationEventObject(&analytics.NotificationEvent{})
+	<-sink.sending
+	for i := 0; i < maxPendingBatches+1; i++ {
+		l.LogNotificationEventObject(&analytics.NotificationEvent{})
+	}
+	require.Eventually(t, func() bool { return l.droppedBatches.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	go func() {
+		for range sink.sending {
+			sink.release <- struct{}{}
+		}
+	}()
+	sink.release <- struct{}{}
+	l.Shutdown()
+	close(sink.sending)
+
+	assert.Len(t, sink.sent(), maxPendingBatches+1)
+	assert.True(t, sink.closed)
+}
+
+func TestStreamLoggerIgnoresNilObjects(t *testing.T) {
+	sink := &mockSink{}
+	l := newTestStreamLogger(t, config.StreamAnalyticsBuffer{BufferSize: "1MB", EventCount: 1, Timeout: "1m"}, sink, clock.NewMock())
+
+	l.LogAuctionObject(nil)
+	l.LogAmpObject(nil)
+	l.LogVideoObject(nil)
+	l.LogSetUIDObject(nil)
+	l.LogCookieSyncObject(nil)
+	l.LogNotificationEventObject(nil)
+	l.Shutdown()
+
+	assert.Empty(t, sink.sent())
+}
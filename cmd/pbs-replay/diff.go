@@ -0,0 +1,141 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// recordDiff holds the differences between the baseline and candidate auctions of a record.
+type recordDiff struct {
+	ID     string    `json:"id"`
+	Imps   []impDiff `json:"imps,omitempty"`
+	Errors []string  `json:"errors,omitempty"`
+}
+
+// impDiff holds the winners of an imp whose winner, price or targeting keys differ.
+// A nil winner means the imp had no bid.
+type impDiff struct {
+	ImpID     string                     `json:"impId"`
+	Baseline  *winner                    `json:"baseline"`
+	Candidate *winner                    `json:"candidate"`
+	Targeting map[string]targetingChange `json:"targeting,omitempty"`
+}
+
+type targetingChange struct {
+	Baseline  string `json:"baseline"`
+	Candidate string `json:"candidate"`
+}
+
+type winner struct {
+	Seat      string            `json:"seat"`
+	BidID     string            `json:"bidId"`
+	Price     float64           `json:"price"`
+	targeting map[string]string `json:"-"`
+}
+
+// findWinners returns the winning bid of each imp. The winner is the bid holding the winner targeting keys
+// or, without targeting, the highest bid.
+func findWinners(response *openrtb2.BidResponse) map[string]*winner {
+	winners := make(map[string]*winner)
+	for _, seatBid := range response.SeatBid {
+		for _, bid := range seatBid.Bid {
+			candidate := &winner{Seat: seatBid.Seat, BidID: bid.ID, Price: bid.Price, targeting: bidTargeting(bid)}
+			if current, ok := winners[bid.ImpID]; !ok || beats(candidate, current) {
+				winners[bid.ImpID] = candidate
+			}
+		}
+	}
+	return winners
+}
+
+func beats(w *winner, other *winner) bool {
+	if isOverallWinner(w) != isOverallWinner(other) {
+		return isOverallWinner(w)
+	}
+	if w.Price != other.Price {
+		return w.Price > other.Price
+	}
+	// ties are broken deterministically so identical auctions never differ
+	if w.Seat != other.Seat {
+		return w.Seat < other.Seat
+	}
+	return w.BidID < other.BidID
+}
+
+// isOverallWinner tells if the bid has the targeting keys without bidder suffix, e.g. hb_pb
+func isOverallWinner(w *winner) bool {
+	for key := range w.targeting {
+		if strings.HasSuffix(key, string(openrtb_ext.PbKey)) {
+			return true
+		}
+	}
+	return false
+}
+
+func bidTargeting(bid openrtb2.Bid) map[string]string {
+	var ext openrtb_ext.ExtBid
+	if err := jsonutil.Unmarshal(bid.Ext, &ext); err != nil || ext.Prebid == nil {
+		return nil
+	}
+	return ext.Prebid.Targeting
+}
+
+// diffWinners compares the winners of every imp, sorted by imp id.
+func diffWinners(baseline map[string]*winner, candidate map[string]*winner) []impDiff {
+	impIDs := slices.Collect(maps.Keys(baseline))
+	for impID := range candidate {
+		if _, ok := baseline[impID]; !ok {
+			impIDs = append(impIDs, impID)
+		}
+	}
+	slices.Sort(impIDs)
+
+	var diffs []impDiff
+	for _, impID := range impIDs {
+		b, c := baseline[impID], candidate[impID]
+		targeting := diffTargeting(b, c)
+		if sameWinner(b, c) && len(targeting) == 0 {
+			continue
+		}
+		diffs = append(diffs, impDiff{ImpID: impID, Baseline: b, Candidate: c, Targeting: targeting})
+	}
+	return diffs
+}
+
+func sameWinner(b *winner, c *winner) bool {
+	if b == nil || c == nil {
+		return b == c
+	}
+	return b.Seat == c.Seat && b.BidID == c.BidID && b.Price == c.Price
+}
+
+func diffTargeting(b *winner, c *winner) map[string]targetingChange {
+	var baseline, candidate map[string]string
+	if b != nil {
+		baseline = b.targeting
+	}
+	if c != nil {
+		candidate = c.targeting
+	}
+
+	changes := make(map[string]targetingChange)
+	for key, value := range baseline {
+		if candidate[key] != value {
+			changes[key] = targetingChange{Baseline: value, Candidate: candidate[key]}
+		}
+	}
+	for key, value := range candidate {
+		if _, ok := baseline[key]; !ok {
+			changes[key] = targetingChange{Candidate: value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindWinners(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response *openrtb2.BidResponse
+		expected map[string]*winner
+	}{
+		{
+			name:     "no-bids",
+			response: &openrtb2.BidResponse{},
+			expected: map[string]*winner{},
+		},
+		{
+			name: "highest-bid-without-targeting",
+			response: &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "a1", ImpID: "imp-1", Price: 1}, {ID: "a2", ImpID: "imp-2", Price: 3}}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "r1", ImpID: "imp-1", Price: 2}}},
+			}},
+			expected: map[string]*winner{
+				"imp-1": {Seat: "rubicon", BidID: "r1", Price: 2},
+				"imp-2": {Seat: "appnexus", BidID: "a2", Price: 3},
+			},
+		},
+		{
+			name: "tie-broken-by-seat",
+			response: &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "r1", ImpID: "imp-1", Price: 1}}},
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "a1", ImpID: "imp-1", Price: 1}}},
+			}},
+			expected: map[string]*winner{
+				"imp-1": {Seat: "appnexus", BidID: "a1", Price: 1},
+			},
+		},
+		{
+			name: "winner-targeting-keys",
+			response: &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "a1", ImpID: "imp-1", Price: 2, Ext: json.RawMessage(`{"prebid":{"targeting":{"hb_pb_appnexus":"2.00"}}}`)}}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "r1", ImpID: "imp-1", Price: 1, Ext: json.RawMessage(`{"prebid":{"targeting":{"hb_pb":"1.00","hb_pb_rubicon":"1.00"}}}`)}}},
+			}},
+			expected: map[string]*winner{
+				"imp-1": {Seat: "rubicon", BidID: "r1", Price: 1, targeting: map[string]string{"hb_pb": "1.00", "hb_pb_rubicon": "1.00"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, findWinners(tc.response))
+		})
+	}
+}
+
+func TestDiffWinners(t *testing.T) {
+	appnexus := &winner{Seat: "appnexus", BidID: "a1", Price: 1, targeting: map[string]string{"hb_pb": "1.00", "hb_bidder": "appnexus"}}
+	rubicon := &winner{Seat: "rubicon", BidID: "r1", Price: 1, targeting: map[string]string{"hb_pb": "1.00", "hb_bidder": "rubicon"}}
+	appnexusNoTargeting := &winner{Seat: "appnexus", BidID: "a1", Price: 1}
+	appnexusLower := &winner{Seat: "appnexus", BidID: "a1", Price: 0.5}
+
+	testCases := []struct {
+		name      string
+		baseline  map[string]*winner
+		candidate map[string]*winner
+		expected  []impDiff
+	}{
+		{
+			name:      "same-winners",
+			baseline:  map[string]*winner{"imp-1": appnexus},
+			candidate: map[string]*winner{"imp-1": appnexus},
+			expected:  nil,
+		},
+		{
+			name:      "different-seat",
+			baseline:  map[string]*winner{"imp-1": appnexus},
+			candidate: map[string]*winner{"imp-1": rubicon},
+			expected: []impDiff{{
+				ImpID:     "imp-1",
+				Baseline:  appnexus,
+				Candidate: rubicon,
+				Targeting: map[string]targetingChange{"hb_bidder": {Baseline: "appnexus", Candidate: "rubicon"}},
+			}},
+		},
+		{
+			name:      "different-price",
+			baseline:  map[string]*winner{"imp-1": appnexusNoTargeting},
+			candidate: map[string]*winner{"imp-1": appnexusLower},
+			expected:  []impDiff{{ImpID: "imp-1", Baseline: appnexusNoTargeting, Candidate: appnexusLower}},
+		},
+		{
+			name:      "targeting-only",
+			baseline:  map[string]*winner{"imp-1": appnexus},
+			candidate: map[string]*winner{"imp-1": appnexusNoTargeting},
+			expected: []impDiff{{
+				ImpID:     "imp-1",
+				Baseline:  appnexus,
+				Candidate: appnexusNoTargeting,
+				Targeting: map[string]targetingChange{"hb_pb": {Baseline: "1.00"}, "hb_bidder": {Baseline: "appnexus"}},
+			}},
+		},
+		{
+			name:      "imps-sorted-and-missing-winners",
+			baseline:  map[string]*winner{"imp-2": appnexusNoTargeting},
+			candidate: map[string]*winner{"imp-1": appnexusNoTargeting},
+			expected: []impDiff{
+				{ImpID: "imp-1", Candidate: appnexusNoTargeting},
+				{ImpID: "imp-2", Baseline: appnexusNoTargeting},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, diffWinners(tc.baseline, tc.candidate))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Command pbs-replay replays captured auctions against two account configs and reports how the
+// auction results differ, so changes to floors, bid adjustments or rules-engine trees can be
+// evaluated before they are deployed.
+//
+// Each line of the input is a JSON object holding a captured BidRequest and the raw responses of
+// the bidders, keyed by bidder then by imp id:
+//
+//	{"id":"capture-1","request":{...},"responses":{"appnexus":{"imp-1":{...}}}}
+//
+// The responses are fed to the bidder adapters the same way stored bid responses are, so no bidder
+// is called over the network. Each request runs through the exchange once with the baseline account
+// config and once with the candidate one, both merged over the host account defaults. The records
+// whose winners, prices or targeting keys differ are written as JSON lines to the output.
+//
+// The host config, bidder infos and bidder params are loaded the same way as the server does, so the
+// command must run from the root of the repository or of a deployment.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+
+	"github.com/golang/glog"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	jsoniter.RegisterExtension(&jsonutil.RawMessageExtension{})
+}
+
+const configFileName = "pbs"
+const infoDirectory = "./static/bidder-info"
+const paramsDirectory = "./static/bidder-params"
+const categoriesDirectory = "./static/category-mapping"
+
+func main() {
+	inputPath := flag.String("input", "-", "JSONL file of captured requests and bidder responses, - for stdin")
+	outputPath := flag.String("output", "-", "file the differences are written to, - for stdout")
+	baselinePath := flag.String("baseline-account", "", "account config JSON of the baseline auctions, the host account defaults if empty")
+	candidatePath := flag.String("candidate-account", "", "account config JSON of the candidate auctions")
+	flag.Parse()
+
+	if *candidatePath == "" {
+		glog.Exit("The -candidate-account flag is required")
+	}
+
+	baseline, err := readAccount(*baselinePath)
+	if err != nil {
+		glog.Exitf("Unable to read the baseline account config: %v", err)
+	}
+	candidate, err := readAccount(*candidatePath)
+	if err != nil {
+		glog.Exitf("Unable to read the candidate account config: %v", err)
+	}
+
+	bidderInfoPath, err := filepath.Abs(infoDirectory)
+	if err != nil {
+		glog.Exitf("Unable to build configuration directory path: %v", err)
+	}
+	bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
+	if err != nil {
+		glog.Exitf("Unable to load bidder configurations: %v", err)
+	}
+
+	v := viper.New()
+	config.SetupViper(v, configFileName, bidderInfos)
+	cfg, err := config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	if err != nil {
+		glog.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
+	}
+
+	r, err := newReplayer(cfg, paramsDirectory, categoriesDirectory, baseline, candidate)
+	if err != nil {
+		glog.Exitf("Unable to set up the auctions: %v", err)
+	}
+	defer r.shutdown()
+
+	in := io.Reader(os.Stdin)
+	if *inputPath != "-" {
+		f, err := os.Open(*inputPath)
+		if err != nil {
+			glog.Exitf("Unable to open the input: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outputPath != "-" {
+		f, err := os.Create(*outputPath)
+		if err != nil {
+			glog.Exitf("Unable to create the output: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	summary, err := r.replayAll(context.Background(), in, out)
+	fmt.Fprintf(os.Stderr, "Replayed %d records: %d changed, %d failed\n", summary.Records, summary.Changed, summary.Failed)
+	if err != nil {
+		glog.Exitf("Replay stopped: %v", err)
+	}
+}
+
+func readAccount(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
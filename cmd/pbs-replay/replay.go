@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/modules"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
+	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// maxRecordSize bounds the size of an input line.
+const maxRecordSize = 64 * 1024 * 1024
+
+var errOffline = errors.New("pbs-replay: the bidder response was not captured")
+
+// record is a captured auction.
+type record struct {
+	ID      string          `json:"id"`
+	Request json.RawMessage `json:"request"`
+	// Responses holds the raw bidder responses by bidder then imp id
+	Responses map[string]map[string]json.RawMessage `json:"responses"`
+}
+
+// replaySummary counts the replayed records.
+type replaySummary struct {
+	Records int
+	Changed int
+	Failed  int
+}
+
+// replayer holds the auctions with the baseline and candidate account configs.
+type replayer struct {
+	cfg             *config.Configuration
+	exchange        exchange.Exchange
+	planBuilder     hooks.ExecutionPlanBuilder
+	metricsEngine   metrics.MetricsEngine
+	baseline        accountFetcher
+	candidate       accountFetcher
+	shutdownModules *modules.ShutdownModules
+}
+
+func newReplayer(cfg *config.Configuration, paramsDir string, categoriesDir string, baseline json.RawMessage, candidate json.RawMessage) (*replayer, error) {
+	// the bidders are never called, their captured responses are used instead
+	client := &http.Client{Transport: offlineTransport{}}
+	metricsEngine := &metricsConf.NilMetricsEngine{}
+	// only the rates sent in the requests are used, so the replays don't depend on the current rates
+	rateConverter := currency.NewRateConverter(client, "", time.Duration(0))
+
+	repo, _, shutdownModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduledeps.ModuleDeps{HTTPClient: client, RateConvertor: rateConverter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init hook modules: %v", err)
+	}
+
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(paramsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the bidder params validator: %v", err)
+	}
+
+	categoriesFetcher, err := file_fetcher.NewFileFetcher(categoriesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the categories fetcher: %v", err)
+	}
+
+	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(client, cfg, cfg.BidderInfos, metricsEngine)
+	if len(adaptersErrs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
+	}
+
+	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ads cert signer: %v", err)
+	}
+
+	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
+	disabledBidders := exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos)
+	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
+
+	vendorListFetcher := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, client, gdpr.VendorListURLMaker)
+	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, cfg.BidderInfos.ToGVLVendorIDMap(), vendorListFetcher)
+
+	// dynamic floors are not fetched, the floors of the requests and account configs are used
+	ex := exchange.NewExchange(adapters, noCache{}, cfg, requestValidator, nil, metricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConverter, categoriesFetcher, adsCertSigner, macros.NewStringIndexBasedReplacer(), nil, singleFormatAdapters, nil)
+
+	return &replayer{
+		cfg:             cfg,
+		exchange:        ex,
+		planBuilder:     hooks.NewExecutionPlanBuilder(cfg.Hooks, repo),
+		metricsEngine:   metricsEngine,
+		baseline:        accountFetcher(baseline),
+		candidate:       accountFetcher(candidate),
+		shutdownModules: shutdownModules,
+	}, nil
+}
+
+func (r *replayer) shutdown() {
+	r.shutdownModules.Shutdown()
+}
+
+// replayAll replays every record read from in and writes the differences to out, one JSON object per line.
+// Only the records with differences or errors are written.
+func (r *replayer) replayAll(ctx context.Context, in io.Reader, out io.Writer) (replaySummary, error) {
+	var summary replaySummary
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	encoder := json.NewEncoder(out)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		summary.Records++
+
+		var diff recordDiff
+		var rec record
+		if err := jsonutil.UnmarshalValid(scanner.Bytes(), &rec); err != nil {
+			diff = recordDiff{ID: "line " + strconv.Itoa(line), Errors: []string{fmt.Sprintf("invalid record: %v", err)}}
+		} else {
+			if rec.ID == "" {
+				rec.ID = "line " + strconv.Itoa(line)
+			}
+			diff = r.replay(ctx, rec)
+		}
+
+		if len(diff.Errors) > 0 {
+			summary.Failed++
+		} else if len(diff.Imps) > 0 {
+			summary.Changed++
+		} else {
+			continue
+		}
+		if err := encoder.Encode(diff); err != nil {
+			return summary, err
+		}
+	}
+	return summary, scanner.Err()
+}
+
+// replay runs the record's auction with both account configs and compares the results.
+func (r *replayer) replay(ctx context.Context, rec record) recordDiff {
+	diff := recordDiff{ID: rec.ID}
+
+	baseline, err := r.holdAuction(ctx, rec, r.baseline)
+	if err != nil {
+		diff.Errors = append(diff.Errors, fmt.Sprintf("baseline: %v", err))
+	}
+	candidate, err := r.holdAuction(ctx, rec, r.candidate)
+	if err != nil {
+		diff.Errors = append(diff.Errors, fmt.Sprintf("candidate: %v", err))
+	}
+	if len(diff.Errors) > 0 {
+		return diff
+	}
+
+	diff.Imps = diffWinners(findWinners(baseline), findWinners(candidate))
+	return diff
+}
+
+// holdAuction runs the auction of the record the same way the auction endpoint does once the request is parsed.
+func (r *replayer) holdAuction(ctx context.Context, rec record, accounts stored_requests.AccountFetcher) (*openrtb2.BidResponse, error) {
+	// the exchange modifies the request, each auction needs its own copy
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+	if err := jsonutil.UnmarshalValid(rec.Request, req.BidRequest); err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+	if err := ortb.SetDefaults(req, r.cfg.TmaxDefault); err != nil {
+		return nil, err
+	}
+
+	labels := makeLabels(req.BidRequest)
+	acct, errs := account.GetAccount(ctx, r.cfg, accounts, labels.PubID, r.metricsEngine)
+	if len(errs) > 0 {
+		return nil, errortypes.NewAggregateError("invalid account config", errs)
+	}
+
+	activityControl := privacy.NewActivityControl(&acct.Privacy)
+	hookExecutor := hookexecution.NewHookExecutor(r.planBuilder, hookexecution.EndpointAuction, r.metricsEngine)
+	hookExecutor.SetActivityControl(activityControl)
+	hookExecutor.SetAccount(acct)
+
+	storedBidResponses, bidderImpReplaceImpID := rec.storedResponses()
+	auctionResponse, err := r.exchange.HoldAuction(ctx, &exchange.AuctionRequest{
+		BidRequestWrapper:     req,
+		Account:               *acct,
+		UserSyncs:             usersync.NewCookie(),
+		RequestType:           labels.RType,
+		StartTime:             time.Now(),
+		LegacyLabels:          labels,
+		StoredBidResponses:    storedBidResponses,
+		BidderImpReplaceImpID: bidderImpReplaceImpID,
+		PubID:                 labels.PubID,
+		HookExecutor:          hookExecutor,
+		TCF2Config:            gdpr.NewTCF2Config(r.cfg.GDPR.TCF2, acct.GDPR),
+		Activities:            activityControl,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auctionResponse == nil || auctionResponse.BidResponse == nil {
+		return &openrtb2.BidResponse{}, nil
+	}
+	return auctionResponse.BidResponse, nil
+}
+
+// storedResponses returns the captured responses the way the exchange expects stored bid responses.
+// The imp ids of the bids are replaced by the ids of the imps they were captured for.
+func (rec record) storedResponses() (stored_responses.ImpBidderStoredResp, stored_responses.BidderImpReplaceImpID) {
+	impBidderResponses := make(stored_responses.ImpBidderStoredResp)
+	bidderImpReplaceImpID := make(stored_responses.BidderImpReplaceImpID, len(rec.Responses))
+	for bidder, responses := range rec.Responses {
+		bidderImpReplaceImpID[bidder] = make(map[string]bool, len(responses))
+		for impID, response := range responses {
+			if impBidderResponses[impID] == nil {
+				impBidderResponses[impID] = make(map[string]json.RawMessage)
+			}
+			impBidderResponses[impID][bidder] = response
+			bidderImpReplaceImpID[bidder][impID] = true
+		}
+	}
+	return impBidderResponses, bidderImpReplaceImpID
+}
+
+func makeLabels(req *openrtb2.BidRequest) metrics.Labels {
+	labels := metrics.Labels{
+		Source: metrics.DemandWeb,
+		RType:  metrics.ReqTypeORTB2Web,
+		PubID:  metrics.PublisherUnknown,
+	}
+
+	var pub *openrtb2.Publisher
+	switch {
+	case req.App != nil:
+		labels.Source = metrics.DemandApp
+		labels.RType = metrics.ReqTypeORTB2App
+		pub = req.App.Publisher
+	case req.DOOH != nil:
+		labels.Source = metrics.DemandDOOH
+		labels.RType = metrics.ReqTypeORTB2DOOH
+		pub = req.DOOH.Publisher
+	case req.Site != nil:
+		pub = req.Site.Publisher
+	}
+	if pub != nil && pub.ID != "" {
+		labels.PubID = pub.ID
+	}
+	return labels
+}
+
+// accountFetcher returns the same account config for every account, merged over the host account defaults.
+type accountFetcher json.RawMessage
+
+func (a accountFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(a) == 0 {
+		return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
+	}
+	if accountDefaultsJSON == nil {
+		return json.RawMessage(a), nil
+	}
+	completeJSON, err := jsonpatch.MergePatch(accountDefaultsJSON, a)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return completeJSON, nil
+}
+
+// offlineTransport fails every request so the replays never reach the bidders.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errOffline
+}
+
+// noCache doesn't cache the bids, so the cache targeting keys are never set.
+type noCache struct{}
+
+func (noCache) PutJson(ctx context.Context, values []pbc.Cacheable) ([]string, []error) {
+	return make([]string, len(values)), nil
+}
+
+func (noCache) GetExtCacheData() (string, string, string) {
+	return "", "", ""
+}
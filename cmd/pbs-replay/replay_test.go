@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRequest = `{
+	"id": "req-1",
+	"site": {"page": "https://example.com", "publisher": {"id": "pub-1"}},
+	"imp": [{
+		"id": "imp-1",
+		"banner": {"format": [{"w": 300, "h": 250}]},
+		"ext": {"prebid": {"bidder": {"boldwin": {"placementId": "p1"}}}}
+	}],
+	"ext": {"prebid": {"targeting": {}}}
+}`
+
+const testFloorsRequest = `{
+	"id": "req-1",
+	"site": {"page": "https://example.com", "publisher": {"id": "pub-1"}},
+	"imp": [{
+		"id": "imp-1",
+		"banner": {"format": [{"w": 300, "h": 250}]},
+		"ext": {"prebid": {"bidder": {"boldwin": {"placementId": "p1"}}}}
+	}],
+	"ext": {"prebid": {"targeting": {}, "floors": {
+		"enforcement": {"enforcepbs": true},
+		"data": {"modelgroups": [{"currency": "USD", "schema": {"fields": ["mediaType"]}, "values": {"banner": 2}}]}
+	}}}
+}`
+
+const testBoldwinResponse = `{
+	"id": "resp-1",
+	"cur": "USD",
+	"seatbid": [{"bid": [{"id": "bid-1", "impid": "captured-imp", "price": 1.5, "adm": "<div></div>", "crid": "cr-1", "w": 300, "h": 250, "mtype": 1}]}]
+}`
+
+func newTestReplayer(t *testing.T, baseline, candidate string) *replayer {
+	t.Helper()
+
+	bidderInfos, err := config.LoadBidderInfoFromDisk("../../static/bidder-info")
+	require.NoError(t, err)
+
+	v := viper.New()
+	config.SetupViper(v, "", bidderInfos)
+	v.Set("gdpr.default_value", "0")
+	v.Set("price_floors.enabled", true)
+	cfg, err := config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	require.NoError(t, err)
+
+	r, err := newReplayer(cfg, "../../static/bidder-params", "../../static/category-mapping", json.RawMessage(baseline), json.RawMessage(candidate))
+	require.NoError(t, err)
+	t.Cleanup(r.shutdown)
+	return r
+}
+
+func testRecord(request string) record {
+	return record{
+		ID:        "capture-1",
+		Request:   json.RawMessage(request),
+		Responses: map[string]map[string]json.RawMessage{"boldwin": {"imp-1": json.RawMessage(testBoldwinResponse)}},
+	}
+}
+
+func TestReplayBidAdjustments(t *testing.T) {
+	r := newTestReplayer(t, "", `{"bidadjustments": {"mediatype": {"banner": {"boldwin": {"*": [{"adjtype": "multiplier", "value": 0.5}]}}}}}`)
+
+	diff := r.replay(context.Background(), testRecord(testRequest))
+
+	require.Empty(t, diff.Errors)
+	require.Len(t, diff.Imps, 1)
+	assert.Equal(t, "imp-1", diff.Imps[0].ImpID, "the captured imp id should be replaced")
+	assert.Equal(t, &winner{Seat: "boldwin", BidID: "bid-1", Price: 1.5}, withoutTargeting(diff.Imps[0].Baseline))
+	assert.Equal(t, &winner{Seat: "boldwin", BidID: "bid-1", Price: 0.75}, withoutTargeting(diff.Imps[0].Candidate))
+	assert.Equal(t, targetingChange{Baseline: "1.50", Candidate: "0.70"}, diff.Imps[0].Targeting["hb_pb"])
+	assert.Equal(t, targetingChange{Baseline: "1.50", Candidate: "0.70"}, diff.Imps[0].Targeting["hb_pb_boldwin"])
+	assert.NotContains(t, diff.Imps[0].Targeting, "hb_bidder", "unchanged targeting keys should not be reported")
+}
+
+func TestReplayFloors(t *testing.T) {
+	r := newTestReplayer(t, `{"price_floors": {"enabled": false}}`, `{"price_floors": {"enabled": true}}`)
+
+	diff := r.replay(context.Background(), testRecord(testFloorsRequest))
+
+	require.Empty(t, diff.Errors)
+	require.Len(t, diff.Imps, 1)
+	assert.NotNil(t, diff.Imps[0].Baseline)
+	assert.Nil(t, diff.Imps[0].Candidate, "the bid below the floor should be rejected")
+	assert.Equal(t, targetingChange{Baseline: "boldwin"}, diff.Imps[0].Targeting["hb_bidder"])
+}
+
+func TestReplayAll(t *testing.T) {
+	r := newTestReplayer(t, "", `{"bidadjustments": {"mediatype": {"banner": {"boldwin": {"*": [{"adjtype": "multiplier", "value": 0.5}]}}}}}`)
+
+	changed, err := json.Marshal(testRecord(testRequest))
+	require.NoError(t, err)
+	unchanged, err := json.Marshal(record{ID: "no-bids", Request: json.RawMessage(testRequest)})
+	require.NoError(t, err)
+
+	input := strings.Join([]string{string(changed), "", string(unchanged), `{"id": `}, "\n")
+	var output bytes.Buffer
+
+	summary, err := r.replayAll(context.Background(), strings.NewReader(input), &output)
+
+	require.NoError(t, err)
+	assert.Equal(t, replaySummary{Records: 3, Changed: 1, Failed: 1}, summary)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var diff recordDiff
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &diff))
+	assert.Equal(t, "capture-1", diff.ID)
+	assert.Len(t, diff.Imps, 1)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &diff))
+	assert.Equal(t, "line 4", diff.ID)
+	assert.Len(t, diff.Errors, 1)
+}
+
+func TestReplayInvalidRequest(t *testing.T) {
+	r := newTestReplayer(t, "", "{}")
+
+	diff := r.replay(context.Background(), record{ID: "capture-1", Request: json.RawMessage(`{"imp": "invalid"}`)})
+
+	assert.Len(t, diff.Errors, 2)
+	assert.Empty(t, diff.Imps)
+}
+
+func TestStoredResponses(t *testing.T) {
+	rec := record{Responses: map[string]map[string]json.RawMessage{
+		"appnexus": {"imp-1": json.RawMessage(`{"a":1}`), "imp-2": json.RawMessage(`{"a":2}`)},
+		"boldwin":  {"imp-1": json.RawMessage(`{"b":1}`)},
+	}}
+
+	impBidderResponses, bidderImpReplaceImpID := rec.storedResponses()
+
+	assert.Equal(t, map[string]map[string]json.RawMessage{
+		"imp-1": {"appnexus": json.RawMessage(`{"a":1}`), "boldwin": json.RawMessage(`{"b":1}`)},
+		"imp-2": {"appnexus": json.RawMessage(`{"a":2}`)},
+	}, map[string]map[string]json.RawMessage(impBidderResponses))
+	assert.Equal(t, map[string]map[string]bool{
+		"appnexus": {"imp-1": true, "imp-2": true},
+		"boldwin":  {"imp-1": true},
+	}, map[string]map[string]bool(bidderImpReplaceImpID))
+}
+
+func TestAccountFetcher(t *testing.T) {
+	testCases := []struct {
+		name         string
+		account      string
+		defaults     string
+		expectedJSON string
+		expectErr    bool
+	}{
+		{
+			name:         "merged-over-defaults",
+			account:      `{"price_floors": {"enabled": true}}`,
+			defaults:     `{"price_floors": {"enabled": false, "enforce_floors_rate": 100}, "debug_allow": true}`,
+			expectedJSON: `{"price_floors": {"enabled": true, "enforce_floors_rate": 100}, "debug_allow": true}`,
+		},
+		{
+			name:         "no-defaults",
+			account:      `{"debug_allow": true}`,
+			expectedJSON: `{"debug_allow": true}`,
+		},
+		{
+			name:      "no-account",
+			defaults:  `{"debug_allow": true}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var defaults json.RawMessage
+			if tc.defaults != "" {
+				defaults = json.RawMessage(tc.defaults)
+			}
+
+			accountJSON, errs := accountFetcher(tc.account).FetchAccount(context.Background(), defaults, "pub-1")
+
+			if tc.expectErr {
+				assert.Len(t, errs, 1)
+				return
+			}
+			assert.Empty(t, errs)
+			assert.JSONEq(t, tc.expectedJSON, string(accountJSON))
+		})
+	}
+}
+
+func withoutTargeting(w *winner) *winner {
+	if w == nil {
+		return nil
+	}
+	return &winner{Seat: w.Seat, BidID: w.BidID, Price: w.Price}
+}
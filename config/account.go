@@ -9,6 +9,7 @@ import (
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
 // ChannelType enumerates the values of integrations Prebid Server can configure for an account
@@ -37,6 +38,7 @@ type Account struct {
 	TruncateTargetAttribute *int                                        `mapstructure:"truncate_target_attr" json:"truncate_target_attr"`
 	AlternateBidderCodes    *openrtb_ext.ExtAlternateBidderCodes        `mapstructure:"alternatebiddercodes" json:"alternatebiddercodes"`
 	Hooks                   AccountHooks                                `mapstructure:"hooks" json:"hooks"`
+	Analytics               AccountAnalytics                            `mapstructure:"analytics" json:"analytics"`
 	PriceFloors             AccountPriceFloors                          `mapstructure:"price_floors" json:"price_floors"`
 	Validations             Validations                                 `mapstructure:"validations" json:"validations"`
 	DefaultBidLimit         int                                         `mapstructure:"default_bid_limit" json:"default_bid_limit"`
@@ -51,6 +53,15 @@ type CookieSync struct {
 	DefaultLimit    *int  `mapstructure:"default_limit" json:"default_limit"`
 	MaxLimit        *int  `mapstructure:"max_limit" json:"max_limit"`
 	DefaultCoopSync *bool `mapstructure:"default_coop_sync" json:"default_coop_sync"`
+	// Auction configures the user syncs returned by the auction endpoint for the requested bidders without a UID
+	Auction AuctionUserSync `mapstructure:"auction" json:"auction"`
+}
+
+// AuctionUserSync represents the account-level settings of the user syncs returned by the auction endpoint.
+type AuctionUserSync struct {
+	Enabled      bool `mapstructure:"enabled" json:"enabled"`
+	DefaultLimit *int `mapstructure:"default_limit" json:"default_limit"`
+	MaxLimit     *int `mapstructure:"max_limit" json:"max_limit"`
 }
 
 // AccountCCPA represents account-specific CCPA configuration
@@ -336,12 +347,64 @@ func (m AccountModules) ModuleConfig(id string) (json.RawMessage, error) {
 	return m[vendor][module], nil
 }
 
+// AccountAnalytics represents account-specific analytics modules configuration
+type AccountAnalytics struct {
+	Modules AccountAnalyticsModules `mapstructure:"modules" json:"modules"`
+}
+
+// AccountAnalyticsModules mapping provides account-level analytics module configuration
+// format: map[module_name]json.RawMessage
+//
+// Every module config accepts the keys of AccountAnalyticsModule, next to the module specific settings.
+// The whole config of a module is handed to it as the AccountConfig of the analytics objects it logs.
+type AccountAnalyticsModules map[string]json.RawMessage
+
+// AccountAnalyticsModule is the account-level config shared by all the analytics modules
+type AccountAnalyticsModule struct {
+	// Enabled turns the module on or off for the account, the module follows the host config when unset
+	Enabled *bool `json:"enabled,omitempty"`
+	// SamplingRate is the share of the transactions logged to the module, in the range [0, 1]. All are logged when unset.
+	SamplingRate *float64 `json:"sampling_rate,omitempty"`
+}
+
+// ModuleConfig returns the account-level config of the analytics module, the zero value if the account doesn't configure it.
+func (m AccountAnalyticsModules) ModuleConfig(name string) (AccountAnalyticsModule, error) {
+	var cfg AccountAnalyticsModule
+	data, ok := m[name]
+	if !ok || len(data) == 0 {
+		return cfg, nil
+	}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return AccountAnalyticsModule{}, fmt.Errorf("invalid config of the analytics module %s: %v", name, err)
+	}
+	if cfg.SamplingRate != nil && (*cfg.SamplingRate < 0 || *cfg.SamplingRate > 1) {
+		return AccountAnalyticsModule{}, fmt.Errorf("the sampling_rate of the analytics module %s must be in the range [0, 1]. Got %v", name, *cfg.SamplingRate)
+	}
+	return cfg, nil
+}
+
+func (a *AccountAnalytics) validate(errs []error) []error {
+	for name := range a.Modules {
+		if _, err := a.Modules.ModuleConfig(name); err != nil {
+			errs = append(errs, fmt.Errorf("account_defaults.analytics.modules: %v", err))
+		}
+	}
+	return errs
+}
+
 type AccountPrivacy struct {
 	AllowActivities *AllowActivities `mapstructure:"allowactivities" json:"allowactivities"`
 	DSA             *AccountDSA      `mapstructure:"dsa" json:"dsa"`
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	USNat           AccountUSNat     `mapstructure:"usnat" json:"usnat"`
+}
+
+// AccountUSNat represents the enforcement of the GPP US National and state sections
+type AccountUSNat struct {
+	Enabled  bool   `mapstructure:"enabled" json:"enabled"`
+	SkipSIDs []int8 `mapstructure:"skip_sids" json:"skip_sids"`
 }
 
 type PrivacySandbox struct {
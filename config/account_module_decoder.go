@@ -59,3 +59,29 @@ func convertToRawMessageMap(input map[string]interface{}) (map[string]map[string
 	}
 	return result, nil
 }
+
+// AccountAnalyticsModulesHookFunc returns a mapstructure.DecodeHookFuncType that converts
+// a map[string]interface{} to a map[string]json.RawMessage for the AccountAnalyticsModules type,
+// so the account-level analytics modules config keeps its module specific settings.
+func AccountAnalyticsModulesHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.Map {
+			return data, nil
+		}
+
+		if t != reflect.TypeOf(AccountAnalyticsModules{}) {
+			return data, nil
+		}
+
+		input := data.(map[string]interface{})
+		result := make(map[string]json.RawMessage, len(input))
+		for name, value := range input {
+			rawBytes, err := jsonutil.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the account analytics module %s: %w", name, err)
+			}
+			result[name] = rawBytes
+		}
+		return result, nil
+	}
+}
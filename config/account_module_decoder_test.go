@@ -159,6 +159,31 @@ func TestAccountModulesHookFunc(t *testing.T) {
 	}
 }
 
+func TestAccountAnalyticsModulesHookFunc(t *testing.T) {
+	hookFunc := AccountAnalyticsModulesHookFunc()
+	accountAnalyticsModulesType := reflect.TypeOf(AccountAnalyticsModules{})
+	mapStringInterface := reflect.TypeOf(map[string]interface{}{})
+
+	data, err := hookFunc(mapStringInterface, accountAnalyticsModulesType, map[string]interface{}{
+		"pubstack": map[string]interface{}{"enabled": false},
+		"agma":     map[string]interface{}{"sampling_rate": 0.1, "code": "agma-code"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"pubstack": json.RawMessage(`{"enabled":false}`),
+		"agma":     json.RawMessage(`{"code":"agma-code","sampling_rate":0.1}`),
+	}, data)
+
+	other := map[string]interface{}{"key": "value"}
+	data, err = hookFunc(mapStringInterface, reflect.TypeOf(AccountModules{}), other)
+	assert.NoError(t, err)
+	assert.Equal(t, other, data, "the other types should be left as is")
+
+	data, err = hookFunc(reflect.TypeOf(""), accountAnalyticsModulesType, "value")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", data, "the non map values should be left as is")
+}
+
 func TestConvertToRawMessageMap(t *testing.T) {
 	tests := []struct {
 		name           string
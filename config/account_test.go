@@ -787,6 +787,90 @@ func TestModulesGetConfig(t *testing.T) {
 	}
 }
 
+func TestAccountAnalyticsModulesModuleConfig(t *testing.T) {
+	modules := AccountAnalyticsModules{
+		"disabled": json.RawMessage(`{"enabled":false}`),
+		"sampled":  json.RawMessage(`{"sampling_rate":0.25,"scopeid":"scope"}`),
+		"empty":    json.RawMessage(`{}`),
+		"invalid":  json.RawMessage(`{"enabled":"yes"}`),
+		"too_high": json.RawMessage(`{"sampling_rate":1.5}`),
+	}
+	falseValue, rate := false, 0.25
+
+	testCases := []struct {
+		description    string
+		givenName      string
+		givenModules   AccountAnalyticsModules
+		expectedConfig AccountAnalyticsModule
+		expectedError  string
+	}{
+		{
+			description:    "disabled",
+			givenName:      "disabled",
+			givenModules:   modules,
+			expectedConfig: AccountAnalyticsModule{Enabled: &falseValue},
+		},
+		{
+			description:    "sampled-with-module-specific-settings",
+			givenName:      "sampled",
+			givenModules:   modules,
+			expectedConfig: AccountAnalyticsModule{SamplingRate: &rate},
+		},
+		{
+			description:    "empty",
+			givenName:      "empty",
+			givenModules:   modules,
+			expectedConfig: AccountAnalyticsModule{},
+		},
+		{
+			description:    "not-configured",
+			givenName:      "other",
+			givenModules:   modules,
+			expectedConfig: AccountAnalyticsModule{},
+		},
+		{
+			description:    "no-modules",
+			givenName:      "other",
+			givenModules:   nil,
+			expectedConfig: AccountAnalyticsModule{},
+		},
+		{
+			description:   "invalid-json",
+			givenName:     "invalid",
+			givenModules:  modules,
+			expectedError: "invalid config of the analytics module invalid",
+		},
+		{
+			description:   "sampling-rate-out-of-range",
+			givenName:     "too_high",
+			givenModules:  modules,
+			expectedError: "the sampling_rate of the analytics module too_high must be in the range [0, 1]. Got 1.5",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			config, err := test.givenModules.ModuleConfig(test.givenName)
+			assert.Equal(t, test.expectedConfig, config)
+			if len(test.expectedError) > 0 {
+				assert.ErrorContains(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAccountAnalyticsValidate(t *testing.T) {
+	valid := AccountAnalytics{Modules: AccountAnalyticsModules{"pubstack": json.RawMessage(`{"enabled":true,"sampling_rate":1}`)}}
+	assert.Empty(t, valid.validate(nil))
+
+	invalid := AccountAnalytics{Modules: AccountAnalyticsModules{"pubstack": json.RawMessage(`{"sampling_rate":-1}`)}}
+	assert.Equal(t, []error{
+		errors.New("account_defaults.analytics.modules: the sampling_rate of the analytics module pubstack must be in the range [0, 1]. Got -1"),
+	}, invalid.validate(nil))
+}
+
 func TestAccountPriceFloorsValidate(t *testing.T) {
 	tests := []struct {
 		description string
@@ -9,6 +9,7 @@ type AllowActivities struct {
 	TransmitPreciseGeo       Activity `mapstructure:"transmitPreciseGeo" json:"transmitPreciseGeo"`
 	TransmitUniqueRequestIds Activity `mapstructure:"transmitUniqueRequestIds" json:"transmitUniqueRequestIds"`
 	TransmitTids             Activity `mapstructure:"transmitTid" json:"transmitTid"`
+	TransmitEIDs             Activity `mapstructure:"transmitEids" json:"transmitEids"`
 }
 
 type Activity struct {
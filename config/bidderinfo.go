@@ -3,7 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
-	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -46,6 +46,9 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+
+	// needed for the generic ORTB adapter
+	GenericORTB *GenericORTBInfo `yaml:"genericOrtb" mapstructure:"genericOrtb"`
 }
 
 type aliasNillableFields struct {
@@ -102,6 +105,24 @@ type OpenRTBInfo struct {
 	MultiformatSupported *bool  `yaml:"multiformat-supported" mapstructure:"multiformat-supported"`
 }
 
+// GenericORTBInfo declares how the generic ORTB adapter talks to a bidder, so a bidder speaking plain
+// OpenRTB 2.x can be onboarded as an alias of the generic ORTB adapter without code.
+type GenericORTBInfo struct {
+	// EndpointMacros maps endpoint template macros, e.g. PublisherID, to the bidder params holding their
+	// values. Imps resolving to different endpoints are sent in separate requests.
+	EndpointMacros map[string]string `yaml:"endpointMacros" mapstructure:"endpointMacros"`
+	// ImpParams maps bidder params to the dot separated imp paths they are copied to, e.g. tagid.
+	ImpParams map[string]string `yaml:"impParams" mapstructure:"impParams"`
+	// MediaType selects how the bid media type is detected: "mtype" from bid.mtype, "imp" from the
+	// media type of the imp or, if empty, bid.mtype with a fallback to the imp.
+	MediaType string `yaml:"mediaType" mapstructure:"mediaType"`
+	// Currency is the only currency the bidder supports. Floors are converted to it and it is assumed
+	// for responses without currency.
+	Currency string `yaml:"currency" mapstructure:"currency"`
+	// Headers are added to every request, e.g. to authenticate to the bidder.
+	Headers map[string]string `yaml:"headers" mapstructure:"headers"`
+}
+
 // Syncer specifies the user sync settings for a bidder. This struct is shared by the account config,
 // so it needs to have both yaml and mapstructure mappings.
 type Syncer struct {
@@ -234,7 +255,7 @@ const (
 func (r InfoReaderFromDisk) Read() (map[string][]byte, error) {
 	bidderConfigs, err := os.ReadDir(r.Path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	bidderInfos := make(map[string][]byte)
@@ -259,10 +280,17 @@ func LoadBidderInfoFromDisk(path string) (BidderInfos, error) {
 }
 
 func LoadBidderInfo(reader InfoReader) (BidderInfos, error) {
-	return processBidderInfos(reader, openrtb_ext.NormalizeBidderName)
+	return processBidderInfos(reader, openrtb_ext.NormalizeBidderName, openrtb_ext.SetAliasBidderName)
+}
+
+// ReloadBidderInfoFromDisk loads the bidder infos like LoadBidderInfoFromDisk without registering the
+// aliases again, so it is safe to call while the server is running. The files of bidders and aliases
+// which weren't loaded at startup are errors.
+func ReloadBidderInfoFromDisk(path string) (BidderInfos, error) {
+	return processBidderInfos(InfoReaderFromDisk{Path: path}, openrtb_ext.NormalizeBidderName, nil)
 }
 
-func processBidderInfos(reader InfoReader, normalizeBidderName openrtb_ext.BidderNameNormalizer) (BidderInfos, error) {
+func processBidderInfos(reader InfoReader, normalizeBidderName openrtb_ext.BidderNameNormalizer, setAliasBidderName func(string, openrtb_ext.BidderName) error) (BidderInfos, error) {
 	bidderConfigs, err := reader.Read()
 	if err != nil {
 		return nil, fmt.Errorf("error loading bidders data")
@@ -287,8 +315,10 @@ func processBidderInfos(reader InfoReader, normalizeBidderName openrtb_ext.Bidde
 				}
 
 				//required for CoreBidderNames function to also return aliasBiddernames
-				if err := openrtb_ext.SetAliasBidderName(bidderName[0], openrtb_ext.BidderName(info.AliasOf)); err != nil {
-					return nil, err
+				if setAliasBidderName != nil {
+					if err := setAliasBidderName(bidderName[0], openrtb_ext.BidderName(info.AliasOf)); err != nil {
+						return nil, err
+					}
 				}
 
 				normalizedBidderName, bidderNameExists := normalizeBidderName(bidderName[0])
@@ -340,6 +370,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.ExtraAdapterInfo == "" {
 			aliasBidderInfo.ExtraAdapterInfo = parentBidderInfo.ExtraAdapterInfo
 		}
+		if aliasBidderInfo.GenericORTB == nil {
+			aliasBidderInfo.GenericORTB = parentBidderInfo.GenericORTB
+		}
 		if aliasBidderInfo.GVLVendorID == 0 {
 			aliasBidderInfo.GVLVendorID = parentBidderInfo.GVLVendorID
 		}
@@ -672,6 +705,7 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		mergedBidderInfo.GenericORTB = configBidderInfo.bidderInfo.GenericORTB.Override(fsBidderInfo.GenericORTB)
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
@@ -684,6 +718,40 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 	return mergedBidderInfos, nil
 }
 
+// Override returns a new GenericORTBInfo object where values in the original are replaced by non-empty
+// values in the override. Maps are merged so hosts can add secrets, e.g. auth headers, without repeating
+// the rest of the bidder info. No changes are made to the original or override GenericORTBInfo.
+func (g *GenericORTBInfo) Override(original *GenericORTBInfo) *GenericORTBInfo {
+	if g == nil {
+		return original
+	}
+	if original == nil {
+		return g
+	}
+
+	merged := *original
+	merged.EndpointMacros = mergeStringMaps(original.EndpointMacros, g.EndpointMacros)
+	merged.ImpParams = mergeStringMaps(original.ImpParams, g.ImpParams)
+	merged.Headers = mergeStringMaps(original.Headers, g.Headers)
+	if g.MediaType != "" {
+		merged.MediaType = g.MediaType
+	}
+	if g.Currency != "" {
+		merged.Currency = g.Currency
+	}
+	return &merged
+}
+
+func mergeStringMaps(original, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return original
+	}
+	merged := make(map[string]string, len(original)+len(override))
+	maps.Copy(merged, original)
+	maps.Copy(merged, override)
+	return merged
+}
+
 // Override returns a new Syncer object where values in the original are replaced by non-empty/non-default
 // values in the override, except for the Supports field which may not be overridden. No changes are made
 // to the original or override Syncer.
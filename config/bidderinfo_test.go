@@ -122,6 +122,32 @@ func TestLoadBidderInfoFromDisk(t *testing.T) {
 	assert.Equal(t, expected, infos)
 }
 
+func TestReloadBidderInfoFromDisk(t *testing.T) {
+	expected, err := LoadBidderInfoFromDisk(testInfoFilesPathValid)
+	require.NoError(t, err)
+
+	infos, err := ReloadBidderInfoFromDisk(testInfoFilesPathValid)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, infos)
+
+	_, err = ReloadBidderInfoFromDisk("./test/unknown-path")
+	assert.EqualError(t, err, "error loading bidders data")
+}
+
+func TestReloadBidderInfoUnknownAlias(t *testing.T) {
+	reader := StubInfoReader{mockBidderInfos: map[string][]byte{
+		"appnexus.yaml":        []byte("endpoint: https://appnexus.com"),
+		"reloadedAlias99.yaml": []byte("aliasOf: appnexus"),
+	}}
+
+	_, err := processBidderInfos(reader, openrtb_ext.NormalizeBidderName, nil)
+
+	assert.EqualError(t, err, "error parsing config for an alias reloadedAlias99.yaml: unknown bidder")
+	_, registered := openrtb_ext.NormalizeBidderName("reloadedAlias99")
+	assert.False(t, registered, "aliases shouldn't be registered on reload")
+}
+
 func TestProcessBidderInfo(t *testing.T) {
 	falseValue := false
 
@@ -281,7 +307,7 @@ func TestProcessBidderInfo(t *testing.T) {
 
 	for _, test := range testCases {
 		reader := StubInfoReader{test.bidderInfos}
-		bidderInfos, err := processBidderInfos(reader, mockNormalizeBidderName)
+		bidderInfos, err := processBidderInfos(reader, mockNormalizeBidderName, openrtb_ext.SetAliasBidderName)
 		if test.expectError != "" {
 			assert.ErrorContains(t, err, test.expectError, "")
 		} else {
@@ -316,7 +342,11 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			},
 		},
 		ExtraAdapterInfo: "extra-info",
-		GVLVendorID:      42,
+		GenericORTB: &GenericORTBInfo{
+			EndpointMacros: map[string]string{"PublisherID": "publisherId"},
+			MediaType:      "mtype",
+		},
+		GVLVendorID: 42,
 		Maintainer: &MaintainerInfo{
 			Email: "some-email@domain.com",
 		},
@@ -364,7 +394,10 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			},
 		},
 		ExtraAdapterInfo: "alias-extra-info",
-		GVLVendorID:      43,
+		GenericORTB: &GenericORTBInfo{
+			ImpParams: map[string]string{"placementId": "tagid"},
+		},
+		GVLVendorID: 43,
 		Maintainer: &MaintainerInfo{
 			Email: "alias-email@domain.com",
 		},
@@ -1487,6 +1520,62 @@ func TestSyncerOverride(t *testing.T) {
 	}
 }
 
+func TestGenericORTBOverride(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenOriginal *GenericORTBInfo
+		givenOverride *GenericORTBInfo
+		expected      *GenericORTBInfo
+	}{
+		{
+			description:   "Nil",
+			givenOriginal: nil,
+			givenOverride: nil,
+			expected:      nil,
+		},
+		{
+			description:   "Original Only",
+			givenOriginal: &GenericORTBInfo{Currency: "EUR"},
+			givenOverride: nil,
+			expected:      &GenericORTBInfo{Currency: "EUR"},
+		},
+		{
+			description:   "Override Only",
+			givenOriginal: nil,
+			givenOverride: &GenericORTBInfo{Currency: "EUR"},
+			expected:      &GenericORTBInfo{Currency: "EUR"},
+		},
+		{
+			description:   "Override Values",
+			givenOriginal: &GenericORTBInfo{MediaType: "imp", Currency: "EUR"},
+			givenOverride: &GenericORTBInfo{MediaType: "mtype", Currency: "GBP"},
+			expected:      &GenericORTBInfo{MediaType: "mtype", Currency: "GBP"},
+		},
+		{
+			description: "Merge Maps",
+			givenOriginal: &GenericORTBInfo{
+				EndpointMacros: map[string]string{"Host": "region"},
+				ImpParams:      map[string]string{"placementId": "tagid", "zone": "ext.zone"},
+				Headers:        map[string]string{"X-Partner": "prebid"},
+			},
+			givenOverride: &GenericORTBInfo{
+				ImpParams: map[string]string{"zone": "ext.zoneId"},
+				Headers:   map[string]string{"Authorization": "secret"},
+			},
+			expected: &GenericORTBInfo{
+				EndpointMacros: map[string]string{"Host": "region"},
+				ImpParams:      map[string]string{"placementId": "tagid", "zone": "ext.zoneId"},
+				Headers:        map[string]string{"X-Partner": "prebid", "Authorization": "secret"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		result := test.givenOverride.Override(test.givenOriginal)
+		assert.Equal(t, test.expected, result, test.description)
+	}
+}
+
 func TestSyncerEndpointOverride(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -1873,6 +1962,18 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override GenericORTB",
+			givenFsBidderInfos:     BidderInfos{"a": {GenericORTB: &GenericORTBInfo{Currency: "EUR"}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {GenericORTB: &GenericORTBInfo{Currency: "EUR"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override GenericORTB",
+			givenFsBidderInfos:     BidderInfos{"a": {GenericORTB: &GenericORTBInfo{Currency: "EUR"}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{GenericORTB: &GenericORTBInfo{Headers: map[string]string{"Authorization": "secret"}}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {GenericORTB: &GenericORTBInfo{Currency: "EUR", Headers: map[string]string{"Authorization": "secret"}}, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override AliasOf",
 			givenFsBidderInfos:     BidderInfos{"a": {AliasOf: "Alias1"}},
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/mitchellh/mapstructure"
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/errortypes"
@@ -100,10 +101,15 @@ type Configuration struct {
 	// BidderInfos supports adapter overrides in extra configs like pbs.json, pbs.yaml, etc.
 	// Refers to main.go `configFileName` constant
 	BidderInfos BidderInfos `mapstructure:"adapters"`
+	// BidderInfoReload configures the reload of the bidder info files while the server is running
+	BidderInfoReload BidderInfoReload `mapstructure:"bidder_info_reload"`
+	// hostBidderInfos holds the adapter overrides of the host config, applied again to reloaded bidder infos
+	hostBidderInfos nillableFieldBidderInfos
 	// Hooks provides a way to specify hook execution plan for specific endpoints and stages
 	Hooks       Hooks       `mapstructure:"hooks"`
 	Validations Validations `mapstructure:"validations"`
 	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	Tracing     Tracing     `mapstructure:"tracing"`
 }
 
 type Admin struct {
@@ -120,6 +126,29 @@ type PriceFloorFetcher struct {
 	Worker     int        `mapstructure:"worker"`
 	Capacity   int        `mapstructure:"capacity"`
 	MaxRetries int        `mapstructure:"max_retries"`
+	// LastKnownGood configures the fallback on the last floor data fetched successfully
+	LastKnownGood PriceFloorLastKnownGood `mapstructure:"last_known_good"`
+}
+
+// PriceFloorLastKnownGood configures the fallback on the last floor data fetched successfully from a URL, served when
+// the cached data expired and can't be fetched again, e.g. while the floors provider is down. The data is persisted
+// in the snapshot directory, if set, to be served right after a restart.
+type PriceFloorLastKnownGood struct {
+	Enabled bool `mapstructure:"enabled"`
+	// SnapshotDir is the directory of the snapshots of the floor data, it can be a volume shared by the instances
+	SnapshotDir string `mapstructure:"snapshot_dir"`
+	// MaxStalenessSec is the age after which the last-known-good floor data isn't served anymore
+	MaxStalenessSec int `mapstructure:"max_staleness_sec"`
+}
+
+func (cfg *PriceFloorLastKnownGood) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxStalenessSec <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.fetcher.last_known_good.max_staleness_sec must be > 0. Got %d", cfg.MaxStalenessSec))
+	}
+	return errs
 }
 
 const MIN_COOKIE_SIZE_BYTES = 500
@@ -143,6 +172,65 @@ type HTTPThrottle struct {
 	ShortQueueWaitThresholdMS int `mapstructure:"short_queue_wait_threshold_ms"`
 	// ThrottleWindow controls the speed that the throttling logic will react to changes in the health of the bidder.
 	ThrottleWindow int `mapstructure:"throttle_window"`
+	// Dimensions the health of a bidder is tracked by: account, channel, media_type and device_country. The health
+	// of a bidder is tracked as a whole if empty.
+	Dimensions []string `mapstructure:"dimensions"`
+	// DecayWindowSeconds is the time it takes for the unhealthy score of a bidder to halve when it's not checked,
+	// so bidders which are no longer requested recover. 0 disables the decay.
+	DecayWindowSeconds int `mapstructure:"decay_window_seconds"`
+	// CircuitBreaker stops requesting the bidders whose health is too low, rather than throttling them randomly.
+	CircuitBreaker HTTPThrottleCircuitBreaker `mapstructure:"circuit_breaker"`
+}
+
+// HTTPThrottleCircuitBreaker configures the bidder circuit breaker. A bidder whose unhealthy score reaches the
+// open threshold isn't requested for the open period. A single probe request is then allowed: the bidder is
+// requested again if it succeeds, or for another open period otherwise.
+type HTTPThrottleCircuitBreaker struct {
+	Enabled       bool    `mapstructure:"enabled"`
+	OpenThreshold float64 `mapstructure:"open_threshold"`
+	OpenSeconds   int     `mapstructure:"open_seconds"`
+}
+
+// The dimensions bidder health can be tracked by.
+const (
+	HealthDimensionAccount       = "account"
+	HealthDimensionChannel       = "channel"
+	HealthDimensionMediaType     = "media_type"
+	HealthDimensionDeviceCountry = "device_country"
+)
+
+// IsHealthTrackedPerDimension returns true if bidder health is tracked by dimension rather than as a whole, which
+// is required for the decay and the circuit breaker.
+func (cfg *HTTPThrottle) IsHealthTrackedPerDimension() bool {
+	return len(cfg.Dimensions) > 0 || cfg.DecayWindowSeconds > 0 || cfg.CircuitBreaker.Enabled
+}
+
+func (cfg *HTTPThrottle) validate(errs []error) []error {
+	seen := make(map[string]struct{}, len(cfg.Dimensions))
+	for _, dimension := range cfg.Dimensions {
+		switch dimension {
+		case HealthDimensionAccount, HealthDimensionChannel, HealthDimensionMediaType, HealthDimensionDeviceCountry:
+		default:
+			errs = append(errs, fmt.Errorf("http_client.throttle.dimensions contains an invalid dimension %s. Valid values are %s, %s, %s and %s", dimension, HealthDimensionAccount, HealthDimensionChannel, HealthDimensionMediaType, HealthDimensionDeviceCountry))
+			continue
+		}
+		if _, ok := seen[dimension]; ok {
+			errs = append(errs, fmt.Errorf("http_client.throttle.dimensions contains %s more than once", dimension))
+		}
+		seen[dimension] = struct{}{}
+	}
+	if cfg.DecayWindowSeconds < 0 {
+		errs = append(errs, fmt.Errorf("http_client.throttle.decay_window_seconds must be >= 0. Got %d", cfg.DecayWindowSeconds))
+	}
+	if cfg.CircuitBreaker.Enabled {
+		if cfg.CircuitBreaker.OpenThreshold <= 0 || cfg.CircuitBreaker.OpenThreshold > 1 {
+			errs = append(errs, fmt.Errorf("http_client.throttle.circuit_breaker.open_threshold must be > 0 and <= 1. Got %f", cfg.CircuitBreaker.OpenThreshold))
+		}
+		if cfg.CircuitBreaker.OpenSeconds <= 0 {
+			errs = append(errs, fmt.Errorf("http_client.throttle.circuit_breaker.open_seconds must be > 0. Got %d", cfg.CircuitBreaker.OpenSeconds))
+		}
+	}
+	return errs
 }
 
 func (cfg *Configuration) validate(v *viper.Viper) []error {
@@ -163,8 +251,11 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
+	errs = cfg.CacheURL.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = cfg.AccountDefaults.Analytics.validate(errs)
+	errs = cfg.PriceFloors.Fetcher.LastKnownGood.validate(errs)
 	if cfg.AccountDefaults.Disabled {
 		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -174,7 +265,13 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	}
 
 	errs = cfg.Experiment.validate(errs)
+	errs = cfg.Tracing.validate(errs)
 	errs = cfg.BidderInfos.validate(errs)
+	errs = cfg.BidderInfoReload.validate(errs)
+	errs = cfg.Client.Throttle.validate(errs)
+	errs = cfg.Analytics.Dispatch.validate(errs)
+	errs = cfg.UserSync.UIDStore.validate(errs)
+	errs = cfg.HostCookie.KeyRing.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
 
@@ -466,21 +563,131 @@ type LMT struct {
 }
 
 type Analytics struct {
-	File     FileLogs      `mapstructure:"file"`
-	Agma     AgmaAnalytics `mapstructure:"agma"`
-	Pubstack Pubstack      `mapstructure:"pubstack"`
+	File     FileLogs        `mapstructure:"file"`
+	Agma     AgmaAnalytics   `mapstructure:"agma"`
+	Pubstack Pubstack        `mapstructure:"pubstack"`
+	Stream   StreamAnalytics `mapstructure:"stream"`
+	// Modules is the config of the registered analytics modules without a dedicated block above,
+	// keyed by module name. A module is built when its config has "enabled": true.
+	Modules AnalyticsModules `mapstructure:"modules"`
+	// Dispatch configures how the analytics objects are handed to the modules
+	Dispatch AnalyticsDispatch `mapstructure:"dispatch"`
+}
+
+const (
+	AnalyticsDropOldest = "drop_oldest"
+	AnalyticsDropNewest = "drop_newest"
+	AnalyticsBlock      = "block"
+)
+
+// AnalyticsDispatch configures the queue each analytics module consumes the objects from, on its own goroutine.
+type AnalyticsDispatch struct {
+	// QueueSize is the number of objects queued per module. The default 0 calls the modules on the request goroutine.
+	QueueSize int `mapstructure:"queue_size"`
+	// DropPolicy applies when the queue of a module is full, either "drop_oldest" (the default), "drop_newest" or "block"
+	DropPolicy string `mapstructure:"drop_policy"`
+	// BlockTimeoutMs is how long the "block" policy waits for room in the queue before dropping the object
+	BlockTimeoutMs int `mapstructure:"block_timeout_ms"`
+}
+
+func (cfg *AnalyticsDispatch) validate(errs []error) []error {
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("analytics.dispatch.queue_size must be >= 0. Got %d", cfg.QueueSize))
+	}
+	switch cfg.DropPolicy {
+	case "", AnalyticsDropOldest, AnalyticsDropNewest:
+	case AnalyticsBlock:
+		if cfg.BlockTimeoutMs <= 0 {
+			errs = append(errs, fmt.Errorf("analytics.dispatch.block_timeout_ms must be > 0 for the %s policy. Got %d", AnalyticsBlock, cfg.BlockTimeoutMs))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("analytics.dispatch.drop_policy must be one of [%s, %s, %s]. Got %q", AnalyticsDropOldest, AnalyticsDropNewest, AnalyticsBlock, cfg.DropPolicy))
+	}
+	return errs
+}
+
+// AnalyticsModules mapping provides the host-level analytics module configuration
+// format: map[module_name]map[key]value
+type AnalyticsModules map[string]map[string]interface{}
+
+// BidderInfoReload configures how the bidder info files are reloaded without a restart.
+type BidderInfoReload struct {
+	// Enabled allows the bidder infos to be reloaded from the admin port and by polling the files.
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the directory of the bidder info files. It must be the one they are loaded from at startup.
+	Path string `mapstructure:"path"`
+	// PollIntervalSeconds is how often the files are checked for changes, 0 to reload only from the admin port.
+	PollIntervalSeconds int `mapstructure:"poll_interval_seconds"`
+}
+
+func (cfg *BidderInfoReload) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Path == "" {
+		errs = append(errs, errors.New("bidder_info_reload.path must be set when bidder_info_reload.enabled is true"))
+	}
+	if cfg.PollIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("bidder_info_reload.poll_interval_seconds must be >= 0. Got %d", cfg.PollIntervalSeconds))
+	}
+	return errs
 }
 
 type CurrencyConverter struct {
 	FetchURL             string `mapstructure:"fetch_url"`
 	FetchIntervalSeconds int    `mapstructure:"fetch_interval_seconds"`
 	StaleRatesSeconds    int    `mapstructure:"stale_rates_seconds"`
+	// Sources lists the rates sources by priority, the fetch_url is the only source when empty
+	Sources []CurrencyRatesSource `mapstructure:"sources"`
+	// SnapshotPath is the file the last fetched rates are persisted to for cold starts, disabled when empty
+	SnapshotPath string `mapstructure:"snapshot_path"`
+}
+
+const (
+	CurrencyRatesSourceHTTP   = "http"
+	CurrencyRatesSourceFile   = "file"
+	CurrencyRatesSourceStatic = "static"
+)
+
+// CurrencyRatesSource is a source of currency rates, the converter falls back to the next source when its rates are stale
+type CurrencyRatesSource struct {
+	Type              string                        `mapstructure:"type"`
+	URL               string                        `mapstructure:"url"`
+	Path              string                        `mapstructure:"path"`
+	Rates             map[string]map[string]float64 `mapstructure:"rates"`
+	StaleRatesSeconds int                           `mapstructure:"stale_rates_seconds"`
 }
 
 func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchIntervalSeconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_interval_seconds must be in the range [0, %d]. Got %d", 0xffff, cfg.FetchIntervalSeconds))
 	}
+	for i, source := range cfg.Sources {
+		errs = source.validate(errs, i)
+	}
+	return errs
+}
+
+func (cfg *CurrencyRatesSource) validate(errs []error, index int) []error {
+	switch cfg.Type {
+	case CurrencyRatesSourceHTTP:
+		if cfg.URL == "" {
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].url must be set for the http source", index))
+		}
+	case CurrencyRatesSourceFile:
+		if cfg.Path == "" {
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].path must be set for the file source", index))
+		}
+	case CurrencyRatesSourceStatic:
+		if len(cfg.Rates) == 0 {
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].rates must be set for the static source", index))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("currency_converter.sources[%d].type must be one of [%s, %s, %s]. Got %q", index, CurrencyRatesSourceHTTP, CurrencyRatesSourceFile, CurrencyRatesSourceStatic, cfg.Type))
+	}
+	if cfg.StaleRatesSeconds < 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.sources[%d].stale_rates_seconds must be >= 0. Got %d", index, cfg.StaleRatesSeconds))
+	}
 	return errs
 }
 
@@ -509,6 +716,55 @@ type AgmaAnalyticsAccount struct {
 	SiteAppId   string `mapstructure:"site_app_id"`
 }
 
+// StreamAnalytics configures the generic streaming analytics module, which serializes every analytics
+// object into a versioned schema and sends batches of them through a pluggable sink.
+type StreamAnalytics struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Format is the serialization of each event. Only "json" is supported.
+	Format string `mapstructure:"format"`
+	// Compression of each batch, either "none" or "gzip".
+	Compression string                `mapstructure:"compression"`
+	Buffers     StreamAnalyticsBuffer `mapstructure:"buffers"`
+	Sink        StreamAnalyticsSink   `mapstructure:"sink"`
+}
+
+type StreamAnalyticsBuffer struct {
+	BufferSize string `mapstructure:"size"`
+	EventCount int    `mapstructure:"count"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+type StreamAnalyticsSink struct {
+	// Type selects the sink: "http", "file" or "kafka".
+	Type  string                   `mapstructure:"type"`
+	HTTP  StreamAnalyticsHTTPSink  `mapstructure:"http"`
+	File  StreamAnalyticsFileSink  `mapstructure:"file"`
+	Kafka StreamAnalyticsKafkaSink `mapstructure:"kafka"`
+}
+
+type StreamAnalyticsHTTPSink struct {
+	Url     string            `mapstructure:"url"`
+	Timeout string            `mapstructure:"timeout"`
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+type StreamAnalyticsFileSink struct {
+	Path string `mapstructure:"path"`
+	// MaxSize is the size a file may reach before it is rotated, e.g. "100MB".
+	MaxSize string `mapstructure:"max_size"`
+	// MaxBackups is the number of rotated files kept next to the active one.
+	MaxBackups int `mapstructure:"max_backups"`
+}
+
+type StreamAnalyticsKafkaSink struct {
+	Brokers  []string `mapstructure:"brokers"`
+	Topic    string   `mapstructure:"topic"`
+	ClientID string   `mapstructure:"client_id"`
+	// Acks is the number of acknowledgments the leader must receive: 0, 1 or -1 for all in-sync replicas.
+	Acks    int    `mapstructure:"acks"`
+	Timeout string `mapstructure:"timeout"`
+}
+
 // FileLogs Corresponding config for FileLogger as a PBS Analytics Module
 type FileLogs struct {
 	Filename string `mapstructure:"filename"`
@@ -548,6 +804,8 @@ type HostCookie struct {
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
 	TTL int64 `mapstructure:"ttl_days"`
+	// KeyRing protects the uids cookie from being read or forged
+	KeyRing CookieKeyRing `mapstructure:"key_ring"`
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
@@ -647,6 +905,108 @@ type Cache struct {
 	ExpectedTimeMillis int `mapstructure:"expected_millis"`
 
 	DefaultTTLs DefaultTTLs `mapstructure:"default_ttl_seconds"`
+
+	// Retry configures the retries of the failed requests to Prebid Cache, within the auction timeout.
+	Retry CacheRetry `mapstructure:"retry"`
+	// Hedge configures the requests sent to a secondary Prebid Cache when the primary one is slow or fails.
+	Hedge CacheHedge `mapstructure:"hedge"`
+	// Backend selects where the cache entries are stored.
+	Backend CacheBackend `mapstructure:"backend"`
+}
+
+// CacheRetry configures the retries of the requests to Prebid Cache failing or returning a 5xx status.
+// The backoff before the nth retry is a random duration up to min(backoff_ms * 2^(n-1), max_backoff_ms),
+// and a retry is skipped if the backoff would exceed the time left for the request.
+type CacheRetry struct {
+	MaxRetries   int `mapstructure:"max_retries"`
+	BackoffMs    int `mapstructure:"backoff_ms"`
+	MaxBackoffMs int `mapstructure:"max_backoff_ms"`
+}
+
+// CacheHedge configures the hedged requests to a secondary Prebid Cache. The same entries are sent to the
+// secondary cache if the primary one hasn't responded after delay_ms or failed, the first success is used.
+//
+// The secondary cache must share the store of the primary one: the UUIDs it returns are served from the
+// external cache host (hb_cache_host, hb_cache_path and the cached VAST URLs) and by /vtrack like the
+// primary ones, so they must be readable from the primary cache.
+type CacheHedge struct {
+	// SecondaryURL is the base URL of the secondary cache, e.g. https://cache2.prebid.org. Hedging is disabled if empty.
+	SecondaryURL string `mapstructure:"secondary_url"`
+	DelayMs      int    `mapstructure:"delay_ms"`
+}
+
+const (
+	CacheBackendPrebidCache = "prebid_cache"
+	CacheBackendLocal       = "local"
+	CacheBackendRedis       = "redis"
+)
+
+// CacheBackend selects the store of the cache entries. With the local and redis backends, PBS stores the entries
+// itself and serves them from its GET /cache endpoint, so the cache host should be the PBS host.
+type CacheBackend struct {
+	// Type is one of prebid_cache, local or redis. Empty stands for prebid_cache.
+	Type string `mapstructure:"type"`
+	// TTLSeconds is the TTL of the entries cached without a TTL.
+	TTLSeconds int `mapstructure:"ttl_seconds"`
+	// MaxTTLSeconds caps the TTL of the entries.
+	MaxTTLSeconds int               `mapstructure:"max_ttl_seconds"`
+	Local         LocalCacheBackend `mapstructure:"local"`
+	Redis         RedisCache        `mapstructure:"redis"`
+}
+
+// LocalCacheBackend stores the cache entries in the memory of the PBS instance.
+// It only suits a single instance deployment, as the entries are only served by the instance which cached them.
+type LocalCacheBackend struct {
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+func (cfg *Cache) validate(errs []error) []error {
+	if cfg.Retry.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("cache.retry.max_retries must be >= 0. Got %d", cfg.Retry.MaxRetries))
+	}
+	if cfg.Retry.MaxRetries > 0 && cfg.Retry.BackoffMs <= 0 {
+		errs = append(errs, fmt.Errorf("cache.retry.backoff_ms must be > 0 when cache.retry.max_retries > 0. Got %d", cfg.Retry.BackoffMs))
+	}
+	if cfg.Retry.MaxBackoffMs < cfg.Retry.BackoffMs {
+		errs = append(errs, fmt.Errorf("cache.retry.max_backoff_ms must be >= cache.retry.backoff_ms. Got %d", cfg.Retry.MaxBackoffMs))
+	}
+	if cfg.Hedge.DelayMs < 0 {
+		errs = append(errs, fmt.Errorf("cache.hedge.delay_ms must be >= 0. Got %d", cfg.Hedge.DelayMs))
+	}
+	return cfg.Backend.validate(errs)
+}
+
+func (cfg *CacheBackend) validate(errs []error) []error {
+	switch cfg.Type {
+	case "", CacheBackendPrebidCache:
+		return errs
+	case CacheBackendLocal:
+		if cfg.Local.MaxEntries <= 0 {
+			errs = append(errs, fmt.Errorf("cache.backend.local.max_entries must be > 0. Got %d", cfg.Local.MaxEntries))
+		}
+	case CacheBackendRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, errors.New("cache.backend.redis.address must be set for the redis backend"))
+		}
+		if cfg.Redis.PoolSize <= 0 {
+			errs = append(errs, fmt.Errorf("cache.backend.redis.pool_size must be > 0. Got %d", cfg.Redis.PoolSize))
+		}
+		if cfg.Redis.DialTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("cache.backend.redis.dial_timeout_ms must be > 0. Got %d", cfg.Redis.DialTimeout))
+		}
+		if cfg.Redis.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("cache.backend.redis.timeout_ms must be > 0. Got %d", cfg.Redis.Timeout))
+		}
+	default:
+		return append(errs, fmt.Errorf("cache.backend.type must be one of [%s, %s, %s]. Got %q", CacheBackendPrebidCache, CacheBackendLocal, CacheBackendRedis, cfg.Type))
+	}
+	if cfg.TTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("cache.backend.ttl_seconds must be > 0. Got %d", cfg.TTLSeconds))
+	}
+	if cfg.MaxTTLSeconds < cfg.TTLSeconds {
+		errs = append(errs, fmt.Errorf("cache.backend.max_ttl_seconds must be >= cache.backend.ttl_seconds. Got %d", cfg.MaxTTLSeconds))
+	}
+	return errs
 }
 
 // Default TTLs to use to cache bids for different types of imps.
@@ -731,7 +1091,7 @@ func (cfg *TimeoutNotification) validate(errs []error) []error {
 // New uses viper to get our server configurations.
 func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName openrtb_ext.BidderNameNormalizer) (*Configuration, error) {
 	var c Configuration
-	if err := v.Unmarshal(&c, viper.DecodeHook(AccountModulesHookFunc())); err != nil {
+	if err := v.Unmarshal(&c, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(AccountModulesHookFunc(), AccountAnalyticsModulesHookFunc()))); err != nil {
 		return nil, fmt.Errorf("viper failed to unmarshal app config: %v", err)
 	}
 
@@ -829,6 +1189,7 @@ func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName openrtb_ex
 		return nil, err
 	}
 	c.BidderInfos = mergedBidderInfos
+	c.hostBidderInfos = configBidderInfosWithNillableFields
 
 	glog.Info("Logging the resolved configuration:")
 	logGeneral(reflect.ValueOf(c), "  \t")
@@ -869,6 +1230,19 @@ func setConfigBidderInfoNillableFields(v *viper.Viper, bidderInfos BidderInfos)
 	return infos, nil
 }
 
+// ResolveBidderInfos applies the adapter overrides of the host config to bidder infos reloaded from disk
+// and validates them, the same way New does at startup.
+func (cfg *Configuration) ResolveBidderInfos(fsBidderInfos BidderInfos) (BidderInfos, error) {
+	mergedBidderInfos, err := applyBidderInfoConfigOverrides(cfg.hostBidderInfos, fsBidderInfos, openrtb_ext.NormalizeBidderName)
+	if err != nil {
+		return nil, err
+	}
+	if errs := mergedBidderInfos.validate(nil); len(errs) > 0 {
+		return nil, errortypes.NewAggregateError("bidder info validation errors", errs)
+	}
+	return mergedBidderInfos, nil
+}
+
 // MarshalAccountDefaults compiles AccountDefaults into the JSON format used for merge patch
 func (cfg *Configuration) MarshalAccountDefaults() error {
 	var err error
@@ -937,6 +1311,24 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("cache.default_ttl_seconds.video", 0)
 	v.SetDefault("cache.default_ttl_seconds.native", 0)
 	v.SetDefault("cache.default_ttl_seconds.audio", 0)
+	v.SetDefault("cache.retry.max_retries", 0)
+	v.SetDefault("cache.retry.backoff_ms", 10)
+	v.SetDefault("cache.retry.max_backoff_ms", 100)
+	v.SetDefault("cache.hedge.secondary_url", "")
+	v.SetDefault("cache.hedge.delay_ms", 50)
+	v.SetDefault("cache.backend.type", CacheBackendPrebidCache)
+	v.SetDefault("cache.backend.ttl_seconds", 300)
+	v.SetDefault("cache.backend.max_ttl_seconds", 3600)
+	v.SetDefault("cache.backend.local.max_entries", 100000)
+	v.SetDefault("cache.backend.redis.address", "")
+	v.SetDefault("cache.backend.redis.password", "")
+	v.SetDefault("cache.backend.redis.database", 0)
+	v.SetDefault("cache.backend.redis.tls", false)
+	v.SetDefault("cache.backend.redis.namespace", "pbs")
+	v.SetDefault("cache.backend.redis.pool_size", 10)
+	v.SetDefault("cache.backend.redis.dial_timeout_ms", 100)
+	v.SetDefault("cache.backend.redis.timeout_ms", 50)
+	v.SetDefault("cache.backend.redis.retry_interval_ms", 5000)
 	v.SetDefault("external_cache.scheme", "")
 	v.SetDefault("external_cache.host", "")
 	v.SetDefault("external_cache.path", "")
@@ -950,6 +1342,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.key_ring.mode", CookieProtectionNone)
+	v.SetDefault("host_cookie.key_ring.accept_plaintext", true)
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
@@ -964,6 +1358,11 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("http_client.throttle.long_queue_wait_threshold_ms", 50)
 	v.SetDefault("http_client.throttle.short_queue_wait_threshold_ms", 10)
 	v.SetDefault("http_client.throttle.throttle_window", 1000)
+	v.SetDefault("http_client.throttle.dimensions", []string{})
+	v.SetDefault("http_client.throttle.decay_window_seconds", 0)
+	v.SetDefault("http_client.throttle.circuit_breaker.enabled", false)
+	v.SetDefault("http_client.throttle.circuit_breaker.open_threshold", 0.8)
+	v.SetDefault("http_client.throttle.circuit_breaker.open_seconds", 30)
 	v.SetDefault("http_client_cache.max_connections_per_host", 0) // unlimited
 	v.SetDefault("http_client_cache.max_idle_connections", 10)
 	v.SetDefault("http_client_cache.max_idle_connections_per_host", 2)
@@ -986,6 +1385,14 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.prometheus.namespace", "")
 	v.SetDefault("metrics.prometheus.subsystem", "")
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "prebid-server")
+	v.SetDefault("tracing.sampling_rate", 1.0)
+	v.SetDefault("tracing.otlp.endpoint", "")
+	v.SetDefault("tracing.otlp.timeout_ms", 1000)
+	v.SetDefault("tracing.otlp.batch_size", 512)
+	v.SetDefault("tracing.otlp.queue_size", 4096)
+	v.SetDefault("tracing.otlp.flush_interval_ms", 5000)
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
 	v.SetDefault("category_mapping.http.endpoint", "")
@@ -1020,6 +1427,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_requests.redis_cache.enabled", false)
+	v.SetDefault("stored_requests.redis_cache.address", "")
+	v.SetDefault("stored_requests.redis_cache.password", "")
+	v.SetDefault("stored_requests.redis_cache.database", 0)
+	v.SetDefault("stored_requests.redis_cache.tls", false)
+	v.SetDefault("stored_requests.redis_cache.namespace", "pbs")
+	v.SetDefault("stored_requests.redis_cache.ttl_seconds", 3600)
+	v.SetDefault("stored_requests.redis_cache.pool_size", 10)
+	v.SetDefault("stored_requests.redis_cache.dial_timeout_ms", 100)
+	v.SetDefault("stored_requests.redis_cache.timeout_ms", 50)
+	v.SetDefault("stored_requests.redis_cache.retry_interval_ms", 5000)
 	v.SetDefault("stored_requests.cache_events_api", false)
 	v.SetDefault("stored_requests.http_events.endpoint", "")
 	v.SetDefault("stored_requests.http_events.amp_endpoint", "")
@@ -1054,6 +1472,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_video_req.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_video_req.redis_cache.enabled", false)
+	v.SetDefault("stored_video_req.redis_cache.address", "")
+	v.SetDefault("stored_video_req.redis_cache.password", "")
+	v.SetDefault("stored_video_req.redis_cache.database", 0)
+	v.SetDefault("stored_video_req.redis_cache.tls", false)
+	v.SetDefault("stored_video_req.redis_cache.namespace", "pbs")
+	v.SetDefault("stored_video_req.redis_cache.ttl_seconds", 3600)
+	v.SetDefault("stored_video_req.redis_cache.pool_size", 10)
+	v.SetDefault("stored_video_req.redis_cache.dial_timeout_ms", 100)
+	v.SetDefault("stored_video_req.redis_cache.timeout_ms", 50)
+	v.SetDefault("stored_video_req.redis_cache.retry_interval_ms", 5000)
 	v.SetDefault("stored_video_req.cache_events.enabled", false)
 	v.SetDefault("stored_video_req.cache_events.endpoint", "")
 	v.SetDefault("stored_video_req.http_events.endpoint", "")
@@ -1086,6 +1515,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_responses.redis_cache.enabled", false)
+	v.SetDefault("stored_responses.redis_cache.address", "")
+	v.SetDefault("stored_responses.redis_cache.password", "")
+	v.SetDefault("stored_responses.redis_cache.database", 0)
+	v.SetDefault("stored_responses.redis_cache.tls", false)
+	v.SetDefault("stored_responses.redis_cache.namespace", "pbs")
+	v.SetDefault("stored_responses.redis_cache.ttl_seconds", 3600)
+	v.SetDefault("stored_responses.redis_cache.pool_size", 10)
+	v.SetDefault("stored_responses.redis_cache.dial_timeout_ms", 100)
+	v.SetDefault("stored_responses.redis_cache.timeout_ms", 50)
+	v.SetDefault("stored_responses.redis_cache.retry_interval_ms", 5000)
 	v.SetDefault("stored_responses.cache_events.enabled", false)
 	v.SetDefault("stored_responses.cache_events.endpoint", "")
 	v.SetDefault("stored_responses.http_events.endpoint", "")
@@ -1099,6 +1539,20 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("event.timeout_ms", 1000)
 
 	v.SetDefault("user_sync.priority_groups", [][]string{})
+	v.SetDefault("user_sync.uid_store.type", UIDStoreCookie)
+	v.SetDefault("user_sync.uid_store.signing_key", "")
+	v.SetDefault("user_sync.uid_store.timeout_ms", 50)
+	v.SetDefault("user_sync.uid_store.memory.max_entries", 1000000)
+	v.SetDefault("user_sync.uid_store.file.path", "")
+	v.SetDefault("user_sync.uid_store.redis.address", "")
+	v.SetDefault("user_sync.uid_store.redis.password", "")
+	v.SetDefault("user_sync.uid_store.redis.database", 0)
+	v.SetDefault("user_sync.uid_store.redis.tls", false)
+	v.SetDefault("user_sync.uid_store.redis.namespace", "pbs")
+	v.SetDefault("user_sync.uid_store.redis.pool_size", 10)
+	v.SetDefault("user_sync.uid_store.redis.dial_timeout_ms", 100)
+	v.SetDefault("user_sync.uid_store.redis.timeout_ms", 50)
+	v.SetDefault("user_sync.uid_store.redis.retry_interval_ms", 5000)
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
@@ -1107,6 +1561,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("accounts.in_memory_cache.type", "none")
 	v.SetDefault("accounts.in_memory_cache.ttl_seconds", 0)
 	v.SetDefault("accounts.in_memory_cache.size_bytes", 0)
+	v.SetDefault("accounts.redis_cache.enabled", false)
+	v.SetDefault("accounts.redis_cache.address", "")
+	v.SetDefault("accounts.redis_cache.password", "")
+	v.SetDefault("accounts.redis_cache.database", 0)
+	v.SetDefault("accounts.redis_cache.tls", false)
+	v.SetDefault("accounts.redis_cache.namespace", "pbs")
+	v.SetDefault("accounts.redis_cache.ttl_seconds", 3600)
+	v.SetDefault("accounts.redis_cache.pool_size", 10)
+	v.SetDefault("accounts.redis_cache.dial_timeout_ms", 100)
+	v.SetDefault("accounts.redis_cache.timeout_ms", 50)
+	v.SetDefault("accounts.redis_cache.retry_interval_ms", 5000)
 	v.SetDefault("accounts.cache_events.enabled", false)
 	v.SetDefault("accounts.cache_events.endpoint", "")
 	v.SetDefault("accounts.http_events.endpoint", "")
@@ -1137,6 +1602,26 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("analytics.agma.buffers.count", 100)
 	v.SetDefault("analytics.agma.buffers.timeout", "15m")
 	v.SetDefault("analytics.agma.accounts", []AgmaAnalyticsAccount{})
+	v.SetDefault("analytics.stream.enabled", false)
+	v.SetDefault("analytics.stream.format", "json")
+	v.SetDefault("analytics.stream.compression", "gzip")
+	v.SetDefault("analytics.stream.buffers.size", "2MB")
+	v.SetDefault("analytics.stream.buffers.count", 100)
+	v.SetDefault("analytics.stream.buffers.timeout", "1m")
+	v.SetDefault("analytics.stream.sink.type", "http")
+	v.SetDefault("analytics.stream.sink.http.url", "")
+	v.SetDefault("analytics.stream.sink.http.timeout", "2s")
+	v.SetDefault("analytics.stream.sink.file.path", "")
+	v.SetDefault("analytics.stream.sink.file.max_size", "100MB")
+	v.SetDefault("analytics.stream.sink.file.max_backups", 5)
+	v.SetDefault("analytics.stream.sink.kafka.brokers", []string{})
+	v.SetDefault("analytics.stream.sink.kafka.topic", "")
+	v.SetDefault("analytics.stream.sink.kafka.client_id", "prebid-server")
+	v.SetDefault("analytics.stream.sink.kafka.acks", 1)
+	v.SetDefault("analytics.stream.sink.kafka.timeout", "5s")
+	v.SetDefault("analytics.dispatch.queue_size", 0)
+	v.SetDefault("analytics.dispatch.drop_policy", AnalyticsDropOldest)
+	v.SetDefault("analytics.dispatch.block_timeout_ms", 10)
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.enabled", true)
@@ -1172,9 +1657,13 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 		"SVK", "SVN", "ESP", "SWE", "GBR"})
 	v.SetDefault("ccpa.enforce", false)
 	v.SetDefault("lmt.enforce", true)
+	v.SetDefault("bidder_info_reload.enabled", false)
+	v.SetDefault("bidder_info_reload.path", "./static/bidder-info")
+	v.SetDefault("bidder_info_reload.poll_interval_seconds", 60)
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
+	v.SetDefault("currency_converter.snapshot_path", "")
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
@@ -1206,6 +1695,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.usnat.enabled", false)
+	v.SetDefault("account_defaults.privacy.usnat.skip_sids", []int8{})
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1216,6 +1707,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("price_floors.fetcher.http_client.max_idle_connections_per_host", 2)
 	v.SetDefault("price_floors.fetcher.http_client.idle_connection_timeout_seconds", 60)
 	v.SetDefault("price_floors.fetcher.max_retries", 10)
+	v.SetDefault("price_floors.fetcher.last_known_good.enabled", false)
+	v.SetDefault("price_floors.fetcher.last_known_good.snapshot_dir", "")
+	v.SetDefault("price_floors.fetcher.last_known_good.max_staleness_sec", 86400)
 
 	v.SetDefault("account_defaults.events_enabled", false)
 	v.SetDefault("compression.response.enable_gzip", false)
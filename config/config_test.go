@@ -14,6 +14,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var bidderInfos = BidderInfos{
@@ -152,6 +153,12 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
+	cmpStrings(t, "currency_converter.snapshot_path", "", cfg.CurrencyConverter.SnapshotPath)
+	cmpInts(t, "cache.retry.max_retries", 0, cfg.CacheURL.Retry.MaxRetries)
+	cmpStrings(t, "cache.hedge.secondary_url", "", cfg.CacheURL.Hedge.SecondaryURL)
+	cmpStrings(t, "cache.backend.type", "prebid_cache", cfg.CacheURL.Backend.Type)
+	cmpInts(t, "cache.backend.local.max_entries", 100000, cfg.CacheURL.Backend.Local.MaxEntries)
+	assert.Empty(t, cfg.CurrencyConverter.Sources, "currency_converter.sources")
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", 20, cfg.Metrics.Influxdb.MetricSendInterval)
 	cmpBools(t, "account_adapter_details", false, cfg.Metrics.Disabled.AccountAdapterDetails)
@@ -162,6 +169,14 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "adapter_gdpr_request_blocked", false, cfg.Metrics.Disabled.AdapterGDPRRequestBlocked)
 	cmpStrings(t, "certificates_file", "", cfg.PemCertsFile)
 	cmpInts(t, "stored_requests_timeout_ms", 50, cfg.StoredRequestsTimeout)
+	cmpBools(t, "bidder_info_reload.enabled", false, cfg.BidderInfoReload.Enabled)
+	assert.Empty(t, cfg.Client.Throttle.Dimensions, "http_client.throttle.dimensions")
+	cmpInts(t, "http_client.throttle.decay_window_seconds", 0, cfg.Client.Throttle.DecayWindowSeconds)
+	cmpBools(t, "http_client.throttle.circuit_breaker.enabled", false, cfg.Client.Throttle.CircuitBreaker.Enabled)
+	assert.Equal(t, 0.8, cfg.Client.Throttle.CircuitBreaker.OpenThreshold, "http_client.throttle.circuit_breaker.open_threshold")
+	cmpInts(t, "http_client.throttle.circuit_breaker.open_seconds", 30, cfg.Client.Throttle.CircuitBreaker.OpenSeconds)
+	cmpStrings(t, "bidder_info_reload.path", "./static/bidder-info", cfg.BidderInfoReload.Path)
+	cmpInts(t, "bidder_info_reload.poll_interval_seconds", 60, cfg.BidderInfoReload.PollIntervalSeconds)
 	cmpBools(t, "stored_requests.filesystem.enabled", false, cfg.StoredRequests.Files.Enabled)
 	cmpStrings(t, "stored_requests.filesystem.directorypath", "./stored_requests/data/by_id", cfg.StoredRequests.Files.Path)
 	cmpStrings(t, "stored_requests.http.endpoint", "", cfg.StoredRequests.HTTP.Endpoint)
@@ -174,6 +189,14 @@ func TestDefaults(t *testing.T) {
 	cmpStrings(t, "accounts.in_memory_cache.type", "none", cfg.Accounts.InMemoryCache.Type)
 	cmpInts(t, "accounts.in_memory_cache.ttl_seconds", 0, cfg.Accounts.InMemoryCache.TTL)
 	cmpInts(t, "accounts.in_memory_cache.size_bytes", 0, cfg.Accounts.InMemoryCache.Size)
+	cmpBools(t, "accounts.redis_cache.enabled", false, cfg.Accounts.RedisCache.Enabled)
+	cmpStrings(t, "accounts.redis_cache.namespace", "pbs", cfg.Accounts.RedisCache.Namespace)
+	cmpInts(t, "accounts.redis_cache.ttl_seconds", 3600, cfg.Accounts.RedisCache.TTL)
+	cmpInts(t, "accounts.redis_cache.pool_size", 10, cfg.Accounts.RedisCache.PoolSize)
+	cmpInts(t, "accounts.redis_cache.dial_timeout_ms", 100, cfg.Accounts.RedisCache.DialTimeout)
+	cmpInts(t, "accounts.redis_cache.timeout_ms", 50, cfg.Accounts.RedisCache.Timeout)
+	cmpInts(t, "accounts.redis_cache.retry_interval_ms", 5000, cfg.Accounts.RedisCache.RetryInterval)
+	cmpBools(t, "stored_requests.redis_cache.enabled", false, cfg.StoredRequests.RedisCache.Enabled)
 	cmpBools(t, "accounts.cache_events.enabled", false, cfg.Accounts.CacheEvents.Enabled)
 	cmpStrings(t, "accounts.cache_events.endpoint", "", cfg.Accounts.CacheEvents.Endpoint)
 	cmpStrings(t, "accounts.http_events.endpoint", "", cfg.Accounts.HTTPEvents.Endpoint)
@@ -201,6 +224,17 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "price_floors.fetcher.http_client.max_idle_connections_per_host", 2, cfg.PriceFloors.Fetcher.HttpClient.MaxIdleConnsPerHost)
 	cmpInts(t, "price_floors.fetcher.http_client.idle_connection_timeout_seconds", 60, cfg.PriceFloors.Fetcher.HttpClient.IdleConnTimeout)
 	cmpInts(t, "price_floors.fetcher.max_retries", 10, cfg.PriceFloors.Fetcher.MaxRetries)
+	cmpBools(t, "price_floors.fetcher.last_known_good.enabled", false, cfg.PriceFloors.Fetcher.LastKnownGood.Enabled)
+	cmpStrings(t, "price_floors.fetcher.last_known_good.snapshot_dir", "", cfg.PriceFloors.Fetcher.LastKnownGood.SnapshotDir)
+	cmpInts(t, "price_floors.fetcher.last_known_good.max_staleness_sec", 86400, cfg.PriceFloors.Fetcher.LastKnownGood.MaxStalenessSec)
+
+	// Assert tracing related defaults
+	cmpBools(t, "tracing.enabled", false, cfg.Tracing.Enabled)
+	cmpStrings(t, "tracing.service_name", "prebid-server", cfg.Tracing.ServiceName)
+	cmpInts(t, "tracing.otlp.timeout_ms", 1000, cfg.Tracing.OTLP.TimeoutMs)
+	cmpInts(t, "tracing.otlp.batch_size", 512, cfg.Tracing.OTLP.BatchSize)
+	cmpInts(t, "tracing.otlp.queue_size", 4096, cfg.Tracing.OTLP.QueueSize)
+	cmpInts(t, "tracing.otlp.flush_interval_ms", 5000, cfg.Tracing.OTLP.FlushIntervalMs)
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", false, cfg.Compression.Request.GZIP)
@@ -243,6 +277,8 @@ func TestDefaults(t *testing.T) {
 
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 56, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 24, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.usnat.enabled", false, cfg.AccountDefaults.Privacy.USNat.Enabled)
+	assert.Empty(t, cfg.AccountDefaults.Privacy.USNat.SkipSIDs, "account_defaults.privacy.usnat.skip_sids")
 
 	//Assert purpose VendorExceptionMap hash tables were built correctly
 	cmpBools(t, "analytics.agma.enabled", false, cfg.Analytics.Agma.Enabled)
@@ -253,6 +289,22 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "analytics.agma.buffers.count", 100, cfg.Analytics.Agma.Buffers.EventCount)
 	cmpStrings(t, "analytics.agma.buffers.timeout", "15m", cfg.Analytics.Agma.Buffers.Timeout)
 	cmpInts(t, "analytics.agma.accounts", 0, len(cfg.Analytics.Agma.Accounts))
+	cmpBools(t, "analytics.stream.enabled", false, cfg.Analytics.Stream.Enabled)
+	cmpStrings(t, "analytics.stream.format", "json", cfg.Analytics.Stream.Format)
+	cmpStrings(t, "analytics.stream.compression", "gzip", cfg.Analytics.Stream.Compression)
+	cmpStrings(t, "analytics.stream.buffers.size", "2MB", cfg.Analytics.Stream.Buffers.BufferSize)
+	cmpInts(t, "analytics.stream.buffers.count", 100, cfg.Analytics.Stream.Buffers.EventCount)
+	cmpStrings(t, "analytics.stream.sink.type", "http", cfg.Analytics.Stream.Sink.Type)
+	cmpInts(t, "analytics.stream.sink.kafka.acks", 1, cfg.Analytics.Stream.Sink.Kafka.Acks)
+	cmpInts(t, "analytics.dispatch.queue_size", 0, cfg.Analytics.Dispatch.QueueSize)
+	cmpStrings(t, "analytics.dispatch.drop_policy", "drop_oldest", cfg.Analytics.Dispatch.DropPolicy)
+	cmpInts(t, "analytics.dispatch.block_timeout_ms", 10, cfg.Analytics.Dispatch.BlockTimeoutMs)
+	cmpStrings(t, "host_cookie.key_ring.mode", "none", cfg.HostCookie.KeyRing.Mode)
+	cmpBools(t, "host_cookie.key_ring.accept_plaintext", true, cfg.HostCookie.KeyRing.AcceptPlaintext)
+	cmpStrings(t, "user_sync.uid_store.type", "cookie", cfg.UserSync.UIDStore.Type)
+	cmpInts(t, "user_sync.uid_store.timeout_ms", 50, cfg.UserSync.UIDStore.TimeoutMs)
+	cmpInts(t, "user_sync.uid_store.memory.max_entries", 1000000, cfg.UserSync.UIDStore.Memory.MaxEntries)
+	cmpStrings(t, "user_sync.uid_store.redis.namespace", "pbs", cfg.UserSync.UIDStore.Redis.Namespace)
 	expectedTCF2 := TCF2{
 		Enabled: true,
 		Purpose1: TCF2Purpose{
@@ -373,6 +425,18 @@ accounts:
     type: "lru"
     ttl_seconds: 300
     size_bytes: 1000
+  redis_cache:
+    enabled: true
+    address: "redis:6379"
+    password: "secret"
+    database: 2
+    tls: true
+    namespace: "pbs-test"
+    ttl_seconds: 600
+    pool_size: 20
+    dial_timeout_ms: 200
+    timeout_ms: 30
+    retry_interval_ms: 1000
   cache_events:
     enabled: true
     endpoint: "https://prebid.org"
@@ -432,6 +496,14 @@ host_cookie:
   opt_out_url: http://prebid.org/optout
   opt_in_url: http://prebid.org/optin
   max_cookie_size_bytes: 32768
+  key_ring:
+    mode: encrypt
+    accept_plaintext: false
+    keys:
+      - id: "2025"
+        secret: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+      - id: "2024"
+        secret: ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=
 external_url: http://prebid-server.prebid.org/
 host: prebid-server.prebid.org
 port: 1234
@@ -451,6 +523,19 @@ cache:
   scheme: http
   host: prebidcache.net
   query: uuid=%PBS_CACHE_UUID%
+  retry:
+    max_retries: 2
+    backoff_ms: 5
+    max_backoff_ms: 20
+  hedge:
+    secondary_url: https://cache2.prebidcache.net
+    delay_ms: 30
+  backend:
+    type: redis
+    ttl_seconds: 600
+    max_ttl_seconds: 7200
+    redis:
+      address: redis.prebidcache.net:6379
 external_cache:
   scheme: https
   host: www.externalprebidcache.net
@@ -468,6 +553,15 @@ http_client_cache:
 currency_converter:
   fetch_url: https://currency.prebid.org
   fetch_interval_seconds: 1800
+  snapshot_path: /var/lib/pbs/currency.json
+  sources:
+    - type: http
+      url: https://currency.prebid.org
+      stale_rates_seconds: 3600
+    - type: static
+      rates:
+        USD:
+          EUR: 0.9
 recaptcha_secret: asdfasdfasdfasdf
 metrics:
   influxdb:
@@ -529,6 +623,10 @@ price_floors:
         max_idle_connections_per_host: 2
         idle_connection_timeout_seconds: 10
       max_retries: 5
+      last_known_good:
+        enabled: true
+        snapshot_dir: /var/lib/pbs/floors
+        max_staleness_sec: 3600
 account_defaults:
     events:
         enabled: true
@@ -576,6 +674,14 @@ account_defaults:
             cookiedeprecation:
                 enabled: true
                 ttl_sec: 86400
+        usnat:
+            enabled: true
+            skip_sids: [9, 11]
+    analytics:
+        modules:
+            pubstack:
+                sampling_rate: 0.5
+                scopeid: account-scope
 tmax_adjustments:
   enabled: true
   bidder_response_duration_min_ms: 700
@@ -597,6 +703,24 @@ analytics:
     - code: agma-code
       publisher_id: publisher-id
       site_app_id: site-or-app-id
+  modules:
+    custom:
+      enabled: true
+      endpoint: "http://custom.com"
+  dispatch:
+    queue_size: 50
+    drop_policy: block
+    block_timeout_ms: 5
+user_sync:
+  uid_store:
+    type: redis
+    signing_key: uid-secret
+    timeout_ms: 20
+    memory:
+      max_entries: 100
+    redis:
+      address: localhost:6379
+      namespace: prebid
 `)
 
 func cmpStrings(t *testing.T, key, expected, actual string) {
@@ -661,6 +785,17 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "accounts.in_memory_cache.type", "lru", cfg.Accounts.InMemoryCache.Type)
 	cmpInts(t, "accounts.in_memory_cache.ttl_seconds", 300, cfg.Accounts.InMemoryCache.TTL)
 	cmpInts(t, "accounts.in_memory_cache.size_bytes", 1000, cfg.Accounts.InMemoryCache.Size)
+	cmpBools(t, "accounts.redis_cache.enabled", true, cfg.Accounts.RedisCache.Enabled)
+	cmpStrings(t, "accounts.redis_cache.address", "redis:6379", cfg.Accounts.RedisCache.Address)
+	cmpStrings(t, "accounts.redis_cache.password", "secret", cfg.Accounts.RedisCache.Password)
+	cmpInts(t, "accounts.redis_cache.database", 2, cfg.Accounts.RedisCache.Database)
+	cmpBools(t, "accounts.redis_cache.tls", true, cfg.Accounts.RedisCache.TLS)
+	cmpStrings(t, "accounts.redis_cache.namespace", "pbs-test", cfg.Accounts.RedisCache.Namespace)
+	cmpInts(t, "accounts.redis_cache.ttl_seconds", 600, cfg.Accounts.RedisCache.TTL)
+	cmpInts(t, "accounts.redis_cache.pool_size", 20, cfg.Accounts.RedisCache.PoolSize)
+	cmpInts(t, "accounts.redis_cache.dial_timeout_ms", 200, cfg.Accounts.RedisCache.DialTimeout)
+	cmpInts(t, "accounts.redis_cache.timeout_ms", 30, cfg.Accounts.RedisCache.Timeout)
+	cmpInts(t, "accounts.redis_cache.retry_interval_ms", 1000, cfg.Accounts.RedisCache.RetryInterval)
 	cmpBools(t, "accounts.cache_events.enabled", true, cfg.Accounts.CacheEvents.Enabled)
 	cmpStrings(t, "accounts.cache_events.endpoint", "https://prebid.org", cfg.Accounts.CacheEvents.Endpoint)
 	cmpStrings(t, "accounts.http_events.endpoint", "https://prebid.org", cfg.Accounts.HTTPEvents.Endpoint)
@@ -669,6 +804,16 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "cache.scheme", "http", cfg.CacheURL.Scheme)
 	cmpStrings(t, "cache.host", "prebidcache.net", cfg.CacheURL.Host)
 	cmpStrings(t, "cache.query", "uuid=%PBS_CACHE_UUID%", cfg.CacheURL.Query)
+	cmpInts(t, "cache.retry.max_retries", 2, cfg.CacheURL.Retry.MaxRetries)
+	cmpInts(t, "cache.retry.backoff_ms", 5, cfg.CacheURL.Retry.BackoffMs)
+	cmpInts(t, "cache.retry.max_backoff_ms", 20, cfg.CacheURL.Retry.MaxBackoffMs)
+	cmpStrings(t, "cache.hedge.secondary_url", "https://cache2.prebidcache.net", cfg.CacheURL.Hedge.SecondaryURL)
+	cmpInts(t, "cache.hedge.delay_ms", 30, cfg.CacheURL.Hedge.DelayMs)
+	cmpStrings(t, "cache.backend.type", "redis", cfg.CacheURL.Backend.Type)
+	cmpInts(t, "cache.backend.ttl_seconds", 600, cfg.CacheURL.Backend.TTLSeconds)
+	cmpInts(t, "cache.backend.max_ttl_seconds", 7200, cfg.CacheURL.Backend.MaxTTLSeconds)
+	cmpStrings(t, "cache.backend.redis.address", "redis.prebidcache.net:6379", cfg.CacheURL.Backend.Redis.Address)
+	cmpInts(t, "cache.backend.redis.pool_size", 10, cfg.CacheURL.Backend.Redis.PoolSize)
 	cmpStrings(t, "external_cache.scheme", "https", cfg.ExtCacheURL.Scheme)
 	cmpStrings(t, "external_cache.host", "www.externalprebidcache.net", cfg.ExtCacheURL.Host)
 	cmpStrings(t, "external_cache.path", "/endpoints/cache", cfg.ExtCacheURL.Path)
@@ -707,6 +852,9 @@ func TestFullConfig(t *testing.T) {
 	cmpInts(t, "price_floors.fetcher.http_client.max_idle_connections_per_host", 2, cfg.PriceFloors.Fetcher.HttpClient.MaxIdleConnsPerHost)
 	cmpInts(t, "price_floors.fetcher.http_client.idle_connection_timeout_seconds", 10, cfg.PriceFloors.Fetcher.HttpClient.IdleConnTimeout)
 	cmpInts(t, "price_floors.fetcher.max_retries", 5, cfg.PriceFloors.Fetcher.MaxRetries)
+	cmpBools(t, "price_floors.fetcher.last_known_good.enabled", true, cfg.PriceFloors.Fetcher.LastKnownGood.Enabled)
+	cmpStrings(t, "price_floors.fetcher.last_known_good.snapshot_dir", "/var/lib/pbs/floors", cfg.PriceFloors.Fetcher.LastKnownGood.SnapshotDir)
+	cmpInts(t, "price_floors.fetcher.last_known_good.max_staleness_sec", 3600, cfg.PriceFloors.Fetcher.LastKnownGood.MaxStalenessSec)
 	cmpBools(t, "account_defaults.price_floors.enabled", true, cfg.AccountDefaults.PriceFloors.Enabled)
 	cmpInts(t, "account_defaults.price_floors.enforce_floors_rate", 50, cfg.AccountDefaults.PriceFloors.EnforceFloorsRate)
 	cmpBools(t, "account_defaults.price_floors.adjust_for_bid_adjustment", false, cfg.AccountDefaults.PriceFloors.AdjustForBidAdjustment)
@@ -745,6 +893,8 @@ func TestFullConfig(t *testing.T) {
 
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 50, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 20, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.usnat.enabled", true, cfg.AccountDefaults.Privacy.USNat.Enabled)
+	assert.Equal(t, []int8{9, 11}, cfg.AccountDefaults.Privacy.USNat.SkipSIDs, "account_defaults.privacy.usnat.skip_sids")
 
 	cmpStrings(t, "account_defaults.privacy.topicsdomain", "test.com", cfg.AccountDefaults.Privacy.PrivacySandbox.TopicsDomain)
 	cmpBools(t, "account_defaults.privacy.cookiedeprecation.enabled", true, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
@@ -899,6 +1049,11 @@ func TestFullConfig(t *testing.T) {
 
 	cmpStrings(t, "currency_converter.fetch_url", "https://currency.prebid.org", cfg.CurrencyConverter.FetchURL)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
+	cmpStrings(t, "currency_converter.snapshot_path", "/var/lib/pbs/currency.json", cfg.CurrencyConverter.SnapshotPath)
+	assert.Equal(t, []CurrencyRatesSource{
+		{Type: CurrencyRatesSourceHTTP, URL: "https://currency.prebid.org", StaleRatesSeconds: 3600},
+		{Type: CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+	}, cfg.CurrencyConverter.Sources, "currency_converter.sources")
 	cmpStrings(t, "recaptcha_secret", "asdfasdfasdfasdf", cfg.RecaptchaSecret)
 	cmpStrings(t, "metrics.influxdb.host", "upstream:8232", cfg.Metrics.Influxdb.Host)
 	cmpStrings(t, "metrics.influxdb.database", "metricsdb", cfg.Metrics.Influxdb.Database)
@@ -942,6 +1097,24 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "analytics.agma.accounts.0.publisher_id", "publisher-id", cfg.Analytics.Agma.Accounts[0].PublisherId)
 	cmpStrings(t, "analytics.agma.accounts.0.code", "agma-code", cfg.Analytics.Agma.Accounts[0].Code)
 	cmpStrings(t, "analytics.agma.accounts.0.site_app_id", "site-or-app-id", cfg.Analytics.Agma.Accounts[0].SiteAppId)
+	assert.Equal(t, map[string]interface{}{"enabled": true, "endpoint": "http://custom.com"}, cfg.Analytics.Modules["custom"], "analytics.modules.custom")
+	cmpInts(t, "analytics.dispatch.queue_size", 50, cfg.Analytics.Dispatch.QueueSize)
+	cmpStrings(t, "analytics.dispatch.drop_policy", "block", cfg.Analytics.Dispatch.DropPolicy)
+	cmpInts(t, "analytics.dispatch.block_timeout_ms", 5, cfg.Analytics.Dispatch.BlockTimeoutMs)
+	cmpStrings(t, "host_cookie.key_ring.mode", "encrypt", cfg.HostCookie.KeyRing.Mode)
+	cmpBools(t, "host_cookie.key_ring.accept_plaintext", false, cfg.HostCookie.KeyRing.AcceptPlaintext)
+	assert.Equal(t, []CookieKey{
+		{ID: "2025", Secret: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+		{ID: "2024", Secret: "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="},
+	}, cfg.HostCookie.KeyRing.Keys, "host_cookie.key_ring.keys")
+	cmpStrings(t, "user_sync.uid_store.type", "redis", cfg.UserSync.UIDStore.Type)
+	cmpStrings(t, "user_sync.uid_store.signing_key", "uid-secret", cfg.UserSync.UIDStore.SigningKey)
+	cmpInts(t, "user_sync.uid_store.timeout_ms", 20, cfg.UserSync.UIDStore.TimeoutMs)
+	cmpInts(t, "user_sync.uid_store.memory.max_entries", 100, cfg.UserSync.UIDStore.Memory.MaxEntries)
+	cmpStrings(t, "user_sync.uid_store.redis.address", "localhost:6379", cfg.UserSync.UIDStore.Redis.Address)
+	cmpStrings(t, "user_sync.uid_store.redis.namespace", "prebid", cfg.UserSync.UIDStore.Redis.Namespace)
+	cmpInts(t, "user_sync.uid_store.redis.pool_size", 10, cfg.UserSync.UIDStore.Redis.PoolSize)
+	assert.JSONEq(t, `{"sampling_rate":0.5,"scopeid":"account-scope"}`, string(cfg.AccountDefaults.Analytics.Modules["pubstack"]), "account_defaults.analytics.modules.pubstack")
 }
 
 func TestValidateConfig(t *testing.T) {
@@ -1252,6 +1425,20 @@ func TestNegativePrometheusTimeout(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "metrics.prometheus.timeout_ms must be positive if metrics.prometheus.port is defined. Got timeout=0 and port=8001")
 }
 
+func TestInvalidTracingSamplingRate(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Tracing.Enabled = true
+	cfg.Tracing.OTLP.Endpoint = "http://localhost:4318"
+	cfg.Tracing.SamplingRate = 1.5
+	assertOneError(t, cfg.validate(v), "tracing.sampling_rate must be between 0 and 1. Got 1.500000")
+}
+
+func TestMissingTracingEndpoint(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Tracing.Enabled = true
+	assertOneError(t, cfg.validate(v), `tracing.otlp.endpoint must be a valid URL. Got ""`)
+}
+
 func TestInvalidHostVendorID(t *testing.T) {
 	tests := []struct {
 		description  string
@@ -1428,6 +1615,325 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateBidderInfoReload(t *testing.T) {
+	testCases := []struct {
+		description   string
+		reload        BidderInfoReload
+		expectedError string
+	}{
+		{
+			description: "disabled",
+			reload:      BidderInfoReload{Enabled: false, Path: "", PollIntervalSeconds: -1},
+		},
+		{
+			description: "enabled",
+			reload:      BidderInfoReload{Enabled: true, Path: "./static/bidder-info", PollIntervalSeconds: 60},
+		},
+		{
+			description: "enabled_without_polling",
+			reload:      BidderInfoReload{Enabled: true, Path: "./static/bidder-info", PollIntervalSeconds: 0},
+		},
+		{
+			description:   "enabled_without_path",
+			reload:        BidderInfoReload{Enabled: true, Path: "", PollIntervalSeconds: 60},
+			expectedError: "bidder_info_reload.path must be set when bidder_info_reload.enabled is true",
+		},
+		{
+			description:   "negative_poll_interval",
+			reload:        BidderInfoReload{Enabled: true, Path: "./static/bidder-info", PollIntervalSeconds: -1},
+			expectedError: "bidder_info_reload.poll_interval_seconds must be >= 0. Got -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.reload.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Equal(t, []error{errors.New(test.expectedError)}, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePriceFloorLastKnownGood(t *testing.T) {
+	testCases := []struct {
+		description   string
+		lastKnownGood PriceFloorLastKnownGood
+		expectedError string
+	}{
+		{
+			description:   "disabled",
+			lastKnownGood: PriceFloorLastKnownGood{Enabled: false, MaxStalenessSec: 0},
+		},
+		{
+			description:   "enabled",
+			lastKnownGood: PriceFloorLastKnownGood{Enabled: true, SnapshotDir: "/var/lib/pbs/floors", MaxStalenessSec: 3600},
+		},
+		{
+			description:   "enabled_without_staleness",
+			lastKnownGood: PriceFloorLastKnownGood{Enabled: true, MaxStalenessSec: 0},
+			expectedError: "price_floors.fetcher.last_known_good.max_staleness_sec must be > 0. Got 0",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.lastKnownGood.validate(nil)
+			if test.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Equal(t, []error{errors.New(test.expectedError)}, errs)
+			}
+		})
+	}
+}
+
+func TestValidateCurrencyConverterSources(t *testing.T) {
+	testCases := []struct {
+		description    string
+		sources        []CurrencyRatesSource
+		expectedErrors []error
+	}{
+		{
+			description: "none",
+		},
+		{
+			description: "valid",
+			sources: []CurrencyRatesSource{
+				{Type: CurrencyRatesSourceHTTP, URL: "https://currency.prebid.org", StaleRatesSeconds: 3600},
+				{Type: CurrencyRatesSourceFile, Path: "/etc/pbs/rates.json"},
+				{Type: CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+			},
+		},
+		{
+			description: "missing_settings",
+			sources: []CurrencyRatesSource{
+				{Type: CurrencyRatesSourceHTTP},
+				{Type: CurrencyRatesSourceFile},
+				{Type: CurrencyRatesSourceStatic},
+			},
+			expectedErrors: []error{
+				errors.New("currency_converter.sources[0].url must be set for the http source"),
+				errors.New("currency_converter.sources[1].path must be set for the file source"),
+				errors.New("currency_converter.sources[2].rates must be set for the static source"),
+			},
+		},
+		{
+			description: "invalid_type_and_staleness",
+			sources: []CurrencyRatesSource{
+				{Type: "redis", StaleRatesSeconds: -1},
+			},
+			expectedErrors: []error{
+				errors.New(`currency_converter.sources[0].type must be one of [http, file, static]. Got "redis"`),
+				errors.New("currency_converter.sources[0].stale_rates_seconds must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := CurrencyConverter{Sources: test.sources}
+			errs := cfg.validate(nil)
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	validRetry := CacheRetry{MaxRetries: 2, BackoffMs: 10, MaxBackoffMs: 100}
+	testCases := []struct {
+		description    string
+		cache          Cache
+		expectedErrors []error
+	}{
+		{
+			description: "prebid_cache",
+			cache:       Cache{Retry: validRetry, Backend: CacheBackend{Type: CacheBackendPrebidCache}},
+		},
+		{
+			description: "local",
+			cache:       Cache{Backend: CacheBackend{Type: CacheBackendLocal, TTLSeconds: 300, MaxTTLSeconds: 3600, Local: LocalCacheBackend{MaxEntries: 10}}},
+		},
+		{
+			description: "redis",
+			cache: Cache{Backend: CacheBackend{Type: CacheBackendRedis, TTLSeconds: 300, MaxTTLSeconds: 300,
+				Redis: RedisCache{Address: "localhost:6379", PoolSize: 1, DialTimeout: 10, Timeout: 10}}},
+		},
+		{
+			description: "invalid_retry_and_hedge",
+			cache: Cache{
+				Retry:   CacheRetry{MaxRetries: -1, BackoffMs: 10, MaxBackoffMs: 5},
+				Hedge:   CacheHedge{SecondaryURL: "https://cache2.prebid.org", DelayMs: -1},
+				Backend: CacheBackend{Type: CacheBackendPrebidCache},
+			},
+			expectedErrors: []error{
+				errors.New("cache.retry.max_retries must be >= 0. Got -1"),
+				errors.New("cache.retry.max_backoff_ms must be >= cache.retry.backoff_ms. Got 5"),
+				errors.New("cache.hedge.delay_ms must be >= 0. Got -1"),
+			},
+		},
+		{
+			description: "retries_without_backoff",
+			cache:       Cache{Retry: CacheRetry{MaxRetries: 1}, Backend: CacheBackend{Type: CacheBackendPrebidCache}},
+			expectedErrors: []error{
+				errors.New("cache.retry.backoff_ms must be > 0 when cache.retry.max_retries > 0. Got 0"),
+			},
+		},
+		{
+			description: "invalid_local",
+			cache:       Cache{Backend: CacheBackend{Type: CacheBackendLocal, TTLSeconds: 300, MaxTTLSeconds: 60}},
+			expectedErrors: []error{
+				errors.New("cache.backend.local.max_entries must be > 0. Got 0"),
+				errors.New("cache.backend.max_ttl_seconds must be >= cache.backend.ttl_seconds. Got 60"),
+			},
+		},
+		{
+			description: "invalid_redis",
+			cache:       Cache{Backend: CacheBackend{Type: CacheBackendRedis, MaxTTLSeconds: 60}},
+			expectedErrors: []error{
+				errors.New("cache.backend.redis.address must be set for the redis backend"),
+				errors.New("cache.backend.redis.pool_size must be > 0. Got 0"),
+				errors.New("cache.backend.redis.dial_timeout_ms must be > 0. Got 0"),
+				errors.New("cache.backend.redis.timeout_ms must be > 0. Got 0"),
+				errors.New("cache.backend.ttl_seconds must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "unknown_backend",
+			cache:       Cache{Backend: CacheBackend{Type: "memcached"}},
+			expectedErrors: []error{
+				errors.New(`cache.backend.type must be one of [prebid_cache, local, redis]. Got "memcached"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cache.validate(nil)
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
+
+func TestValidateHTTPThrottle(t *testing.T) {
+	testCases := []struct {
+		description    string
+		throttle       HTTPThrottle
+		expectedErrors []error
+	}{
+		{
+			description: "default",
+			throttle:    HTTPThrottle{CircuitBreaker: HTTPThrottleCircuitBreaker{OpenThreshold: 0.8, OpenSeconds: 30}},
+		},
+		{
+			description: "all_dimensions",
+			throttle:    HTTPThrottle{Dimensions: []string{"account", "channel", "media_type", "device_country"}, DecayWindowSeconds: 60},
+		},
+		{
+			description: "invalid_dimensions",
+			throttle:    HTTPThrottle{Dimensions: []string{"account", "country", "account"}},
+			expectedErrors: []error{
+				errors.New("http_client.throttle.dimensions contains an invalid dimension country. Valid values are account, channel, media_type and device_country"),
+				errors.New("http_client.throttle.dimensions contains account more than once"),
+			},
+		},
+		{
+			description:    "negative_decay_window",
+			throttle:       HTTPThrottle{DecayWindowSeconds: -1},
+			expectedErrors: []error{errors.New("http_client.throttle.decay_window_seconds must be >= 0. Got -1")},
+		},
+		{
+			description: "circuit_breaker",
+			throttle:    HTTPThrottle{CircuitBreaker: HTTPThrottleCircuitBreaker{Enabled: true, OpenThreshold: 1, OpenSeconds: 30}},
+		},
+		{
+			description: "invalid_circuit_breaker",
+			throttle:    HTTPThrottle{CircuitBreaker: HTTPThrottleCircuitBreaker{Enabled: true, OpenThreshold: 1.5, OpenSeconds: 0}},
+			expectedErrors: []error{
+				errors.New("http_client.throttle.circuit_breaker.open_threshold must be > 0 and <= 1. Got 1.500000"),
+				errors.New("http_client.throttle.circuit_breaker.open_seconds must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.throttle.validate(nil))
+		})
+	}
+}
+
+func TestHTTPThrottleIsHealthTrackedPerDimension(t *testing.T) {
+	assert.False(t, (&HTTPThrottle{EnableThrottling: true, CircuitBreaker: HTTPThrottleCircuitBreaker{OpenThreshold: 0.8}}).IsHealthTrackedPerDimension())
+	assert.True(t, (&HTTPThrottle{Dimensions: []string{"account"}}).IsHealthTrackedPerDimension())
+	assert.True(t, (&HTTPThrottle{DecayWindowSeconds: 60}).IsHealthTrackedPerDimension())
+	assert.True(t, (&HTTPThrottle{CircuitBreaker: HTTPThrottleCircuitBreaker{Enabled: true}}).IsHealthTrackedPerDimension())
+}
+
+func TestResolveBidderInfos(t *testing.T) {
+	capabilities := &CapabilitiesInfo{Site: &PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}}}
+	bidderInfos := BidderInfos{
+		"appnexus": {
+			Endpoint:     "http://appnexus.com",
+			Maintainer:   &MaintainerInfo{Email: "appnexus@domain.com"},
+			Capabilities: capabilities,
+		},
+	}
+
+	v := viper.New()
+	SetupViper(v, "", bidderInfos)
+	v.Set("gdpr.default_value", "0")
+	v.Set("adapters.appnexus.endpoint", "http://host.appnexus.com")
+	cfg, err := New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description   string
+		fsBidderInfos BidderInfos
+		expected      BidderInfos
+		expectedError string
+	}{
+		{
+			description: "host_override_applied",
+			fsBidderInfos: BidderInfos{
+				"appnexus": {
+					Endpoint:     "http://eu.appnexus.com",
+					Maintainer:   &MaintainerInfo{Email: "support@appnexus.com"},
+					Capabilities: capabilities,
+				},
+			},
+			expected: BidderInfos{
+				"appnexus": {
+					Endpoint:     "http://host.appnexus.com",
+					Maintainer:   &MaintainerInfo{Email: "support@appnexus.com"},
+					Capabilities: capabilities,
+				},
+			},
+		},
+		{
+			description: "invalid",
+			fsBidderInfos: BidderInfos{
+				"appnexus": {Endpoint: "http://eu.appnexus.com", Capabilities: capabilities},
+			},
+			expectedError: "bidder info validation errors (1 error):\n  1: missing required field: maintainer.email for adapter: appnexus\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			infos, err := cfg.ResolveBidderInfos(test.fsBidderInfos)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, infos)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
@@ -2014,3 +2520,183 @@ func TestUnpackDSADefault(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAnalyticsDispatch(t *testing.T) {
+	testCases := []struct {
+		description    string
+		dispatch       AnalyticsDispatch
+		expectedErrors []error
+	}{
+		{
+			description: "drop_oldest",
+			dispatch:    AnalyticsDispatch{QueueSize: 100, DropPolicy: AnalyticsDropOldest},
+		},
+		{
+			description: "synchronous",
+			dispatch:    AnalyticsDispatch{QueueSize: 0, DropPolicy: AnalyticsDropNewest},
+		},
+		{
+			description: "block",
+			dispatch:    AnalyticsDispatch{QueueSize: 100, DropPolicy: AnalyticsBlock, BlockTimeoutMs: 10},
+		},
+		{
+			description: "block_without_timeout",
+			dispatch:    AnalyticsDispatch{QueueSize: 100, DropPolicy: AnalyticsBlock},
+			expectedErrors: []error{
+				errors.New("analytics.dispatch.block_timeout_ms must be > 0 for the block policy. Got 0"),
+			},
+		},
+		{
+			description: "invalid",
+			dispatch:    AnalyticsDispatch{QueueSize: -1, DropPolicy: "drop_all"},
+			expectedErrors: []error{
+				errors.New("analytics.dispatch.queue_size must be >= 0. Got -1"),
+				errors.New(`analytics.dispatch.drop_policy must be one of [drop_oldest, drop_newest, block]. Got "drop_all"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.dispatch.validate(nil))
+		})
+	}
+}
+
+func TestValidateCookieKeyRing(t *testing.T) {
+	const (
+		secret32 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+		secret16 = "MDEyMzQ1Njc4OWFiY2RlZg=="
+	)
+
+	testCases := []struct {
+		description    string
+		keyRing        CookieKeyRing
+		expectedErrors []error
+	}{
+		{
+			description: "default",
+			keyRing:     CookieKeyRing{},
+		},
+		{
+			description: "none",
+			keyRing:     CookieKeyRing{Mode: CookieProtectionNone},
+		},
+		{
+			description: "encrypt",
+			keyRing:     CookieKeyRing{Mode: CookieProtectionEncrypt, Keys: []CookieKey{{ID: "new", Secret: secret32}, {ID: "old", Secret: secret32}}},
+		},
+		{
+			description: "sign",
+			keyRing:     CookieKeyRing{Mode: CookieProtectionSign, Keys: []CookieKey{{ID: "key_1", Secret: secret16}}},
+		},
+		{
+			description: "without_keys",
+			keyRing:     CookieKeyRing{Mode: CookieProtectionSign},
+			expectedErrors: []error{
+				errors.New("host_cookie.key_ring.keys must not be empty for the sign mode"),
+			},
+		},
+		{
+			description: "invalid_keys",
+			keyRing: CookieKeyRing{Mode: CookieProtectionEncrypt, Keys: []CookieKey{
+				{ID: "key.1", Secret: secret32},
+				{ID: "key", Secret: secret16},
+				{ID: "key", Secret: "not base64"},
+			}},
+			expectedErrors: []error{
+				errors.New(`host_cookie.key_ring.keys[0].id must only contain letters, digits, '-' and '_'. Got "key.1"`),
+				errors.New("host_cookie.key_ring.keys[1].secret must be 32 bytes long to encrypt. Got 16"),
+				errors.New(`host_cookie.key_ring.keys[2].id "key" is duplicated`),
+				errors.New("host_cookie.key_ring.keys[2].secret must be base64 encoded: illegal base64 data at input byte 3"),
+			},
+		},
+		{
+			description: "short_signing_key",
+			keyRing:     CookieKeyRing{Mode: CookieProtectionSign, Keys: []CookieKey{{ID: "key", Secret: "MDEyMzQ1Njc="}}},
+			expectedErrors: []error{
+				errors.New("host_cookie.key_ring.keys[0].secret must be at least 16 bytes long to sign. Got 8"),
+			},
+		},
+		{
+			description: "invalid_mode",
+			keyRing:     CookieKeyRing{Mode: "obfuscate"},
+			expectedErrors: []error{
+				errors.New(`host_cookie.key_ring.mode must be one of [none, encrypt, sign]. Got "obfuscate"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.keyRing.validate(nil))
+		})
+	}
+}
+
+func TestValidateUIDStore(t *testing.T) {
+	testCases := []struct {
+		description    string
+		uidStore       UIDStore
+		expectedErrors []error
+	}{
+		{
+			description: "default",
+			uidStore:    UIDStore{},
+		},
+		{
+			description: "cookie",
+			uidStore:    UIDStore{Type: UIDStoreCookie},
+		},
+		{
+			description: "memory",
+			uidStore:    UIDStore{Type: UIDStoreMemory, SigningKey: "secret", TimeoutMs: 50, Memory: MemoryUIDStore{MaxEntries: 10}},
+		},
+		{
+			description: "memory_without_max_entries",
+			uidStore:    UIDStore{Type: UIDStoreMemory, SigningKey: "secret", TimeoutMs: 50},
+			expectedErrors: []error{
+				errors.New("user_sync.uid_store.memory.max_entries must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "file_without_path",
+			uidStore:    UIDStore{Type: UIDStoreFile, SigningKey: "secret", TimeoutMs: 50},
+			expectedErrors: []error{
+				errors.New("user_sync.uid_store.file.path must be set for the file store"),
+			},
+		},
+		{
+			description: "redis",
+			uidStore:    UIDStore{Type: UIDStoreRedis, SigningKey: "secret", TimeoutMs: 50, Redis: RedisCache{Address: "localhost:6379", PoolSize: 10, DialTimeout: 100, Timeout: 50}},
+		},
+		{
+			description: "redis_without_address",
+			uidStore:    UIDStore{Type: UIDStoreRedis, SigningKey: "secret", TimeoutMs: 50, Redis: RedisCache{PoolSize: 10, DialTimeout: 100, Timeout: 50}},
+			expectedErrors: []error{
+				errors.New("user_sync.uid_store.redis.address must be set for the redis store"),
+			},
+		},
+		{
+			description: "without_signing_key_and_timeout",
+			uidStore:    UIDStore{Type: UIDStoreFile, File: FileUIDStore{Path: "/var/uids"}},
+			expectedErrors: []error{
+				errors.New("user_sync.uid_store.signing_key must be set for a server-side store"),
+				errors.New("user_sync.uid_store.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "invalid_type",
+			uidStore:    UIDStore{Type: "database"},
+			expectedErrors: []error{
+				errors.New(`user_sync.uid_store.type must be one of [cookie, memory, file, redis]. Got "database"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.uidStore.validate(nil))
+		})
+	}
+}
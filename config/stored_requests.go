@@ -65,6 +65,10 @@ type StoredRequests struct {
 	// InMemoryCache configures an instance of stored_requests/caches/memory/cache.go.
 	// If non-nil, Stored Requests will be saved in an in-memory cache.
 	InMemoryCache InMemoryCache `mapstructure:"in_memory_cache"`
+	// RedisCache configures an instance of stored_requests/caches/redis/cache.go.
+	// If enabled, Stored Requests will be saved in a Redis-compatible server shared by all PBS instances,
+	// used behind the in-memory cache if there is one.
+	RedisCache RedisCache `mapstructure:"redis_cache"`
 	// CacheEvents configures an instance of stored_requests/events/api/api.go.
 	// This is a sub-object containing the endpoint name to use for this API endpoint.
 	CacheEvents CacheEventsConfig `mapstructure:"cache_events"`
@@ -166,6 +170,7 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 		}
 	}
 	errs = cfg.InMemoryCache.validate(cfg.DataType(), errs)
+	errs = cfg.RedisCache.validate(cfg.DataType(), errs)
 	return errs
 }
 
@@ -380,3 +385,56 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 	}
 	return errs
 }
+
+// RedisCache configures a stored_requests/caches/redis/cache.go
+type RedisCache struct {
+	// Enabled should be true to save Stored Requests in a Redis-compatible server.
+	Enabled bool `mapstructure:"enabled"`
+	// Address is the host:port of the server.
+	Address string `mapstructure:"address"`
+	// Password authenticates the connections to the server if not empty.
+	Password string `mapstructure:"password"`
+	// Database is the index of the database selected on the server.
+	Database int `mapstructure:"database"`
+	// TLS should be true if the server requires TLS connections.
+	TLS bool `mapstructure:"tls"`
+	// Namespace prefixes the keys, so several PBS deployments can share a server.
+	Namespace string `mapstructure:"namespace"`
+	// TTL is the number of seconds values are kept in the server. TTL <= 0 can be used for "no ttl".
+	TTL int `mapstructure:"ttl_seconds"`
+	// PoolSize is the maximum number of idle connections kept open to the server.
+	PoolSize int `mapstructure:"pool_size"`
+	// DialTimeout is the maximum number of milliseconds to open a connection to the server.
+	DialTimeout int `mapstructure:"dial_timeout_ms"`
+	// Timeout is the maximum number of milliseconds to execute a command on the server.
+	Timeout int `mapstructure:"timeout_ms"`
+	// RetryInterval is the number of milliseconds the server is skipped for after a failure.
+	RetryInterval int `mapstructure:"retry_interval_ms"`
+}
+
+func (cfg *RedisCache) validate(dataType DataType, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+
+	section := dataType.Section()
+	if cfg.Address == "" {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.address must be set when redis_cache.enabled=true", section))
+	}
+	if cfg.Database < 0 {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.database must be >= 0. Got %d", section, cfg.Database))
+	}
+	if cfg.PoolSize <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.pool_size must be > 0. Got %d", section, cfg.PoolSize))
+	}
+	if cfg.DialTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.dial_timeout_ms must be > 0. Got %d", section, cfg.DialTimeout))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.timeout_ms must be > 0. Got %d", section, cfg.Timeout))
+	}
+	if cfg.RetryInterval < 0 {
+		errs = append(errs, fmt.Errorf("%s: redis_cache.retry_interval_ms must be >= 0. Got %d", section, cfg.RetryInterval))
+	}
+	return errs
+}
@@ -63,6 +63,32 @@ func TestInMemoryCacheValidationStoredRequests(t *testing.T) {
 	}).validate(RequestDataType, nil))
 }
 
+func TestRedisCacheValidation(t *testing.T) {
+	valid := RedisCache{
+		Enabled:       true,
+		Address:       "localhost:6379",
+		PoolSize:      10,
+		DialTimeout:   100,
+		Timeout:       50,
+		RetryInterval: 1000,
+	}
+	assertNoErrs(t, valid.validate(RequestDataType, nil))
+	assertNoErrs(t, (&RedisCache{Enabled: false}).validate(RequestDataType, nil))
+
+	for _, modify := range []func(cfg *RedisCache){
+		func(cfg *RedisCache) { cfg.Address = "" },
+		func(cfg *RedisCache) { cfg.Database = -1 },
+		func(cfg *RedisCache) { cfg.PoolSize = 0 },
+		func(cfg *RedisCache) { cfg.DialTimeout = 0 },
+		func(cfg *RedisCache) { cfg.Timeout = 0 },
+		func(cfg *RedisCache) { cfg.RetryInterval = -1 },
+	} {
+		cfg := valid
+		modify(&cfg)
+		assertErrsExist(t, cfg.validate(AccountDataType, nil))
+	}
+}
+
 func TestInMemoryCacheValidationSingleCache(t *testing.T) {
 	assertNoErrs(t, (&InMemoryCache{
 		Type: "unbounded",
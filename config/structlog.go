@@ -65,11 +65,14 @@ func logMapWithLogger(v reflect.Value, prefix string, logger logMsg) {
 	for _, k := range v.MapKeys() {
 		if k.Kind() == reflect.String && !allowedName(k.String()) {
 			logger("%s: <REDACTED>", extendMapPrefix(prefix, k.String()))
+		} else if k.Kind() == reflect.String {
+			// Use k.String() rather than k.Interface() so that unexported maps can be logged too.
+			logGeneralWithLogger(v.MapIndex(k), extendMapPrefix(prefix, k.String()), logger)
 		} else {
 			// Use Sprintf("%v", k.Interface) to handle non-string keys. Should not be possible to have a key
 			// too complex to represent by %v.
-			// NOTE: This will break if we have an unexported map in the object. If so we will have to switch
-			// on k.Kind() rather than rely on fmt.Sprintf("%v") doing that work.
+			// NOTE: This will break if we have an unexported map with non-string keys in the object. If so we
+			// will have to switch on k.Kind() rather than rely on fmt.Sprintf("%v") doing that work.
 			logGeneralWithLogger(v.MapIndex(k), extendMapPrefix(prefix, fmt.Sprintf("%v", k.Interface())), logger)
 		}
 	}
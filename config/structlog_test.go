@@ -16,8 +16,9 @@ type testStruct struct {
 }
 
 type innerStruct struct {
-	int1     int    `mapstructure:"int1"`
-	password string `mapstructure:"password"`
+	int1     int               `mapstructure:"int1"`
+	password string            `mapstructure:"password"`
+	states   map[string]string `mapstructure:"states"`
 }
 
 var expected string = `this_int: 5
@@ -25,6 +26,7 @@ mystring: foobar
 ((Flag)): false
 sub.int1: 3
 sub.password: <REDACTED>
+sub.states[Alaska]: Juneau
 ((Caps))[Alabama]: Montgomery
 `
 
@@ -41,6 +43,9 @@ func TestBasic(t *testing.T) {
 		Sub: innerStruct{
 			int1:     3,
 			password: "secret",
+			states: map[string]string{
+				"Alaska": "Juneau",
+			},
 		},
 		// Can't do more than one entry as order is not guaranteed.
 		Caps: map[string]string{
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Tracing configures distributed tracing of the auction pipeline.
+type Tracing struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ServiceName is reported as the service.name resource attribute of every exported span.
+	ServiceName string `mapstructure:"service_name"`
+	// SamplingRate is the fraction of new traces which are recorded, between 0 and 1. Requests which
+	// arrive with a W3C traceparent header follow the sampling decision of the caller instead.
+	SamplingRate float64     `mapstructure:"sampling_rate"`
+	OTLP         TracingOTLP `mapstructure:"otlp"`
+}
+
+// TracingOTLP configures the OTLP/HTTP exporter spans are sent through.
+type TracingOTLP struct {
+	// Endpoint is the base URL of the collector. Spans are posted to {endpoint}/v1/traces.
+	Endpoint string            `mapstructure:"endpoint"`
+	Headers  map[string]string `mapstructure:"headers"`
+	// TimeoutMs is the maximum time an export call is allowed to take.
+	TimeoutMs int `mapstructure:"timeout_ms"`
+	// BatchSize is the maximum number of spans sent in one export call.
+	BatchSize int `mapstructure:"batch_size"`
+	// QueueSize is the number of finished spans buffered before new spans are dropped.
+	QueueSize int `mapstructure:"queue_size"`
+	// FlushIntervalMs is the longest time a finished span waits in the queue before being exported.
+	FlushIntervalMs int `mapstructure:"flush_interval_ms"`
+}
+
+func (cfg *Tracing) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.SamplingRate < 0 || cfg.SamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sampling_rate must be between 0 and 1. Got %f", cfg.SamplingRate))
+	}
+	if _, err := url.ParseRequestURI(cfg.OTLP.Endpoint); err != nil {
+		errs = append(errs, fmt.Errorf("tracing.otlp.endpoint must be a valid URL. Got %q", cfg.OTLP.Endpoint))
+	}
+	if cfg.OTLP.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("tracing.otlp.timeout_ms must be positive. Got %d", cfg.OTLP.TimeoutMs))
+	}
+	if cfg.OTLP.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("tracing.otlp.batch_size must be positive. Got %d", cfg.OTLP.BatchSize))
+	}
+	if cfg.OTLP.QueueSize < cfg.OTLP.BatchSize {
+		errs = append(errs, fmt.Errorf("tracing.otlp.queue_size must be at least tracing.otlp.batch_size. Got %d", cfg.OTLP.QueueSize))
+	}
+	if cfg.OTLP.FlushIntervalMs <= 0 {
+		errs = append(errs, fmt.Errorf("tracing.otlp.flush_interval_ms must be positive. Got %d", cfg.OTLP.FlushIntervalMs))
+	}
+	return errs
+}
+
+func (cfg *TracingOTLP) Timeout() time.Duration {
+	return time.Duration(cfg.TimeoutMs) * time.Millisecond
+}
+
+func (cfg *TracingOTLP) FlushInterval() time.Duration {
+	return time.Duration(cfg.FlushIntervalMs) * time.Millisecond
+}
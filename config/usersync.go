@@ -1,14 +1,152 @@
 package config
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
 // UserSync specifies the static global user sync configuration.
 type UserSync struct {
 	Cooperative    UserSyncCooperative `mapstructure:"coop_sync"`
 	ExternalURL    string              `mapstructure:"external_url"`
 	RedirectURL    string              `mapstructure:"redirect_url"`
 	PriorityGroups [][]string          `mapstructure:"priority_groups"`
+	UIDStore       UIDStore            `mapstructure:"uid_store"`
 }
 
 // UserSyncCooperative specifies the static global default cooperative cookie sync
 type UserSyncCooperative struct {
 	EnabledByDefault bool `mapstructure:"default"`
 }
+
+const (
+	UIDStoreCookie = "cookie"
+	UIDStoreMemory = "memory"
+	UIDStoreFile   = "file"
+	UIDStoreRedis  = "redis"
+)
+
+// UIDStore specifies where the bidder UIDs of the users are kept. With a server-side store, the uids cookie
+// only carries a signed PBS user key and the UIDs are kept for host_cookie.ttl_days.
+type UIDStore struct {
+	// Type is either "cookie" (the default, the UIDs are kept in the uids cookie itself), "memory", "file" or "redis".
+	Type string `mapstructure:"type"`
+	// SigningKey signs the user key carried by the uids cookie, required by the server-side stores.
+	SigningKey string `mapstructure:"signing_key"`
+	// TimeoutMs bounds each read or write of the store.
+	TimeoutMs int            `mapstructure:"timeout_ms"`
+	Memory    MemoryUIDStore `mapstructure:"memory"`
+	File      FileUIDStore   `mapstructure:"file"`
+	Redis     RedisCache     `mapstructure:"redis"`
+}
+
+// MemoryUIDStore keeps the UIDs in memory, they are lost on restart and not shared between the PBS instances.
+type MemoryUIDStore struct {
+	// MaxEntries is the number of users kept, the expired ones then arbitrary ones are evicted when full.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// FileUIDStore keeps the UIDs of each user in a file of the directory.
+type FileUIDStore struct {
+	Path string `mapstructure:"path"`
+}
+
+func (cfg *UIDStore) validate(errs []error) []error {
+	switch cfg.Type {
+	case "", UIDStoreCookie:
+		return errs
+	case UIDStoreMemory:
+		if cfg.Memory.MaxEntries <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.memory.max_entries must be > 0. Got %d", cfg.Memory.MaxEntries))
+		}
+	case UIDStoreFile:
+		if cfg.File.Path == "" {
+			errs = append(errs, errors.New("user_sync.uid_store.file.path must be set for the file store"))
+		}
+	case UIDStoreRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, errors.New("user_sync.uid_store.redis.address must be set for the redis store"))
+		}
+		if cfg.Redis.PoolSize <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.redis.pool_size must be > 0. Got %d", cfg.Redis.PoolSize))
+		}
+		if cfg.Redis.DialTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.redis.dial_timeout_ms must be > 0. Got %d", cfg.Redis.DialTimeout))
+		}
+		if cfg.Redis.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("user_sync.uid_store.redis.timeout_ms must be > 0. Got %d", cfg.Redis.Timeout))
+		}
+	default:
+		return append(errs, fmt.Errorf("user_sync.uid_store.type must be one of [%s, %s, %s, %s]. Got %q", UIDStoreCookie, UIDStoreMemory, UIDStoreFile, UIDStoreRedis, cfg.Type))
+	}
+
+	if cfg.SigningKey == "" {
+		errs = append(errs, errors.New("user_sync.uid_store.signing_key must be set for a server-side store"))
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.uid_store.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	return errs
+}
+
+const (
+	CookieProtectionNone    = "none"
+	CookieProtectionEncrypt = "encrypt"
+	CookieProtectionSign    = "sign"
+)
+
+// CookieKeyRing specifies the keys protecting the UIDs held by the uids cookie. The first key protects the cookies
+// written and all of them are accepted, so a key is rotated by adding the new key first then removing the old one
+// once the cookies it protects have expired.
+type CookieKeyRing struct {
+	// Mode is either "none" (the default, the UIDs are readable base64 JSON), "encrypt" (AES-256-GCM) or "sign" (HMAC-SHA256).
+	Mode string      `mapstructure:"mode"`
+	Keys []CookieKey `mapstructure:"keys"`
+	// AcceptPlaintext accepts the unprotected cookies written before the mode was set, for a migration window.
+	AcceptPlaintext bool `mapstructure:"accept_plaintext"`
+}
+
+// CookieKey is a key of the CookieKeyRing
+type CookieKey struct {
+	// ID identifies the key in the cookies it protects.
+	ID string `mapstructure:"id"`
+	// Secret is the base64 encoded key, 32 bytes long to encrypt and at least 16 bytes long to sign.
+	Secret string `mapstructure:"secret"`
+}
+
+var validCookieKeyID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (cfg *CookieKeyRing) validate(errs []error) []error {
+	switch cfg.Mode {
+	case "", CookieProtectionNone:
+		return errs
+	case CookieProtectionEncrypt, CookieProtectionSign:
+	default:
+		return append(errs, fmt.Errorf("host_cookie.key_ring.mode must be one of [%s, %s, %s]. Got %q", CookieProtectionNone, CookieProtectionEncrypt, CookieProtectionSign, cfg.Mode))
+	}
+
+	if len(cfg.Keys) == 0 {
+		return append(errs, fmt.Errorf("host_cookie.key_ring.keys must not be empty for the %s mode", cfg.Mode))
+	}
+	ids := make(map[string]struct{}, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		if !validCookieKeyID.MatchString(key.ID) {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].id must only contain letters, digits, '-' and '_'. Got %q", i, key.ID))
+		} else if _, duplicate := ids[key.ID]; duplicate {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].id %q is duplicated", i, key.ID))
+		}
+		ids[key.ID] = struct{}{}
+
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].secret must be base64 encoded: %v", i, err))
+		} else if cfg.Mode == CookieProtectionEncrypt && len(secret) != 32 {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].secret must be 32 bytes long to encrypt. Got %d", i, len(secret)))
+		} else if cfg.Mode == CookieProtectionSign && len(secret) < 16 {
+			errs = append(errs, fmt.Errorf("host_cookie.key_ring.keys[%d].secret must be at least 16 bytes long to sign. Got %d", i, len(secret)))
+		}
+	}
+	return errs
+}
@@ -1,27 +1,41 @@
 package currency
 
 import (
-	"fmt"
-	"io"
+	"errors"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/prebid/prebid-server/v3/errortypes"
-	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/timeutil"
 )
 
 // RateConverter holds the currencies conversion rates dictionary
 type RateConverter struct {
-	httpClient          httpClient
-	staleRatesThreshold time.Duration
-	syncSourceURL       string
-	rates               atomic.Value // Should only hold Rates struct
-	lastUpdated         atomic.Value // Should only hold time.Time
-	constantRates       Conversions
-	time                timeutil.Time
+	sources       []*ratesSourceState
+	snapshotPath  string
+	mutex         sync.Mutex   // Guards the sources states, never held while fetching
+	rates         atomic.Value // Should only hold Rates struct
+	lastUpdated   atomic.Value // Should only hold time.Time
+	activeSource  atomic.Value // Should only hold string
+	constantRates Conversions
+	time          timeutil.Time
+}
+
+// ratesSourceState holds the last rates fetched from a source
+type ratesSourceState struct {
+	RatesSource
+	rates       *Rates
+	lastUpdated time.Time
+}
+
+// RatesSourceInfo describes a source of the converter
+type RatesSourceInfo struct {
+	Source      string    `json:"source"`
+	Active      bool      `json:"active"`
+	Stale       bool      `json:"stale"`
+	LastUpdated time.Time `json:"lastUpdated"`
 }
 
 // NewRateConverter returns a new RateConverter
@@ -30,66 +44,73 @@ func NewRateConverter(
 	syncSourceURL string,
 	staleRatesThreshold time.Duration,
 ) *RateConverter {
-	return &RateConverter{
-		httpClient:          httpClient,
-		staleRatesThreshold: staleRatesThreshold,
-		syncSourceURL:       syncSourceURL,
-		rates:               atomic.Value{},
-		lastUpdated:         atomic.Value{},
-		constantRates:       NewConstantRates(),
-		time:                &timeutil.RealTime{},
-	}
+	return NewMultiSourceRateConverter([]RatesSource{
+		{
+			Fetcher:             NewHTTPRatesFetcher(httpClient, syncSourceURL),
+			StaleRatesThreshold: staleRatesThreshold,
+		},
+	}, "")
 }
 
-// fetch allows to retrieve the currencies rates from the syncSourceURL provided
-func (rc *RateConverter) fetch() (*Rates, error) {
-	request, err := http.NewRequest("GET", rc.syncSourceURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := rc.httpClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.StatusCode >= 400 {
-		message := fmt.Sprintf("The currency rates request failed with status code %d", response.StatusCode)
-		return nil, &errortypes.BadServerResponse{Message: message}
-	}
-
-	defer response.Body.Close()
-
-	bytesJSON, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
+// NewMultiSourceRateConverter returns a new RateConverter serving the rates of the first source having fresh rates,
+// sources are ordered by priority. When snapshotPath is set, the last fetched rates are persisted to the file and
+// loaded back on creation so the rates are available before the first fetch.
+func NewMultiSourceRateConverter(sources []RatesSource, snapshotPath string) *RateConverter {
+	rc := &RateConverter{
+		sources:       make([]*ratesSourceState, 0, len(sources)),
+		snapshotPath:  snapshotPath,
+		rates:         atomic.Value{},
+		lastUpdated:   atomic.Value{},
+		activeSource:  atomic.Value{},
+		constantRates: NewConstantRates(),
+		time:          &timeutil.RealTime{},
 	}
-
-	updatedRates := &Rates{}
-	err = jsonutil.UnmarshalValid(bytesJSON, updatedRates)
-	if err != nil {
-		return nil, err
+	for _, source := range sources {
+		rc.sources = append(rc.sources, &ratesSourceState{RatesSource: source})
 	}
-
-	return updatedRates, err
+	rc.loadSnapshot()
+	return rc
 }
 
-// Update updates the internal currencies rates from remote sources
+// update updates the internal currencies rates from the first source having fresh rates,
+// it falls back to constant rates when none has
 func (rc *RateConverter) update() error {
-	rates, err := rc.fetch()
-	if err == nil {
-		rc.rates.Store(rates)
-		rc.lastUpdated.Store(rc.time.Now())
-	} else {
-		if rc.checkStaleRates() {
-			rc.clearRates()
-			glog.Errorf("Error updating conversion rates, falling back to constant rates: %v", err)
-		} else {
-			glog.Errorf("Error updating conversion rates: %v", err)
+	var errs []error
+	for _, source := range rc.sources {
+		// the rates are fetched without holding the lock, so a slow source doesn't block the readers of the sources states
+		rates, err := source.Fetcher.Fetch()
+		if err == nil {
+			rc.mutex.Lock()
+			source.rates = rates
+			source.lastUpdated = rc.time.Now()
+			rc.activate(source)
+			snapshot := newRatesSnapshot(source)
+			rc.mutex.Unlock()
+
+			rc.saveSnapshot(snapshot)
+			return errors.Join(errs...)
+		}
+
+		errs = append(errs, err)
+		rc.mutex.Lock()
+		fresh := source.rates != nil && !rc.isStale(source)
+		if fresh {
+			rc.activate(source)
 		}
+		rc.mutex.Unlock()
+
+		if fresh {
+			glog.Errorf("Error updating conversion rates from %s: %v", source.Fetcher.Source(), err)
+			return errors.Join(errs...)
+		}
+		glog.Errorf("Error updating conversion rates from %s, falling back to the next source: %v", source.Fetcher.Source(), err)
 	}
 
-	return err
+	if rc.ActiveSource() != "" {
+		glog.Errorf("No fresh conversion rates, falling back to constant rates")
+	}
+	rc.clearRates()
+	return errors.Join(errs...)
 }
 
 func (rc *RateConverter) Run() error {
@@ -104,6 +125,14 @@ func (rc *RateConverter) LastUpdated() time.Time {
 	return time.Time{}
 }
 
+// ActiveSource returns the source of the rates served, empty when the constant rates are served
+func (rc *RateConverter) ActiveSource() string {
+	if activeSource := rc.activeSource.Load(); activeSource != nil {
+		return activeSource.(string)
+	}
+	return ""
+}
+
 // Rates returns current conversions rates
 func (rc *RateConverter) Rates() Conversions {
 	// atomic.Value field rates is an empty interface and will be of type *Rates the first time rates are stored
@@ -114,35 +143,57 @@ func (rc *RateConverter) Rates() Conversions {
 	return rc.constantRates
 }
 
+// activate serves the rates of the source
+func (rc *RateConverter) activate(source *ratesSourceState) {
+	rc.rates.Store(source.rates)
+	rc.lastUpdated.Store(source.lastUpdated)
+	rc.activeSource.Store(source.Fetcher.Source())
+}
+
 // clearRates sets the rates to nil
 func (rc *RateConverter) clearRates() {
 	// atomic.Value field rates must be of type *Rates so we cast nil to that type
 	rc.rates.Store((*Rates)(nil))
+	rc.activeSource.Store("")
 }
 
-// checkStaleRates checks if loaded third party conversion rates are stale
-func (rc *RateConverter) checkStaleRates() bool {
-	if rc.staleRatesThreshold <= 0 {
+// isStale checks if the rates fetched from the source are stale
+func (rc *RateConverter) isStale(source *ratesSourceState) bool {
+	if source.StaleRatesThreshold <= 0 || source.lastUpdated.IsZero() {
 		return false
 	}
 
-	currentTime := rc.time.Now().UTC()
-	if lastUpdated := rc.lastUpdated.Load(); lastUpdated != nil {
-		delta := currentTime.Sub(lastUpdated.(time.Time).UTC())
-		if delta.Seconds() > rc.staleRatesThreshold.Seconds() {
-			return true
-		}
-	}
-	return false
+	delta := rc.time.Now().UTC().Sub(source.lastUpdated.UTC())
+	return delta.Seconds() > source.StaleRatesThreshold.Seconds()
 }
 
-// GetInfo returns setup information about the converter
+// GetInfo returns setup information about the converter, the additional info lists the state of every source
 func (rc *RateConverter) GetInfo() ConverterInfo {
 	var rates *map[string]map[string]float64 = rc.Rates().GetRates()
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	activeSource := rc.ActiveSource()
+	source := activeSource
+	sourcesInfo := make([]RatesSourceInfo, 0, len(rc.sources))
+	for _, state := range rc.sources {
+		if source == "" {
+			source = state.Fetcher.Source()
+		}
+		sourcesInfo = append(sourcesInfo, RatesSourceInfo{
+			Source:      state.Fetcher.Source(),
+			Active:      state.Fetcher.Source() == activeSource,
+			Stale:       rc.isStale(state),
+			LastUpdated: state.lastUpdated,
+		})
+	}
+
 	return converterInfo{
-		source:      rc.syncSourceURL,
-		lastUpdated: rc.LastUpdated(),
-		rates:       rates,
+		source:         source,
+		lastUpdated:    rc.LastUpdated(),
+		rates:          rates,
+		additionalInfo: sourcesInfo,
 	}
 }
 
@@ -4,6 +4,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -293,6 +295,163 @@ func TestRatesAreNeverConsideredStale(t *testing.T) {
 	assert.Equal(t, initialFakeTime, currencyConverter.LastUpdated(), "LastUpdated should be set")
 }
 
+func TestMultiSourceFallback(t *testing.T) {
+	primaryRates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})
+	secondaryRates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.7}})
+	staticRates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.6}})
+	primary := &mockRatesFetcher{source: "primary", rates: primaryRates}
+	secondary := &mockRatesFetcher{source: "secondary", rates: secondaryRates}
+	static := &mockRatesFetcher{source: StaticRatesSource, rates: staticRates}
+
+	initialFakeTime := time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)
+	fakeTime := &FakeTime{time: initialFakeTime}
+	currencyConverter := NewMultiSourceRateConverter([]RatesSource{
+		{Fetcher: primary, StaleRatesThreshold: time.Minute},
+		{Fetcher: secondary, StaleRatesThreshold: time.Hour},
+		{Fetcher: static},
+	}, "")
+	currencyConverter.time = fakeTime
+
+	// Primary source is fetched
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+
+	// Primary source fails but its rates are still fresh
+	primary.err = errFetch
+	fakeTime.time = initialFakeTime.Add(30 * time.Second)
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+	assert.Equal(t, initialFakeTime, currencyConverter.LastUpdated())
+
+	// Primary rates get stale, the secondary source is fetched
+	fakeTime.time = initialFakeTime.Add(2 * time.Minute)
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, secondaryRates, currencyConverter.Rates())
+	assert.Equal(t, "secondary", currencyConverter.ActiveSource())
+	assert.Equal(t, fakeTime.time, currencyConverter.LastUpdated())
+
+	// Secondary source fails too, its rates are still fresh
+	secondary.err = errFetch
+	fakeTime.time = initialFakeTime.Add(30 * time.Minute)
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, secondaryRates, currencyConverter.Rates())
+
+	// Secondary rates get stale, the static rates are served
+	fakeTime.time = initialFakeTime.Add(3 * time.Hour)
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, staticRates, currencyConverter.Rates())
+	assert.Equal(t, StaticRatesSource, currencyConverter.ActiveSource())
+
+	// Primary source recovers
+	primary.err = nil
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, primaryRates, currencyConverter.Rates())
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+
+	info := currencyConverter.GetInfo()
+	assert.Equal(t, "primary", info.Source())
+	assert.Equal(t, []RatesSourceInfo{
+		{Source: "primary", Active: true, Stale: false, LastUpdated: fakeTime.time},
+		{Source: "secondary", Active: false, Stale: true, LastUpdated: initialFakeTime.Add(2 * time.Minute)},
+		{Source: StaticRatesSource, Active: false, Stale: false, LastUpdated: initialFakeTime.Add(3 * time.Hour)},
+	}, info.AdditionalInfo())
+}
+
+func TestMultiSourceNoFreshSource(t *testing.T) {
+	primary := &mockRatesFetcher{source: "primary", rates: NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})}
+	initialFakeTime := time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)
+	fakeTime := &FakeTime{time: initialFakeTime}
+	currencyConverter := NewMultiSourceRateConverter([]RatesSource{
+		{Fetcher: primary, StaleRatesThreshold: time.Minute},
+		{Fetcher: &mockRatesFetcher{source: "secondary", err: errFetch}, StaleRatesThreshold: time.Minute},
+	}, "")
+	currencyConverter.time = fakeTime
+
+	assert.NoError(t, currencyConverter.Run())
+
+	primary.err = errFetch
+	fakeTime.time = initialFakeTime.Add(2 * time.Minute)
+	err := currencyConverter.Run()
+	assert.ErrorIs(t, err, errFetch)
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates(), "Rates should return constant rates")
+	assert.Equal(t, "", currencyConverter.ActiveSource())
+	assert.Equal(t, "primary", currencyConverter.GetInfo().Source(), "the first source should be reported when none is active")
+}
+
+// blockingRatesFetcher blocks every fetch until released
+type blockingRatesFetcher struct {
+	mockRatesFetcher
+	fetching chan struct{}
+	release  chan struct{}
+}
+
+func (f *blockingRatesFetcher) Fetch() (*Rates, error) {
+	f.fetching <- struct{}{}
+	<-f.release
+	return f.mockRatesFetcher.Fetch()
+}
+
+func TestGetInfoNotBlockedByFetch(t *testing.T) {
+	fetcher := &blockingRatesFetcher{
+		mockRatesFetcher: mockRatesFetcher{source: "primary", rates: NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})},
+		fetching:         make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	currencyConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: fetcher}}, "")
+
+	done := make(chan error)
+	go func() { done <- currencyConverter.Run() }()
+	<-fetcher.fetching
+
+	info := make(chan ConverterInfo)
+	go func() { info <- currencyConverter.GetInfo() }()
+	select {
+	case converterInfo := <-info:
+		assert.Equal(t, "primary", converterInfo.Source())
+	case <-time.After(time.Second):
+		t.Fatal("GetInfo should not wait for the fetch of the rates")
+	}
+
+	close(fetcher.release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+}
+
+func TestRatesSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "rates.json")
+	rates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})
+	primary := &mockRatesFetcher{source: "primary", rates: rates}
+
+	currencyConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: primary, StaleRatesThreshold: time.Hour}}, snapshotPath)
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates(), "no snapshot should be loaded")
+	assert.NoError(t, currencyConverter.Run())
+	fetchedAt := currencyConverter.LastUpdated()
+
+	// A new converter serves the snapshot before fetching
+	primary.err = errFetch
+	restartedConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: primary, StaleRatesThreshold: time.Hour}}, snapshotPath)
+	assert.Equal(t, rates, restartedConverter.Rates())
+	assert.Equal(t, "primary", restartedConverter.ActiveSource())
+	assert.True(t, fetchedAt.Equal(restartedConverter.LastUpdated()))
+	assert.Error(t, restartedConverter.Run())
+	assert.Equal(t, rates, restartedConverter.Rates(), "the snapshot rates should be kept while fresh")
+
+	// The snapshot of a source no longer configured is ignored
+	otherConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: &mockRatesFetcher{source: "other"}}}, snapshotPath)
+	assert.Equal(t, &ConstantRates{}, otherConverter.Rates())
+
+	// A stale snapshot is not served
+	staleConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: primary, StaleRatesThreshold: time.Nanosecond}}, snapshotPath)
+	assert.Equal(t, &ConstantRates{}, staleConverter.Rates())
+
+	// An invalid snapshot is ignored
+	assert.NoError(t, os.WriteFile(snapshotPath, []byte("invalid"), 0644))
+	invalidConverter := NewMultiSourceRateConverter([]RatesSource{{Fetcher: primary, StaleRatesThreshold: time.Hour}}, snapshotPath)
+	assert.Equal(t, &ConstantRates{}, invalidConverter.Rates())
+}
+
 func TestRace(t *testing.T) {
 	// This test is checking that no race conditions appear in rate converter.
 	// It simulate multiple clients (in different goroutines) asking for updates
@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/fileutil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// ratesSnapshot holds the last rates fetched, persisted so they are available right after a restart
+type ratesSnapshot struct {
+	Source      string                        `json:"source"`
+	FetchedAt   time.Time                     `json:"fetchedAt"`
+	Conversions map[string]map[string]float64 `json:"conversions"`
+}
+
+// loadSnapshot restores the rates of the source the snapshot was fetched from and serves them if still fresh
+func (rc *RateConverter) loadSnapshot() {
+	if rc.snapshotPath == "" {
+		return
+	}
+
+	content, err := os.ReadFile(rc.snapshotPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("Unable to read the currency rates snapshot %s: %v", rc.snapshotPath, err)
+		}
+		return
+	}
+
+	var snapshot ratesSnapshot
+	if err := jsonutil.UnmarshalValid(content, &snapshot); err != nil {
+		glog.Errorf("Invalid currency rates snapshot %s: %v", rc.snapshotPath, err)
+		return
+	}
+
+	for _, source := range rc.sources {
+		if source.Fetcher.Source() != snapshot.Source {
+			continue
+		}
+		source.rates = NewRates(snapshot.Conversions)
+		source.lastUpdated = snapshot.FetchedAt
+		if !rc.isStale(source) {
+			rc.activate(source)
+		}
+		return
+	}
+}
+
+// newRatesSnapshot returns the snapshot of the rates of the source
+func newRatesSnapshot(source *ratesSourceState) ratesSnapshot {
+	return ratesSnapshot{
+		Source:      source.Fetcher.Source(),
+		FetchedAt:   source.lastUpdated,
+		Conversions: source.rates.Conversions,
+	}
+}
+
+// saveSnapshot persists the rates of the snapshot, replacing the file atomically
+func (rc *RateConverter) saveSnapshot(snapshot ratesSnapshot) {
+	if rc.snapshotPath == "" || snapshot.Source == StaticRatesSource {
+		return
+	}
+
+	content, err := jsonutil.Marshal(snapshot)
+	if err != nil {
+		glog.Errorf("Unable to marshal the currency rates snapshot: %v", err)
+		return
+	}
+
+	if err := fileutil.WriteFileAtomically(rc.snapshotPath, content); err != nil {
+		glog.Errorf("Unable to save the currency rates snapshot %s: %v", rc.snapshotPath, err)
+	}
+}
@@ -0,0 +1,128 @@
+package currency
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// StaticRatesSource is the name of the source serving the rates set in the configuration
+const StaticRatesSource = "static"
+
+// RatesFetcher retrieves the currencies rates from a source
+type RatesFetcher interface {
+	// Source identifies where the rates are fetched from
+	Source() string
+	Fetch() (*Rates, error)
+}
+
+// RatesSource is a source of currencies rates along with the duration its rates are considered fresh.
+// A non positive StaleRatesThreshold means the rates never get stale.
+type RatesSource struct {
+	Fetcher             RatesFetcher
+	StaleRatesThreshold time.Duration
+}
+
+type httpRatesFetcher struct {
+	httpClient httpClient
+	url        string
+}
+
+// NewHTTPRatesFetcher returns a fetcher retrieving the rates from the URL
+func NewHTTPRatesFetcher(httpClient httpClient, url string) RatesFetcher {
+	return &httpRatesFetcher{
+		httpClient: httpClient,
+		url:        url,
+	}
+}
+
+func (f *httpRatesFetcher) Source() string {
+	return f.url
+}
+
+func (f *httpRatesFetcher) Fetch() (*Rates, error) {
+	request, err := http.NewRequest("GET", f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := f.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 400 {
+		message := fmt.Sprintf("The currency rates request failed with status code %d", response.StatusCode)
+		return nil, &errortypes.BadServerResponse{Message: message}
+	}
+
+	defer response.Body.Close()
+
+	bytesJSON, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRates(bytesJSON)
+}
+
+type fileRatesFetcher struct {
+	path string
+}
+
+// NewFileRatesFetcher returns a fetcher reading the rates from a local file, in the same format as the remote rates
+func NewFileRatesFetcher(path string) RatesFetcher {
+	return &fileRatesFetcher{path: path}
+}
+
+func (f *fileRatesFetcher) Source() string {
+	return "file://" + f.path
+}
+
+func (f *fileRatesFetcher) Fetch() (*Rates, error) {
+	bytesJSON, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRates(bytesJSON)
+}
+
+type staticRatesFetcher struct {
+	conversions map[string]map[string]float64
+}
+
+// NewStaticRatesFetcher returns a fetcher always serving the conversions provided.
+// The currency codes are upper cased so they can be written in any case.
+func NewStaticRatesFetcher(conversions map[string]map[string]float64) RatesFetcher {
+	upperConversions := make(map[string]map[string]float64, len(conversions))
+	for from, rates := range conversions {
+		upperRates := make(map[string]float64, len(rates))
+		for to, rate := range rates {
+			upperRates[strings.ToUpper(to)] = rate
+		}
+		upperConversions[strings.ToUpper(from)] = upperRates
+	}
+	return &staticRatesFetcher{conversions: upperConversions}
+}
+
+func (f *staticRatesFetcher) Source() string {
+	return StaticRatesSource
+}
+
+func (f *staticRatesFetcher) Fetch() (*Rates, error) {
+	return NewRates(f.conversions), nil
+}
+
+func parseRates(bytesJSON []byte) (*Rates, error) {
+	rates := &Rates{}
+	if err := jsonutil.UnmarshalValid(bytesJSON, rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
@@ -0,0 +1,62 @@
+package currency
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRatesFetcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rates.json")
+	assert.NoError(t, os.WriteFile(path, getMockRates(), 0644))
+
+	fetcher := NewFileRatesFetcher(path)
+	assert.Equal(t, "file://"+path, fetcher.Source())
+
+	rates, err := fetcher.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.77208}, "GBP": {"USD": 1.2952}}, rates.Conversions)
+
+	_, err = NewFileRatesFetcher(filepath.Join(dir, "missing.json")).Fetch()
+	assert.Error(t, err)
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte(`{"conversions": 1}`), 0644))
+	_, err = NewFileRatesFetcher(invalidPath).Fetch()
+	assert.Error(t, err)
+}
+
+func TestStaticRatesFetcher(t *testing.T) {
+	fetcher := NewStaticRatesFetcher(map[string]map[string]float64{"usd": {"eur": 0.9}})
+	assert.Equal(t, StaticRatesSource, fetcher.Source())
+
+	rates, err := fetcher.Fetch()
+	assert.NoError(t, err)
+	rate, err := rates.GetRate("USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.9, rate)
+}
+
+// mockRatesFetcher returns the rates or the error set
+type mockRatesFetcher struct {
+	source string
+	rates  *Rates
+	err    error
+}
+
+func (f *mockRatesFetcher) Source() string {
+	return f.source
+}
+
+func (f *mockRatesFetcher) Fetch() (*Rates, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.rates, nil
+}
+
+var errFetch = errors.New("fetch failed")
@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// BidderHealthReporter reports the health vectors of the bidders.
+type BidderHealthReporter interface {
+	Vectors() map[string][]exchange.BidderHealthVector
+}
+
+// BidderHealthEndpoints serves the admin endpoints exposing the health of the bidders used to throttle them:
+//
+//	GET /bidders/health           returns the health vectors of all bidders
+//	GET /bidders/health/{bidder}  returns the health vectors of a bidder
+//
+// A bidder has a health vector per combination of the values of the http_client.throttle.dimensions, or a single
+// one without dimensions. The bidders are omitted if throttling is disabled.
+type BidderHealthEndpoints struct {
+	reporter BidderHealthReporter
+}
+
+// NewBidderHealthEndpoints returns the admin endpoints of the health of the bidders.
+func NewBidderHealthEndpoints(reporter BidderHealthReporter) *BidderHealthEndpoints {
+	return &BidderHealthEndpoints{reporter: reporter}
+}
+
+// HandleList returns the health vectors of all bidders.
+func (e *BidderHealthEndpoints) HandleList(w http.ResponseWriter, _ *http.Request) {
+	writeBidderHealthResponse(w, e.reporter.Vectors())
+}
+
+// HandleBidder returns the health vectors of a bidder.
+func (e *BidderHealthEndpoints) HandleBidder(w http.ResponseWriter, r *http.Request) {
+	vectors, ok := e.reporter.Vectors()[r.PathValue("bidder")]
+	if !ok {
+		http.Error(w, "Bidder not found or its health is not tracked.", http.StatusNotFound)
+		return
+	}
+	writeBidderHealthResponse(w, vectors)
+}
+
+func writeBidderHealthResponse(w http.ResponseWriter, response interface{}) {
+	body, err := jsonutil.Marshal(response)
+	if err != nil {
+		glog.Errorf("/bidders/health Critical error when trying to marshal the response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,92 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBidderHealthReporter struct {
+	vectors map[string][]exchange.BidderHealthVector
+}
+
+func (r *fakeBidderHealthReporter) Vectors() map[string][]exchange.BidderHealthVector {
+	return r.vectors
+}
+
+func newTestBidderHealthReporter() *fakeBidderHealthReporter {
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	openedAt := time.Date(2024, 5, 1, 11, 59, 0, 0, time.UTC)
+	return &fakeBidderHealthReporter{
+		vectors: map[string][]exchange.BidderHealthVector{
+			"appnexus": {
+				{Dimensions: map[string]string{"account": "1234"}, Score: 0.9, State: metrics.AdapterHealthOpen, CheckedAt: &checkedAt, OpenedAt: &openedAt},
+				{Dimensions: map[string]string{"account": "5678"}, Score: 0, State: metrics.AdapterHealthClosed, CheckedAt: &checkedAt},
+			},
+			"rubicon": {
+				{Score: 0.25, State: metrics.AdapterHealthClosed},
+			},
+		},
+	}
+}
+
+func TestBidderHealthHandleList(t *testing.T) {
+	e := NewBidderHealthEndpoints(newTestBidderHealthReporter())
+
+	w := httptest.NewRecorder()
+	e.HandleList(w, httptest.NewRequest(http.MethodGet, "/bidders/health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"appnexus": [
+			{"dimensions":{"account":"1234"},"score":0.9,"state":"open","checkedAt":"2024-05-01T12:00:00Z","openedAt":"2024-05-01T11:59:00Z"},
+			{"dimensions":{"account":"5678"},"score":0,"state":"closed","checkedAt":"2024-05-01T12:00:00Z"}
+		],
+		"rubicon": [
+			{"score":0.25,"state":"closed"}
+		]
+	}`, w.Body.String())
+}
+
+func TestBidderHealthHandleBidder(t *testing.T) {
+	testCases := []struct {
+		description    string
+		bidder         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "found",
+			bidder:         "rubicon",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"score":0.25,"state":"closed"}]`,
+		},
+		{
+			description:    "not_found",
+			bidder:         "unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := NewBidderHealthEndpoints(newTestBidderHealthReporter())
+
+			r := httptest.NewRequest(http.MethodGet, "/bidders/health/"+test.bidder, nil)
+			r.SetPathValue("bidder", test.bidder)
+			w := httptest.NewRecorder()
+			e.HandleBidder(w, r)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
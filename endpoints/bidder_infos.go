@@ -0,0 +1,151 @@
+package endpoints
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "REDACTED"
+
+// BidderInfoReloader holds the bidder infos in effect and reloads them from disk.
+type BidderInfoReloader interface {
+	BidderInfos() config.BidderInfos
+	Status() exchange.BidderInfoReloadStatus
+	ReloadFromDisk() ([]string, error)
+}
+
+type bidderInfosResponse struct {
+	Status  exchange.BidderInfoReloadStatus `json:"status"`
+	Bidders map[string]interface{}          `json:"bidders"`
+}
+
+type bidderInfosReloadResponse struct {
+	Changed []string `json:"changed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// BidderInfosEndpoints serves the admin endpoints exposing the bidder infos in effect, after the host
+// config overrides and the reloads are applied, and reloading them from disk:
+//
+//	GET  /bidder_infos           returns the reload status and the infos of all bidders
+//	GET  /bidder_infos/{bidder}  returns the info of a bidder
+//	POST /bidder_infos/reload    reloads the bidder info files and returns the bidders which changed
+//
+// The infos use the field names of the bidder info files. Secrets, like auth headers, are redacted.
+type BidderInfosEndpoints struct {
+	reloader      BidderInfoReloader
+	reloadEnabled bool
+}
+
+// NewBidderInfosEndpoints returns the admin endpoints of the bidder infos. Reloads are refused unless
+// reloadEnabled is true.
+func NewBidderInfosEndpoints(reloader BidderInfoReloader, reloadEnabled bool) *BidderInfosEndpoints {
+	return &BidderInfosEndpoints{
+		reloader:      reloader,
+		reloadEnabled: reloadEnabled,
+	}
+}
+
+// HandleList returns the reload status and the infos of all bidders.
+func (e *BidderInfosEndpoints) HandleList(w http.ResponseWriter, _ *http.Request) {
+	infos := e.reloader.BidderInfos()
+	response := bidderInfosResponse{
+		Status:  e.reloader.Status(),
+		Bidders: make(map[string]interface{}, len(infos)),
+	}
+	for name, info := range infos {
+		bidder, err := toBidderInfoResponse(info)
+		if err != nil {
+			glog.Errorf("/bidder_infos Critical error when trying to convert the info of bidder %s: %v", name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		response.Bidders[name] = bidder
+	}
+	writeBidderInfosResponse(w, http.StatusOK, response)
+}
+
+// HandleBidder returns the info of a bidder.
+func (e *BidderInfosEndpoints) HandleBidder(w http.ResponseWriter, r *http.Request) {
+	info, ok := e.reloader.BidderInfos()[r.PathValue("bidder")]
+	if !ok {
+		http.Error(w, "Bidder not found.", http.StatusNotFound)
+		return
+	}
+
+	bidder, err := toBidderInfoResponse(info)
+	if err != nil {
+		glog.Errorf("/bidder_infos Critical error when trying to convert the info of bidder %s: %v", r.PathValue("bidder"), err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeBidderInfosResponse(w, http.StatusOK, bidder)
+}
+
+// HandleReload reloads the bidder info files. The current bidder infos are kept if the reload is refused.
+func (e *BidderInfosEndpoints) HandleReload(w http.ResponseWriter, _ *http.Request) {
+	if !e.reloadEnabled {
+		http.Error(w, "Bidder info reload is disabled. Set bidder_info_reload.enabled to true to allow it.", http.StatusForbidden)
+		return
+	}
+
+	changed, err := e.reloader.ReloadFromDisk()
+	response := bidderInfosReloadResponse{Changed: changed}
+	if response.Changed == nil {
+		response.Changed = []string{}
+	}
+	if err != nil {
+		response.Errors = e.reloader.Status().Errors
+		writeBidderInfosResponse(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+	writeBidderInfosResponse(w, http.StatusOK, response)
+}
+
+// toBidderInfoResponse converts the bidder info to a generic value with the field names of the bidder
+// info files, with its secrets redacted.
+func toBidderInfoResponse(info config.BidderInfo) (interface{}, error) {
+	if info.AppSecret != "" {
+		info.AppSecret = redactedValue
+	}
+	if info.XAPI.Password != "" {
+		info.XAPI.Password = redactedValue
+	}
+	if info.GenericORTB != nil && len(info.GenericORTB.Headers) > 0 {
+		genericORTB := *info.GenericORTB
+		genericORTB.Headers = maps.Clone(genericORTB.Headers)
+		for name := range genericORTB.Headers {
+			genericORTB.Headers[name] = redactedValue
+		}
+		info.GenericORTB = &genericORTB
+	}
+
+	data, err := yaml.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	var response map[string]interface{}
+	if err := yaml.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func writeBidderInfosResponse(w http.ResponseWriter, status int, response interface{}) {
+	body, err := jsonutil.Marshal(response)
+	if err != nil {
+		glog.Errorf("/bidder_infos Critical error when trying to marshal the response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
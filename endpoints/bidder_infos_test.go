@@ -0,0 +1,189 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBidderInfoReloader struct {
+	infos         config.BidderInfos
+	status        exchange.BidderInfoReloadStatus
+	changed       []string
+	err           error
+	reloadedCount int
+}
+
+func (r *fakeBidderInfoReloader) BidderInfos() config.BidderInfos {
+	return r.infos
+}
+
+func (r *fakeBidderInfoReloader) Status() exchange.BidderInfoReloadStatus {
+	return r.status
+}
+
+func (r *fakeBidderInfoReloader) ReloadFromDisk() ([]string, error) {
+	r.reloadedCount++
+	return r.changed, r.err
+}
+
+func newTestBidderInfoReloader() *fakeBidderInfoReloader {
+	reloadedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return &fakeBidderInfoReloader{
+		infos: config.BidderInfos{
+			"appnexus": {
+				Endpoint:    "https://appnexus.com/bid",
+				GVLVendorID: 32,
+			},
+			"audienceNetwork": {
+				Endpoint:  "https://an.facebook.com/bid",
+				AppSecret: "secret",
+			},
+			"genericortb": {
+				Endpoint: "https://ssp.com/bid",
+				XAPI:     config.AdapterXAPI{Username: "user", Password: "password"},
+				GenericORTB: &config.GenericORTBInfo{
+					Headers: map[string]string{"Authorization": "Bearer token"},
+				},
+			},
+		},
+		status: exchange.BidderInfoReloadStatus{ReloadedAt: &reloadedAt},
+	}
+}
+
+func TestBidderInfosHandleList(t *testing.T) {
+	reloader := newTestBidderInfoReloader()
+	e := NewBidderInfosEndpoints(reloader, true)
+
+	w := httptest.NewRecorder()
+	e.HandleList(w, httptest.NewRequest(http.MethodGet, "/bidder_infos", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response struct {
+		Status  json.RawMessage                       `json:"status"`
+		Bidders map[string]map[string]json.RawMessage `json:"bidders"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.JSONEq(t, `{"reloadedAt":"2024-05-01T12:00:00Z"}`, string(response.Status))
+	require.Len(t, response.Bidders, 3)
+
+	assert.JSONEq(t, `"https://appnexus.com/bid"`, string(response.Bidders["appnexus"]["endpoint"]))
+	assert.JSONEq(t, `32`, string(response.Bidders["appnexus"]["gvlVendorID"]))
+
+	assert.JSONEq(t, `"REDACTED"`, string(response.Bidders["audienceNetwork"]["app_secret"]))
+
+	assert.JSONEq(t, `{"username":"user","password":"REDACTED","tracker":""}`, string(response.Bidders["genericortb"]["xapi"]))
+	assert.Contains(t, string(response.Bidders["genericortb"]["genericOrtb"]), `"headers":{"Authorization":"REDACTED"}`)
+	assert.Equal(t, "Bearer token", reloader.infos["genericortb"].GenericORTB.Headers["Authorization"], "the bidder info in effect should be kept")
+}
+
+func TestBidderInfosHandleBidder(t *testing.T) {
+	testCases := []struct {
+		description      string
+		bidder           string
+		expectedStatus   int
+		expectedEndpoint string
+	}{
+		{
+			description:      "found",
+			bidder:           "appnexus",
+			expectedStatus:   http.StatusOK,
+			expectedEndpoint: `"https://appnexus.com/bid"`,
+		},
+		{
+			description:    "not_found",
+			bidder:         "unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := NewBidderInfosEndpoints(newTestBidderInfoReloader(), true)
+
+			r := httptest.NewRequest(http.MethodGet, "/bidder_infos/"+test.bidder, nil)
+			r.SetPathValue("bidder", test.bidder)
+			w := httptest.NewRecorder()
+			e.HandleBidder(w, r)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedEndpoint != "" {
+				var response map[string]json.RawMessage
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.JSONEq(t, test.expectedEndpoint, string(response["endpoint"]))
+			}
+		})
+	}
+}
+
+func TestBidderInfosHandleReload(t *testing.T) {
+	testCases := []struct {
+		description    string
+		reloadEnabled  bool
+		changed        []string
+		err            error
+		status         exchange.BidderInfoReloadStatus
+		expectedStatus int
+		expectedBody   string
+		expectedReload bool
+	}{
+		{
+			description:    "disabled",
+			reloadEnabled:  false,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Bidder info reload is disabled. Set bidder_info_reload.enabled to true to allow it.\n",
+		},
+		{
+			description:    "no_changes",
+			reloadEnabled:  true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"changed":[]}`,
+			expectedReload: true,
+		},
+		{
+			description:    "changes",
+			reloadEnabled:  true,
+			changed:        []string{"appnexus", "rubicon"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"changed":["appnexus","rubicon"]}`,
+			expectedReload: true,
+		},
+		{
+			description:    "refused",
+			reloadEnabled:  true,
+			err:            errors.New("bidder info reload refused"),
+			status:         exchange.BidderInfoReloadStatus{Errors: []string{"bidder pubmatic cannot be added without a restart"}},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"changed":[],"errors":["bidder pubmatic cannot be added without a restart"]}`,
+			expectedReload: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			reloader := &fakeBidderInfoReloader{changed: test.changed, err: test.err, status: test.status}
+			e := NewBidderInfosEndpoints(reloader, test.reloadEnabled)
+
+			w := httptest.NewRecorder()
+			e.HandleReload(w, httptest.NewRequest(http.MethodPost, "/bidder_infos/reload", nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedReload, reloader.reloadedCount == 1)
+			if test.expectedStatus == http.StatusForbidden {
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			} else {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
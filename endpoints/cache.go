@@ -0,0 +1,49 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// NewCacheEndpoint returns the endpoint serving the values PBS cached in its own backend, like Prebid Cache does:
+//
+//	GET /cache?uuid={key}  returns the JSON value, or the XML document of a VAST value
+func NewCacheEndpoint(backend prebid_cache_client.Backend) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		key := r.URL.Query().Get("uuid")
+		if key == "" {
+			http.Error(w, "GET /cache requires the uuid query parameter", http.StatusBadRequest)
+			return
+		}
+
+		entry, found, err := backend.Get(r.Context(), key)
+		if err != nil {
+			glog.Errorf("/cache Failed to get the value of %s: %v", key, err)
+			http.Error(w, "Failed to get the cached value", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "uuid not found", http.StatusNotFound)
+			return
+		}
+
+		if entry.Type == prebid_cache_client.TypeXML {
+			var xml string
+			if err := jsonutil.UnmarshalValid(entry.Data, &xml); err != nil {
+				glog.Errorf("/cache The XML value of %s is not a JSON string: %v", key, err)
+				http.Error(w, "Invalid cached value", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(xml))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(entry.Data)
+	}
+}
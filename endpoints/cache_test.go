@@ -0,0 +1,102 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCacheBackend struct {
+	entries map[string]prebid_cache_client.Entry
+	err     error
+}
+
+func (b *mockCacheBackend) Put(_ context.Context, _ []prebid_cache_client.Entry) error {
+	return nil
+}
+
+func (b *mockCacheBackend) Get(_ context.Context, key string) (prebid_cache_client.Entry, bool, error) {
+	entry, ok := b.entries[key]
+	return entry, ok, b.err
+}
+
+func (b *mockCacheBackend) Shutdown() {}
+
+func TestCacheEndpoint(t *testing.T) {
+	backend := &mockCacheBackend{
+		entries: map[string]prebid_cache_client.Entry{
+			"json-key": {Type: prebid_cache_client.TypeJSON, Data: json.RawMessage(`{"id":"bid"}`)},
+			"xml-key":  {Type: prebid_cache_client.TypeXML, Data: json.RawMessage(`"<VAST version=\"3.0\"></VAST>"`)},
+			"bad-xml":  {Type: prebid_cache_client.TypeXML, Data: json.RawMessage(`{}`)},
+		},
+	}
+
+	testCases := []struct {
+		description         string
+		query               string
+		backendErr          error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			description:         "json",
+			query:               "?uuid=json-key",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"id":"bid"}`,
+		},
+		{
+			description:         "xml",
+			query:               "?uuid=xml-key",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml",
+			expectedBody:        `<VAST version="3.0"></VAST>`,
+		},
+		{
+			description:    "missing_uuid",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "GET /cache requires the uuid query parameter\n",
+		},
+		{
+			description:    "not_found",
+			query:          "?uuid=unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "uuid not found\n",
+		},
+		{
+			description:    "invalid_xml",
+			query:          "?uuid=bad-xml",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Invalid cached value\n",
+		},
+		{
+			description:    "backend_error",
+			query:          "?uuid=json-key",
+			backendErr:     errors.New("redis: server marked as unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Failed to get the cached value\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			backend.err = test.backendErr
+			w := httptest.NewRecorder()
+			NewCacheEndpoint(backend)(w, httptest.NewRequest("GET", "/cache"+test.query, nil), nil)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedBody, w.Body.String())
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -58,7 +58,8 @@ func NewCookieSyncEndpoint(
 	metrics metrics.MetricsEngine,
 	analyticsRunner analytics.Runner,
 	accountsFetcher stored_requests.AccountFetcher,
-	bidders map[string]openrtb_ext.BidderName) HTTPRouterHandler {
+	bidders map[string]openrtb_ext.BidderName,
+	codec usersync.Codec) HTTPRouterHandler {
 
 	bidderHashSet := make(map[string]struct{}, len(bidders))
 	for _, bidder := range bidders {
@@ -79,6 +80,7 @@ func NewCookieSyncEndpoint(
 		pbsAnalytics:    analyticsRunner,
 		accountsFetcher: accountsFetcher,
 		time:            &timeutil.RealTime{},
+		codec:           codec,
 	}
 }
 
@@ -90,6 +92,7 @@ type cookieSyncEndpoint struct {
 	pbsAnalytics    analytics.Runner
 	accountsFetcher stored_requests.AccountFetcher
 	time            timeutil.Time
+	codec           usersync.Codec
 }
 
 func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -100,9 +103,8 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 		c.handleError(w, err, http.StatusBadRequest)
 		return
 	}
-	decoder := usersync.Base64Decoder{}
-
-	cookie := usersync.ReadCookie(r, decoder, &c.config.HostCookie)
+	cookie := usersync.ReadCookie(r, c.codec, &c.config.HostCookie)
+	writeMigratedCookie(w, r, cookie, c.codec, &c.config.HostCookie)
 	usersync.SyncHostCookie(r, cookie, &c.config.HostCookie)
 
 	result := c.chooser.Choose(request, cookie)
@@ -253,6 +255,7 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 
 	privacyPolicies := privacy.Policies{
 		GPPSID: gppSID,
+		GPP:    request.GPP,
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
@@ -75,6 +75,7 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		&analytics,
 		&fetcher,
 		bidders,
+		usersync.Base64Codec{},
 	)
 	result := endpoint.(*cookieSyncEndpoint)
 
@@ -365,6 +366,7 @@ func TestCookieSyncHandle(t *testing.T) {
 
 		endpoint := cookieSyncEndpoint{
 			chooser: FakeChooser{Result: test.givenChooserResult},
+			codec:   usersync.Base64Codec{},
 			config: &config.Configuration{
 				AccountDefaults: config.Account{Disabled: false},
 			},
@@ -566,7 +568,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 					GPPSID:      "6",
 				},
 				gdprSignal: gdpr.SignalNo,
-				policies:   privacy.Policies{GPPSID: []int8{6}},
+				policies:   privacy.Policies{GPPSID: []int8{6}, GPP: "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN"},
 				err:        nil,
 			},
 		},
@@ -635,7 +637,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}}),
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}, GPP: "DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -55,11 +55,11 @@ func newCurrencyRatesInfo(rateConverter rateConverter, fetchingInterval time.Dur
 	return currencyRatesInfo
 }
 
-// NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server.
+// NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server,
+// along with the source they are served from.
 func NewCurrencyRatesEndpoint(rateConverter rateConverter, fetchingInterval time.Duration) http.HandlerFunc {
-	currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
-
 	return func(w http.ResponseWriter, _ *http.Request) {
+		currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
 		jsonOutput, err := jsonutil.Marshal(currencyRateInfo)
 		if err != nil {
 			glog.Errorf("/currency/rates Critical error when trying to marshal currencyRateInfo: %v", err)
@@ -106,6 +106,20 @@ func TestCurrencyRatesEndpoint(t *testing.T) {
 	}
 }
 
+func TestCurrencyRatesEndpointServesActiveSource(t *testing.T) {
+	converter := &rateConverterMock{syncSourceURL: "https://sync.test.com"}
+	handler := NewCurrencyRatesEndpoint(converter, time.Duration(0))
+
+	w := httptest.NewRecorder()
+	handler(w, nil)
+	assert.JSONEq(t, `{"active": true, "source": "https://sync.test.com", "fetchingIntervalNs": 0, "lastUpdated": "0001-01-01T00:00:00Z"}`, w.Body.String())
+
+	converter.syncSourceURL = "file:///etc/pbs/rates.json"
+	w = httptest.NewRecorder()
+	handler(w, nil)
+	assert.JSONEq(t, `{"active": true, "source": "file:///etc/pbs/rates.json", "fetchingIntervalNs": 0, "lastUpdated": "0001-01-01T00:00:00Z"}`, w.Body.String(), "the source should be the one active when requested")
+}
+
 type conversionMock struct {
 	rates *map[string]map[string]float64
 }
@@ -3,6 +3,7 @@ package endpoints
 import (
 	"net/http"
 
+	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/usersync"
@@ -16,9 +17,10 @@ type userSyncs struct {
 
 // NewGetUIDsEndpoint implements the /getuid endpoint which
 // returns all the existing syncs for the user
-func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
+func NewGetUIDsEndpoint(cfg config.HostCookie, codec usersync.Codec) httprouter.Handle {
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		cookie := usersync.ReadCookie(r, usersync.Base64Decoder{}, &cfg)
+		cookie := usersync.ReadCookie(r, codec, &cfg)
+		writeMigratedCookie(w, r, cookie, codec, &cfg)
 		usersync.SyncHostCookie(r, cookie, &cfg)
 
 		userSyncs := new(userSyncs)
@@ -26,3 +28,17 @@ func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
 		json.NewEncoder(w).Encode(userSyncs)
 	})
 }
+
+// writeMigratedCookie writes back the uids cookie whose UIDs were just moved to the UID store,
+// so the cookie carries the user key of the UIDs from now on
+func writeMigratedCookie(w http.ResponseWriter, r *http.Request, cookie *usersync.Cookie, encoder usersync.Encoder, cfg *config.HostCookie) {
+	if !cookie.Migrated() {
+		return
+	}
+	encodedCookie, err := encoder.Encode(cookie)
+	if err != nil {
+		glog.Warningf("Unable to write back a migrated uids cookie: %v", err)
+		return
+	}
+	usersync.WriteCookie(w, encodedCookie, cfg, siteCookieCheck(r.UserAgent()))
+}
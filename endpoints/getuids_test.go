@@ -6,12 +6,14 @@ import (
 	"testing"
 
 	"github.com/prebid/prebid-server/v3/config"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetUIDs(t *testing.T) {
 	req := makeRequest("/getuids", map[string]string{"adnxs": "123", "audienceNetwork": "456"})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, usersync.Base64Codec{})
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -22,7 +24,7 @@ func TestGetUIDs(t *testing.T) {
 
 func TestGetUIDsWithNoSyncs(t *testing.T) {
 	req := makeRequest("/getuids", map[string]string{})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, usersync.Base64Codec{})
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -32,10 +34,36 @@ func TestGetUIDsWithNoSyncs(t *testing.T) {
 
 func TestGetUIDWIthNoCookie(t *testing.T) {
 	req := httptest.NewRequest("GET", "/getuids", nil)
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, usersync.Base64Codec{})
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.JSONEq(t, `{}`, res.Body.String(), "GetUIDs endpoint shouldn't return anything if there doesn't exist a PBS cookie")
 }
+
+func TestGetUIDsWithUIDStore(t *testing.T) {
+	codec, err := usersync.NewCodec(&config.HostCookie{TTL: 90}, &config.UIDStore{SigningKey: "secret", TimeoutMs: 50}, usersync.NewMemoryUIDStore(10), &metricsConf.NilMetricsEngine{})
+	assert.NoError(t, err)
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, codec)
+
+	req := makeRequest("/getuids", map[string]string{"adnxs": "123"})
+	res := httptest.NewRecorder()
+	endpoint(res, req, nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"buyeruids": {"adnxs": "123"}}`, res.Body.String())
+
+	cookies := res.Result().Cookies()
+	if assert.Len(t, cookies, 1, "the migrated cookie should be written back") {
+		assert.Equal(t, "uids", cookies[0].Name)
+
+		req = httptest.NewRequest("GET", "/getuids", nil)
+		req.AddCookie(cookies[0])
+		res = httptest.NewRecorder()
+		endpoint(res, req, nil)
+
+		assert.JSONEq(t, `{"buyeruids": {"adnxs": "123"}}`, res.Body.String(), "the UIDs should be read from the store")
+		assert.Empty(t, res.Result().Cookies())
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -71,6 +72,7 @@ func NewAmpEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidCodec usersync.Codec,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -105,6 +107,7 @@ func NewAmpEndpoint(
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
 		openrtb_ext.NormalizeBidderName,
+		uidCodec,
 	}).AmpAuction), nil
 
 }
@@ -119,6 +122,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	start := time.Now()
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAmp, deps.metricsEngine)
+	hookExecutor.SetTraceContext(r.Context())
 
 	ao := analytics.AmpObject{
 		Status:    http.StatusOK,
@@ -180,7 +184,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	ao.RequestWrapper = reqWrapper
 
-	ctx := context.Background()
+	ctx := tracing.ContextWithSpan(context.Background(), tracing.SpanFromContext(r.Context()))
 	var cancel context.CancelFunc
 	if reqWrapper.TMax > 0 {
 		ctx, cancel = context.WithDeadline(ctx, start.Add(time.Duration(reqWrapper.TMax)*time.Millisecond))
@@ -190,7 +194,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	defer cancel()
 
 	// Read UserSyncs/Cookie from Request
-	usersyncs := usersync.ReadCookie(r, usersync.Base64Decoder{}, &deps.cfg.HostCookie)
+	usersyncs := usersync.ReadCookie(r, deps.cookieDecoder(), &deps.cfg.HostCookie)
 	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
 	if usersyncs.HasAnyLiveSyncs() {
 		labels.CookieFlag = metrics.CookieFlagYes
@@ -230,6 +234,12 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		ao.Errors = append(ao.Errors, acctIDErrs...)
 		return
 	}
+	ao.Account = account
+
+	activityControl = privacy.NewActivityControl(&account.Privacy)
+
+	hookExecutor.SetActivityControl(activityControl)
+	hookExecutor.SetAccount(account)
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, reqWrapper, account); len(errs) > 0 {
@@ -251,11 +261,6 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
-
-	hookExecutor.SetActivityControl(activityControl)
-	hookExecutor.SetAccount(account)
-
 	secGPC := r.Header.Get("Sec-GPC")
 
 	auctionRequest := &exchange.AuctionRequest{
@@ -288,6 +293,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		response = auctionResponse.BidResponse
 	}
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.FloorsOutcome = auctionResponse.GetFloorsOutcome()
 	ao.AuctionResponse = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
@@ -404,7 +410,8 @@ func sendAmpResponse(
 	ao.AmpTargetingValues = targets
 
 	// Fixes #231
-	enc := json.NewEncoder(w) // nosemgrep: json-encoder-needs-type
+	var buffer bytes.Buffer
+	enc := json.NewEncoder(&buffer) // nosemgrep: json-encoder-needs-type
 	enc.SetEscapeHTML(false)
 	// Explicitly set content type to text/plain, which had previously been
 	// the implied behavior from the time the project was launched.
@@ -412,10 +419,30 @@ func sendAmpResponse(
 	// nevertheless we will keep it as such for compatibility reasons.
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 
-	// If an error happens when encoding the response, there isn't much we can do.
+	if err := enc.Encode(ampResponse); err != nil {
+		labels.RequestStatus = metrics.RequestStatusNetworkErr
+		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/amp Failed to send response: %v", err))
+		return labels, ao
+	}
+
+	var request *openrtb2.BidRequest
+	if reqWrapper != nil {
+		request = reqWrapper.BidRequest
+	}
+	body, err := executeExitpointStage(w, hookExecutor, buffer.Bytes(), []string{"ortb2", "ext", "prebid", "modules"}, request, account)
+	if err != nil {
+		err = fmt.Errorf("Failed to enrich response with exitpoint hook debug information: %s", err)
+		glog.Errorf(err.Error())
+		ao.Errors = append(ao.Errors, err)
+	}
+	if reqWrapper != nil {
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+	}
+
+	// If an error happens when writing the response, there isn't much we can do.
 	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
 	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(ampResponse); err != nil {
+	if _, err := w.Write(body); err != nil {
 		labels.RequestStatus = metrics.RequestStatusNetworkErr
 		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/amp Failed to send response: %v", err))
 	}
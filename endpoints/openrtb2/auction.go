@@ -1,6 +1,7 @@
 package openrtb2
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -47,6 +48,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/httputil"
 	"github.com/prebid/prebid-server/v3/util/iputil"
@@ -98,6 +100,7 @@ func NewEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidCodec usersync.Codec,
 ) (httprouter.Handle, error) {
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
@@ -130,7 +133,8 @@ func NewEndpoint(
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		uidCodec}).Auction), nil
 }
 
 type endpointDeps struct {
@@ -154,6 +158,15 @@ type endpointDeps struct {
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       openrtb_ext.BidderNameNormalizer
+	uidCodec                  usersync.Codec
+}
+
+// cookieDecoder returns the decoder of the uids cookie, the UIDs being kept in the cookie unless a UID store is configured
+func (deps *endpointDeps) cookieDecoder() usersync.Decoder {
+	if deps.uidCodec == nil {
+		return usersync.Base64Decoder{}
+	}
+	return deps.uidCodec
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -166,6 +179,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	start := time.Now()
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+	hookExecutor.SetTraceContext(r.Context())
 
 	ao := analytics.AuctionObject{
 		Status:    http.StatusOK,
@@ -186,6 +200,10 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
 		deps.analytics.LogAuctionObject(&ao, activityControl)
+		tracing.SpanFromContext(r.Context()).SetAttributes(
+			tracing.String("pbs.account_id", labels.PubID),
+			tracing.String("pbs.request_status", string(labels.RequestStatus)),
+			tracing.Int("http.status_code", ao.Status))
 	}()
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
@@ -204,12 +222,13 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
+	// the activity control of the account is set on the hook executor before the raw auction stage
 	activityControl = privacy.NewActivityControl(&account.Privacy)
 
-	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
-	ctx := context.Background()
+	// The auction must not be cancelled when the client goes away, so only the span is carried over
+	ctx := tracing.ContextWithSpan(context.Background(), tracing.SpanFromContext(r.Context()))
 
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
@@ -219,8 +238,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
+	usersyncs := usersync.ReadCookie(r, deps.cookieDecoder(), &deps.cfg.HostCookie)
 	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
 
 	if req.Site != nil {
@@ -275,6 +293,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 	ao.Response = response
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.FloorsOutcome = auctionResponse.GetFloorsOutcome()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
@@ -377,15 +396,32 @@ func sendAuctionResponse(
 	}
 
 	// Fixes #231
-	enc := json.NewEncoder(w)
+	var buffer bytes.Buffer
+	enc := json.NewEncoder(&buffer)
 	enc.SetEscapeHTML(false)
 
 	w.Header().Set("Content-Type", "application/json")
 
-	// If an error happens when encoding the response, there isn't much we can do.
+	if err := enc.Encode(response); err != nil {
+		labels.RequestStatus = metrics.RequestStatusNetworkErr
+		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
+		return labels, ao
+	}
+
+	body, err := executeExitpointStage(w, hookExecutor, buffer.Bytes(), []string{"ext", "prebid", "modules"}, request, account)
+	if err != nil {
+		err = fmt.Errorf("Failed to enrich response with exitpoint hook debug information: %s", err)
+		glog.Errorf(err.Error())
+		ao.Errors = append(ao.Errors, err)
+	}
+	if response != nil {
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+	}
+
+	// If an error happens when writing the response, there isn't much we can do.
 	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
 	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(response); err != nil {
+	if _, err := w.Write(body); err != nil {
 		labels.RequestStatus = metrics.RequestStatusNetworkErr
 		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
 	}
@@ -393,6 +429,35 @@ func sendAuctionResponse(
 	return labels, ao
 }
 
+// executeExitpointStage runs the exitpoint hooks on the serialized response and applies the headers they return.
+// If the stage ran, the modules outcome found at modulesPath in the response is refreshed to report it too.
+func executeExitpointStage(
+	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
+	body []byte,
+	modulesPath []string,
+	request *openrtb2.BidRequest,
+	account *config.Account,
+) ([]byte, error) {
+	executedStages := len(hookExecutor.GetOutcomes())
+	body, headers := hookExecutor.ExecuteExitpointStage(body, w.Header().Clone())
+
+	// hooks are allowed to remove headers, so they are replaced rather than merged
+	for name := range w.Header() {
+		w.Header().Del(name)
+	}
+	for name, values := range headers {
+		w.Header()[name] = values
+	}
+
+	stageOutcomes := hookExecutor.GetOutcomes()
+	if len(stageOutcomes) == executedStages {
+		return body, nil
+	}
+
+	return hookexecution.EnrichResponseBody(body, modulesPath, stageOutcomes, request, account)
+}
+
 // setBrowsingTopicsHeader always set the Observe-Browsing-Topics header to a value of ?1 if the Sec-Browsing-Topics is present in request
 func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 	if value := r.Header.Get(secBrowsingTopics); value != "" {
@@ -411,6 +476,15 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
 func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+	_, span := tracing.StartSpan(httpRequest.Context(), "auction.parse_request", tracing.SpanKindInternal)
+	defer func() {
+		span.SetAttributes(tracing.Int("pbs.errors", len(errs)))
+		if fatalErrs := errortypes.FatalOnly(errs); len(fatalErrs) > 0 {
+			span.SetError(fatalErrs[0])
+		}
+		span.End()
+	}()
+
 	errs = nil
 	var err error
 	var errL []error
@@ -503,6 +577,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	}
 
 	hookExecutor.SetAccount(account)
+	hookExecutor.SetActivityControl(privacy.NewActivityControl(&account.Privacy))
 	requestJson, rejectErr = hookExecutor.ExecuteRawAuctionStage(requestJson)
 	if rejectErr != nil {
 		errs = []error{rejectErr}
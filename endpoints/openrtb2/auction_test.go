@@ -42,6 +42,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jsonFileExtension string = ".json"
@@ -480,13 +481,14 @@ func TestExplicitUserId(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		cfg,
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	endpoint(httptest.NewRecorder(), request, nil)
@@ -538,13 +540,14 @@ func doBadAliasRequest(t *testing.T, filename string, expectMsg string) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		disabledBidders,
 		aliasJSON,
 		bidderMap,
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(testBidRequest))
@@ -593,13 +596,14 @@ func TestNilExchange(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	if err == nil {
@@ -619,13 +623,14 @@ func TestExchangeError(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
@@ -746,13 +751,14 @@ func TestImplicitIPsEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			cfg,
 			&metricsConfig.NilMetricsEngine{},
-			analyticsBuild.New(&config.Analytics{}),
+			analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 			map[string]string{},
 			[]byte{},
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -946,13 +952,14 @@ func TestImplicitDNTEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			&config.Configuration{MaxRequestSize: maxSize},
 			&metricsConfig.NilMetricsEngine{},
-			analyticsBuild.New(&config.Analytics{}),
+			analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 			map[string]string{},
 			[]byte{},
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -1183,7 +1190,7 @@ func TestStoredRequests(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1195,6 +1202,7 @@ func TestStoredRequests(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	testStoreVideoAttr := []bool{true, true, false, false, false}
@@ -1633,7 +1641,7 @@ func TestValidateRequest(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1645,6 +1653,7 @@ func TestValidateRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2351,7 +2360,7 @@ func TestSetIntegrationType(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2363,6 +2372,7 @@ func TestSetIntegrationType(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2418,7 +2428,7 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2430,6 +2440,7 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	req := &openrtb2.BidRequest{}
@@ -2523,7 +2534,7 @@ func TestOversizedRequest(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: int64(len(reqBody) - 1)},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2535,6 +2546,7 @@ func TestOversizedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2563,7 +2575,7 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: int64(len(reqBody))},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2575,6 +2587,7 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2601,13 +2614,14 @@ func TestNoEncoding(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2686,13 +2700,14 @@ func TestContentType(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2724,7 +2739,7 @@ func TestCurrencyTrunc(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2736,6 +2751,7 @@ func TestCurrencyTrunc(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2773,7 +2789,7 @@ func TestCCPAInvalid(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2785,6 +2801,7 @@ func TestCCPAInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2826,7 +2843,7 @@ func TestNoSaleInvalid(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2838,6 +2855,7 @@ func TestNoSaleInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2882,7 +2900,7 @@ func TestValidateSourceTID(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		cfg,
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2894,6 +2912,7 @@ func TestValidateSourceTID(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2928,7 +2947,7 @@ func TestSChainInvalid(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -2940,6 +2959,7 @@ func TestSChainInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3369,7 +3389,7 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -3381,6 +3401,7 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3648,13 +3669,14 @@ func TestIOS14EndToEnd(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "app-ios140-no-ifa.json")))
@@ -3711,7 +3733,7 @@ func TestAuctionWarnings(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -3723,6 +3745,7 @@ func TestAuctionWarnings(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -3758,7 +3781,7 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: int64(len(reqBody))},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -3770,6 +3793,7 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -3839,7 +3863,7 @@ func TestParseGzipedRequest(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: int64(50), Compression: config.Compression{Request: config.CompressionInfo{GZIP: false}}},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -3851,6 +3875,7 @@ func TestParseGzipedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -3947,13 +3972,14 @@ func TestAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -4022,7 +4048,7 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				empty_fetcher.EmptyFetcher{},
 				&config.Configuration{MaxRequestSize: int64(len(test.givenRequestBody))},
 				&metricsConfig.NilMetricsEngine{},
-				analyticsBuild.New(&config.Analytics{}),
+				analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 				map[string]string{},
 				false,
 				[]byte{},
@@ -4034,6 +4060,7 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4128,7 +4155,7 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				empty_fetcher.EmptyFetcher{},
 				&config.Configuration{MaxRequestSize: int64(len(test.givenRequestBody))},
 				&metricsConfig.NilMetricsEngine{},
-				analyticsBuild.New(&config.Analytics{}),
+				analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 				map[string]string{},
 				false,
 				[]byte{},
@@ -4140,6 +4167,7 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4241,7 +4269,7 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				empty_fetcher.EmptyFetcher{},
 				&config.Configuration{MaxRequestSize: int64(len(test.givenRequestBody))},
 				&metricsConfig.NilMetricsEngine{},
-				analyticsBuild.New(&config.Analytics{}),
+				analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 				map[string]string{},
 				false,
 				[]byte{},
@@ -4253,6 +4281,7 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4279,7 +4308,7 @@ func TestValidateStoredResp(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -4291,6 +4320,7 @@ func TestValidateStoredResp(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	testCases := []struct {
@@ -5039,6 +5069,53 @@ func TestSendAuctionResponse_LogsErrors(t *testing.T) {
 	}
 }
 
+func TestSendAuctionResponse_ExitpointStage(t *testing.T) {
+	testCases := []struct {
+		description     string
+		request         *openrtb2.BidRequest
+		expectedModules bool
+	}{
+		{
+			description:     "Response replaced by exitpoint hook",
+			request:         &openrtb2.BidRequest{ID: "some-id"},
+			expectedModules: false,
+		},
+		{
+			description:     "Response replaced by exitpoint hook and enriched with hook debug information",
+			request:         &openrtb2.BidRequest{ID: "some-id", Ext: json.RawMessage(`{"prebid": {"trace": "verbose"}}`)},
+			expectedModules: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			planBuilder := mockPlanBuilder{exitpointPlan: makePlan[hookstage.Exitpoint](mockExitpointHook{})}
+			hookExecutor := hookexecution.NewHookExecutor(planBuilder, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			writer := httptest.NewRecorder()
+			writer.Header().Set("Content-Type", "application/json")
+			account := &config.Account{DebugAllow: true}
+
+			_, ao := sendAuctionResponse(writer, hookExecutor, &openrtb2.BidResponse{ID: "some-id"}, test.request, account, metrics.Labels{}, analytics.AuctionObject{})
+
+			assert.Empty(t, ao.Errors, "Invalid errors.")
+			assert.Equal(t, "true", writer.Header().Get("X-Exitpoint"), "Headers not updated by exitpoint hook.")
+			assert.Equal(t, "application/json", writer.Header().Get("Content-Type"), "Headers not preserved.")
+
+			id, err := jsonparser.GetString(writer.Body.Bytes(), "id")
+			require.NoError(t, err)
+			assert.Equal(t, "exitpoint-id", id, "Body not updated by exitpoint hook.")
+
+			stage, err := jsonparser.GetString(writer.Body.Bytes(), "ext", "prebid", "modules", "trace", "stages", "[0]", "stage")
+			if test.expectedModules {
+				require.NoError(t, err)
+				assert.Equal(t, hooks.StageExitpoint.String(), stage)
+			} else {
+				assert.Error(t, err, "Hook debug information not expected.")
+			}
+		})
+	}
+}
+
 func TestParseRequestMultiBid(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -5097,7 +5174,7 @@ func TestParseRequestMultiBid(t *testing.T) {
 				empty_fetcher.EmptyFetcher{},
 				&config.Configuration{MaxRequestSize: int64(len(test.givenRequestBody))},
 				&metricsConfig.NilMetricsEngine{},
-				analyticsBuild.New(&config.Analytics{}),
+				analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 				map[string]string{},
 				false,
 				[]byte{},
@@ -5109,6 +5186,7 @@ func TestParseRequestMultiBid(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -5828,7 +5906,7 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		&mockAccountFetcher{},
 		&config.Configuration{},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -5840,6 +5918,7 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	for _, test := range testCases {
@@ -33,6 +33,7 @@ import (
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -57,10 +58,12 @@ func NewVideoEndpoint(
 	defReqJSON []byte,
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidCodec usersync.Codec,
 ) (httprouter.Handle, error) {
 
-	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
+	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil || hookExecutionPlanBuilder == nil {
 		return nil, errors.New("NewVideoEndpoint requires non-nil arguments.")
 	}
 
@@ -91,9 +94,10 @@ func NewVideoEndpoint(
 		videoEndpointRegexp,
 		ipValidator,
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{},
+		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		uidCodec}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -122,6 +126,9 @@ func NewVideoEndpoint(
 func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	start := time.Now()
 
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointVideo, deps.metricsEngine)
+	hookExecutor.SetTraceContext(r.Context())
+
 	vo := analytics.VideoObject{
 		Status:    http.StatusOK,
 		Errors:    make([]error, 0),
@@ -173,7 +180,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	requestJson, err := io.ReadAll(lr)
 	if err != nil {
-		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		handleError(&labels, w, []error{err}, &vo, &debugLog, hookExecutor)
 		return
 	}
 
@@ -192,27 +199,27 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	if err != nil {
 		if deps.cfg.VideoStoredRequestRequired {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(&labels, w, []error{err}, &vo, &debugLog, hookExecutor)
 			return
 		}
 	} else {
 		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId)
 		if len(errs) > 0 {
-			handleError(&labels, w, errs, &vo, &debugLog)
+			handleError(&labels, w, errs, &vo, &debugLog, hookExecutor)
 			return
 		}
 
 		//merge incoming req with stored video req
 		resolvedRequest, err = jsonpatch.MergePatch(storedRequest, requestJson)
 		if err != nil {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(&labels, w, []error{err}, &vo, &debugLog, hookExecutor)
 			return
 		}
 	}
 	//unmarshal and validate combined result
 	videoBidReq, errL, podErrors := deps.parseVideoRequest(resolvedRequest, r.Header)
 	if len(errL) > 0 {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
@@ -222,7 +229,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	if deps.defaultRequest {
 		if err := jsonutil.UnmarshalValid(deps.defReqJSON, bidReq); err != nil {
 			err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", err)
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(&labels, w, []error{err}, &vo, &debugLog, hookExecutor)
 			return
 		}
 	}
@@ -250,7 +257,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		}
 		err := fmt.Errorf("all pods are incorrect: %s", strings.Join(resPodErr, "; "))
 		errL = append(errL, err)
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
@@ -261,16 +268,16 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	bidReqWrapper := &openrtb_ext.RequestWrapper{BidRequest: bidReq}
 
 	if err := openrtb_ext.ConvertUpTo26(bidReqWrapper); err != nil {
-		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		handleError(&labels, w, []error{err}, &vo, &debugLog, hookExecutor)
 		return
 	}
 
 	if err := ortb.SetDefaults(bidReqWrapper, deps.cfg.TmaxDefault); err != nil {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := tracing.ContextWithSpan(context.Background(), tracing.SpanFromContext(r.Context()))
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(bidReqWrapper.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
@@ -279,8 +286,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
+	usersyncs := usersync.ReadCookie(r, deps.cookieDecoder(), &deps.cfg.HostCookie)
 	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
 
 	if bidReqWrapper.App != nil {
@@ -299,9 +305,10 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
 	if len(acctIDErrs) > 0 {
-		handleError(&labels, w, acctIDErrs, &vo, &debugLog)
+		handleError(&labels, w, acctIDErrs, &vo, &debugLog, hookExecutor)
 		return
 	}
+	vo.Account = account
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, bidReqWrapper, account); len(errs) > 0 {
@@ -311,12 +318,15 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	errs := deps.validateRequest(account, r, bidReqWrapper, false, false, nil, false)
 	errL = append(errL, errs...)
 	if errortypes.ContainsFatalError(errL) {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
 
+	hookExecutor.SetActivityControl(activityControl)
+	hookExecutor.SetAccount(account)
+
 	warnings := errortypes.WarningOnly(errL)
 
 	secGPC := r.Header.Get("Sec-GPC")
@@ -330,7 +340,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		Warnings:                   warnings,
 		GlobalPrivacyControlHeader: secGPC,
 		PubID:                      labels.PubID,
-		HookExecutor:               hookexecution.EmptyHookExecutor{},
+		HookExecutor:               hookExecutor,
 		TmaxAdjustments:            deps.tmaxAdjustments,
 		Activities:                 activityControl,
 	}
@@ -350,7 +360,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
@@ -358,7 +368,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	bidResp, err := buildVideoResponse(response, podErrors)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 	if bidReq.Test == 1 {
@@ -389,11 +399,17 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	resp, err := jsonutil.Marshal(bidResp)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(&labels, w, errL, &vo, &debugLog, hookExecutor)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	resp, err = executeExitpointStage(w, hookExecutor, resp, []string{"ext", "prebid", "modules"}, bidReq, account)
+	if err != nil {
+		err = fmt.Errorf("Failed to enrich response with exitpoint hook debug information: %s", err)
+		glog.Errorf(err.Error())
+		vo.Errors = append(vo.Errors, err)
+	}
 	w.Write(resp)
 }
 
@@ -404,7 +420,9 @@ func cleanupVideoBidRequest(videoReq *openrtb_ext.BidRequestVideo, podErrors []P
 	return videoReq
 }
 
-func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo *analytics.VideoObject, debugLog *exchange.DebugLog) {
+// handleError writes the error response of a rejected video request, the exitpoint hooks run on it as they do on
+// the bid responses.
+func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo *analytics.VideoObject, debugLog *exchange.DebugLog, hookExecutor hookexecution.HookStageExecutor) {
 	if debugLog != nil && debugLog.DebugEnabledOrOverridden {
 		if rawUUID, err := uuid.NewV4(); err == nil {
 			debugLog.CacheKey = rawUUID.String()
@@ -431,11 +449,14 @@ func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo
 		}
 		errors = fmt.Sprintf("%s %s", errors, er.Error())
 	}
-	w.WriteHeader(status)
 	vo.Status = status
-	fmt.Fprintf(w, "Critical error while running the video endpoint: %v", errors)
 	glog.Errorf("/openrtb2/video Critical error: %v", errors)
 	vo.Errors = append(vo.Errors, errL...)
+
+	// the error body isn't JSON, so the exitpoint outcomes aren't added to it and need neither the request nor the account
+	body, _ := executeExitpointStage(w, hookExecutor, []byte(fmt.Sprintf("Critical error while running the video endpoint: %v", errors)), nil, nil, nil)
+	w.WriteHeader(status)
+	w.Write(body)
 }
 
 func (deps *endpointDeps) createImpressions(videoReq *openrtb_ext.BidRequestVideo, podErrors []PodError) ([]openrtb2.Imp, []PodError) {
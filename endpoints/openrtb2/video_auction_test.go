@@ -17,6 +17,8 @@ import (
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange"
 	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -866,7 +868,7 @@ func TestHandleError(t *testing.T) {
 		}
 
 		recorder := httptest.NewRecorder()
-		handleError(&labels, recorder, tt.giveErrors, &vo, nil)
+		handleError(&labels, recorder, tt.giveErrors, &vo, nil, hookexecution.EmptyHookExecutor{})
 
 		assert.Equal(t, tt.wantMetricsStatus, labels.RequestStatus, tt.description)
 		assert.Equal(t, tt.wantCode, recorder.Code, tt.description)
@@ -875,6 +877,20 @@ func TestHandleError(t *testing.T) {
 	}
 }
 
+func TestHandleErrorExitpointStage(t *testing.T) {
+	planBuilder := mockPlanBuilder{exitpointPlan: makePlan[hookstage.Exitpoint](mockExitpointHook{})}
+	hookExecutor := hookexecution.NewHookExecutor(planBuilder, hookexecution.EndpointVideo, &metricsConfig.NilMetricsEngine{})
+	vo := analytics.VideoObject{}
+	labels := metrics.Labels{}
+
+	recorder := httptest.NewRecorder()
+	handleError(&labels, recorder, []error{errors.New("some error")}, &vo, nil, hookExecutor)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "true", recorder.Header().Get("X-Exitpoint"), "Headers not updated by exitpoint hook.")
+	assert.Equal(t, `{"id":"exitpoint-id"}`, recorder.Body.String(), "Body not updated by exitpoint hook.")
+}
+
 func TestHandleErrorMetrics(t *testing.T) {
 	ex := &mockExchangeVideo{}
 	reqBody := readVideoTestFile(t, "sample-requests/video/video_invalid_sample.json")
@@ -1015,7 +1031,7 @@ func TestHandleErrorDebugLog(t *testing.T) {
 		DebugOverride:            false,
 		DebugEnabledOrOverridden: true,
 	}
-	handleError(&labels, recorder, []error{err1, err2}, &vo, &debugLog)
+	handleError(&labels, recorder, []error{err1, err2}, &vo, &debugLog, hookexecution.EmptyHookExecutor{})
 
 	assert.Equal(t, metrics.RequestStatusErr, labels.RequestStatus, "labels.RequestStatus should indicate an error")
 	assert.Equal(t, 500, recorder.Code, "Error status should be written to writer")
@@ -1287,6 +1303,7 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 	return deps, metrics, mockModule
 }
@@ -1326,7 +1343,7 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		&mockAccountFetcher{data: mockVideoAccountData},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1338,6 +1355,7 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 }
 
@@ -1351,7 +1369,7 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1363,6 +1381,7 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	return deps
@@ -1378,7 +1397,7 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1390,6 +1409,7 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
 	}
 
 	return edep
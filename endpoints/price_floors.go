@@ -0,0 +1,34 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// FloorsFetchReporter reports the outcome of the last fetch of the floors URLs.
+type FloorsFetchReporter interface {
+	FetchStatuses() []floors.FetchStatus
+}
+
+// NewPriceFloorsFetchesEndpoint returns the admin endpoint exposing the outcome of the last fetch of the floors URLs:
+//
+//	GET /price_floors/fetches  returns the status, age and rule count of the floor data of each URL
+//
+// The list is empty if the dynamic fetch of floor data is disabled.
+func NewPriceFloorsFetchesEndpoint(reporter FloorsFetchReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		body, err := jsonutil.Marshal(reporter.FetchStatuses())
+		if err != nil {
+			glog.Errorf("/price_floors/fetches Critical error when trying to marshal the response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
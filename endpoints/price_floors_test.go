@@ -0,0 +1,60 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFloorsFetchReporter struct {
+	statuses []floors.FetchStatus
+}
+
+func (r *fakeFloorsFetchReporter) FetchStatuses() []floors.FetchStatus {
+	return r.statuses
+}
+
+func TestPriceFloorsFetchesEndpoint(t *testing.T) {
+	fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	attemptedAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description  string
+		statuses     []floors.FetchStatus
+		expectedBody string
+	}{
+		{
+			description:  "no_fetch",
+			statuses:     []floors.FetchStatus{},
+			expectedBody: `[]`,
+		},
+		{
+			description: "fetches",
+			statuses: []floors.FetchStatus{
+				{URL: "https://floors.example.com/1234.json", AccountID: "1234", Status: "error", AttemptedAt: &attemptedAt, FetchedAt: &fetchedAt, AgeSec: 3600, RuleCount: 12, RetryCount: 2},
+				{URL: "https://floors.example.com/5678.json", Status: "none", FetchedAt: &fetchedAt, AgeSec: 60, RuleCount: 3},
+			},
+			expectedBody: `[
+				{"url":"https://floors.example.com/1234.json","accountId":"1234","status":"error","attemptedAt":"2024-05-01T13:00:00Z","fetchedAt":"2024-05-01T12:00:00Z","ageSec":3600,"ruleCount":12,"retryCount":2},
+				{"url":"https://floors.example.com/5678.json","status":"none","fetchedAt":"2024-05-01T12:00:00Z","ageSec":60,"ruleCount":3,"retryCount":0}
+			]`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			handler := NewPriceFloorsFetchesEndpoint(&fakeFloorsFetchReporter{statuses: test.statuses})
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/price_floors/fetches", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
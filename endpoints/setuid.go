@@ -37,10 +37,7 @@ const (
 
 const uidCookieName = "uids"
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
-
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, codec usersync.Codec) httprouter.Handle {
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		so := analytics.SetUIDObject{
 			Status: http.StatusOK,
@@ -49,7 +46,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		defer analyticsRunner.LogSetUIDObject(&so)
 
-		cookie := usersync.ReadCookie(r, decoder, &cfg.HostCookie)
+		cookie := usersync.ReadCookie(r, codec, &cfg.HostCookie)
 		if !cookie.AllowSyncs() {
 			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
 			return
@@ -108,6 +105,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		policies := privacy.Policies{
 			GPPSID: gppSID,
+			GPP:    query.Get("gpp"),
 		}
 
 		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
@@ -163,7 +161,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
 
 		// Write Cookie
-		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
+		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, codec, priorityEjector)
 		if err != nil {
 			if err.Error() == errSyncerIsNotPriority.Error() {
 				w.WriteHeader(http.StatusOK)
@@ -0,0 +1,176 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// storedCacheInfo holds the statistics of a stored data cache.
+type storedCacheInfo struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Entries  int     `json:"entries"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hitRatio"`
+}
+
+// storedCacheEntry describes a value held by a stored data cache.
+type storedCacheEntry struct {
+	ID         string          `json:"id"`
+	Size       int             `json:"size"`
+	SavedAt    *time.Time      `json:"savedAt,omitempty"`
+	AgeSeconds *int64          `json:"ageSeconds,omitempty"`
+	Value      json.RawMessage `json:"value,omitempty"`
+}
+
+// StoredCachesEndpoints serves the admin endpoints inspecting and invalidating the in-memory caches
+// of stored requests, imps, responses and accounts:
+//
+//	GET    /stored_requests/caches                  lists the caches with their hit and miss counts
+//	GET    /stored_requests/caches/{cache}          lists the entries of a cache, optionally filtered by ?prefix=
+//	DELETE /stored_requests/caches/{cache}?prefix=  invalidates the entries whose IDs start with the prefix
+//	GET    /stored_requests/caches/{cache}/{id}     returns a cached value
+//	DELETE /stored_requests/caches/{cache}/{id}     invalidates a cached value
+//
+// Looking up values from these endpoints doesn't affect the cache statistics or eviction order.
+type StoredCachesEndpoints struct {
+	caches stored_requests.NamedCaches
+	time   timeutil.Time
+}
+
+// NewStoredCachesEndpoints returns the admin endpoints of the given caches.
+func NewStoredCachesEndpoints(caches stored_requests.NamedCaches) *StoredCachesEndpoints {
+	return &StoredCachesEndpoints{
+		caches: caches,
+		time:   &timeutil.RealTime{},
+	}
+}
+
+// HandleList lists the caches with their statistics.
+func (e *StoredCachesEndpoints) HandleList(w http.ResponseWriter, _ *http.Request) {
+	infos := make([]storedCacheInfo, 0, len(e.caches))
+	for name, cache := range e.caches {
+		stats := cache.Stats()
+		info := storedCacheInfo{
+			Name:    name,
+			Type:    stats.Type,
+			Entries: stats.Entries,
+			Hits:    stats.Hits,
+			Misses:  stats.Misses,
+		}
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			info.HitRatio = float64(stats.Hits) / float64(lookups)
+		}
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b storedCacheInfo) int { return strings.Compare(a.Name, b.Name) })
+
+	writeStoredCachesResponse(w, http.StatusOK, infos)
+}
+
+// HandleEntries lists the entries of a cache on GET and invalidates them by prefix on DELETE.
+func (e *StoredCachesEndpoints) HandleEntries(w http.ResponseWriter, r *http.Request) {
+	cache, ok := e.getCache(w, r)
+	if !ok {
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	if r.Method == http.MethodDelete && prefix == "" {
+		http.Error(w, "The prefix query parameter is required to invalidate entries.", http.StatusBadRequest)
+		return
+	}
+
+	now := e.time.Now()
+	entries := make([]storedCacheEntry, 0)
+	for _, entry := range cache.Entries() {
+		if strings.HasPrefix(entry.ID, prefix) {
+			entries = append(entries, newStoredCacheEntry(entry, now))
+		}
+	}
+	slices.SortFunc(entries, func(a, b storedCacheEntry) int { return strings.Compare(a.ID, b.ID) })
+
+	if r.Method == http.MethodDelete {
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		cache.Invalidate(context.Background(), ids)
+		glog.Infof("Invalidated %d entries with prefix %q from the %s cache", len(ids), prefix, r.PathValue("cache"))
+		writeStoredCachesResponse(w, http.StatusOK, map[string][]string{"invalidated": ids})
+		return
+	}
+
+	writeStoredCachesResponse(w, http.StatusOK, entries)
+}
+
+// HandleEntry returns a cached value on GET and invalidates it on DELETE.
+func (e *StoredCachesEndpoints) HandleEntry(w http.ResponseWriter, r *http.Request) {
+	cache, ok := e.getCache(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	entry, value, found := cache.Peek(id)
+	if !found {
+		http.Error(w, "Entry not found.", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		cache.Invalidate(context.Background(), []string{id})
+		glog.Infof("Invalidated entry %q from the %s cache", id, r.PathValue("cache"))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	response := newStoredCacheEntry(entry, e.time.Now())
+	response.Value = value
+	writeStoredCachesResponse(w, http.StatusOK, response)
+}
+
+func (e *StoredCachesEndpoints) getCache(w http.ResponseWriter, r *http.Request) (stored_requests.InspectableCache, bool) {
+	cache, ok := e.caches[r.PathValue("cache")]
+	if !ok {
+		http.Error(w, "Cache not found.", http.StatusNotFound)
+	}
+	return cache, ok
+}
+
+func newStoredCacheEntry(entry stored_requests.CacheEntry, now time.Time) storedCacheEntry {
+	response := storedCacheEntry{
+		ID:   entry.ID,
+		Size: entry.Size,
+	}
+	if !entry.SavedAt.IsZero() {
+		savedAt := entry.SavedAt.UTC()
+		ageSeconds := int64(now.Sub(entry.SavedAt).Seconds())
+		response.SavedAt = &savedAt
+		response.AgeSeconds = &ageSeconds
+	}
+	return response
+}
+
+func writeStoredCachesResponse(w http.ResponseWriter, status int, response interface{}) {
+	body, err := jsonutil.Marshal(response)
+	if err != nil {
+		glog.Errorf("/stored_requests/caches Critical error when trying to marshal the response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,245 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInspectableCache struct {
+	values  map[string]json.RawMessage
+	savedAt time.Time
+	stats   stored_requests.CacheStats
+}
+
+func (c *fakeInspectableCache) Get(_ context.Context, ids []string) map[string]json.RawMessage {
+	data := make(map[string]json.RawMessage)
+	for _, id := range ids {
+		if value, ok := c.values[id]; ok {
+			data[id] = value
+		}
+	}
+	return data
+}
+
+func (c *fakeInspectableCache) Save(_ context.Context, data map[string]json.RawMessage) {
+	for id, value := range data {
+		c.values[id] = value
+	}
+}
+
+func (c *fakeInspectableCache) Invalidate(_ context.Context, ids []string) {
+	for _, id := range ids {
+		delete(c.values, id)
+	}
+}
+
+func (c *fakeInspectableCache) Entries() []stored_requests.CacheEntry {
+	entries := make([]stored_requests.CacheEntry, 0, len(c.values))
+	for id, value := range c.values {
+		entries = append(entries, stored_requests.CacheEntry{ID: id, Size: len(value), SavedAt: c.savedAt})
+	}
+	return entries
+}
+
+func (c *fakeInspectableCache) Peek(id string) (stored_requests.CacheEntry, json.RawMessage, bool) {
+	value, ok := c.values[id]
+	return stored_requests.CacheEntry{ID: id, Size: len(value), SavedAt: c.savedAt}, value, ok
+}
+
+func (c *fakeInspectableCache) Stats() stored_requests.CacheStats {
+	return c.stats
+}
+
+func newTestStoredCachesEndpoints() (*StoredCachesEndpoints, *fakeInspectableCache) {
+	savedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	accounts := &fakeInspectableCache{
+		values: map[string]json.RawMessage{
+			"pub-1": json.RawMessage(`{"id":"pub-1"}`),
+			"pub-2": json.RawMessage(`{"id":"pub-2"}`),
+			"other": json.RawMessage(`{"id":"other"}`),
+		},
+		savedAt: savedAt,
+		stats:   stored_requests.CacheStats{Type: "lru", Entries: 3, Hits: 3, Misses: 1},
+	}
+	imps := &fakeInspectableCache{
+		values: map[string]json.RawMessage{},
+		stats:  stored_requests.CacheStats{Type: "unbounded"},
+	}
+
+	e := NewStoredCachesEndpoints(stored_requests.NamedCaches{
+		"accounts.accounts":    accounts,
+		"stored_requests.imps": imps,
+	})
+	e.time = &fakeTime{time: savedAt.Add(90 * time.Second)}
+	return e, accounts
+}
+
+func TestStoredCachesHandleList(t *testing.T) {
+	e, _ := newTestStoredCachesEndpoints()
+
+	w := httptest.NewRecorder()
+	e.HandleList(w, httptest.NewRequest(http.MethodGet, "/stored_requests/caches", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"name":"accounts.accounts","type":"lru","entries":3,"hits":3,"misses":1,"hitRatio":0.75},
+		{"name":"stored_requests.imps","type":"unbounded","entries":0,"hits":0,"misses":0,"hitRatio":0}
+	]`, w.Body.String())
+}
+
+func TestStoredCachesHandleEntries(t *testing.T) {
+	testCases := []struct {
+		description       string
+		method            string
+		cache             string
+		query             string
+		expectedStatus    int
+		expectedBody      string
+		expectedRemaining []string
+	}{
+		{
+			description:       "list_all",
+			method:            http.MethodGet,
+			cache:             "accounts.accounts",
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `[{"id":"other","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90},{"id":"pub-1","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90},{"id":"pub-2","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90}]`,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+		{
+			description:       "list_by_prefix",
+			method:            http.MethodGet,
+			cache:             "accounts.accounts",
+			query:             "?prefix=pub-",
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `[{"id":"pub-1","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90},{"id":"pub-2","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90}]`,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+		{
+			description:       "invalidate_by_prefix",
+			method:            http.MethodDelete,
+			cache:             "accounts.accounts",
+			query:             "?prefix=pub-",
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `{"invalidated":["pub-1","pub-2"]}`,
+			expectedRemaining: []string{"other"},
+		},
+		{
+			description:       "invalidate_without_prefix",
+			method:            http.MethodDelete,
+			cache:             "accounts.accounts",
+			expectedStatus:    http.StatusBadRequest,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+		{
+			description:       "unknown_cache",
+			method:            http.MethodGet,
+			cache:             "unknown",
+			expectedStatus:    http.StatusNotFound,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e, accounts := newTestStoredCachesEndpoints()
+
+			r := httptest.NewRequest(test.method, "/stored_requests/caches/"+test.cache+test.query, nil)
+			r.SetPathValue("cache", test.cache)
+			w := httptest.NewRecorder()
+			e.HandleEntries(w, r)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+
+			var remaining []string
+			for id := range accounts.values {
+				remaining = append(remaining, id)
+			}
+			assert.ElementsMatch(t, test.expectedRemaining, remaining)
+		})
+	}
+}
+
+func TestStoredCachesHandleEntry(t *testing.T) {
+	testCases := []struct {
+		description       string
+		method            string
+		id                string
+		expectedStatus    int
+		expectedBody      string
+		expectedRemaining []string
+	}{
+		{
+			description:       "get",
+			method:            http.MethodGet,
+			id:                "pub-1",
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `{"id":"pub-1","size":14,"savedAt":"2024-05-01T12:00:00Z","ageSeconds":90,"value":{"id":"pub-1"}}`,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+		{
+			description:       "get_not_found",
+			method:            http.MethodGet,
+			id:                "pub-3",
+			expectedStatus:    http.StatusNotFound,
+			expectedRemaining: []string{"other", "pub-1", "pub-2"},
+		},
+		{
+			description:       "invalidate",
+			method:            http.MethodDelete,
+			id:                "pub-1",
+			expectedStatus:    http.StatusNoContent,
+			expectedRemaining: []string{"other", "pub-2"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e, accounts := newTestStoredCachesEndpoints()
+
+			r := httptest.NewRequest(test.method, "/stored_requests/caches/accounts.accounts/"+test.id, nil)
+			r.SetPathValue("cache", "accounts.accounts")
+			r.SetPathValue("id", test.id)
+			w := httptest.NewRecorder()
+			e.HandleEntry(w, r)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+
+			var remaining []string
+			for id := range accounts.values {
+				remaining = append(remaining, id)
+			}
+			assert.ElementsMatch(t, test.expectedRemaining, remaining)
+		})
+	}
+}
+
+func TestStoredCachesInvalidateTieredCache(t *testing.T) {
+	local := &fakeInspectableCache{values: map[string]json.RawMessage{"pub-1": json.RawMessage(`{"id":"pub-1"}`)}}
+	shared := &fakeInspectableCache{values: map[string]json.RawMessage{"pub-1": json.RawMessage(`{"id":"pub-1"}`)}}
+	tiered := stored_requests.TieredCache{Local: local, Shared: shared}
+	e := NewStoredCachesEndpoints(stored_requests.NamedCaches{
+		"accounts.accounts": stored_requests.InspectableTieredCache{TieredCache: tiered, Inspectable: local},
+	})
+
+	r := httptest.NewRequest(http.MethodDelete, "/stored_requests/caches/accounts.accounts/pub-1", nil)
+	r.SetPathValue("cache", "accounts.accounts")
+	r.SetPathValue("id", "pub-1")
+	w := httptest.NewRecorder()
+	e.HandleEntry(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, tiered.Get(context.Background(), []string{"pub-1"}), "The invalidated value shouldn't be read back from the shared cache")
+}
@@ -92,6 +92,7 @@ func buildAdapterInfo(bidderInfo config.BidderInfo) config.Adapter {
 	adapter.PlatformID = bidderInfo.PlatformID
 	adapter.AppSecret = bidderInfo.AppSecret
 	adapter.XAPI = bidderInfo.XAPI
+	adapter.GenericORTB = bidderInfo.GenericORTB
 	return adapter
 }
 
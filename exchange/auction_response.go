@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
@@ -9,6 +10,8 @@ import (
 type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
+	// FloorsOutcome is the outcome of the price floors reported to analytics
+	FloorsOutcome *analytics.FloorsOutcome
 }
 
 // GetSeatNonBid returns array of seat non-bid if present. nil otherwise
@@ -18,3 +21,11 @@ func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
 	}
 	return nil
 }
+
+// GetFloorsOutcome returns the outcome of the price floors if enforced. nil otherwise
+func (ar *AuctionResponse) GetFloorsOutcome() *analytics.FloorsOutcome {
+	if ar != nil {
+		return ar.FloorsOutcome
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/experiment/adscert"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/version"
 
 	"github.com/prebid/openrtb/v20/adcom1"
@@ -130,6 +131,9 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 	// Precalculate bulk and delta values for health updates.
 	ba.config.ThrottleConfig.deltaValue = 1.0 / float64(ba.config.ThrottleConfig.throttleWindow)
 	ba.config.ThrottleConfig.bulkValue = 1.0 - ba.config.ThrottleConfig.deltaValue
+	if cfg.Client.Throttle.EnableThrottling && cfg.Client.Throttle.IsHealthTrackedPerDimension() {
+		ba.health = newBidderHealthVectors(name, ba.config.ThrottleConfig, cfg.Client.Throttle, me)
+	}
 
 	return ba
 }
@@ -148,7 +152,8 @@ type BidderAdapter struct {
 	me         metrics.MetricsEngine
 	config     bidderAdapterConfig
 	healthBits atomic.Uint64 // use atomic on this
-
+	// health tracks the health by the dimensions of the requests instead of the health bits, if configured.
+	health *bidderHealthVectors
 }
 
 type bidderAdapterConfig struct {
@@ -174,7 +179,19 @@ type bidderAdapterThrottleConfig struct {
 	deltaValue     float64
 }
 
-func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
+func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) (seatBids []*entities.PbsOrtbSeatBid, extraRespInfo extraBidderRespInfo, errs []error) {
+	ctx, span := tracing.StartSpan(ctx, "bidder."+bidderRequest.BidderName.String(), tracing.SpanKindInternal,
+		tracing.String("pbs.bidder", bidderRequest.BidderName.String()),
+		tracing.Int("pbs.imps", len(bidderRequest.BidRequest.Imp)))
+	defer func() {
+		bids := 0
+		for _, seatBid := range seatBids {
+			bids += len(seatBid.Bids)
+		}
+		span.SetAttributes(tracing.Int("pbs.bids", bids), tracing.Int("pbs.errors", len(errs)))
+		span.End()
+	}()
+
 	request := openrtb_ext.RequestWrapper{BidRequest: bidderRequest.BidRequest}
 	reject := hookExecutor.ExecuteBidderRequestStage(&request, string(bidderRequest.BidderName))
 	seatNonBidBuilder := SeatNonBidBuilder{}
@@ -182,11 +199,11 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		return nil, extraBidderRespInfo{}, []error{reject}
 	}
 
+	healthKey := bidder.healthKey(bidderRequest)
+
 	var (
 		reqData         []*adapters.RequestData
-		errs            []error
 		responseChannel chan *httpCallInfo
-		extraRespInfo   extraBidderRespInfo
 	)
 
 	// rebuild request after modules execution
@@ -242,11 +259,11 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		dataLen = len(reqData) + len(bidderRequest.BidderStoredResponses)
 		responseChannel = make(chan *httpCallInfo, dataLen)
 		if len(reqData) == 1 {
-			responseChannel <- bidder.doRequest(ctx, reqData[0], bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
+			responseChannel <- bidder.doRequest(ctx, reqData[0], healthKey, bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
 		} else {
 			for _, oneReqData := range reqData {
 				go func(data *adapters.RequestData) {
-					responseChannel <- bidder.doRequest(ctx, data, bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
+					responseChannel <- bidder.doRequest(ctx, data, healthKey, bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
 				}(oneReqData) // Method arg avoids a race condition on oneReqData
 			}
 		}
@@ -305,7 +322,7 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			errs = append(errs, moreErrs...)
 
 			if bidResponse != nil {
-				reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, string(bidder.BidderName))
+				reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, &openrtb_ext.RequestWrapper{BidRequest: bidderRequest.BidRequest}, string(bidder.BidderName))
 				if reject != nil {
 					errs = append(errs, reject)
 					continue
@@ -446,7 +463,7 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		}
 	}
 
-	seatBids := make([]*entities.PbsOrtbSeatBid, 0, len(seatBidMap))
+	seatBids = make([]*entities.PbsOrtbSeatBid, 0, len(seatBidMap))
 	for _, seatBid := range seatBidMap {
 		seatBids = append(seatBids, seatBid)
 	}
@@ -566,9 +583,9 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
-func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
-	if bidder.shouldRequest() {
-		return bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, healthKey string, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+	if bidder.shouldRequestFor(healthKey) {
+		return bidder.doRequestImpl(ctx, req, healthKey, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
 	}
 	return &httpCallInfo{
 		request: req,
@@ -576,7 +593,19 @@ func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.Reques
 	}
 }
 
-func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, healthKey string, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) (callInfo *httpCallInfo) {
+	ctx, span := tracing.StartSpan(ctx, "bidder.http", tracing.SpanKindClient,
+		tracing.String("pbs.bidder", bidder.BidderName.String()),
+		tracing.String("http.method", req.Method),
+		tracing.String("http.url", req.Uri))
+	defer func() {
+		if callInfo.response != nil {
+			span.SetAttributes(tracing.Int("http.status_code", callInfo.response.StatusCode))
+		}
+		span.SetError(callInfo.err)
+		span.End()
+	}()
+
 	requestBody, err := getRequestBody(req, bidder.config.EndpointCompression)
 	if err != nil {
 		return &httpCallInfo{
@@ -591,12 +620,17 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 			err:     err,
 		}
 	}
-	httpReq.Header = req.Headers
+	// the trace context is set on a copy to keep it out of the request data of the adapter
+	httpReq.Header = req.Headers.Clone()
+	if httpReq.Header == nil {
+		httpReq.Header = http.Header{}
+	}
+	tracing.Inject(ctx, httpReq.Header)
 
 	// If adapter connection metrics are not disabled, add the client trace
 	// to get complete connection info into our metrics
 	if !bidder.config.DisableConnMetrics {
-		ctx = bidder.addClientTrace(ctx)
+		ctx = bidder.addClientTrace(ctx, healthKey)
 	}
 	bidder.me.RecordOverheadTime(metrics.PreBidder, time.Since(bidderRequestStartTime))
 
@@ -613,7 +647,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	httpCallStart := time.Now()
 	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
 	if err != nil {
-		bidder.logHealthCheck(false)
+		bidder.logHealthCheckFor(healthKey, false)
 		if err == context.DeadlineExceeded {
 			err = &errortypes.Timeout{Message: err.Error()}
 			var corebidder adapters.Bidder = bidder.Bidder
@@ -648,14 +682,14 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
 		if httpResp.StatusCode >= 500 {
-			bidder.logHealthCheck(false)
+			bidder.logHealthCheckFor(healthKey, false)
 		}
 		err = &errortypes.BadServerResponse{
 			Message: fmt.Sprintf("Server responded with failure status: %d. Set request.test = 1 for debugging info.", httpResp.StatusCode),
 		}
 	}
 
-	bidder.logHealthCheck(true)
+	bidder.logHealthCheckFor(healthKey, true)
 	bidder.me.RecordBidderServerResponseTime(time.Since(httpCallStart))
 	return &httpCallInfo{
 		request: req,
@@ -718,7 +752,7 @@ type httpCallInfo struct {
 // This function adds an httptrace.ClientTrace object to the context so, if connection with the bidder
 // endpoint is established, we can keep track of whether the connection was newly created, reused, and
 // the time from the connection request, to the connection creation.
-func (bidder *BidderAdapter) addClientTrace(ctx context.Context) context.Context {
+func (bidder *BidderAdapter) addClientTrace(ctx context.Context, healthKey string) context.Context {
 	var connStart, dnsStart, tlsStart time.Time
 
 	trace := &httptrace.ClientTrace{
@@ -732,9 +766,9 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context) context.Context
 			if info.Reused {
 				// If the connection was reused, this is the time we waited in the pool
 				if bidder.config.ThrottleConfig.longQueueWaitThreshold > 0 && connWaitTime > bidder.config.ThrottleConfig.longQueueWaitThreshold {
-					bidder.logHealthCheck(false) // Mark as unhealthy if wait was too long
+					bidder.logHealthCheckFor(healthKey, false) // Mark as unhealthy if wait was too long
 				} else if bidder.config.ThrottleConfig.shortQueueWaitThreshold > 0 && connWaitTime < bidder.config.ThrottleConfig.shortQueueWaitThreshold {
-					bidder.logHealthCheck(true) // Mark as healthy if wait was short
+					bidder.logHealthCheckFor(healthKey, true) // Mark as healthy if wait was short
 					// Note if there is a short wait time for the pool, but the auction times out,
 					// we would mark the bidder as healthy once and unhealthy once, pushing the
 					// health to 0.5
@@ -873,6 +907,43 @@ func (bidder *BidderAdapter) logHealthCheck(success bool) {
 	bidder.healthBits.Store(math.Float64bits(newVal))
 }
 
+// healthKey returns the key of the health vector of the bidder request, if the health is tracked by dimensions.
+func (bidder *BidderAdapter) healthKey(bidderRequest BidderRequest) string {
+	if bidder.health == nil {
+		return ""
+	}
+	return bidder.health.key(bidderRequest)
+}
+
+// logHealthCheckFor registers a health check for the health vector of the request, or for the bidder if the
+// health isn't tracked by dimensions.
+func (bidder *BidderAdapter) logHealthCheckFor(healthKey string, success bool) {
+	if bidder.health != nil {
+		bidder.health.logHealthCheck(healthKey, success)
+		return
+	}
+	bidder.logHealthCheck(success)
+}
+
+// shouldRequestFor returns true if a request should be made to the bidder for the health vector of the
+// request, or for the bidder if the health isn't tracked by dimensions.
+func (bidder *BidderAdapter) shouldRequestFor(healthKey string) bool {
+	if bidder.health != nil {
+		return bidder.health.shouldRequest(healthKey)
+	}
+	return bidder.shouldRequest()
+}
+
+func (bidder *BidderAdapter) healthVectors() []BidderHealthVector {
+	if !bidder.config.ThrottleConfig.enabled {
+		return nil
+	}
+	if bidder.health != nil {
+		return bidder.health.snapshot()
+	}
+	return []BidderHealthVector{{Score: bidder.getHealth(), State: metrics.AdapterHealthClosed}}
+}
+
 func (bidder *BidderAdapter) shouldRequest() bool {
 	if !bidder.config.ThrottleConfig.enabled {
 		return true
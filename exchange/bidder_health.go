@@ -0,0 +1,388 @@
+package exchange
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	// unknownHealthDimension is the value of the dimensions missing from a bidder request
+	unknownHealthDimension = "unknown"
+	// minHealthScore is the unhealthy score below which a health vector is as good as new and can be pruned
+	minHealthScore = 0.001
+	// healthVectorsPruneInterval is the number of health vectors created between two prunes
+	healthVectorsPruneInterval = 1000
+)
+
+// BidderHealthVector is the health of a bidder for a combination of dimension values, like an account and a
+// channel, or for the bidder as a whole if there are no dimensions.
+type BidderHealthVector struct {
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	// Score is the unhealthy score, from 0 for a healthy bidder to 1.
+	Score     float64                    `json:"score"`
+	State     metrics.AdapterHealthState `json:"state"`
+	CheckedAt *time.Time                 `json:"checkedAt,omitempty"`
+	OpenedAt  *time.Time                 `json:"openedAt,omitempty"`
+}
+
+// healthReporter is implemented by the adapted bidders which track their health.
+type healthReporter interface {
+	healthVectors() []BidderHealthVector
+}
+
+// BidderHealth reports the health vectors of the bidders.
+type BidderHealth struct {
+	adapters map[openrtb_ext.BidderName]AdaptedBidder
+}
+
+// NewBidderHealth returns the reporter of the health vectors of the given adapters.
+func NewBidderHealth(adapters map[openrtb_ext.BidderName]AdaptedBidder) *BidderHealth {
+	return &BidderHealth{adapters: adapters}
+}
+
+// Vectors returns the health vectors by bidder. The bidders whose health isn't tracked are omitted.
+func (h *BidderHealth) Vectors() map[string][]BidderHealthVector {
+	vectors := make(map[string][]BidderHealthVector)
+	for name, adapter := range h.adapters {
+		if reporter, ok := adapter.(healthReporter); ok {
+			if bidderVectors := reporter.healthVectors(); len(bidderVectors) > 0 {
+				vectors[name.String()] = bidderVectors
+			}
+		}
+	}
+	return vectors
+}
+
+// bidderHealthVectors tracks the health of a bidder by the values of the configured dimensions of its requests,
+// e.g. account 1234 × app × video × USA, so a bidder failing for a part of the traffic is only throttled for that
+// part. The unhealthy scores decay over time, and the circuit breaker stops requesting a bidder whose score is too
+// high until a probe request succeeds.
+type bidderHealthVectors struct {
+	bidderName   openrtb_ext.BidderName
+	dimensions   []string
+	bulkValue    float64
+	deltaValue   float64
+	simulateOnly bool
+	decayWindow  time.Duration
+	breaker      bool
+	openScore    float64
+	openPeriod   time.Duration
+	me           metrics.MetricsEngine
+	time         func() time.Time
+	random       func() float64
+
+	mutex   sync.RWMutex
+	vectors map[string]*bidderHealthVector
+	created int
+
+	statesMutex sync.Mutex
+	states      map[metrics.AdapterHealthState]int
+}
+
+type bidderHealthVector struct {
+	dimensionValues []string
+
+	mutex     sync.Mutex
+	score     float64
+	decayedAt time.Time
+	checkedAt time.Time
+	state     metrics.AdapterHealthState
+	openedAt  time.Time
+	probedAt  time.Time
+}
+
+func newBidderHealthVectors(bidderName openrtb_ext.BidderName, throttleConfig bidderAdapterThrottleConfig, cfg config.HTTPThrottle, me metrics.MetricsEngine) *bidderHealthVectors {
+	return &bidderHealthVectors{
+		bidderName:   bidderName,
+		dimensions:   cfg.Dimensions,
+		bulkValue:    throttleConfig.bulkValue,
+		deltaValue:   throttleConfig.deltaValue,
+		simulateOnly: throttleConfig.simulateOnly,
+		decayWindow:  time.Duration(cfg.DecayWindowSeconds) * time.Second,
+		breaker:      cfg.CircuitBreaker.Enabled,
+		openScore:    cfg.CircuitBreaker.OpenThreshold,
+		openPeriod:   time.Duration(cfg.CircuitBreaker.OpenSeconds) * time.Second,
+		me:           me,
+		time:         time.Now,
+		random:       rand.Float64,
+		vectors:      make(map[string]*bidderHealthVector),
+		states:       make(map[metrics.AdapterHealthState]int),
+	}
+}
+
+// key returns the key of the health vector of the bidder request, made of the values of the dimensions.
+func (h *bidderHealthVectors) key(bidderRequest BidderRequest) string {
+	if len(h.dimensions) == 0 {
+		return ""
+	}
+
+	var key strings.Builder
+	for i, dimension := range h.dimensions {
+		if i > 0 {
+			key.WriteByte('|')
+		}
+		key.WriteString(healthDimensionValue(dimension, bidderRequest))
+	}
+	return key.String()
+}
+
+func healthDimensionValue(dimension string, bidderRequest BidderRequest) string {
+	request := bidderRequest.BidRequest
+	switch dimension {
+	case config.HealthDimensionAccount:
+		if bidderRequest.BidderLabels.PubID != "" {
+			return bidderRequest.BidderLabels.PubID
+		}
+	case config.HealthDimensionChannel:
+		switch {
+		case request.App != nil:
+			return string(metrics.DemandApp)
+		case request.DOOH != nil:
+			return string(metrics.DemandDOOH)
+		case request.Site != nil:
+			return "site"
+		}
+	case config.HealthDimensionMediaType:
+		if mediaTypes := impMediaTypes(request.Imp); len(mediaTypes) > 0 {
+			return strings.Join(mediaTypes, "+")
+		}
+	case config.HealthDimensionDeviceCountry:
+		if request.Device != nil && request.Device.Geo != nil && request.Device.Geo.Country != "" {
+			return request.Device.Geo.Country
+		}
+	}
+	return unknownHealthDimension
+}
+
+// impMediaTypes returns the sorted media types of the imps.
+func impMediaTypes(imps []openrtb2.Imp) []string {
+	var hasBanner, hasVideo, hasAudio, hasNative bool
+	for _, imp := range imps {
+		hasBanner = hasBanner || imp.Banner != nil
+		hasVideo = hasVideo || imp.Video != nil
+		hasAudio = hasAudio || imp.Audio != nil
+		hasNative = hasNative || imp.Native != nil
+	}
+
+	var mediaTypes []string
+	if hasAudio {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeAudio))
+	}
+	if hasBanner {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeBanner))
+	}
+	if hasNative {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeNative))
+	}
+	if hasVideo {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeVideo))
+	}
+	return mediaTypes
+}
+
+// shouldRequest returns true if a request should be made to the bidder for the health vector.
+func (h *bidderHealthVectors) shouldRequest(key string) bool {
+	h.mutex.RLock()
+	vector := h.vectors[key]
+	h.mutex.RUnlock()
+	if vector == nil {
+		return true
+	}
+
+	if h.allowRequest(vector) {
+		return true
+	}
+	h.me.RecordAdapterThrottled(h.bidderName)
+	return h.simulateOnly
+}
+
+func (h *bidderHealthVectors) allowRequest(vector *bidderHealthVector) bool {
+	now := h.time()
+	vector.mutex.Lock()
+	defer vector.mutex.Unlock()
+
+	h.decay(vector, now)
+	switch vector.state {
+	case metrics.AdapterHealthOpen:
+		if now.Sub(vector.openedAt) < h.openPeriod {
+			return false
+		}
+		// the first request after the open period is the probe
+		h.setState(vector, metrics.AdapterHealthHalfOpen)
+		vector.probedAt = now
+		return true
+	case metrics.AdapterHealthHalfOpen:
+		// another probe is allowed if the last one didn't complete, e.g. because of a local error
+		if now.Sub(vector.probedAt) < h.openPeriod {
+			return false
+		}
+		vector.probedAt = now
+		return true
+	}
+
+	if h.breaker || vector.score < 0.2 {
+		return true
+	}
+	// Probability of throttling ramps from 0 at 0.2 to 0.9 at 1.0, as for the health of the bidder as a whole
+	p := ((vector.score - 0.2) / 0.8) * 0.9
+	return h.random() >= p
+}
+
+// logHealthCheck registers a health check for the health vector. True for a healthy result, false for an
+// unhealthy result.
+func (h *bidderHealthVectors) logHealthCheck(key string, success bool) {
+	now := h.time()
+	vector := h.getOrCreate(key, now)
+	vector.mutex.Lock()
+	defer vector.mutex.Unlock()
+
+	h.decay(vector, now)
+	if success {
+		vector.score = h.bulkValue * vector.score
+	} else {
+		vector.score = h.bulkValue*vector.score + h.deltaValue
+	}
+	vector.checkedAt = now
+
+	switch vector.state {
+	case metrics.AdapterHealthHalfOpen:
+		if success {
+			vector.score = 0
+			h.setState(vector, metrics.AdapterHealthClosed)
+		} else {
+			vector.openedAt = now
+			h.setState(vector, metrics.AdapterHealthOpen)
+		}
+	case metrics.AdapterHealthClosed:
+		if h.breaker && vector.score >= h.openScore {
+			vector.openedAt = now
+			h.setState(vector, metrics.AdapterHealthOpen)
+		}
+	}
+}
+
+func (h *bidderHealthVectors) getOrCreate(key string, now time.Time) *bidderHealthVector {
+	h.mutex.RLock()
+	vector := h.vectors[key]
+	h.mutex.RUnlock()
+	if vector != nil {
+		return vector
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if vector = h.vectors[key]; vector != nil {
+		return vector
+	}
+
+	h.created++
+	if h.created%healthVectorsPruneInterval == 0 {
+		h.prune(now)
+	}
+
+	vector = &bidderHealthVector{state: metrics.AdapterHealthClosed, decayedAt: now}
+	if len(h.dimensions) > 0 {
+		vector.dimensionValues = strings.Split(key, "|")
+	}
+	h.vectors[key] = vector
+	h.updateStateCount(metrics.AdapterHealthClosed, 1)
+	return vector
+}
+
+// prune removes the health vectors which are as good as new. The caller must hold the write lock.
+func (h *bidderHealthVectors) prune(now time.Time) {
+	for key, vector := range h.vectors {
+		vector.mutex.Lock()
+		h.decay(vector, now)
+		prunable := vector.state == metrics.AdapterHealthClosed && vector.score < minHealthScore
+		vector.mutex.Unlock()
+
+		if prunable {
+			delete(h.vectors, key)
+			h.updateStateCount(metrics.AdapterHealthClosed, -1)
+		}
+	}
+}
+
+// decay halves the unhealthy score of the vector every decay window. The caller must hold the vector lock.
+func (h *bidderHealthVectors) decay(vector *bidderHealthVector, now time.Time) {
+	if h.decayWindow <= 0 {
+		return
+	}
+	if elapsed := now.Sub(vector.decayedAt); elapsed > 0 {
+		vector.score *= math.Pow(0.5, float64(elapsed)/float64(h.decayWindow))
+		vector.decayedAt = now
+	}
+}
+
+// setState changes the state of the vector. The caller must hold the vector lock.
+func (h *bidderHealthVectors) setState(vector *bidderHealthVector, state metrics.AdapterHealthState) {
+	if vector.state == state {
+		return
+	}
+	h.updateStateCount(vector.state, -1)
+	h.updateStateCount(state, 1)
+	vector.state = state
+}
+
+func (h *bidderHealthVectors) updateStateCount(state metrics.AdapterHealthState, delta int) {
+	h.statesMutex.Lock()
+	h.states[state] += delta
+	count := h.states[state]
+	h.statesMutex.Unlock()
+
+	h.me.RecordAdapterHealthVectors(h.bidderName, state, count)
+}
+
+// snapshot returns the health vectors sorted by key.
+func (h *bidderHealthVectors) snapshot() []BidderHealthVector {
+	now := h.time()
+	h.mutex.RLock()
+	keys := make([]string, 0, len(h.vectors))
+	for key := range h.vectors {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	vectors := make([]*bidderHealthVector, len(keys))
+	for i, key := range keys {
+		vectors[i] = h.vectors[key]
+	}
+	h.mutex.RUnlock()
+
+	snapshot := make([]BidderHealthVector, 0, len(vectors))
+	for _, vector := range vectors {
+		vector.mutex.Lock()
+		h.decay(vector, now)
+		healthVector := BidderHealthVector{
+			Score: vector.score,
+			State: vector.state,
+		}
+		if !vector.checkedAt.IsZero() {
+			checkedAt := vector.checkedAt
+			healthVector.CheckedAt = &checkedAt
+		}
+		if vector.state != metrics.AdapterHealthClosed {
+			openedAt := vector.openedAt
+			healthVector.OpenedAt = &openedAt
+		}
+		vector.mutex.Unlock()
+
+		if len(vector.dimensionValues) > 0 {
+			healthVector.Dimensions = make(map[string]string, len(h.dimensions))
+			for i, dimension := range h.dimensions {
+				healthVector.Dimensions[dimension] = vector.dimensionValues[i]
+			}
+		}
+		snapshot = append(snapshot, healthVector)
+	}
+	return snapshot
+}
@@ -3,13 +3,19 @@ package exchange
 import (
 	"math"
 	"math/rand"
+	"net/http"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
+	pbsconfig "github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestAdapter() *BidderAdapter {
@@ -282,3 +288,227 @@ func TestBidderAdapter_GetHealth(t *testing.T) {
 		assert.InDelta(t, val, actual, 0.000001, "getHealth() should return the stored health value")
 	}
 }
+
+var healthTestTime = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func newTestHealthVectors(cfg pbsconfig.HTTPThrottle, me metrics.MetricsEngine) (*bidderHealthVectors, *time.Time) {
+	now := healthTestTime
+	h := newBidderHealthVectors(openrtb_ext.BidderAppnexus, bidderAdapterThrottleConfig{bulkValue: 0.5, deltaValue: 0.5}, cfg, me)
+	h.time = func() time.Time { return now }
+	h.random = func() float64 { return 0 }
+	return h, &now
+}
+
+func TestBidderHealthVectorsKey(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dimensions    []string
+		bidderRequest BidderRequest
+		expectedKey   string
+	}{
+		{
+			name:          "no-dimensions",
+			bidderRequest: BidderRequest{BidRequest: &openrtb2.BidRequest{}},
+			expectedKey:   "",
+		},
+		{
+			name:       "all-dimensions",
+			dimensions: []string{"account", "channel", "media_type", "device_country"},
+			bidderRequest: BidderRequest{
+				BidRequest: &openrtb2.BidRequest{
+					App:    &openrtb2.App{},
+					Imp:    []openrtb2.Imp{{Video: &openrtb2.Video{}}, {Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}}},
+					Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+				},
+				BidderLabels: metrics.AdapterLabels{PubID: "1234"},
+			},
+			expectedKey: "1234|app|banner+video|USA",
+		},
+		{
+			name:       "site-and-dooh",
+			dimensions: []string{"channel"},
+			bidderRequest: BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Site: &openrtb2.Site{}},
+			},
+			expectedKey: "site",
+		},
+		{
+			name:       "unknown-values",
+			dimensions: []string{"device_country", "account", "channel", "media_type"},
+			bidderRequest: BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{}},
+			},
+			expectedKey: "unknown|unknown|unknown|unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, _ := newTestHealthVectors(pbsconfig.HTTPThrottle{Dimensions: tc.dimensions}, &config.NilMetricsEngine{})
+			assert.Equal(t, tc.expectedKey, h.key(tc.bidderRequest))
+		})
+	}
+}
+
+func TestBidderHealthVectorsPerDimension(t *testing.T) {
+	h, _ := newTestHealthVectors(pbsconfig.HTTPThrottle{Dimensions: []string{"account"}}, &config.NilMetricsEngine{})
+
+	for i := 0; i < 10; i++ {
+		h.logHealthCheck("unhealthy", false)
+		h.logHealthCheck("healthy", true)
+	}
+
+	assert.False(t, h.shouldRequest("unhealthy"), "the failing account should be throttled")
+	assert.True(t, h.shouldRequest("healthy"), "the other accounts should not be throttled")
+	assert.True(t, h.shouldRequest("new"), "the accounts without health checks should not be throttled")
+
+	assert.Equal(t, []BidderHealthVector{
+		{Dimensions: map[string]string{"account": "healthy"}, Score: 0, State: metrics.AdapterHealthClosed, CheckedAt: &healthTestTime},
+		{Dimensions: map[string]string{"account": "unhealthy"}, Score: 1 - math.Pow(0.5, 10), State: metrics.AdapterHealthClosed, CheckedAt: &healthTestTime},
+	}, h.snapshot())
+}
+
+func TestBidderHealthVectorsSimulateOnly(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, metrics.AdapterHealthClosed, 1).Return()
+	me.On("RecordAdapterThrottled", openrtb_ext.BidderAppnexus).Return()
+	h, _ := newTestHealthVectors(pbsconfig.HTTPThrottle{DecayWindowSeconds: 60}, me)
+	h.simulateOnly = true
+
+	h.logHealthCheck("", false)
+	h.logHealthCheck("", false)
+
+	assert.True(t, h.shouldRequest(""))
+	me.AssertNumberOfCalls(t, "RecordAdapterThrottled", 1)
+}
+
+func TestBidderHealthVectorsDecay(t *testing.T) {
+	h, now := newTestHealthVectors(pbsconfig.HTTPThrottle{DecayWindowSeconds: 60}, &config.NilMetricsEngine{})
+
+	h.logHealthCheck("", false)
+	h.logHealthCheck("", false)
+	assert.InDelta(t, 0.75, h.snapshot()[0].Score, 0.000001)
+	assert.False(t, h.shouldRequest(""))
+
+	*now = now.Add(time.Minute)
+	assert.InDelta(t, 0.375, h.snapshot()[0].Score, 0.000001, "the score should halve every decay window")
+
+	*now = now.Add(2 * time.Minute)
+	assert.InDelta(t, 0.09375, h.snapshot()[0].Score, 0.000001, "the score should halve every decay window")
+	assert.True(t, h.shouldRequest(""), "the bidder should recover without health checks")
+
+	h.logHealthCheck("", false)
+	assert.InDelta(t, 0.546875, h.snapshot()[0].Score, 0.000001, "the health checks should apply to the decayed score")
+}
+
+func TestBidderHealthVectorsCircuitBreaker(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, mock.Anything, mock.Anything).Return()
+	me.On("RecordAdapterThrottled", openrtb_ext.BidderAppnexus).Return()
+	h, now := newTestHealthVectors(pbsconfig.HTTPThrottle{
+		CircuitBreaker: pbsconfig.HTTPThrottleCircuitBreaker{Enabled: true, OpenThreshold: 0.7, OpenSeconds: 30},
+	}, me)
+	h.random = func() float64 { panic("the breaker should not throttle randomly") }
+	openedAt := healthTestTime
+
+	h.logHealthCheck("", false)
+	assert.Equal(t, metrics.AdapterHealthClosed, h.snapshot()[0].State)
+	assert.True(t, h.shouldRequest(""), "the closed breaker should not throttle below the threshold")
+
+	h.logHealthCheck("", false)
+	assert.Equal(t, BidderHealthVector{Score: 0.75, State: metrics.AdapterHealthOpen, CheckedAt: &healthTestTime, OpenedAt: &openedAt}, h.snapshot()[0])
+	assert.False(t, h.shouldRequest(""), "the open breaker should throttle")
+
+	*now = now.Add(30 * time.Second)
+	assert.True(t, h.shouldRequest(""), "the first request after the open period should be the probe")
+	assert.Equal(t, metrics.AdapterHealthHalfOpen, h.snapshot()[0].State)
+	assert.False(t, h.shouldRequest(""), "the other requests should be throttled during the probe")
+
+	h.logHealthCheck("", false)
+	openedAt = *now
+	assert.Equal(t, metrics.AdapterHealthOpen, h.snapshot()[0].State, "the failed probe should open the breaker again")
+	assert.Equal(t, &openedAt, h.snapshot()[0].OpenedAt)
+	assert.False(t, h.shouldRequest(""))
+
+	*now = now.Add(30 * time.Second)
+	assert.True(t, h.shouldRequest(""))
+	*now = now.Add(30 * time.Second)
+	assert.True(t, h.shouldRequest(""), "another probe should be allowed if the last one didn't complete")
+
+	h.logHealthCheck("", true)
+	assert.Equal(t, BidderHealthVector{Score: 0, State: metrics.AdapterHealthClosed, CheckedAt: now}, h.snapshot()[0], "the successful probe should close the breaker")
+	assert.True(t, h.shouldRequest(""))
+
+	me.AssertNumberOfCalls(t, "RecordAdapterThrottled", 3)
+	me.AssertCalled(t, "RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, metrics.AdapterHealthOpen, 1)
+	me.AssertCalled(t, "RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, metrics.AdapterHealthHalfOpen, 1)
+	me.AssertCalled(t, "RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, metrics.AdapterHealthHalfOpen, 0)
+	me.AssertCalled(t, "RecordAdapterHealthVectors", openrtb_ext.BidderAppnexus, metrics.AdapterHealthClosed, 1)
+}
+
+func TestBidderHealthVectorsPrune(t *testing.T) {
+	h, now := newTestHealthVectors(pbsconfig.HTTPThrottle{Dimensions: []string{"account"}, DecayWindowSeconds: 1}, &config.NilMetricsEngine{})
+	h.logHealthCheck("unhealthy", false)
+	h.logHealthCheck("healthy", true)
+
+	*now = now.Add(time.Minute)
+	h.mutex.Lock()
+	h.prune(*now)
+	h.mutex.Unlock()
+
+	assert.Empty(t, h.snapshot(), "the vectors as good as new should be pruned")
+	assert.Equal(t, map[metrics.AdapterHealthState]int{metrics.AdapterHealthClosed: 0}, h.states)
+}
+
+func TestAdaptBidderHealthVectors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		throttle       pbsconfig.HTTPThrottle
+		expectedHealth bool
+	}{
+		{
+			name:     "throttling-disabled",
+			throttle: pbsconfig.HTTPThrottle{Dimensions: []string{"account"}},
+		},
+		{
+			name:     "bidder-health",
+			throttle: pbsconfig.HTTPThrottle{EnableThrottling: true},
+		},
+		{
+			name:           "health-vectors",
+			throttle:       pbsconfig.HTTPThrottle{EnableThrottling: true, Dimensions: []string{"account"}},
+			expectedHealth: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &pbsconfig.Configuration{Client: pbsconfig.HTTPClient{Throttle: tc.throttle}}
+			bidder := AdaptBidder(nil, &http.Client{}, cfg, &config.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+			assert.Equal(t, tc.expectedHealth, bidder.health != nil)
+		})
+	}
+}
+
+func TestBidderHealthVectors(t *testing.T) {
+	withoutThrottling := newTestAdapter()
+	withoutThrottling.config.ThrottleConfig.enabled = false
+
+	withHealth := newTestAdapter()
+	withHealth.healthBits.Store(math.Float64bits(0.5))
+
+	withVectors := newTestAdapter()
+	withVectors.health, _ = newTestHealthVectors(pbsconfig.HTTPThrottle{Dimensions: []string{"account"}}, &config.NilMetricsEngine{})
+	withVectors.health.logHealthCheck("1234", false)
+
+	h := NewBidderHealth(map[openrtb_ext.BidderName]AdaptedBidder{
+		openrtb_ext.BidderAppnexus: withoutThrottling,
+		openrtb_ext.BidderRubicon:  &validatedBidder{bidder: withHealth},
+		openrtb_ext.BidderPubmatic: withVectors,
+	})
+
+	vectors := h.Vectors()
+	require.Len(t, vectors, 2, "the bidders without throttling should be omitted")
+	assert.Equal(t, []BidderHealthVector{{Score: 0.5, State: metrics.AdapterHealthClosed}}, vectors["rubicon"])
+	assert.Equal(t, []BidderHealthVector{{Dimensions: map[string]string{"account": "1234"}, Score: 0.5, State: metrics.AdapterHealthClosed, CheckedAt: &healthTestTime}}, vectors["pubmatic"])
+}
@@ -0,0 +1,395 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/usersync"
+)
+
+// BidderInfoReloadStatus describes the outcome of the last bidder info reloads.
+type BidderInfoReloadStatus struct {
+	// ReloadedAt is when bidder info changes were last applied.
+	ReloadedAt *time.Time `json:"reloadedAt,omitempty"`
+	// FailedAt is when a reload was last refused, and Errors explains why.
+	FailedAt *time.Time `json:"failedAt,omitempty"`
+	Errors   []string   `json:"errors,omitempty"`
+}
+
+// BidderInfoReloader swaps the adapters and syncers of the bidders when their bidder infos change, so a
+// bidder can be disabled or its endpoint or user sync changed without a restart. New bidder infos are
+// validated as at startup and refused as a whole if they can't be applied to the running server, for
+// instance because they add bidders or change settings read once at startup.
+//
+// The adapters and syncers given to the exchange and endpoints must be the ones returned by the
+// reloader. Requests already sent to a bidder complete with the adapter they started with. Unless
+// bidder_info_reload.enabled is true, the adapters and syncers aren't wrapped and the reloads are refused.
+type BidderInfoReloader struct {
+	cfg     *config.Configuration
+	client  *http.Client
+	me      metrics.MetricsEngine
+	load    func() (config.BidderInfos, error)
+	time    func() time.Time
+	enabled bool
+
+	// startupAdapters and startupSyncers are handed out as is when the reloads are disabled
+	startupAdapters map[openrtb_ext.BidderName]AdaptedBidder
+	startupSyncers  map[string]usersync.Syncer
+
+	// mutex serializes the reloads
+	mutex     sync.Mutex
+	infos     atomic.Pointer[config.BidderInfos]
+	status    atomic.Pointer[BidderInfoReloadStatus]
+	adapters  map[openrtb_ext.BidderName]*reloadableBidder
+	syncers   map[string]*usersync.ReloadableSyncer
+	listeners []func(config.BidderInfos)
+}
+
+// NewBidderInfoReloader wraps the adapters and syncers built at startup from the host config bidder infos if
+// the reloads are enabled. Its Run method reloads the bidder infos from the bidder_info_reload.path directory.
+func NewBidderInfoReloader(cfg *config.Configuration, client *http.Client, me metrics.MetricsEngine, adapters map[openrtb_ext.BidderName]AdaptedBidder, syncersByBidder map[string]usersync.Syncer) *BidderInfoReloader {
+	r := &BidderInfoReloader{
+		cfg:    cfg,
+		client: client,
+		me:     me,
+		load: func() (config.BidderInfos, error) {
+			return config.ReloadBidderInfoFromDisk(cfg.BidderInfoReload.Path)
+		},
+		time:            time.Now,
+		enabled:         cfg.BidderInfoReload.Enabled,
+		startupAdapters: adapters,
+		startupSyncers:  syncersByBidder,
+	}
+	r.infos.Store(&cfg.BidderInfos)
+	r.status.Store(&BidderInfoReloadStatus{})
+	if !r.enabled {
+		return r
+	}
+
+	r.adapters = make(map[openrtb_ext.BidderName]*reloadableBidder, len(adapters))
+	for name, adapter := range adapters {
+		bidder := &reloadableBidder{name: name, last: adapter}
+		bidder.current.Store(&adapter)
+		r.adapters[name] = bidder
+	}
+	r.syncers = make(map[string]*usersync.ReloadableSyncer, len(syncersByBidder))
+	for name, syncer := range syncersByBidder {
+		r.syncers[name] = usersync.NewReloadableSyncer(syncer)
+	}
+	return r
+}
+
+// Adapters returns the adapters to be used by the exchange.
+func (r *BidderInfoReloader) Adapters() map[openrtb_ext.BidderName]AdaptedBidder {
+	if !r.enabled {
+		return r.startupAdapters
+	}
+	adapters := make(map[openrtb_ext.BidderName]AdaptedBidder, len(r.adapters))
+	for name, adapter := range r.adapters {
+		adapters[name] = adapter
+	}
+	return adapters
+}
+
+// Syncers returns the syncers to be used by the exchange and the user sync endpoints.
+func (r *BidderInfoReloader) Syncers() map[string]usersync.Syncer {
+	if !r.enabled {
+		return r.startupSyncers
+	}
+	syncers := make(map[string]usersync.Syncer, len(r.syncers))
+	for name, syncer := range r.syncers {
+		syncers[name] = syncer
+	}
+	return syncers
+}
+
+// OnReload registers a listener called with the new bidder infos after every reload applied, for the
+// components serving data derived from them. It must be called before the reloads start.
+func (r *BidderInfoReloader) OnReload(listener func(config.BidderInfos)) {
+	r.listeners = append(r.listeners, listener)
+}
+
+// BidderInfos returns the bidder infos currently in effect. They must not be modified.
+func (r *BidderInfoReloader) BidderInfos() config.BidderInfos {
+	return *r.infos.Load()
+}
+
+// Status returns the outcome of the last reloads.
+func (r *BidderInfoReloader) Status() BidderInfoReloadStatus {
+	return *r.status.Load()
+}
+
+// Run reloads the bidder infos from disk. It implements the task.Runner interface to poll the files.
+func (r *BidderInfoReloader) Run() error {
+	_, err := r.ReloadFromDisk()
+	return err
+}
+
+// ReloadFromDisk reads the bidder info files, applies the host config adapter overrides and reloads the
+// result. It returns the names of the bidders whose info changed.
+func (r *BidderInfoReloader) ReloadFromDisk() ([]string, error) {
+	fsBidderInfos, err := r.load()
+	if err == nil {
+		var infos config.BidderInfos
+		if infos, err = r.cfg.ResolveBidderInfos(fsBidderInfos); err == nil {
+			return r.Reload(infos)
+		}
+	}
+	r.fail([]error{err})
+	return nil, err
+}
+
+// Reload applies the given bidder infos if they are valid and can be applied to the running server,
+// otherwise the current bidder infos are kept. It returns the names of the bidders whose info changed.
+func (r *BidderInfoReloader) Reload(infos config.BidderInfos) ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.enabled {
+		return nil, r.fail([]error{errors.New("bidder info reload is disabled")})
+	}
+
+	current := *r.infos.Load()
+	changed := changedBidders(current, infos)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	errs := r.validateReload(current, infos)
+	if len(errs) > 0 {
+		return nil, r.fail(errs)
+	}
+
+	syncersByBidder, errs := usersync.BuildSyncers(r.cfg, infos)
+	if len(errs) > 0 {
+		return nil, r.fail(errs)
+	}
+	for _, bidder := range sortedBidders(syncersByBidder) {
+		if current, ok := r.syncers[bidder]; !ok || current.Key() != syncersByBidder[bidder].Key() {
+			errs = append(errs, fmt.Errorf("the user sync key of bidder %s cannot be changed without a restart", bidder))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, r.fail(errs)
+	}
+
+	changedInfos := make(config.BidderInfos, len(changed))
+	for _, bidder := range changed {
+		changedInfos[bidder] = infos[bidder]
+	}
+	adapters, _, errs := BuildAdapters(r.client, r.cfg, changedInfos, r.me)
+	if len(errs) > 0 {
+		return nil, r.fail(errs)
+	}
+
+	for _, bidder := range changed {
+		reloadable, ok := r.adapters[openrtb_ext.BidderName(bidder)]
+		if !ok {
+			continue
+		}
+		if adapter, ok := adapters[openrtb_ext.BidderName(bidder)]; ok {
+			// the health is kept so a reload doesn't reset the throttling of an unhealthy bidder
+			inheritHealth(reloadable.last, adapter)
+			reloadable.last = adapter
+			reloadable.current.Store(&adapter)
+		} else {
+			reloadable.current.Store(nil)
+		}
+	}
+	for bidder, syncer := range r.syncers {
+		if newSyncer, ok := syncersByBidder[bidder]; ok {
+			syncer.Swap(newSyncer)
+		} else {
+			syncer.Swap(nil)
+		}
+	}
+	r.infos.Store(&infos)
+	for _, listener := range r.listeners {
+		listener(infos)
+	}
+
+	now := r.time()
+	status := r.Status()
+	status.ReloadedAt = &now
+	r.status.Store(&status)
+	glog.Infof("Reloaded the bidder infos of %v", changed)
+	return changed, nil
+}
+
+// validateReload refuses the changes which can't be applied to the running server. The bidder names, the
+// aliases and the settings read once at startup by the exchange, endpoints and metrics are fixed.
+func (r *BidderInfoReloader) validateReload(current config.BidderInfos, infos config.BidderInfos) []error {
+	var errs []error
+	for _, bidder := range sortedBidders(current) {
+		if _, ok := infos[bidder]; !ok {
+			errs = append(errs, fmt.Errorf("bidder %s cannot be removed without a restart", bidder))
+		}
+	}
+	for _, bidder := range sortedBidders(infos) {
+		info := infos[bidder]
+		currentInfo, ok := current[bidder]
+		if !ok {
+			errs = append(errs, fmt.Errorf("bidder %s cannot be added without a restart", bidder))
+			continue
+		}
+		if _, ok := r.adapters[openrtb_ext.BidderName(bidder)]; !ok && info.IsEnabled() {
+			errs = append(errs, fmt.Errorf("bidder %s was disabled at startup and cannot be enabled without a restart", bidder))
+		}
+		for _, field := range []struct {
+			name  string
+			equal bool
+		}{
+			{"aliasOf", info.AliasOf == currentInfo.AliasOf},
+			{"whiteLabelOnly", info.WhiteLabelOnly == currentInfo.WhiteLabelOnly},
+			{"gvlVendorID", info.GVLVendorID == currentInfo.GVLVendorID},
+			{"modifyingVastXmlAllowed", info.ModifyingVastXmlAllowed == currentInfo.ModifyingVastXmlAllowed},
+			{"experiment", info.Experiment == currentInfo.Experiment},
+			{"openrtb", reflect.DeepEqual(info.OpenRTB, currentInfo.OpenRTB)},
+			{"userSync.enabled", reflect.DeepEqual(syncerEnabled(info), syncerEnabled(currentInfo))},
+			{"userSync.skipwhen", reflect.DeepEqual(syncerSkipWhen(info), syncerSkipWhen(currentInfo))},
+		} {
+			if !field.equal {
+				errs = append(errs, fmt.Errorf("%s of bidder %s cannot be changed without a restart", field.name, bidder))
+			}
+		}
+	}
+	return errs
+}
+
+func (r *BidderInfoReloader) fail(errs []error) error {
+	err := errortypes.NewAggregateError("bidder info reload refused", errs)
+	glog.Errorf("%v", err)
+
+	now := r.time()
+	status := r.Status()
+	status.FailedAt = &now
+	status.Errors = make([]string, 0, len(errs))
+	for _, e := range errs {
+		status.Errors = append(status.Errors, e.Error())
+	}
+	r.status.Store(&status)
+	return err
+}
+
+func changedBidders(current config.BidderInfos, infos config.BidderInfos) []string {
+	var changed []string
+	for bidder, info := range infos {
+		if currentInfo, ok := current[bidder]; !ok || !reflect.DeepEqual(info, currentInfo) {
+			changed = append(changed, bidder)
+		}
+	}
+	for bidder := range current {
+		if _, ok := infos[bidder]; !ok {
+			changed = append(changed, bidder)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func sortedBidders[V any](bidders map[string]V) []string {
+	return slices.Sorted(maps.Keys(bidders))
+}
+
+func syncerEnabled(info config.BidderInfo) *bool {
+	if info.Syncer == nil {
+		return nil
+	}
+	return info.Syncer.Enabled
+}
+
+func syncerSkipWhen(info config.BidderInfo) *config.SkipWhen {
+	if info.Syncer == nil {
+		return nil
+	}
+	return info.Syncer.SkipWhen
+}
+
+// currentBidderInfos returns the bidder infos in effect, the startup ones unless they're reloadable.
+func currentBidderInfos(reloader *BidderInfoReloader, startupInfos config.BidderInfos) config.BidderInfos {
+	if reloader == nil {
+		return startupInfos
+	}
+	return reloader.BidderInfos()
+}
+
+// inheritHealth hands the health tracked by the previous adapter of a bidder over to its new adapter.
+func inheritHealth(previous, next AdaptedBidder) {
+	from, to := unwrapBidderAdapter(previous), unwrapBidderAdapter(next)
+	if from == nil || to == nil {
+		return
+	}
+	to.healthBits.Store(from.healthBits.Load())
+	if from.health != nil && to.health != nil {
+		to.health = from.health
+	}
+}
+
+func unwrapBidderAdapter(bidder AdaptedBidder) *BidderAdapter {
+	switch b := bidder.(type) {
+	case *BidderAdapter:
+		return b
+	case *validatedBidder:
+		return unwrapBidderAdapter(b.bidder)
+	}
+	return nil
+}
+
+// reloadableBidder delegates to the adapter of the current bidder info of the bidder, or rejects the
+// requests if the bidder has been disabled.
+type reloadableBidder struct {
+	name    openrtb_ext.BidderName
+	current atomic.Pointer[AdaptedBidder]
+	// last is the last adapter of the bidder, kept while it's disabled to carry its health over, guarded by
+	// the mutex of the reloader
+	last AdaptedBidder
+}
+
+func (b *reloadableBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
+	bidder := b.current.Load()
+	if bidder == nil {
+		msg := fmt.Sprintf(`Bidder "%s" has been disabled on this instance of Prebid Server. Please work with the PBS host to enable this bidder again.`, b.name)
+		return nil, extraBidderRespInfo{}, []error{&errortypes.BidderTemporarilyDisabled{Message: msg}}
+	}
+	return (*bidder).requestBid(ctx, bidderRequest, conversions, reqInfo, adsCertSigner, bidRequestOptions, alternateBidderCodes, hookExecutor, ruleToAdjustments)
+}
+
+func (b *reloadableBidder) logHealthCheck(success bool) {
+	if bidder := b.current.Load(); bidder != nil {
+		(*bidder).logHealthCheck(success)
+	}
+}
+
+func (b *reloadableBidder) shouldRequest() bool {
+	if bidder := b.current.Load(); bidder != nil {
+		return (*bidder).shouldRequest()
+	}
+	return false
+}
+
+func (b *reloadableBidder) healthVectors() []BidderHealthVector {
+	if bidder := b.current.Load(); bidder != nil {
+		if reporter, ok := (*bidder).(healthReporter); ok {
+			return reporter.healthVectors()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	metrics "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReloaderInfos() config.BidderInfos {
+	return config.BidderInfos{
+		"appnexus": {
+			Endpoint:    "https://appnexus.com/bid",
+			GVLVendorID: 32,
+			Syncer: &config.Syncer{
+				Key:      "adnxs",
+				Redirect: &config.SyncerEndpoint{URL: "https://appnexus.com/sync?r={{.RedirectURL}}", UserMacro: "$UID"},
+			},
+		},
+		"rubicon": {
+			Endpoint: "https://rubicon.com/bid",
+		},
+		"boldwin": {
+			Disabled: true,
+			Endpoint: "https://boldwin.com/bid",
+		},
+	}
+}
+
+func newTestReloader(t *testing.T) *BidderInfoReloader {
+	t.Helper()
+	return newTestReloaderWithConfig(t, func(cfg *config.Configuration) {})
+}
+
+func newTestReloaderWithConfig(t *testing.T, setConfig func(cfg *config.Configuration)) *BidderInfoReloader {
+	t.Helper()
+
+	cfg := &config.Configuration{
+		ExternalURL:      "http://host.com",
+		UserSync:         config.UserSync{RedirectURL: "{{.ExternalURL}}/setuid?bidder={{.SyncerKey}}&uid={{.UserMacro}}"},
+		BidderInfos:      newTestReloaderInfos(),
+		BidderInfoReload: config.BidderInfoReload{Enabled: true},
+	}
+	setConfig(cfg)
+	client := &http.Client{}
+	me := &metrics.NilMetricsEngine{}
+
+	adapters, _, errs := BuildAdapters(client, cfg, cfg.BidderInfos, me)
+	require.Empty(t, errs)
+	syncers, errs := usersync.BuildSyncers(cfg, cfg.BidderInfos)
+	require.Empty(t, errs)
+
+	r := NewBidderInfoReloader(cfg, client, me, adapters, syncers)
+	r.time = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	return r
+}
+
+func TestBidderInfoReloaderWraps(t *testing.T) {
+	r := newTestReloader(t)
+
+	assert.Len(t, r.Adapters(), 2, "only the enabled bidders have adapters")
+	assert.IsType(t, &reloadableBidder{}, r.Adapters()[openrtb_ext.BidderAppnexus])
+
+	syncers := r.Syncers()
+	require.Len(t, syncers, 1)
+	assert.IsType(t, &usersync.ReloadableSyncer{}, syncers["appnexus"])
+	assert.Equal(t, "adnxs", syncers["appnexus"].Key())
+
+	assert.Equal(t, newTestReloaderInfos(), r.BidderInfos())
+	assert.Equal(t, BidderInfoReloadStatus{}, r.Status())
+}
+
+func TestBidderInfoReloaderReloadDisabled(t *testing.T) {
+	r := newTestReloaderWithConfig(t, func(cfg *config.Configuration) {
+		cfg.BidderInfoReload.Enabled = false
+	})
+
+	assert.Len(t, r.Adapters(), 2)
+	assert.IsType(t, &validatedBidder{}, r.Adapters()[openrtb_ext.BidderAppnexus], "the adapters should not be wrapped")
+	_, wrapped := r.Syncers()["appnexus"].(*usersync.ReloadableSyncer)
+	assert.False(t, wrapped, "the syncers should not be wrapped")
+
+	infos := newTestReloaderInfos()
+	infos["rubicon"] = config.BidderInfo{Endpoint: "https://new.rubicon.com/bid"}
+	changed, err := r.Reload(infos)
+
+	assert.Nil(t, changed)
+	assert.ErrorContains(t, err, "bidder info reload is disabled")
+	assert.Equal(t, newTestReloaderInfos(), r.BidderInfos())
+}
+
+func TestBidderInfoReloaderKeepsHealth(t *testing.T) {
+	r := newTestReloaderWithConfig(t, func(cfg *config.Configuration) {
+		cfg.Client.Throttle = config.HTTPThrottle{EnableThrottling: true, ThrottleWindow: 10}
+	})
+	bidder := r.Adapters()[openrtb_ext.BidderRubicon]
+	bidder.logHealthCheck(false)
+	health := bidder.(*reloadableBidder).healthVectors()
+	require.Len(t, health, 1)
+	require.Greater(t, health[0].Score, 0.0)
+
+	infos := newTestReloaderInfos()
+	infos["rubicon"] = config.BidderInfo{Endpoint: "https://new.rubicon.com/bid"}
+	_, err := r.Reload(infos)
+	require.NoError(t, err)
+
+	assert.Equal(t, health, bidder.(*reloadableBidder).healthVectors(), "the health should be kept across reloads")
+}
+
+func TestBidderInfoReloaderOnReload(t *testing.T) {
+	r := newTestReloader(t)
+	var reloaded []config.BidderInfos
+	r.OnReload(func(infos config.BidderInfos) {
+		reloaded = append(reloaded, infos)
+	})
+
+	infos := newTestReloaderInfos()
+	infos["rubicon"] = config.BidderInfo{Endpoint: "https://new.rubicon.com/bid"}
+	_, err := r.Reload(infos)
+	require.NoError(t, err)
+	_, err = r.Reload(infos)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.BidderInfos{infos}, reloaded, "the listeners should only be called when the bidder infos change")
+}
+
+func TestBidderInfoReloaderReload(t *testing.T) {
+	reloadedAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		modify          func(infos config.BidderInfos)
+		expectedChanged []string
+		expectedErrors  []string
+	}{
+		{
+			name:   "no-changes",
+			modify: func(infos config.BidderInfos) {},
+		},
+		{
+			name: "endpoint-changed",
+			modify: func(infos config.BidderInfos) {
+				info := infos["rubicon"]
+				info.Endpoint = "https://eu.rubicon.com/bid"
+				infos["rubicon"] = info
+			},
+			expectedChanged: []string{"rubicon"},
+		},
+		{
+			name: "bidder-disabled",
+			modify: func(infos config.BidderInfos) {
+				info := infos["appnexus"]
+				info.Disabled = true
+				infos["appnexus"] = info
+			},
+			expectedChanged: []string{"appnexus"},
+		},
+		{
+			name: "disabled-bidder-changed",
+			modify: func(infos config.BidderInfos) {
+				info := infos["boldwin"]
+				info.Endpoint = "https://eu.boldwin.com/bid"
+				infos["boldwin"] = info
+			},
+			expectedChanged: []string{"boldwin"},
+		},
+		{
+			name: "bidder-disabled-at-startup-enabled",
+			modify: func(infos config.BidderInfos) {
+				info := infos["boldwin"]
+				info.Disabled = false
+				infos["boldwin"] = info
+			},
+			expectedErrors: []string{"bidder boldwin was disabled at startup and cannot be enabled without a restart"},
+		},
+		{
+			name: "bidder-added-and-removed",
+			modify: func(infos config.BidderInfos) {
+				delete(infos, "rubicon")
+				infos["pubmatic"] = config.BidderInfo{Endpoint: "https://pubmatic.com/bid"}
+			},
+			expectedErrors: []string{
+				"bidder rubicon cannot be removed without a restart",
+				"bidder pubmatic cannot be added without a restart",
+			},
+		},
+		{
+			name: "startup-settings-changed",
+			modify: func(infos config.BidderInfos) {
+				info := infos["appnexus"]
+				info.GVLVendorID = 33
+				info.OpenRTB = &config.OpenRTBInfo{Version: "2.6"}
+				infos["appnexus"] = info
+			},
+			expectedErrors: []string{
+				"gvlVendorID of bidder appnexus cannot be changed without a restart",
+				"openrtb of bidder appnexus cannot be changed without a restart",
+			},
+		},
+		{
+			name: "syncer-key-changed",
+			modify: func(infos config.BidderInfos) {
+				info := infos["appnexus"]
+				info.Syncer = &config.Syncer{Key: "appnexus", Redirect: info.Syncer.Redirect}
+				infos["appnexus"] = info
+			},
+			expectedErrors: []string{"the user sync key of bidder appnexus cannot be changed without a restart"},
+		},
+		{
+			name: "syncer-invalid",
+			modify: func(infos config.BidderInfos) {
+				info := infos["appnexus"]
+				info.Syncer = &config.Syncer{Key: "adnxs", Redirect: &config.SyncerEndpoint{URL: "https://appnexus.com/sync?r={{xRedirectURL}}"}}
+				infos["appnexus"] = info
+			},
+			expectedErrors: []string{`cannot create syncer for bidder appnexus with key adnxs: redirect template: appnexus_usersync_url:1: function "xRedirectURL" not defined`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReloader(t)
+			appnexus := r.adapters[openrtb_ext.BidderAppnexus].current.Load()
+			rubicon := r.adapters[openrtb_ext.BidderRubicon].current.Load()
+
+			infos := newTestReloaderInfos()
+			tc.modify(infos)
+			changed, err := r.Reload(infos)
+
+			if len(tc.expectedErrors) > 0 {
+				assert.Error(t, err)
+				assert.Nil(t, changed)
+				assert.Equal(t, newTestReloaderInfos(), r.BidderInfos(), "the bidder infos should be kept")
+				assert.Equal(t, BidderInfoReloadStatus{FailedAt: &reloadedAt, Errors: tc.expectedErrors}, r.Status())
+				assert.Same(t, appnexus, r.adapters[openrtb_ext.BidderAppnexus].current.Load())
+				assert.Same(t, rubicon, r.adapters[openrtb_ext.BidderRubicon].current.Load())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedChanged, changed)
+			assert.Equal(t, infos, r.BidderInfos())
+			if len(tc.expectedChanged) > 0 {
+				assert.Equal(t, BidderInfoReloadStatus{ReloadedAt: &reloadedAt}, r.Status())
+			}
+			assert.Equal(t, slices.Contains(changed, "appnexus"), appnexus != r.adapters[openrtb_ext.BidderAppnexus].current.Load(), "only the changed adapters should be swapped")
+			assert.Equal(t, slices.Contains(changed, "rubicon"), rubicon != r.adapters[openrtb_ext.BidderRubicon].current.Load(), "only the changed adapters should be swapped")
+		})
+	}
+}
+
+func TestBidderInfoReloaderDisabledBidder(t *testing.T) {
+	r := newTestReloader(t)
+
+	infos := newTestReloaderInfos()
+	info := infos["appnexus"]
+	info.Disabled = true
+	infos["appnexus"] = info
+	_, err := r.Reload(infos)
+	require.NoError(t, err)
+
+	bidder := r.Adapters()[openrtb_ext.BidderAppnexus]
+	seatBids, _, errs := bidder.requestBid(context.Background(), BidderRequest{}, nil, nil, nil, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, nil, nil)
+	assert.Empty(t, seatBids)
+	assert.Equal(t, []error{&errortypes.BidderTemporarilyDisabled{Message: `Bidder "appnexus" has been disabled on this instance of Prebid Server. Please work with the PBS host to enable this bidder again.`}}, errs)
+	assert.False(t, bidder.shouldRequest())
+
+	syncer := r.Syncers()["appnexus"]
+	assert.Equal(t, "adnxs", syncer.Key())
+	assert.False(t, syncer.SupportsType([]usersync.SyncType{usersync.SyncTypeRedirect}))
+
+	// enabled again
+	_, err = r.Reload(newTestReloaderInfos())
+	require.NoError(t, err)
+	assert.True(t, bidder.shouldRequest())
+	assert.True(t, syncer.SupportsType([]usersync.SyncType{usersync.SyncTypeRedirect}))
+}
+
+func TestBidderInfoReloaderReloadFromDisk(t *testing.T) {
+	bidderInfos, err := config.LoadBidderInfoFromDisk("../static/bidder-info")
+	require.NoError(t, err)
+
+	v := viper.New()
+	config.SetupViper(v, "", bidderInfos)
+	v.Set("gdpr.default_value", "0")
+	v.Set("bidder_info_reload.enabled", true)
+	v.Set("bidder_info_reload.path", "../static/bidder-info")
+	v.Set("adapters.appnexus.endpoint", "https://host.appnexus.com/bid")
+	cfg, err := config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	require.NoError(t, err)
+
+	client := &http.Client{}
+	me := &metrics.NilMetricsEngine{}
+	adapters, _, errs := BuildAdapters(client, cfg, cfg.BidderInfos, me)
+	require.Empty(t, errs)
+	syncers, errs := usersync.BuildSyncers(cfg, cfg.BidderInfos)
+	require.Empty(t, errs)
+	r := NewBidderInfoReloader(cfg, client, me, adapters, syncers)
+
+	changed, err := r.ReloadFromDisk()
+
+	assert.NoError(t, err)
+	assert.Empty(t, changed, "the files and host overrides loaded at startup should be unchanged")
+	assert.Equal(t, "https://host.appnexus.com/bid", r.BidderInfos()["appnexus"].Endpoint)
+	assert.Equal(t, BidderInfoReloadStatus{}, r.Status())
+}
+
+func TestBidderInfoReloaderReloadFromDiskError(t *testing.T) {
+	r := newTestReloader(t)
+	r.load = func() (config.BidderInfos, error) {
+		return nil, errors.New("error loading bidders data")
+	}
+
+	changed, err := r.ReloadFromDisk()
+
+	assert.Nil(t, changed)
+	assert.EqualError(t, err, "error loading bidders data")
+	failedAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, BidderInfoReloadStatus{FailedAt: &failedAt, Errors: []string{"error loading bidders data"}}, r.Status())
+	assert.EqualError(t, r.Run(), "error loading bidders data")
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/prebid/prebid-server/v3/version"
@@ -467,6 +468,52 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].HttpCalls, expectedHttpCall)
 }
 
+func TestTraceparentHeaderPropagated(t *testing.T) {
+	var receivedTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceparent = r.Header.Get(tracing.TraceparentHeader)
+		w.Write([]byte("responseJson"))
+	}))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL,
+			Body:    []byte("requestJson"),
+			Headers: http.Header{"Content-Type": []string{"application/json"}},
+		},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{},
+		},
+	}
+
+	tracer := tracing.NewTracer(config.Tracing{
+		Enabled:      true,
+		SamplingRate: 1,
+		OTLP:         config.TracingOTLP{Endpoint: server.URL, TimeoutMs: 100, BatchSize: 10, QueueSize: 10, FlushIntervalMs: 60000},
+	}, server.Client())
+	defer tracer.Shutdown()
+	ctx, rootSpan := tracer.StartRootSpan(context.Background(), "openrtb2.auction", http.Header{})
+
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{}, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+		BidderName: "test",
+	}
+	_, _, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+	rootSpan.End()
+
+	assert.Empty(t, errs)
+	sc, err := tracing.ParseTraceparent(receivedTraceparent)
+	assert.NoError(t, err)
+	assert.Equal(t, rootSpan.SpanContext().TraceID, sc.TraceID)
+	assert.NotEqual(t, rootSpan.SpanContext().SpanID, sc.SpanID, "bidder call must be made from a child span")
+	assert.True(t, sc.Sampled)
+	assert.Empty(t, bidderImpl.httpRequest.Headers.Get(tracing.TraceparentHeader), "the trace context must not be set on the adapter headers")
+}
+
 // TestMultiBidder makes sure all the requests get sent, and the responses processed.
 // Because this is done in parallel, it should be run under the race detector.
 func TestMultiBidder(t *testing.T) {
@@ -566,7 +613,7 @@ func TestBidderTimeout(t *testing.T) {
 	callInfo := bidder.doRequest(ctx, &adapters.RequestData{
 		Method: "POST",
 		Uri:    server.URL,
-	}, time.Now(), tmaxAdjustments)
+	}, "", time.Now(), tmaxAdjustments)
 	if callInfo.err == nil {
 		t.Errorf("The bidder should report an error if the context has expired already.")
 	}
@@ -585,7 +632,7 @@ func TestInvalidRequest(t *testing.T) {
 	tmaxAdjustments := &TmaxAdjustmentsPreprocessed{}
 	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
 		Method: "\"", // force http.NewRequest() to fail
-	}, time.Now(), tmaxAdjustments)
+	}, "", time.Now(), tmaxAdjustments)
 	if callInfo.err == nil {
 		t.Errorf("bidderAdapter.doRequest should return an error if the request data is malformed.")
 	}
@@ -609,7 +656,7 @@ func TestConnectionClose(t *testing.T) {
 	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
 		Method: "POST",
 		Uri:    server.URL,
-	}, time.Now(), tmaxAdjustments)
+	}, "", time.Now(), tmaxAdjustments)
 	if callInfo.err == nil {
 		t.Errorf("bidderAdapter.doRequest should return an error if the connection closes unexpectedly.")
 	}
@@ -2157,7 +2204,7 @@ func TestCallRecordDNSTime(t *testing.T) {
 	tmaxAdjustments := &TmaxAdjustmentsPreprocessed{}
 
 	// Run test
-	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, time.Now(), tmaxAdjustments)
+	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, "", time.Now(), tmaxAdjustments)
 
 	// Tried one or another, none seem to work without panicking
 	metricsMock.AssertExpectations(t)
@@ -2181,7 +2228,7 @@ func TestCallRecordTLSHandshakeTime(t *testing.T) {
 	tmaxAdjustments := &TmaxAdjustmentsPreprocessed{}
 
 	// Run test
-	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, time.Now(), tmaxAdjustments)
+	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, "", time.Now(), tmaxAdjustments)
 
 	// Tried one or another, none seem to work without panicking
 	metricsMock.AssertExpectations(t)
@@ -2269,7 +2316,7 @@ func TestTimeoutNotificationOn(t *testing.T) {
 		loggerBuffer.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
 	}
 	tmaxAdjustments := &TmaxAdjustmentsPreprocessed{}
-	bidderAdapter.doRequestImpl(ctx, &bidRequest, logger, time.Now(), tmaxAdjustments)
+	bidderAdapter.doRequestImpl(ctx, &bidRequest, "", logger, time.Now(), tmaxAdjustments)
 
 	// Wait a little longer than the 205ms mock server sleep.
 	time.Sleep(210 * time.Millisecond)
@@ -3434,7 +3481,7 @@ func TestDoRequestImplWithTmax(t *testing.T) {
 			defer cancelFn()
 		}
 
-		httpCallInfo := bidderAdapter.doRequestImpl(ctx, &bidRequest, logger, requestStartTime, test.tmaxAdjustments)
+		httpCallInfo := bidderAdapter.doRequestImpl(ctx, &bidRequest, "", logger, requestStartTime, test.tmaxAdjustments)
 		test.assertFn(httpCallInfo.err)
 	}
 }
@@ -3488,7 +3535,7 @@ func TestDoRequestImplWithTmaxTimeout(t *testing.T) {
 			defer cancelFn()
 		}
 
-		httpCallInfo := bidderAdapter.doRequestImpl(ctx, &bidRequest, logger, requestStartTime, test.tmaxAdjustments)
+		httpCallInfo := bidderAdapter.doRequestImpl(ctx, &bidRequest, "", logger, requestStartTime, test.tmaxAdjustments)
 		test.assertFn(httpCallInfo.err)
 	}
 }
@@ -49,6 +49,13 @@ func (v *validatedBidder) shouldRequest() bool {
 	return v.bidder.shouldRequest()
 }
 
+func (v *validatedBidder) healthVectors() []BidderHealthVector {
+	if reporter, ok := v.bidder.(healthReporter); ok {
+		return reporter.healthVectors()
+	}
+	return nil
+}
+
 // validateBids will run some validation checks on the returned bids and excise any invalid bids
 func removeInvalidBids(request *openrtb2.BidRequest, seatBid *entities.PbsOrtbSeatBid, debug bool) []error {
 	// Exit early if there is nothing to do.
@@ -1,6 +1,7 @@
 package exchange
 
 import (
+	"slices"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/exchange/entities"
@@ -9,6 +10,9 @@ import (
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/endpoints/events"
+	"github.com/prebid/prebid-server/v3/injector"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
@@ -22,11 +26,16 @@ type eventTracking struct {
 	integrationType    string
 	bidderInfos        config.BidderInfos
 	externalURL        string
+	// vastEvents are the account VAST event trackers injected in the video bids, nil if there are none
+	vastEvents    *injector.VASTEvents
+	macroProvider *macros.MacroProvider
+	macroReplacer macros.Replacer
+	me            metrics.MetricsEngine
 }
 
 // getEventTracking creates an eventTracking object from the different configuration sources
-func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Time, account *config.Account, bidderInfos config.BidderInfos, externalURL string) *eventTracking {
-	return &eventTracking{
+func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Time, account *config.Account, bidderInfos config.BidderInfos, externalURL string, reqWrapper *openrtb_ext.RequestWrapper, macroReplacer macros.Replacer, me metrics.MetricsEngine) *eventTracking {
+	ev := &eventTracking{
 		accountID:          account.ID,
 		enabledForAccount:  account.Events.Enabled,
 		enabledForRequest:  requestExtPrebid != nil && requestExtPrebid.Events != nil,
@@ -35,6 +44,49 @@ func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Ti
 		bidderInfos:        bidderInfos,
 		externalURL:        externalURL,
 	}
+	if macroReplacer != nil {
+		if ev.vastEvents = makeVASTEvents(account.Events); ev.vastEvents != nil {
+			ev.macroProvider = macros.NewProvider(reqWrapper)
+			ev.macroReplacer = macroReplacer
+			ev.me = me
+		}
+	}
+	return ev
+}
+
+// makeVASTEvents groups the tracker URLs of the account VAST events by the VAST element they are injected in.
+// The default URL is added to the URLs of each event unless it's excluded.
+func makeVASTEvents(accountEvents config.Events) *injector.VASTEvents {
+	if !accountEvents.Enabled || len(accountEvents.VASTEvents) == 0 {
+		return nil
+	}
+
+	vastEvents := &injector.VASTEvents{}
+	for _, vastEvent := range accountEvents.VASTEvents {
+		urls := vastEvent.URLs
+		if !vastEvent.ExcludeDefaultURL && accountEvents.DefaultURL != "" {
+			urls = append(slices.Clone(urls), accountEvents.DefaultURL)
+		}
+
+		switch vastEvent.CreateElement {
+		case config.ImpressionVASTElement:
+			vastEvents.Impressions = append(vastEvents.Impressions, urls...)
+		case config.ErrorVASTElement:
+			vastEvents.Errors = append(vastEvents.Errors, urls...)
+		case config.ClickTrackingVASTElement:
+			vastEvents.VideoClicks = append(vastEvents.VideoClicks, urls...)
+		case config.NonLinearClickTrackingVASTElement:
+			vastEvents.NonLinearClickTracking = append(vastEvents.NonLinearClickTracking, urls...)
+		case config.CompanionClickThroughVASTElement:
+			vastEvents.CompanionClickThrough = append(vastEvents.CompanionClickThrough, urls...)
+		case config.TrackingVASTElement:
+			if vastEvents.TrackingEvents == nil {
+				vastEvents.TrackingEvents = make(map[string][]string)
+			}
+			vastEvents.TrackingEvents[string(vastEvent.Type)] = append(vastEvents.TrackingEvents[string(vastEvent.Type)], urls...)
+		}
+	}
+	return vastEvents
 }
 
 func getIntegrationType(requestExtPrebid *openrtb_ext.ExtRequestPrebid) string {
@@ -63,7 +115,8 @@ func (ev *eventTracking) isModifyingVASTXMLAllowed(bidderName string) bool {
 	return ev.bidderInfos[bidderName].ModifyingVastXmlAllowed && ev.isEventAllowed()
 }
 
-// modifyBidVAST injects event Impression url if needed, otherwise returns original VAST string
+// modifyBidVAST injects event Impression url and the account VAST event trackers if needed, otherwise
+// returns original VAST string
 func (ev *eventTracking) modifyBidVAST(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) {
 	bid := pbsBid.Bid
 	if pbsBid.BidType != openrtb_ext.BidTypeVideo || len(bid.AdM) == 0 && len(bid.NURL) == 0 {
@@ -76,7 +129,25 @@ func (ev *eventTracking) modifyBidVAST(pbsBid *entities.PbsOrtbBid, bidderName o
 	}
 	if newVastXML, ok := events.ModifyVastXmlString(ev.externalURL, vastXML, bidID, bidderName.String(), ev.accountID, ev.auctionTimestampMs, ev.integrationType); ok {
 		bid.AdM = newVastXML
+		vastXML = newVastXML
+	}
+	if ev.vastEvents != nil {
+		ev.injectVASTTrackers(pbsBid, bidderName, vastXML)
+	}
+}
+
+// injectVASTTrackers injects the account VAST event trackers in the wrapper or inline VAST of the bid. The
+// VAST is kept as is if it can't be parsed.
+func (ev *eventTracking) injectVASTTrackers(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName, vastXML string) {
+	ev.macroProvider.PopulateBidMacros(pbsBid, bidderName.String())
+	trackerInjector := injector.NewTrackerInjector(ev.macroReplacer, ev.macroProvider, *ev.vastEvents)
+
+	newVastXML, err := trackerInjector.InjectTracker(vastXML, pbsBid.Bid.NURL)
+	if err != nil {
+		ev.me.RecordAdapterVASTTrackerInjectionError(bidderName)
+		return
 	}
+	pbsBid.Bid.AdM = newVastXML
 }
 
 // modifyBidJSON injects "wurl" (win) event url if needed, otherwise returns original json
@@ -2,9 +2,14 @@ package exchange
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/injector"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -192,3 +197,177 @@ func Test_isEventAllowed(t *testing.T) {
 		})
 	}
 }
+
+func Test_makeVASTEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		accountEvents config.Events
+		want          *injector.VASTEvents
+	}{
+		{
+			name: "events disabled",
+			accountEvents: config.Events{
+				Enabled:    false,
+				DefaultURL: "http://default.url",
+				VASTEvents: []config.VASTEvent{{CreateElement: config.ImpressionVASTElement}},
+			},
+			want: nil,
+		},
+		{
+			name:          "no vast events",
+			accountEvents: config.Events{Enabled: true, DefaultURL: "http://default.url"},
+			want:          nil,
+		},
+		{
+			name: "vast events",
+			accountEvents: config.Events{
+				Enabled:    true,
+				DefaultURL: "http://default.url",
+				VASTEvents: []config.VASTEvent{
+					{CreateElement: config.ImpressionVASTElement, URLs: []string{"http://imp.url"}},
+					{CreateElement: config.ErrorVASTElement, URLs: []string{"http://error.url"}, ExcludeDefaultURL: true},
+					{CreateElement: config.ClickTrackingVASTElement},
+					{CreateElement: config.NonLinearClickTrackingVASTElement, URLs: []string{"http://nonlinear.url"}, ExcludeDefaultURL: true},
+					{CreateElement: config.CompanionClickThroughVASTElement, URLs: []string{"http://companion.url"}, ExcludeDefaultURL: true},
+					{CreateElement: config.TrackingVASTElement, Type: config.Start, URLs: []string{"http://start.url"}},
+					{CreateElement: config.TrackingVASTElement, Type: config.Start, URLs: []string{"http://start2.url"}, ExcludeDefaultURL: true},
+					{CreateElement: config.TrackingVASTElement, Type: config.Complete, URLs: []string{"http://complete.url"}, ExcludeDefaultURL: true},
+				},
+			},
+			want: &injector.VASTEvents{
+				Impressions:            []string{"http://imp.url", "http://default.url"},
+				Errors:                 []string{"http://error.url"},
+				VideoClicks:            []string{"http://default.url"},
+				NonLinearClickTracking: []string{"http://nonlinear.url"},
+				CompanionClickThrough:  []string{"http://companion.url"},
+				TrackingEvents: map[string][]string{
+					"start":    {"http://start.url", "http://default.url", "http://start2.url"},
+					"complete": {"http://complete.url"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, makeVASTEvents(tt.accountEvents))
+		})
+	}
+}
+
+func Test_eventsData_modifyBidVASTTrackers(t *testing.T) {
+	vastEvents := &injector.VASTEvents{
+		Impressions:    []string{"http://imp.url?bidder=##PBS-BIDDER##&bidid=##PBS-BIDID##"},
+		Errors:         []string{"http://error.url?account=##PBS-ACCOUNTID##"},
+		VideoClicks:    []string{"http://click.url?crid=##PBS-VASTCRTID##"},
+		TrackingEvents: map[string][]string{"start": {"http://start.url?event=##PBS-EVENTTYPE##"}},
+	}
+
+	tests := []struct {
+		name               string
+		bid                *openrtb2.Bid
+		generatedBidID     string
+		want               string
+		wantInjectionError bool
+	}{
+		{
+			name: "inline",
+			bid:  &openrtb2.Bid{ID: "BID-1", AdM: `<VAST version="3.0"><Ad><InLine><Impression><![CDATA[http://bidder.imp]]></Impression><Creatives><Creative AdID="crid-1"><Linear><TrackingEvents></TrackingEvents><VideoClicks></VideoClicks></Linear></Creative></Creatives></InLine></Ad></VAST>`},
+			want: `<VAST version="3.0"><Ad><InLine>` +
+				`<Impression><![CDATA[http://bidder.imp]]></Impression>` +
+				`<Impression><![CDATA[http://imp.url?bidder=openx&bidid=BID-1]]></Impression>` +
+				`<Impression><![CDATA[http://localhost/event?t=imp&b=BID-1&a=123456&bidder=openx&f=b&ts=1234567890]]></Impression>` +
+				`<Creatives><Creative AdID="crid-1"><Linear>` +
+				`<TrackingEvents><Tracking event="start"><![CDATA[http://start.url?event=start]]></Tracking></TrackingEvents>` +
+				`<VideoClicks><ClickTracking><![CDATA[http://click.url?crid=crid-1]]></ClickTracking></VideoClicks>` +
+				`</Linear></Creative></Creatives>` +
+				`<Error><![CDATA[http://error.url?account=123456]]></Error>` +
+				`</InLine></Ad></VAST>`,
+		},
+		{
+			name:           "wrapper with generated bid id",
+			bid:            &openrtb2.Bid{ID: "BID-1", AdM: `<VAST version="3.0"><Ad><Wrapper><VASTAdTagURI><![CDATA[http://bidder.vast]]></VASTAdTagURI><Error><![CDATA[http://bidder.error]]></Error><Creatives><Creative><Linear></Linear></Creative></Creatives></Wrapper></Ad></VAST>`},
+			generatedBidID: "GEN-1",
+			want: `<VAST version="3.0"><Ad><Wrapper>` +
+				`<VASTAdTagURI><![CDATA[http://bidder.vast]]></VASTAdTagURI>` +
+				`<Error><![CDATA[http://bidder.error]]></Error>` +
+				`<Error><![CDATA[http://error.url?account=123456]]></Error>` +
+				`<Creatives><Creative><Linear>` +
+				`<VideoClicks><ClickTracking><![CDATA[http://click.url?crid=]]></ClickTracking></VideoClicks>` +
+				`<TrackingEvents><Tracking event="start"><![CDATA[http://start.url?event=start]]></Tracking></TrackingEvents>` +
+				`</Linear></Creative></Creatives>` +
+				`<Impression><![CDATA[http://imp.url?bidder=openx&bidid=GEN-1]]></Impression>` +
+				`</Wrapper></Ad></VAST>`,
+		},
+		{
+			name: "nurl only",
+			bid:  &openrtb2.Bid{ID: "BID-1", NURL: "http://bidder.nurl"},
+			want: `<VAST version="3.0"><Ad><Wrapper>` +
+				`<AdSystem><![CDATA[prebid.org wrapper]]></AdSystem>` +
+				`<VASTAdTagURI><![CDATA[http://bidder.nurl]]></VASTAdTagURI>` +
+				`<Impression><![CDATA[http://localhost/event?t=imp&b=BID-1&a=123456&bidder=openx&f=b&ts=1234567890]]></Impression>` +
+				`<Impression><![CDATA[http://imp.url?bidder=openx&bidid=BID-1]]></Impression>` +
+				`<Creatives></Creatives>` +
+				`<Error><![CDATA[http://error.url?account=123456]]></Error>` +
+				`</Wrapper></Ad></VAST>`,
+		},
+		{
+			name:               "invalid vast",
+			bid:                &openrtb2.Bid{ID: "BID-1", AdM: `<VAST version="3.0"><Ad></Ad></VAST>`},
+			want:               `<VAST version="3.0"><Ad></Ad></VAST>`,
+			wantInjectionError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if tt.wantInjectionError {
+				metricsEngine.On("RecordAdapterVASTTrackerInjectionError", openrtb_ext.BidderOpenx).Once()
+			}
+			reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "AUCTION-1", Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: "123456"}}}}
+			evData := &eventTracking{
+				enabledForAccount:  true,
+				accountID:          "123456",
+				auctionTimestampMs: 1234567890,
+				externalURL:        "http://localhost",
+				vastEvents:         vastEvents,
+				macroProvider:      macros.NewProvider(reqWrapper),
+				macroReplacer:      macros.NewStringIndexBasedReplacer(),
+				me:                 metricsEngine,
+			}
+			bid := &entities.PbsOrtbBid{Bid: tt.bid, BidType: openrtb_ext.BidTypeVideo, GeneratedBidID: tt.generatedBidID}
+
+			evData.modifyBidVAST(bid, openrtb_ext.BidderOpenx)
+
+			assert.Equal(t, tt.want, bid.Bid.AdM)
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_eventsData_modifyBidsForEventsVASTTrackers(t *testing.T) {
+	account := &config.Account{
+		ID: "123456",
+		Events: config.Events{
+			Enabled:    true,
+			DefaultURL: "http://default.url",
+			VASTEvents: []config.VASTEvent{{CreateElement: config.ImpressionVASTElement, ExcludeDefaultURL: true, URLs: []string{"http://imp.url?bidder=##PBS-BIDDER##"}}},
+		},
+	}
+	bidderInfos := config.BidderInfos{
+		"appnexus": {ModifyingVastXmlAllowed: true},
+		"openx":    {ModifyingVastXmlAllowed: false},
+	}
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "AUCTION-1"}}
+	vastXML := `<VAST version="3.0"><Ad><InLine><Creatives></Creatives></InLine></Ad></VAST>`
+
+	evData := getEventTracking(nil, time.Unix(1234567, 0), account, bidderInfos, "http://localhost", reqWrapper, macros.NewStringIndexBasedReplacer(), &metrics.MetricsEngineMock{})
+	seatBids := evData.modifyBidsForEvents(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "BID-1", AdM: vastXML}, BidType: openrtb_ext.BidTypeVideo}}},
+		openrtb_ext.BidderOpenx:    {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "BID-2", AdM: vastXML}, BidType: openrtb_ext.BidTypeVideo}}},
+	})
+
+	assert.Equal(t, `<VAST version="3.0"><Ad><InLine><Creatives></Creatives><Impression><![CDATA[http://imp.url?bidder=appnexus]]></Impression></InLine></Ad></VAST>`, seatBids[openrtb_ext.BidderAppnexus].Bids[0].Bid.AdM)
+	assert.Equal(t, vastXML, seatBids[openrtb_ext.BidderOpenx].Bids[0].Bid.AdM, "the VAST of bidders not allowed to modify it should be kept")
+}
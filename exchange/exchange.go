@@ -16,6 +16,7 @@ import (
 
 	"github.com/prebid/prebid-server/v3/adapters"
 	"github.com/prebid/prebid-server/v3/adservertargeting"
+	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/bidadjustment"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -65,6 +66,7 @@ type IdFetcher interface {
 type exchange struct {
 	adapterMap               map[openrtb_ext.BidderName]AdaptedBidder
 	bidderInfo               config.BidderInfos
+	bidderInfoReloader       *BidderInfoReloader
 	bidderToSyncerKey        map[string]string
 	me                       metrics.MetricsEngine
 	cache                    prebid_cache_client.Client
@@ -85,6 +87,8 @@ type exchange struct {
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
 	singleFormatBidders      map[openrtb_ext.BidderName]struct{}
+	userSyncChooser          usersync.Chooser
+	auctionTimeouts          config.AuctionTimeouts
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -136,7 +140,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, singleFormatBidders map[openrtb_ext.BidderName]struct{}) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, singleFormatBidders map[openrtb_ext.BidderName]struct{}, bidderInfoReloader *BidderInfoReloader) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -147,24 +151,33 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		gdprDefaultValue = gdpr.SignalNo
 	}
 
+	// the syncers are chosen among the active bidders, as the /cookie_sync endpoint does
+	activeBidders := GetActiveBidders(infos)
+	biddersKnown := make(map[string]struct{}, len(activeBidders))
+	for bidder := range activeBidders {
+		biddersKnown[bidder] = struct{}{}
+	}
+
 	privacyConfig := config.Privacy{
 		CCPA: cfg.CCPA,
 		GDPR: cfg.GDPR,
 		LMT:  cfg.LMT,
 	}
 	requestSplitter := requestSplitter{
-		bidderToSyncerKey: bidderToSyncerKey,
-		me:                metricsEngine,
-		privacyConfig:     privacyConfig,
-		gdprPermsBuilder:  gdprPermsBuilder,
-		hostSChainNode:    cfg.HostSChainNode,
-		bidderInfo:        infos,
-		requestValidator:  requestValidator,
+		bidderToSyncerKey:  bidderToSyncerKey,
+		me:                 metricsEngine,
+		privacyConfig:      privacyConfig,
+		gdprPermsBuilder:   gdprPermsBuilder,
+		hostSChainNode:     cfg.HostSChainNode,
+		bidderInfo:         infos,
+		bidderInfoReloader: bidderInfoReloader,
+		requestValidator:   requestValidator,
 	}
 
 	return &exchange{
 		adapterMap:               adapters,
 		bidderInfo:               infos,
+		bidderInfoReloader:       bidderInfoReloader,
 		bidderToSyncerKey:        bidderToSyncerKey,
 		cache:                    cache,
 		cacheTime:                time.Duration(cfg.CacheURL.ExpectedTimeMillis) * time.Millisecond,
@@ -185,6 +198,8 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
 		singleFormatBidders:      singleFormatBidders,
+		userSyncChooser:          usersync.NewChooser(syncersByBidder, biddersKnown, infos),
+		auctionTimeouts:          cfg.AuctionTimeouts,
 	}
 }
 
@@ -242,11 +257,18 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, nil
 	}
 
+	tmax := r.BidRequestWrapper.TMax
 	err := r.HookExecutor.ExecuteProcessedAuctionStage(r.BidRequestWrapper)
 	if err != nil {
 		return nil, err
 	}
 
+	if r.BidRequestWrapper.TMax != tmax {
+		var cancel context.CancelFunc
+		ctx, cancel = withHookTmax(ctx, r.StartTime, r.BidRequestWrapper.TMax, e.auctionTimeouts)
+		defer cancel()
+	}
+
 	requestExt, err := r.BidRequestWrapper.GetRequestExt()
 	if err != nil {
 		return nil, err
@@ -358,6 +380,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 	errs = append(errs, floorErrs...)
 
+	userSyncs := e.makeUserSyncs(ctx, r, requestExtPrebid, bidderRequests, gdprSignal, gdprEnforced)
+
 	mergedBidAdj, err := bidadjustment.Merge(r.BidRequestWrapper, r.Account.BidAdjustments)
 	if err != nil {
 		if errortypes.ContainsFatalError([]error{err}) {
@@ -423,6 +447,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		auc            *auction
 		cacheErrs      []error
 		bidResponseExt *openrtb_ext.ExtBidResponse
+		floorsOutcome  *analytics.FloorsOutcome
 	)
 
 	if anyBidsReturned {
@@ -430,7 +455,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			var rejectedBids []*entities.PbsOrtbSeatBid
 			var enforceErrs []error
 
-			adapterBids, enforceErrs, rejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
+			adapterBids, enforceErrs, rejectedBids, floorsOutcome = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
 			errs = append(errs, enforceErrs...)
 			for _, rejectedBid := range rejectedBids {
 				errs = append(errs, &errortypes.Warning{
@@ -469,7 +494,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
-		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL)
+		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, currentBidderInfos(e.bidderInfoReloader, e.bidderInfo), e.externalURL, r.BidRequestWrapper, e.macroReplacer, e.me)
 		adapterBids = evTracking.modifyBidsForEvents(adapterBids)
 
 		r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids)
@@ -548,6 +573,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], generalWarning)
 	}
 
+	bidResponseExt = setUserSyncs(bidResponseExt, userSyncs)
+
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
 
 	// Build the response
@@ -563,6 +590,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	return &AuctionResponse{
 		BidResponse:    bidResponse,
 		ExtBidResponse: bidResponseExt,
+		FloorsOutcome:  floorsOutcome,
 	}, nil
 }
 
@@ -738,6 +766,20 @@ func updateHbPbCatDur(bid *entities.PbsOrtbBid, dealTier openrtb_ext.DealTier, b
 	}
 }
 
+// withHookTmax shortens the auction deadline to the tmax set by the processed auction request hooks, limited by
+// the auction timeouts the same way the tmax of the request is. The deadline can't be extended past the one the
+// auction started with.
+func withHookTmax(ctx context.Context, start time.Time, tmax int64, auctionTimeouts config.AuctionTimeouts) (context.Context, context.CancelFunc) {
+	if start.IsZero() {
+		return ctx, func() {}
+	}
+	timeout := auctionTimeouts.LimitAuctionTimeout(time.Duration(tmax) * time.Millisecond)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, start.Add(timeout))
+}
+
 func (e *exchange) makeAuctionContext(ctx context.Context, needsCache bool) (auctionCtx context.Context, cancel context.CancelFunc) {
 	auctionCtx = ctx
 	cancel = func() {}
@@ -777,6 +819,7 @@ func (e *exchange) getAllBids(
 
 	e.me.RecordOverheadTime(metrics.MakeBidderRequests, time.Since(pbsRequestStartTime))
 
+	bidderInfos := currentBidderInfos(e.bidderInfoReloader, e.bidderInfo)
 	for _, bidder := range bidderRequests {
 		// Here we actually call the adapters and collect the bids.
 		bidderRunner := e.recoverSafely(bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -807,7 +850,7 @@ func (e *exchange) getAllBids(
 			bidReqOptions := bidRequestOptions{
 				accountDebugAllowed:    accountDebugAllowed,
 				headerDebugAllowed:     headerDebugAllowed,
-				addCallSignHeader:      isAdsCertEnabled(experiment, e.bidderInfo[string(bidderRequest.BidderName)]),
+				addCallSignHeader:      isAdsCertEnabled(experiment, bidderInfos[string(bidderRequest.BidderName)]),
 				bidAdjustments:         bidAdjustments,
 				tmaxAdjustments:        tmaxAdjustments,
 				bidderRequestStartTime: start,
@@ -1269,6 +1312,29 @@ func getPrimaryAdServer(adServerId int) (string, error) {
 	}
 }
 
+// makeExtResponsePrivacyDebug reports in the debug output how the GPP US National or state section of the request
+// was enforced: the section applied, the activities it denied and the errors reading it. It's nil if the account
+// doesn't enforce the section or the request has none.
+func makeExtResponsePrivacyDebug(r AuctionRequest) *openrtb_ext.ExtResponsePrivacyDebug {
+	if r.BidRequestWrapper == nil {
+		return nil
+	}
+
+	decision, enabled := r.Activities.USNat(privacy.NewRequestFromBidRequest(*r.BidRequestWrapper))
+	if !enabled || decision.SectionID == 0 {
+		return nil
+	}
+
+	usNat := &openrtb_ext.ExtResponseUSNatDebug{SID: int8(decision.SectionID)}
+	for _, activity := range decision.Denied {
+		usNat.Denied = append(usNat.Denied, activity.String())
+	}
+	for _, err := range decision.Errors {
+		usNat.Errors = append(usNat.Errors, err.Error())
+	}
+	return &openrtb_ext.ExtResponsePrivacyDebug{USNat: usNat}
+}
+
 // Extract all the data from the SeatBids and build the ExtBidResponse
 func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, r AuctionRequest, debugInfo bool, passthrough json.RawMessage, fledge *openrtb_ext.Fledge, errList []error) *openrtb_ext.ExtBidResponse {
 	bidResponseExt := &openrtb_ext.ExtBidResponse{
@@ -1281,6 +1347,7 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 		bidResponseExt.Debug = &openrtb_ext.ExtResponseDebug{
 			HttpCalls:       make(map[openrtb_ext.BidderName][]*openrtb_ext.ExtHttpCall),
 			ResolvedRequest: r.ResolvedBidRequest,
+			Privacy:         makeExtResponsePrivacyDebug(r),
 		}
 	}
 
@@ -0,0 +1,206 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/ccpa"
+	"github.com/prebid/prebid-server/v3/usersync"
+)
+
+var userSyncBidderFilterAllowAll = usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude)
+
+// makeUserSyncs returns the user syncs of the requested bidders without a UID when asked by bidrequest.ext.prebid.usersync
+// and enabled for the account. The syncers are chosen and filtered by the privacy policies of the request the same way the
+// /cookie_sync endpoint does, so apps and CTVs can fire them without calling it. Only /openrtb2/auction returns them.
+func (e *exchange) makeUserSyncs(ctx context.Context, r *AuctionRequest, requestExtPrebid *openrtb_ext.ExtRequestPrebid, bidderRequests []BidderRequest, gdprSignal gdpr.Signal, gdprEnforced bool) []*openrtb_ext.ExtResponseUserSync {
+	if e.userSyncChooser == nil || requestExtPrebid.UserSync == nil || !r.Account.CookieSync.Auction.Enabled {
+		return nil
+	}
+	if r.RequestType != metrics.ReqTypeORTB2Web && r.RequestType != metrics.ReqTypeORTB2App && r.RequestType != metrics.ReqTypeORTB2DOOH {
+		return nil
+	}
+
+	// the syncers are chosen against the uids cookie of the user
+	cookie, ok := r.UserSyncs.(*usersync.Cookie)
+	if !ok || cookie == nil {
+		return nil
+	}
+
+	// the aliases defined by the request are unknown to the chooser and synced by their core bidder, reported
+	// under the bidder name requested
+	bidders := make([]string, 0, len(bidderRequests))
+	requestedNames := make(map[string]string, len(bidderRequests))
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.BidRequest.User != nil && bidderRequest.BidRequest.User.BuyerUID != "" {
+			continue
+		}
+		bidder := bidderRequest.BidderName.String()
+		if _, known := openrtb_ext.NormalizeBidderName(bidder); !known {
+			bidder = bidderRequest.BidderCoreName.String()
+		}
+		if _, seen := requestedNames[bidder]; !seen || bidder == bidderRequest.BidderName.String() {
+			requestedNames[bidder] = bidderRequest.BidderName.String()
+		}
+		bidders = append(bidders, bidder)
+	}
+	if len(bidders) == 0 {
+		return nil
+	}
+
+	req := r.BidRequestWrapper
+	var gpp gpplib.GppContainer
+	var gppSID []string
+	var gppString string
+	if req.Regs != nil {
+		// the GPP errors are reported by the request splitter
+		if len(req.Regs.GPP) > 0 {
+			gpp, _ = gpplib.Parse(req.Regs.GPP)
+		}
+		gppString = req.Regs.GPP
+		for _, sid := range req.Regs.GPPSID {
+			gppSID = append(gppSID, strconv.Itoa(int(sid)))
+		}
+	}
+
+	consent, _ := getConsent(req, gpp)
+	var gdprPerms gdpr.Permissions = &gdpr.AlwaysAllow{}
+	if gdprEnforced {
+		gdprPerms = e.gdprPermsBuilder(r.TCF2Config, gdpr.RequestInfo{
+			Consent:     consent,
+			GDPRSignal:  gdprSignal,
+			PublisherID: r.LegacyLabels.PubID,
+		})
+	}
+
+	ccpaPolicy, _ := ccpa.ReadFromRequestWrapper(req, gpp)
+	ccpaEnforcer, err := extractCCPA(req.BidRequest, e.privacyConfig, &r.Account, nil, channelTypeMap[r.LegacyLabels.RType], gpp)
+	if err != nil {
+		ccpaEnforcer = privacy.NilPolicyEnforcer{}
+	}
+
+	syncTypeFilter := usersync.SyncTypeFilter{
+		IFrame:   userSyncBidderFilterAllowAll,
+		Redirect: userSyncBidderFilterAllowAll,
+	}
+
+	result := e.userSyncChooser.Choose(usersync.Request{
+		Bidders: bidders,
+		Limit:   getUserSyncLimit(requestExtPrebid.UserSync.Limit, r.Account.CookieSync.Auction),
+		Privacy: userSyncPrivacy{
+			ctx:             ctx,
+			gdprPermissions: gdprPerms,
+			gdprSignal:      gdprSignal,
+			ccpaEnforcer:    ccpaEnforcer,
+			activityControl: r.Activities,
+			activityRequest: privacy.NewRequestFromBidRequest(*req),
+		},
+		SyncTypeFilter: syncTypeFilter,
+		GPPSID:         strings.Join(gppSID, ","),
+	}, cookie)
+	if result.Status != usersync.StatusOK {
+		return nil
+	}
+
+	var gdprString string
+	if gdprSignal != gdpr.SignalAmbiguous {
+		gdprString = strconv.Itoa(int(gdprSignal))
+	}
+	privacyMacros := macros.UserSyncPrivacy{
+		GDPR:        gdprString,
+		GDPRConsent: consent,
+		USPrivacy:   ccpaPolicy.Consent,
+		GPP:         gppString,
+		GPPSID:      strings.Join(gppSID, ","),
+	}
+
+	userSyncs := make([]*openrtb_ext.ExtResponseUserSync, 0, len(result.SyncersChosen))
+	for _, syncerChoice := range result.SyncersChosen {
+		sync, err := syncerChoice.Syncer.GetSync(syncTypeFilter.ForBidder(syncerChoice.Bidder), privacyMacros)
+		if err != nil {
+			glog.Errorf("Failed to get usersync info for %s: %v", syncerChoice.Bidder, err)
+			continue
+		}
+		userSyncs = append(userSyncs, &openrtb_ext.ExtResponseUserSync{
+			Bidder:      requestedNames[syncerChoice.Bidder],
+			URL:         sync.URL,
+			Type:        string(sync.Type),
+			SupportCORS: sync.SupportCORS,
+		})
+	}
+	return userSyncs
+}
+
+// getUserSyncLimit returns the limit of the request, else the default one of the account, capped by the max limit
+// of the account. A missing or non-positive limit leaves the syncs unlimited.
+func getUserSyncLimit(requestLimit *int, cfg config.AuctionUserSync) int {
+	limit := math.MaxInt
+	if requestLimit != nil && *requestLimit > 0 {
+		limit = *requestLimit
+	} else if cfg.DefaultLimit != nil && *cfg.DefaultLimit > 0 {
+		limit = *cfg.DefaultLimit
+	}
+
+	if cfg.MaxLimit != nil && *cfg.MaxLimit > 0 && *cfg.MaxLimit < limit {
+		limit = *cfg.MaxLimit
+	}
+	return limit
+}
+
+// setUserSyncs adds the user syncs within bidResponse.Ext.Prebid.UserSync
+func setUserSyncs(bidResponseExt *openrtb_ext.ExtBidResponse, userSyncs []*openrtb_ext.ExtResponseUserSync) *openrtb_ext.ExtBidResponse {
+	if len(userSyncs) == 0 {
+		return bidResponseExt
+	}
+	if bidResponseExt.Prebid == nil {
+		bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+	}
+
+	bidResponseExt.Prebid.UserSync = userSyncs
+	return bidResponseExt
+}
+
+type userSyncPrivacy struct {
+	// ctx is the context of the auction, the GDPR permissions may fetch the vendor list
+	ctx             context.Context
+	gdprPermissions gdpr.Permissions
+	gdprSignal      gdpr.Signal
+	ccpaEnforcer    privacy.PolicyEnforcer
+	activityControl privacy.ActivityControl
+	activityRequest privacy.ActivityRequest
+}
+
+func (p userSyncPrivacy) GDPRAllowsHostCookie() bool {
+	allowCookie, err := p.gdprPermissions.HostCookiesAllowed(p.ctx)
+	return err == nil && allowCookie
+}
+
+func (p userSyncPrivacy) GDPRInScope() bool {
+	return p.gdprSignal == gdpr.SignalYes
+}
+
+func (p userSyncPrivacy) GDPRAllowsBidderSync(bidder string) bool {
+	allowSync, err := p.gdprPermissions.BidderSyncAllowed(p.ctx, openrtb_ext.BidderName(bidder))
+	return err == nil && allowSync
+}
+
+func (p userSyncPrivacy) CCPAAllowsBidderSync(bidder string) bool {
+	return !p.ccpaEnforcer.ShouldEnforce(bidder)
+}
+
+func (p userSyncPrivacy) ActivityAllowsUserSync(bidder string) bool {
+	return p.activityControl.Allow(
+		privacy.ActivitySyncUser,
+		privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidder},
+		p.activityRequest)
+}
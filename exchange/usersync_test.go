@@ -0,0 +1,359 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// denyAllPermissions denies the host cookie and every bidder sync
+type denyAllPermissions struct {
+	gdpr.AlwaysAllow
+}
+
+func (denyAllPermissions) HostCookiesAllowed(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+func (denyAllPermissions) BidderSyncAllowed(ctx context.Context, bidder openrtb_ext.BidderName) (bool, error) {
+	return false, nil
+}
+
+func newUserSyncTestExchange(t *testing.T, permissions gdpr.Permissions) *exchange {
+	syncersByBidder := make(map[string]usersync.Syncer)
+	for _, bidder := range []string{"appnexus", "rubicon", "pubmatic"} {
+		syncer, err := usersync.NewSyncer(config.UserSync{ExternalURL: "http://host.com", RedirectURL: "{{.ExternalURL}}/setuid"}, config.Syncer{
+			Key:      bidder,
+			Redirect: &config.SyncerEndpoint{URL: "https://" + bidder + ".com/sync?gdpr={{.GDPR}}&consent={{.GDPRConsent}}&redirect={{.RedirectURL}}"},
+		}, bidder)
+		require.NoError(t, err)
+		syncersByBidder[bidder] = syncer
+	}
+
+	return &exchange{
+		gdprPermsBuilder: fakePermissionsBuilder{permissions: permissions}.Builder,
+		userSyncChooser:  usersync.NewChooser(syncersByBidder, map[string]struct{}{}, config.BidderInfos{}),
+	}
+}
+
+func newUserSyncTestAliasRequest(alias, coreBidder string) BidderRequest {
+	return BidderRequest{BidRequest: &openrtb2.BidRequest{ID: "some-request-id"}, BidderName: openrtb_ext.BidderName(alias), BidderCoreName: openrtb_ext.BidderName(coreBidder)}
+}
+
+func newUserSyncTestBidderRequest(bidder, buyerUID string) BidderRequest {
+	request := &openrtb2.BidRequest{ID: "some-request-id"}
+	if buyerUID != "" {
+		request.User = &openrtb2.User{BuyerUID: buyerUID}
+	}
+	return BidderRequest{BidRequest: request, BidderName: openrtb_ext.BidderName(bidder), BidderCoreName: openrtb_ext.BidderName(bidder)}
+}
+
+func TestMakeUserSyncs(t *testing.T) {
+	syncedCookie := usersync.NewCookie()
+	require.NoError(t, syncedCookie.Sync("appnexus", "123"))
+
+	denySyncUser := privacy.NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{SyncUser: config.Activity{Default: ptrutil.ToPtr(false)}},
+	})
+
+	testCases := []struct {
+		description       string
+		givenUserSync     *openrtb_ext.ExtRequestPrebidUserSync
+		givenRequestType  metrics.RequestType
+		givenAccount      config.AuctionUserSync
+		givenCookie       IdFetcher
+		givenActivities   privacy.ActivityControl
+		givenPermissions  gdpr.Permissions
+		givenGDPREnforced bool
+		expectedBidders   []string
+		expectedCount     int
+	}{
+		{
+			description:  "not_requested",
+			givenAccount: config.AuctionUserSync{Enabled: true},
+			givenCookie:  usersync.NewCookie(),
+		},
+		{
+			description:   "disabled_for_account",
+			givenUserSync: &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenCookie:   usersync.NewCookie(),
+		},
+		{
+			description:     "bidders_without_uid",
+			givenUserSync:   &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:    config.AuctionUserSync{Enabled: true},
+			givenCookie:     usersync.NewCookie(),
+			expectedBidders: []string{"appnexus", "pubmatic"},
+		},
+		{
+			description:      "app",
+			givenUserSync:    &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenRequestType: metrics.ReqTypeORTB2App,
+			givenAccount:     config.AuctionUserSync{Enabled: true},
+			givenCookie:      usersync.NewCookie(),
+			expectedBidders:  []string{"appnexus", "pubmatic"},
+		},
+		{
+			description:      "dooh",
+			givenUserSync:    &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenRequestType: metrics.ReqTypeORTB2DOOH,
+			givenAccount:     config.AuctionUserSync{Enabled: true},
+			givenCookie:      usersync.NewCookie(),
+			expectedBidders:  []string{"appnexus", "pubmatic"},
+		},
+		{
+			description:      "amp",
+			givenUserSync:    &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenRequestType: metrics.ReqTypeAMP,
+			givenAccount:     config.AuctionUserSync{Enabled: true},
+			givenCookie:      usersync.NewCookie(),
+		},
+		{
+			description:      "video",
+			givenUserSync:    &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenRequestType: metrics.ReqTypeVideo,
+			givenAccount:     config.AuctionUserSync{Enabled: true},
+			givenCookie:      usersync.NewCookie(),
+		},
+		{
+			description:     "already_synced",
+			givenUserSync:   &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:    config.AuctionUserSync{Enabled: true},
+			givenCookie:     syncedCookie,
+			expectedBidders: []string{"pubmatic"},
+		},
+		{
+			description:   "limited",
+			givenUserSync: &openrtb_ext.ExtRequestPrebidUserSync{Limit: ptrutil.ToPtr(5)},
+			givenAccount:  config.AuctionUserSync{Enabled: true, MaxLimit: ptrutil.ToPtr(1)},
+			givenCookie:   usersync.NewCookie(),
+			expectedCount: 1,
+		},
+		{
+			description:     "blocked_by_activity",
+			givenUserSync:   &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:    config.AuctionUserSync{Enabled: true},
+			givenCookie:     usersync.NewCookie(),
+			givenActivities: denySyncUser,
+		},
+		{
+			description:       "blocked_by_gdpr",
+			givenUserSync:     &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:      config.AuctionUserSync{Enabled: true},
+			givenCookie:       usersync.NewCookie(),
+			givenPermissions:  denyAllPermissions{},
+			givenGDPREnforced: true,
+		},
+		{
+			description:       "gdpr_allowed",
+			givenUserSync:     &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:      config.AuctionUserSync{Enabled: true},
+			givenCookie:       usersync.NewCookie(),
+			givenPermissions:  &permissionsMock{allowAllBidders: true},
+			givenGDPREnforced: true,
+			expectedBidders:   []string{"appnexus", "pubmatic"},
+		},
+		{
+			description:   "no_cookie",
+			givenUserSync: &openrtb_ext.ExtRequestPrebidUserSync{},
+			givenAccount:  config.AuctionUserSync{Enabled: true},
+			givenCookie:   &mockIdFetcher{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := newUserSyncTestExchange(t, test.givenPermissions)
+			requestType := test.givenRequestType
+			if requestType == "" {
+				requestType = metrics.ReqTypeORTB2Web
+			}
+			r := &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+					ID:   "some-request-id",
+					User: &openrtb2.User{Consent: "some-consent"},
+				}},
+				Account:     config.Account{CookieSync: config.CookieSync{Auction: test.givenAccount}},
+				UserSyncs:   test.givenCookie,
+				Activities:  test.givenActivities,
+				RequestType: requestType,
+			}
+			bidderRequests := []BidderRequest{
+				newUserSyncTestBidderRequest("appnexus", ""),
+				newUserSyncTestBidderRequest("rubicon", "456"),
+				newUserSyncTestBidderRequest("pubmatic", ""),
+			}
+
+			userSyncs := e.makeUserSyncs(context.Background(), r, &openrtb_ext.ExtRequestPrebid{UserSync: test.givenUserSync}, bidderRequests, gdpr.SignalYes, test.givenGDPREnforced)
+
+			if test.expectedCount > 0 {
+				assert.Len(t, userSyncs, test.expectedCount)
+				return
+			}
+			var bidders []string
+			for _, userSync := range userSyncs {
+				bidders = append(bidders, userSync.Bidder)
+				assert.Equal(t, "redirect", userSync.Type)
+				assert.Equal(t, "https://"+userSync.Bidder+".com/sync?gdpr=1&consent=some-consent&redirect=http%3A%2F%2Fhost.com%2Fsetuid", userSync.URL)
+			}
+			assert.ElementsMatch(t, test.expectedBidders, bidders)
+		})
+	}
+}
+
+func TestMakeUserSyncsAliases(t *testing.T) {
+	require.NoError(t, openrtb_ext.SetAliasBidderName("userSyncAlias", "appnexus"))
+
+	e := newUserSyncTestExchange(t, &gdpr.AlwaysAllow{})
+	syncer, err := usersync.NewSyncer(config.UserSync{ExternalURL: "http://host.com", RedirectURL: "{{.ExternalURL}}/setuid"}, config.Syncer{
+		Key:      "userSyncAlias",
+		Redirect: &config.SyncerEndpoint{URL: "https://userSyncAlias.com/sync"},
+	}, "userSyncAlias")
+	require.NoError(t, err)
+	syncersByBidder := map[string]usersync.Syncer{"userSyncAlias": syncer}
+	for _, bidder := range []string{"appnexus", "pubmatic"} {
+		syncersByBidder[bidder], err = usersync.NewSyncer(config.UserSync{ExternalURL: "http://host.com", RedirectURL: "{{.ExternalURL}}/setuid"}, config.Syncer{
+			Key:      bidder,
+			Redirect: &config.SyncerEndpoint{URL: "https://" + bidder + ".com/sync"},
+		}, bidder)
+		require.NoError(t, err)
+	}
+	e.userSyncChooser = usersync.NewChooser(syncersByBidder, map[string]struct{}{}, config.BidderInfos{})
+
+	cookie := usersync.NewCookie()
+	require.NoError(t, cookie.Sync("appnexus", "123"))
+	r := &AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "some-request-id"}},
+		Account:           config.Account{CookieSync: config.CookieSync{Auction: config.AuctionUserSync{Enabled: true}}},
+		UserSyncs:         cookie,
+		RequestType:       metrics.ReqTypeORTB2Web,
+	}
+	bidderRequests := []BidderRequest{
+		newUserSyncTestAliasRequest("userSyncAlias", "appnexus"),
+		newUserSyncTestAliasRequest("requestAlias", "pubmatic"),
+	}
+
+	userSyncs := e.makeUserSyncs(context.Background(), r, &openrtb_ext.ExtRequestPrebid{UserSync: &openrtb_ext.ExtRequestPrebidUserSync{}}, bidderRequests, gdpr.SignalNo, false)
+
+	urls := make(map[string]string)
+	for _, userSync := range userSyncs {
+		urls[userSync.Bidder] = userSync.URL
+	}
+	assert.Equal(t, map[string]string{
+		"userSyncAlias": "https://userSyncAlias.com/sync",
+		"requestAlias":  "https://pubmatic.com/sync",
+	}, urls, "the alias with its own syncer should be synced by it and the request alias by its core bidder, under the bidder names requested")
+}
+
+// contextPermissions records the context the permissions are checked with
+type contextPermissions struct {
+	gdpr.AlwaysAllow
+	contexts []context.Context
+}
+
+func (p *contextPermissions) HostCookiesAllowed(ctx context.Context) (bool, error) {
+	p.contexts = append(p.contexts, ctx)
+	return true, nil
+}
+
+func (p *contextPermissions) BidderSyncAllowed(ctx context.Context, bidder openrtb_ext.BidderName) (bool, error) {
+	p.contexts = append(p.contexts, ctx)
+	return true, nil
+}
+
+func TestUserSyncPrivacyContext(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "auction")
+	permissions := &contextPermissions{}
+	userSyncPrivacy := userSyncPrivacy{ctx: ctx, gdprPermissions: permissions}
+
+	assert.True(t, userSyncPrivacy.GDPRAllowsHostCookie())
+	assert.True(t, userSyncPrivacy.GDPRAllowsBidderSync("appnexus"))
+	assert.Equal(t, []context.Context{ctx, ctx}, permissions.contexts, "the permissions should be checked with the auction context")
+}
+
+func TestGetUserSyncLimit(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenRequest  *int
+		givenAccount  config.AuctionUserSync
+		expectedLimit int
+	}{
+		{
+			description:   "unlimited",
+			expectedLimit: math.MaxInt,
+		},
+		{
+			description:   "request",
+			givenRequest:  ptrutil.ToPtr(3),
+			givenAccount:  config.AuctionUserSync{DefaultLimit: ptrutil.ToPtr(2)},
+			expectedLimit: 3,
+		},
+		{
+			description:   "account_default",
+			givenRequest:  ptrutil.ToPtr(0),
+			givenAccount:  config.AuctionUserSync{DefaultLimit: ptrutil.ToPtr(2)},
+			expectedLimit: 2,
+		},
+		{
+			description:   "capped_by_account_max",
+			givenRequest:  ptrutil.ToPtr(3),
+			givenAccount:  config.AuctionUserSync{DefaultLimit: ptrutil.ToPtr(2), MaxLimit: ptrutil.ToPtr(1)},
+			expectedLimit: 1,
+		},
+		{
+			description:   "unlimited_capped_by_account_max",
+			givenAccount:  config.AuctionUserSync{MaxLimit: ptrutil.ToPtr(4)},
+			expectedLimit: 4,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedLimit, getUserSyncLimit(test.givenRequest, test.givenAccount))
+		})
+	}
+}
+
+func TestSetUserSyncs(t *testing.T) {
+	userSyncs := []*openrtb_ext.ExtResponseUserSync{{Bidder: "appnexus", URL: "https://appnexus.com/sync", Type: "redirect"}}
+
+	bidResponseExt := setUserSyncs(&openrtb_ext.ExtBidResponse{}, userSyncs)
+	assert.Equal(t, &openrtb_ext.ExtBidResponse{Prebid: &openrtb_ext.ExtResponsePrebid{UserSync: userSyncs}}, bidResponseExt)
+
+	bidResponseExt = setUserSyncs(&openrtb_ext.ExtBidResponse{}, nil)
+	assert.Nil(t, bidResponseExt.Prebid)
+}
+
+func TestNewExchangeUserSyncBiddersKnown(t *testing.T) {
+	infos := config.BidderInfos{
+		"appnexus": config.BidderInfo{},
+		"rubicon":  config.BidderInfo{Disabled: true},
+	}
+	e := NewExchange(nil, nil, &config.Configuration{}, &mockRequestValidator{}, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, infos, nil, nil, nilCategoryFetcher{}, &adscert.NilSigner{}, nil, nil, nil, nil).(*exchange)
+
+	result := e.userSyncChooser.Choose(usersync.Request{
+		Bidders: []string{"appnexus", "rubicon"},
+		Limit:   math.MaxInt,
+		Privacy: userSyncPrivacy{ctx: context.Background(), gdprPermissions: &gdpr.AlwaysAllow{}, ccpaEnforcer: privacy.NilPolicyEnforcer{}},
+		Debug:   true,
+	}, usersync.NewCookie())
+
+	assert.ElementsMatch(t, []usersync.BidderEvaluation{
+		{Bidder: "appnexus", Status: usersync.StatusUnconfiguredBidder},
+		{Bidder: "rubicon", Status: usersync.StatusUnknownBidder},
+	}, result.BiddersEvaluated, "the disabled bidders should be unknown to the chooser")
+}
@@ -42,13 +42,14 @@ var channelTypeMap = map[metrics.RequestType]config.ChannelType{
 const unknownBidder string = ""
 
 type requestSplitter struct {
-	bidderToSyncerKey map[string]string
-	me                metrics.MetricsEngine
-	privacyConfig     config.Privacy
-	gdprPermsBuilder  gdpr.PermissionsBuilder
-	hostSChainNode    *openrtb2.SupplyChainNode
-	bidderInfo        config.BidderInfos
-	requestValidator  ortb.RequestValidator
+	bidderToSyncerKey  map[string]string
+	me                 metrics.MetricsEngine
+	privacyConfig      config.Privacy
+	gdprPermsBuilder   gdpr.PermissionsBuilder
+	hostSChainNode     *openrtb2.SupplyChainNode
+	bidderInfo         config.BidderInfos
+	bidderInfoReloader *BidderInfoReloader
+	requestValidator   ortb.RequestValidator
 }
 
 // cleanOpenRTBRequests splits the input request into requests which are sanitized for each bidder. Intended behavior is:
@@ -157,6 +158,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 
 	bidderRequests = make([]BidderRequest, 0, len(impsByBidder))
 
+	bidderInfos := currentBidderInfos(rs.bidderInfoReloader, rs.bidderInfo)
 	for bidder, imps := range impsByBidder {
 		fpdUserEIDsPresent := fpdUserEIDExists(req, auctionReq.FirstPartyData, bidder)
 		reqWrapperCopy := req.CloneAndClearImpWrappers()
@@ -208,7 +210,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		}
 
 		// GPP downgrade: always downgrade unless we can confirm GPP is supported
-		if shouldSetLegacyPrivacy(rs.bidderInfo, string(coreBidder)) {
+		if shouldSetLegacyPrivacy(bidderInfos, string(coreBidder)) {
 			setLegacyGDPRFromGPP(reqWrapperCopy, gpp)
 			setLegacyUSPFromGPP(reqWrapperCopy, gpp)
 		}
@@ -219,7 +221,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		}
 
 		// down convert
-		info, ok := rs.bidderInfo[bidder]
+		info, ok := bidderInfos[bidder]
 		if !ok || info.OpenRTB == nil || info.OpenRTB.Version != "2.6" {
 			reqWrapperCopy.Regs = ortb.CloneRegs(reqWrapperCopy.Regs)
 			if err := openrtb_ext.ConvertDownTo25(reqWrapperCopy); err != nil {
@@ -413,6 +415,13 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		privacy.ScrubTID(reqWrapper)
 	}
 
+	passEIDsAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitEIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if !passEIDsAllowed {
+		if err := privacy.ScrubEIDs(reqWrapper); err != nil {
+			return err
+		}
+	}
+
 	if err := reqWrapper.RebuildRequest(); err != nil {
 		return err
 	}
@@ -5252,6 +5252,35 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			},
 			expectedImpExt: json.RawMessage(`{"bidder": {"placementId": 1}}`),
 		},
+		{
+			name:              "transmit_eids_allowed",
+			req:               newBidRequest(),
+			privacyConfig:     getTransmitEIDsActivityConfig("appnexus", true),
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser:      expectedUserDefault,
+			expectedDevice:    expectedDeviceDefault,
+			expectedSource:    expectedSourceDefault,
+		},
+		{
+			// remove user.eids and user.ext.eids
+			name:              "transmit_eids_deny",
+			req:               newBidRequest(),
+			privacyConfig:     getTransmitEIDsActivityConfig("appnexus", false),
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser: openrtb2.User{
+				ID:       "our-id",
+				BuyerUID: "their-id",
+				Yob:      1982,
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+				Gender:   "test",
+				Ext:      json.RawMessage(`{"data": 1, "test": 2}`),
+				Data:     []openrtb2.Data{{ID: "data-id"}},
+			},
+			expectedDevice: expectedDeviceDefault,
+			expectedSource: expectedSourceDefault,
+		},
 	}
 
 	for _, test := range testCases {
@@ -5352,6 +5381,14 @@ func getTransmitTIDActivityConfig(componentName string, allow bool) config.Accou
 	}
 }
 
+func getTransmitEIDsActivityConfig(componentName string, allow bool) config.AccountPrivacy {
+	return config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitEIDs: buildDefaultActivityConfig(componentName, allow),
+		},
+	}
+}
+
 func TestApplyBidAdjustmentToFloor(t *testing.T) {
 	type args struct {
 		bidRequestWrapper    *openrtb_ext.RequestWrapper
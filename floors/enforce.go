@@ -4,46 +4,155 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
-// Enforce does floors enforcement for bids from all bidders based on floors provided in request, account level floors config
-func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, account config.Account, conversions currency.Conversions) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
+// Enforce does floors enforcement for bids from all bidders based on floors provided in request, account level floors config.
+// It returns the floors outcome reported to analytics, nil if floors are disabled
+func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, account config.Account, conversions currency.Conversions) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid, *analytics.FloorsOutcome) {
 	rejectionErrs := []error{}
 
 	rejectedBids := []*entities.PbsOrtbSeatBid{}
 
 	requestExt, err := bidRequestWrapper.GetRequestExt()
 	if err != nil {
-		return seatBids, []error{errors.New("Error in getting request extension")}, rejectedBids
+		return seatBids, []error{errors.New("Error in getting request extension")}, rejectedBids, nil
 	}
 
 	if !isPriceFloorsEnabled(account, bidRequestWrapper) {
-		return seatBids, nil, rejectedBids
+		return seatBids, nil, rejectedBids, nil
 	}
 
+	outcome := newFloorsOutcome(getFloorsExt(requestExt))
 	if isSignalingSkipped(requestExt) || !isValidImpBidFloorPresent(bidRequestWrapper.BidRequest.Imp) {
-		return seatBids, nil, rejectedBids
+		return seatBids, nil, rejectedBids, outcome
 	}
 
 	enforceFloors := isSatisfiedByEnforceRate(requestExt, account.PriceFloors.EnforceFloorsRate, rand.Intn)
 	if updateEnforcePBS(enforceFloors, requestExt) {
 		err := bidRequestWrapper.RebuildRequest()
 		if err != nil {
-			return seatBids, []error{err}, rejectedBids
+			return seatBids, []error{err}, rejectedBids, outcome
 		}
 	}
 	updateBidExt(bidRequestWrapper, seatBids)
+	enforceDealFloors := account.PriceFloors.EnforceDealFloors && getEnforceDealsFlag(requestExt)
 	if enforceFloors {
-		enforceDealFloors := account.PriceFloors.EnforceDealFloors && getEnforceDealsFlag(requestExt)
 		seatBids, rejectionErrs, rejectedBids = enforceFloorToBids(bidRequestWrapper, seatBids, conversions, enforceDealFloors)
 	}
-	return seatBids, rejectionErrs, rejectedBids
+	outcome.Enforced = isEnforcementEnabled(requestExt)
+	outcome.Bids = getFloorsBidOutcomes(bidRequestWrapper, seatBids, rejectedBids, conversions, outcome, enforceDealFloors)
+	return seatBids, rejectionErrs, rejectedBids, outcome
+}
+
+// newFloorsOutcome returns the floors outcome with the details of the floor data selected for the auction
+func newFloorsOutcome(floorsExt *openrtb_ext.PriceFloorRules) *analytics.FloorsOutcome {
+	outcome := &analytics.FloorsOutcome{}
+	if floorsExt == nil {
+		return outcome
+	}
+
+	outcome.Location = floorsExt.PriceFloorLocation
+	outcome.FetchStatus = floorsExt.FetchStatus
+	outcome.FloorProvider = floorsExt.FloorProvider
+	outcome.Skipped = floorsExt.GetFloorsSkippedFlag()
+	if floorsExt.Data != nil {
+		if outcome.FloorProvider == "" {
+			outcome.FloorProvider = floorsExt.Data.FloorProvider
+		}
+		if len(floorsExt.Data.ModelGroups) > 0 {
+			outcome.ModelVersion = floorsExt.Data.ModelGroups[0].ModelVersion
+		}
+	}
+	return outcome
+}
+
+// getFloorsBidOutcomes returns the floor each bid with a floor was checked against, the rejected bids included,
+// sorted by seat, imp and bid
+func getFloorsBidOutcomes(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, rejectedBids []*entities.PbsOrtbSeatBid, conversions currency.Conversions, outcome *analytics.FloorsOutcome, enforceDealFloors bool) []analytics.FloorsBidOutcome {
+	impMap := make(map[string]*openrtb_ext.ImpWrapper, bidRequestWrapper.LenImp())
+	for _, imp := range bidRequestWrapper.GetImp() {
+		impMap[imp.ID] = imp
+	}
+
+	var bidOutcomes []analytics.FloorsBidOutcome
+	addBidOutcomes := func(seat string, seatBid *entities.PbsOrtbSeatBid, rejected bool) {
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil || bid.BidFloors == nil {
+				continue
+			}
+
+			bidOutcome := analytics.FloorsBidOutcome{
+				Seat:           seat,
+				BidID:          bid.Bid.ID,
+				ImpID:          bid.Bid.ImpID,
+				Deal:           hasDealID(bid),
+				FloorValue:     bid.BidFloors.FloorValue,
+				FloorCurrency:  bid.BidFloors.FloorCurrency,
+				FloorRule:      bid.BidFloors.FloorRule,
+				FloorRuleValue: bid.BidFloors.FloorRuleValue,
+				ModelVersion:   outcome.ModelVersion,
+				Enforced:       outcome.Enforced && (!hasDealID(bid) || enforceDealFloors),
+				Rejected:       rejected,
+			}
+			if imp, ok := impMap[bid.Bid.ImpID]; ok {
+				if modelVersion := getImpFloorsModelVersion(imp); modelVersion != "" {
+					bidOutcome.ModelVersion = modelVersion
+				}
+			}
+
+			floorCurrency := bidOutcome.FloorCurrency
+			if floorCurrency == "" {
+				floorCurrency = defaultCurrency
+			}
+			if rate, err := getCurrencyConversionRate(seatBid.Currency, floorCurrency, conversions); err == nil {
+				bidOutcome.BidPrice = roundToFourDecimals(rate * bid.Bid.Price)
+				bidOutcome.Margin = roundToFourDecimals(bidOutcome.BidPrice - bidOutcome.FloorValue)
+			}
+			bidOutcomes = append(bidOutcomes, bidOutcome)
+		}
+	}
+
+	for bidderName, seatBid := range seatBids {
+		seat := seatBid.Seat
+		if seat == "" {
+			seat = bidderName.String()
+		}
+		addBidOutcomes(seat, seatBid, false)
+	}
+	for _, seatBid := range rejectedBids {
+		addBidOutcomes(seatBid.Seat, seatBid, true)
+	}
+
+	sort.Slice(bidOutcomes, func(i, j int) bool {
+		if bidOutcomes[i].Seat != bidOutcomes[j].Seat {
+			return bidOutcomes[i].Seat < bidOutcomes[j].Seat
+		}
+		if bidOutcomes[i].ImpID != bidOutcomes[j].ImpID {
+			return bidOutcomes[i].ImpID < bidOutcomes[j].ImpID
+		}
+		return bidOutcomes[i].BidID < bidOutcomes[j].BidID
+	})
+	return bidOutcomes
+}
+
+// getImpFloorsModelVersion returns the model version of the floor of the imp, set if computed by a floor provider
+func getImpFloorsModelVersion(imp *openrtb_ext.ImpWrapper) string {
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return ""
+	}
+	if prebidExt := impExt.GetPrebid(); prebidExt != nil && prebidExt.Floors != nil {
+		return prebidExt.Floors.ModelVersion
+	}
+	return ""
 }
 
 // updateEnforcePBS updates prebid extension in request if enforcePBS needs to be updated
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
@@ -722,7 +723,7 @@ func TestEnforce(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		actEligibleBids, actErrs, actRejecteBids := Enforce(tt.args.bidRequestWrapper, tt.args.seatBids, config.Account{PriceFloors: tt.args.priceFloorsCfg}, tt.args.conversions)
+		actEligibleBids, actErrs, actRejecteBids, _ := Enforce(tt.args.bidRequestWrapper, tt.args.seatBids, config.Account{PriceFloors: tt.args.priceFloorsCfg}, tt.args.conversions)
 		assert.Equal(t, tt.expErrs, actErrs, tt.name)
 		assert.ElementsMatch(t, tt.expRejectedBids, actRejecteBids, tt.name)
 
@@ -732,6 +733,103 @@ func TestEnforce(t *testing.T) {
 	}
 }
 
+func TestEnforceFloorsOutcome(t *testing.T) {
+	floorsExt := `"floorprovider":"provider1","location":"fetch","fetchstatus":"success","data":{"modelgroups":[{"modelversion":"group1"}]}`
+	newRequest := func(floors string) *openrtb_ext.RequestWrapper {
+		return &openrtb_ext.RequestWrapper{
+			BidRequest: &openrtb2.BidRequest{
+				ID: "some-request-id",
+				Imp: []openrtb2.Imp{
+					{ID: "imp1", BidFloor: 5, BidFloorCur: "USD", Ext: json.RawMessage(`{"prebid":{"floors":{"floorrule":"banner|*","floorrulevalue":5,"floorvalue":5,"modelversion":"gbt-v1"}}}`)},
+					{ID: "imp2", BidFloor: 1, BidFloorCur: "USD"},
+				},
+				Ext: json.RawMessage(`{"prebid":{"floors":{` + floors + `}}}`),
+			},
+		}
+	}
+	newSeatBids := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {
+				Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "a1", Price: 4, ImpID: "imp1"}}},
+				Seat:     "appnexus",
+				Currency: "USD",
+			},
+			"pubmatic": {
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "p2", Price: 10, ImpID: "imp2", DealID: "deal1"}},
+					{Bid: &openrtb2.Bid{ID: "p1", Price: 400, ImpID: "imp1"}},
+				},
+				Currency: "INR",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		request         *openrtb_ext.RequestWrapper
+		priceFloorsCfg  config.AccountPriceFloors
+		expectedOutcome *analytics.FloorsOutcome
+	}{
+		{
+			name:           "floors_disabled",
+			request:        newRequest(floorsExt),
+			priceFloorsCfg: config.AccountPriceFloors{Enabled: false},
+		},
+		{
+			name:           "signaling_skipped",
+			request:        newRequest(floorsExt + `,"skipped":true`),
+			priceFloorsCfg: config.AccountPriceFloors{Enabled: true},
+			expectedOutcome: &analytics.FloorsOutcome{
+				Location:      "fetch",
+				FetchStatus:   "success",
+				FloorProvider: "provider1",
+				ModelVersion:  "group1",
+				Skipped:       true,
+			},
+		},
+		{
+			name:           "enforced",
+			request:        newRequest(floorsExt + `,"enforcement":{"enforcepbs":true,"floordeals":false}`),
+			priceFloorsCfg: config.AccountPriceFloors{Enabled: true, EnforceDealFloors: true},
+			expectedOutcome: &analytics.FloorsOutcome{
+				Location:      "fetch",
+				FetchStatus:   "success",
+				FloorProvider: "provider1",
+				ModelVersion:  "group1",
+				Enforced:      true,
+				Bids: []analytics.FloorsBidOutcome{
+					{Seat: "appnexus", BidID: "a1", ImpID: "imp1", FloorValue: 5, FloorCurrency: "USD", FloorRule: "banner|*", FloorRuleValue: 5, ModelVersion: "gbt-v1", BidPrice: 4, Margin: -1, Enforced: true, Rejected: true},
+					{Seat: "pubmatic", BidID: "p1", ImpID: "imp1", FloorValue: 5, FloorCurrency: "USD", FloorRule: "banner|*", FloorRuleValue: 5, ModelVersion: "gbt-v1", BidPrice: 5.2, Margin: 0.2, Enforced: true},
+					{Seat: "pubmatic", BidID: "p2", ImpID: "imp2", Deal: true, FloorValue: 1, FloorCurrency: "USD", ModelVersion: "group1", BidPrice: 0.13, Margin: -0.87},
+				},
+			},
+		},
+		{
+			name:           "not_enforced",
+			request:        newRequest(floorsExt + `,"enforcement":{"enforcepbs":false}`),
+			priceFloorsCfg: config.AccountPriceFloors{Enabled: true},
+			expectedOutcome: &analytics.FloorsOutcome{
+				Location:      "fetch",
+				FetchStatus:   "success",
+				FloorProvider: "provider1",
+				ModelVersion:  "group1",
+				Bids: []analytics.FloorsBidOutcome{
+					{Seat: "appnexus", BidID: "a1", ImpID: "imp1", FloorValue: 5, FloorCurrency: "USD", FloorRule: "banner|*", FloorRuleValue: 5, ModelVersion: "gbt-v1", BidPrice: 4, Margin: -1},
+					{Seat: "pubmatic", BidID: "p1", ImpID: "imp1", FloorValue: 5, FloorCurrency: "USD", FloorRule: "banner|*", FloorRuleValue: 5, ModelVersion: "gbt-v1", BidPrice: 5.2, Margin: 0.2},
+					{Seat: "pubmatic", BidID: "p2", ImpID: "imp2", Deal: true, FloorValue: 1, FloorCurrency: "USD", ModelVersion: "group1", BidPrice: 0.13, Margin: -0.87},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, outcome := Enforce(tc.request, newSeatBids(), config.Account{PriceFloors: tc.priceFloorsCfg}, convert{})
+			assert.Equal(t, tc.expectedOutcome, outcome)
+		})
+	}
+}
+
 func TestUpdateBidExtWithFloors(t *testing.T) {
 	type args struct {
 		reqImp        *openrtb_ext.ImpWrapper
@@ -8,7 +8,9 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -51,6 +53,32 @@ type PriceFloorFetcher struct {
 	time            timeutil.Time         // time interface to record request timings
 	metricEngine    metrics.MetricsEngine // Records malfunctions in dynamic fetch
 	maxRetries      int                   // Max number of retries for failing URLs
+
+	mutex         sync.RWMutex
+	statuses      map[string]*FetchStatus        // Outcome of the last fetch of the URLs
+	lastKnownGood map[string]lastKnownGoodFloors // Last floor data fetched successfully from the URLs, nil if the fallback is disabled
+	maxStaleness  time.Duration                  // Age after which the last-known-good floor data isn't served
+	snapshotStore FloorsSnapshotStore            // Persists the last-known-good floor data, nil if it's kept in memory only
+}
+
+// lastKnownGoodFloors is the last floor data fetched successfully from a URL, kept parsed as it's served on every
+// request while the URL can't be fetched.
+type lastKnownGoodFloors struct {
+	fetchedAt time.Time
+	floorData *openrtb_ext.PriceFloorRules
+}
+
+// FetchStatus is the outcome of the last fetch of a floors URL. The status is none for the floor data loaded from
+// a snapshot and not fetched again yet.
+type FetchStatus struct {
+	URL         string     `json:"url"`
+	AccountID   string     `json:"accountId,omitempty"`
+	Status      string     `json:"status"`
+	AttemptedAt *time.Time `json:"attemptedAt,omitempty"`
+	FetchedAt   *time.Time `json:"fetchedAt,omitempty"`
+	AgeSec      int64      `json:"ageSec"`
+	RuleCount   int        `json:"ruleCount"`
+	RetryCount  int        `json:"retryCount"`
 }
 
 type FetchQueue []*fetchInfo
@@ -109,6 +137,21 @@ func NewPriceFloorFetcher(config config.PriceFloors, httpClient *http.Client, me
 		time:            &timeutil.RealTime{},
 		metricEngine:    metricEngine,
 		maxRetries:      config.Fetcher.MaxRetries,
+		statuses:        make(map[string]*FetchStatus),
+	}
+
+	if lastKnownGood := config.Fetcher.LastKnownGood; lastKnownGood.Enabled {
+		floorFetcher.lastKnownGood = make(map[string]lastKnownGoodFloors)
+		floorFetcher.maxStaleness = time.Duration(lastKnownGood.MaxStalenessSec) * time.Second
+		if lastKnownGood.SnapshotDir != "" {
+			store, err := NewFileSnapshotStore(lastKnownGood.SnapshotDir)
+			if err != nil {
+				glog.Errorf("Floors snapshots disabled: %v", err)
+			} else {
+				floorFetcher.snapshotStore = store
+				floorFetcher.preloadSnapshots()
+			}
+		}
 	}
 
 	go floorFetcher.Fetcher()
@@ -149,10 +192,143 @@ func (f *PriceFloorFetcher) Fetch(config config.AccountPriceFloors) (*openrtb_ex
 		f.configReceiver <- fetchConfig
 	}
 
+	// Fall back on the last-known-good floor data, if not too stale, while the floor data is fetched again
+	if lastKnownGood, found := f.getLastKnownGood(config.Fetcher.URL); found {
+		return lastKnownGood, openrtb_ext.FetchStale
+	}
+
 	return nil, openrtb_ext.FetchInprogress
 }
 
+// getLastKnownGood returns a copy of the last floor data fetched successfully from the URL if it's not older than
+// the staleness budget.
+func (f *PriceFloorFetcher) getLastKnownGood(url string) (*openrtb_ext.PriceFloorRules, bool) {
+	f.mutex.RLock()
+	lastKnownGood, found := f.lastKnownGood[url]
+	f.mutex.RUnlock()
+	if !found || f.time.Now().Sub(lastKnownGood.fetchedAt) > f.maxStaleness {
+		return nil, false
+	}
+	return lastKnownGood.floorData.DeepCopy(), true
+}
+
+// setLastKnownGood keeps the floor data fetched successfully from the URL and persists it if a snapshot store is
+// configured.
+func (f *PriceFloorFetcher) setLastKnownGood(config config.AccountFloorFetch, floorData *openrtb_ext.PriceFloorRules, floorDataJSON json.RawMessage, fetchedAt time.Time) {
+	if f.lastKnownGood == nil {
+		return
+	}
+
+	f.mutex.Lock()
+	f.lastKnownGood[config.URL] = lastKnownGoodFloors{fetchedAt: fetchedAt, floorData: floorData}
+	f.mutex.Unlock()
+
+	snapshot := FloorsSnapshot{URL: config.URL, AccountID: config.AccountID, FetchedAt: fetchedAt, Data: floorDataJSON}
+
+	if f.snapshotStore != nil {
+		if err := f.snapshotStore.Save(snapshot); err != nil {
+			glog.Errorf("Unable to save the floors snapshot of url %s: %v", config.URL, err)
+		}
+	}
+}
+
+// preloadSnapshots loads the last-known-good floor data persisted which isn't older than the staleness budget.
+func (f *PriceFloorFetcher) preloadSnapshots() {
+	snapshots, err := f.snapshotStore.Load()
+	if err != nil {
+		glog.Errorf("Unable to load the floors snapshots: %v", err)
+		return
+	}
+
+	now := f.time.Now()
+	for _, snapshot := range snapshots {
+		if now.Sub(snapshot.FetchedAt) > f.maxStaleness {
+			continue
+		}
+
+		var floorData openrtb_ext.PriceFloorRules
+		if err := json.Unmarshal(snapshot.Data, &floorData); err != nil || floorData.Data == nil {
+			glog.Errorf("Invalid floor data in the floors snapshot of url %s", snapshot.URL)
+			continue
+		}
+
+		f.lastKnownGood[snapshot.URL] = lastKnownGoodFloors{fetchedAt: snapshot.FetchedAt, floorData: &floorData}
+		fetchedAt := snapshot.FetchedAt
+		f.statuses[snapshot.URL] = &FetchStatus{
+			URL:       snapshot.URL,
+			AccountID: snapshot.AccountID,
+			Status:    openrtb_ext.FetchNone,
+			FetchedAt: &fetchedAt,
+			RuleCount: countFloorRules(&floorData),
+		}
+	}
+	glog.Infof("Loaded %d floors snapshots", len(f.lastKnownGood))
+}
+
+// recordFetchStatus records the outcome of a fetch of the URL, the floor data is nil if the fetch failed.
+func (f *PriceFloorFetcher) recordFetchStatus(fetchConfig fetchInfo, floorData *openrtb_ext.PriceFloorRules, attemptedAt time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.statuses == nil {
+		f.statuses = make(map[string]*FetchStatus)
+	}
+	status, found := f.statuses[fetchConfig.URL]
+	if !found {
+		status = &FetchStatus{URL: fetchConfig.URL}
+		f.statuses[fetchConfig.URL] = status
+	}
+
+	status.AccountID = fetchConfig.AccountID
+	status.AttemptedAt = &attemptedAt
+	status.RetryCount = fetchConfig.retryCount
+	if floorData != nil {
+		status.Status = openrtb_ext.FetchSuccess
+		status.FetchedAt = &attemptedAt
+		status.RuleCount = countFloorRules(floorData)
+	} else {
+		status.Status = openrtb_ext.FetchError
+	}
+}
+
+// FetchStatuses returns the outcome of the last fetch of the URLs sorted by URL, the age is the age of the last
+// floor data fetched successfully.
+func (f *PriceFloorFetcher) FetchStatuses() []FetchStatus {
+	if f == nil {
+		return []FetchStatus{}
+	}
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	now := f.time.Now()
+	statuses := make([]FetchStatus, 0, len(f.statuses))
+	for _, status := range f.statuses {
+		copied := *status
+		if copied.FetchedAt != nil {
+			copied.AgeSec = int64(now.Sub(*copied.FetchedAt).Seconds())
+		}
+		statuses = append(statuses, copied)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].URL < statuses[j].URL
+	})
+	return statuses
+}
+
+// countFloorRules returns the number of floor rules of all the model groups
+func countFloorRules(floorData *openrtb_ext.PriceFloorRules) int {
+	count := 0
+	if floorData.Data != nil {
+		for _, modelGroup := range floorData.Data.ModelGroups {
+			count += len(modelGroup.Values)
+		}
+	}
+	return count
+}
+
 func (f *PriceFloorFetcher) worker(fetchConfig fetchInfo) {
+	attemptedAt := f.time.Now()
 	floorData, fetchedMaxAge := f.fetchAndValidate(fetchConfig.AccountFloorFetch)
 	if floorData != nil {
 		// Reset retry count when data is successfully fetched
@@ -163,15 +339,17 @@ func (f *PriceFloorFetcher) worker(fetchConfig fetchInfo) {
 		if fetchedMaxAge != 0 {
 			cacheExpiry = fetchedMaxAge
 		}
-		floorData, err := json.Marshal(floorData)
+		floorDataJSON, err := json.Marshal(floorData)
 		if err != nil {
 			glog.Errorf("Error while marshaling fetched floor data for url %s", fetchConfig.AccountFloorFetch.URL)
 		} else {
-			f.SetWithExpiry(fetchConfig.AccountFloorFetch.URL, floorData, cacheExpiry)
+			f.SetWithExpiry(fetchConfig.AccountFloorFetch.URL, floorDataJSON, cacheExpiry)
+			f.setLastKnownGood(fetchConfig.AccountFloorFetch, floorData, floorDataJSON, attemptedAt)
 		}
 	} else {
 		fetchConfig.retryCount++
 	}
+	f.recordFetchStatus(fetchConfig, floorData, attemptedAt)
 
 	// Send to refetch channel
 	if fetchConfig.retryCount < f.maxRetries {
@@ -314,7 +492,7 @@ func validateRules(config config.AccountFloorFetch, priceFloors *openrtb_ext.Pri
 	}
 
 	for _, modelGroup := range priceFloors.Data.ModelGroups {
-		if len(modelGroup.Values) == 0 || len(modelGroup.Values) > config.MaxRules {
+		if (modelGroup.Model == nil && len(modelGroup.Values) == 0) || len(modelGroup.Values) > config.MaxRules {
 			return errors.New("invalid number of floor rules, floor rules should be greater than zero and less than MaxRules specified in account config")
 		}
 
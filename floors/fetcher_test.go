@@ -248,6 +248,28 @@ func TestValidatePriceFloorRules(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "floor rules is empty with a floor model",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      5,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Model: &openrtb_ext.PriceFloorModel{Type: GBTModelType},
+						}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "floor rules is grater than max floor rules",
 			args: args{
@@ -1305,3 +1327,143 @@ func TestFetchWhenPriceFloorsDisabled(t *testing.T) {
 	assert.Equal(t, (*openrtb_ext.PriceFloorRules)(nil), data, "floor data should be nil as fetcher instance does not created")
 	assert.Equal(t, openrtb_ext.FetchNone, status, "floor status should be none as fetcher instance does not created")
 }
+
+type fakeFetcherTime struct {
+	now time.Time
+}
+
+func (f *fakeFetcherTime) Now() time.Time {
+	return f.now
+}
+
+func TestPriceFloorFetcherLastKnownGood(t *testing.T) {
+	response := []byte(`{"currency":"USD","modelgroups":[{"modelversion":"version1","values":{"banner|*":1,"*|*":2},"schema":{"fields":["mediaType","size"]}}]}`)
+	responseStatus := http.StatusOK
+	mockHttpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(responseStatus)
+		w.Write(response)
+	}))
+	defer mockHttpServer.Close()
+
+	snapshotStore, err := NewFileSnapshotStore(t.TempDir())
+	assert.NoError(t, err)
+	fakeTime := &fakeFetcherTime{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	fetcherInstance := PriceFloorFetcher{
+		configReceiver: make(chan fetchInfo, 2),
+		cache:          freecache.NewCache(1 * 1024 * 1024),
+		httpClient:     mockHttpServer.Client(),
+		time:           fakeTime,
+		metricEngine:   &metricsConf.NilMetricsEngine{},
+		maxRetries:     10,
+		lastKnownGood:  make(map[string]lastKnownGoodFloors),
+		maxStaleness:   time.Hour,
+		snapshotStore:  snapshotStore,
+	}
+
+	fetchInfo := fetchInfo{
+		AccountFloorFetch: config.AccountFloorFetch{
+			AccountID:     "1234",
+			URL:           mockHttpServer.URL,
+			Timeout:       100,
+			MaxFileSizeKB: 1000,
+			MaxRules:      100,
+			MaxAge:        20,
+			Period:        1,
+		},
+	}
+	accountConfig := config.AccountPriceFloors{UseDynamicData: true, Fetcher: config.AccountFloorFetch{URL: mockHttpServer.URL}}
+
+	// successful fetch
+	fetcherInstance.worker(fetchInfo)
+	<-fetcherInstance.configReceiver
+	fetchedAt := fakeTime.now
+	assert.Equal(t, []FetchStatus{
+		{URL: mockHttpServer.URL, AccountID: "1234", Status: openrtb_ext.FetchSuccess, AttemptedAt: &fetchedAt, FetchedAt: &fetchedAt, RuleCount: 2},
+	}, fetcherInstance.FetchStatuses())
+
+	snapshots, err := snapshotStore.Load()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, fetchedAt, snapshots[0].FetchedAt)
+
+	// failed fetch after the cached data expired
+	responseStatus = http.StatusInternalServerError
+	fakeTime.now = fetchedAt.Add(30 * time.Minute)
+	fetcherInstance.cache.Clear()
+	fetcherInstance.worker(fetchInfo)
+	<-fetcherInstance.configReceiver
+	attemptedAt := fakeTime.now
+	assert.Equal(t, []FetchStatus{
+		{URL: mockHttpServer.URL, AccountID: "1234", Status: openrtb_ext.FetchError, AttemptedAt: &attemptedAt, FetchedAt: &fetchedAt, AgeSec: 1800, RuleCount: 2, RetryCount: 1},
+	}, fetcherInstance.FetchStatuses())
+
+	floors, status := fetcherInstance.Fetch(accountConfig)
+	assert.Equal(t, openrtb_ext.FetchStale, status, "the last-known-good floor data should be served")
+	if assert.NotNil(t, floors) && assert.NotNil(t, floors.Data) {
+		assert.Equal(t, "version1", floors.Data.ModelGroups[0].ModelVersion)
+		floors.Data.ModelGroups[0].ModelVersion = "modified"
+	}
+
+	floors, status = fetcherInstance.Fetch(accountConfig)
+	assert.Equal(t, openrtb_ext.FetchStale, status)
+	if assert.NotNil(t, floors) && assert.NotNil(t, floors.Data) {
+		assert.Equal(t, "version1", floors.Data.ModelGroups[0].ModelVersion, "each request should get its own copy")
+	}
+
+	// last-known-good data older than the staleness budget
+	fakeTime.now = fetchedAt.Add(2 * time.Hour)
+	floors, status = fetcherInstance.Fetch(accountConfig)
+	assert.Equal(t, openrtb_ext.FetchInprogress, status)
+	assert.Nil(t, floors)
+}
+
+func TestNewPriceFloorFetcherPreloadsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	snapshotStore, err := NewFileSnapshotStore(dir)
+	assert.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	fresh := FloorsSnapshot{URL: "http://test.com/fresh", AccountID: "1234", FetchedAt: now.Add(-time.Minute), Data: json.RawMessage(`{"data":{"modelgroups":[{"values":{"*":1}}]}}`)}
+	stale := FloorsSnapshot{URL: "http://test.com/stale", FetchedAt: now.Add(-2 * time.Hour), Data: json.RawMessage(`{"data":{"modelgroups":[{"values":{"*":1}}]}}`)}
+	noData := FloorsSnapshot{URL: "http://test.com/nodata", FetchedAt: now, Data: json.RawMessage(`{}`)}
+	for _, snapshot := range []FloorsSnapshot{fresh, stale, noData} {
+		assert.NoError(t, snapshotStore.Save(snapshot))
+	}
+
+	floorConfig := config.PriceFloors{
+		Enabled: true,
+		Fetcher: config.PriceFloorFetcher{
+			CacheSize: 1,
+			Worker:    1,
+			Capacity:  1,
+			LastKnownGood: config.PriceFloorLastKnownGood{
+				Enabled:         true,
+				SnapshotDir:     dir,
+				MaxStalenessSec: 3600,
+			},
+		},
+	}
+	fetcherInstance := NewPriceFloorFetcher(floorConfig, http.DefaultClient, &metricsConf.NilMetricsEngine{})
+	defer fetcherInstance.Stop()
+
+	statuses := fetcherInstance.FetchStatuses()
+	if assert.Len(t, statuses, 1) {
+		assert.Equal(t, fresh.URL, statuses[0].URL)
+		assert.Equal(t, "1234", statuses[0].AccountID)
+		assert.Equal(t, openrtb_ext.FetchNone, statuses[0].Status)
+		assert.Equal(t, 1, statuses[0].RuleCount)
+	}
+
+	floors, status := fetcherInstance.Fetch(config.AccountPriceFloors{UseDynamicData: true, Fetcher: config.AccountFloorFetch{URL: fresh.URL}})
+	assert.Equal(t, openrtb_ext.FetchStale, status)
+	assert.NotNil(t, floors)
+
+	floors, status = fetcherInstance.Fetch(config.AccountPriceFloors{UseDynamicData: true, Fetcher: config.AccountFloorFetch{URL: stale.URL}})
+	assert.Equal(t, openrtb_ext.FetchInprogress, status)
+	assert.Nil(t, floors)
+}
+
+func TestFetchStatusesNilFetcher(t *testing.T) {
+	var fetcherInstance *PriceFloorFetcher
+	assert.Equal(t, []FetchStatus{}, fetcherInstance.FetchStatuses())
+}
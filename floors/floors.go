@@ -73,6 +73,10 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 	}
 
 	floorErrList = validateFloorRulesAndLowerValidRuleKey(modelGroup.Schema, modelGroup.Schema.Delimiter, modelGroup.Values)
+	if modelGroup.Model != nil {
+		return append(floorErrList, updateBidRequestWithFloorModel(extFloorRules, modelGroup, request, conversions)...)
+	}
+
 	if len(modelGroup.Values) > 0 {
 		for _, imp := range request.GetImp() {
 			desiredRuleKey := createRuleKey(modelGroup.Schema, request, imp)
@@ -87,24 +91,66 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 				continue
 			}
 
-			floorMinVal, floorCur, err := getMinFloorValue(extFloorRules, imp, conversions)
-			if err == nil {
-				floorVal = roundToFourDecimals(floorVal)
-				bidFloor := floorVal
-				if floorMinVal > 0.0 && floorVal < floorMinVal {
-					bidFloor = floorMinVal
+			var err error
+			floorVal, err = setImpFloor(extFloorRules, imp, floorVal, conversions)
+			if err != nil {
+				floorErrList = append(floorErrList, err)
+				continue
+			}
+
+			if isRuleMatched {
+				err = updateImpExtWithFloorDetails(imp, matchedRule, floorVal, imp.BidFloor, "")
+				if err != nil {
+					floorErrList = append(floorErrList, err)
 				}
+			}
+		}
+	}
+	return floorErrList
+}
 
-				imp.BidFloor = bidFloor
-				imp.BidFloorCur = floorCur
+// updateBidRequestWithFloorModel will update imp.bidfloor and imp.bidfloorcur with the floors computed by the floor
+// provider of the model group, the default floor of the model group applies when the model computes no floor
+func updateBidRequestWithFloorModel(extFloorRules *openrtb_ext.PriceFloorRules, modelGroup openrtb_ext.PriceFloorModelGroup, request *openrtb_ext.RequestWrapper, conversions currency.Conversions) []error {
+	var floorErrList []error
 
-				if isRuleMatched {
-					err = updateImpExtWithFloorDetails(imp, matchedRule, floorVal, imp.BidFloor)
-					if err != nil {
-						floorErrList = append(floorErrList, err)
-					}
-				}
-			} else {
+	provider, err := getFloorProvider(modelGroup.Model)
+	if err != nil {
+		return []error{err}
+	}
+	// only the models of the fetched floors are trusted to be cached, the request ones vary with every request
+	model, err := provider.Model(modelGroup.Model.Definition, extFloorRules.PriceFloorLocation == openrtb_ext.FetchLocation)
+	if err != nil {
+		return []error{err}
+	}
+	modelVersion := model.Version()
+	if modelVersion == "" {
+		modelVersion = modelGroup.ModelVersion
+	}
+
+	for _, imp := range request.GetImp() {
+		modelFloorVal, err := model.Floor(request, imp)
+		if err != nil {
+			floorErrList = append(floorErrList, err)
+		}
+		floorVal := modelFloorVal
+		if floorVal <= 0.0 {
+			floorVal = modelGroup.Default
+		}
+
+		if floorVal == 0.0 {
+			continue
+		}
+
+		floorVal, err = setImpFloor(extFloorRules, imp, floorVal, conversions)
+		if err != nil {
+			floorErrList = append(floorErrList, err)
+			continue
+		}
+
+		if modelFloorVal > 0.0 {
+			err = updateImpExtWithFloorDetails(imp, "", floorVal, imp.BidFloor, modelVersion)
+			if err != nil {
 				floorErrList = append(floorErrList, err)
 			}
 		}
@@ -112,6 +158,25 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 	return floorErrList
 }
 
+// setImpFloor updates imp.bidfloor and imp.bidfloorcur with the floor value, raised to the floor min if lower, and
+// returns the floor value rounded to four decimals
+func setImpFloor(extFloorRules *openrtb_ext.PriceFloorRules, imp *openrtb_ext.ImpWrapper, floorVal float64, conversions currency.Conversions) (float64, error) {
+	floorMinVal, floorCur, err := getMinFloorValue(extFloorRules, imp, conversions)
+	if err != nil {
+		return floorVal, err
+	}
+
+	floorVal = roundToFourDecimals(floorVal)
+	bidFloor := floorVal
+	if floorMinVal > 0.0 && floorVal < floorMinVal {
+		bidFloor = floorMinVal
+	}
+
+	imp.BidFloor = bidFloor
+	imp.BidFloorCur = floorCur
+	return floorVal, nil
+}
+
 // roundToFourDecimals retuns given value to 4 decimal points
 func roundToFourDecimals(in float64) float64 {
 	return math.Round(in*10000) / 10000
@@ -166,7 +231,7 @@ func resolveFloors(account config.Account, bidRequestWrapper *openrtb_ext.Reques
 		fetchResult, fetchStatus = priceFloorFetcher.Fetch(account.PriceFloors)
 	}
 
-	if fetchResult != nil && fetchStatus == openrtb_ext.FetchSuccess && useFetchedData(fetchResult.Data.UseFetchDataRate) {
+	if fetchResult != nil && (fetchStatus == openrtb_ext.FetchSuccess || fetchStatus == openrtb_ext.FetchStale) && useFetchedData(fetchResult.Data.UseFetchDataRate) {
 		mergedFloor := mergeFloors(reqFloor, fetchResult, conversions)
 		floorRules, errList = createFloorsFrom(mergedFloor, account, fetchStatus, openrtb_ext.FetchLocation)
 	} else if reqFloor != nil {
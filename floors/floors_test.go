@@ -1931,3 +1931,145 @@ func TestMergeFloors(t *testing.T) {
 		})
 	}
 }
+
+type mockFloorModel struct {
+	version string
+	floors  map[string]float64
+}
+
+func (m *mockFloorModel) Version() string {
+	return m.version
+}
+
+func (m *mockFloorModel) Floor(request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) (float64, error) {
+	if floor, ok := m.floors[imp.ID]; ok {
+		return floor, nil
+	}
+	return 0, errors.New("no floor for imp " + imp.ID)
+}
+
+type mockFloorProvider struct {
+	model *mockFloorModel
+}
+
+func (p *mockFloorProvider) Model(definition json.RawMessage, cacheable bool) (FloorModel, error) {
+	if p.model == nil {
+		return nil, errors.New("invalid model")
+	}
+	return p.model, nil
+}
+
+func TestEnrichWithPriceFloorsFromFloorModel(t *testing.T) {
+	RegisterFloorProvider("mock", &mockFloorProvider{model: &mockFloorModel{floors: map[string]float64{"1": 2.123456, "2": 0.5}}})
+	RegisterFloorProvider("mockInvalid", &mockFloorProvider{})
+	defer delete(floorProviders, "mock")
+	defer delete(floorProviders, "mockInvalid")
+
+	account := config.Account{
+		PriceFloors: config.AccountPriceFloors{
+			Enabled: true,
+			MaxRule: 100,
+		},
+	}
+	imps := `[{"id":"1","banner":{"format":[{"w":300,"h":250}]}},{"id":"2","banner":{"format":[{"w":300,"h":250}]}},{"id":"3","video":{"placement":1}}]`
+
+	testCases := []struct {
+		name             string
+		floors           string
+		expectedErrs     []error
+		expectedFloors   map[string]float64
+		expectedImpExts  map[string]string
+		expectedFloorCur string
+	}{
+		{
+			name:   "mock_provider",
+			floors: `{"floormin":1,"data":{"currency":"EUR","modelgroups":[{"modelversion":"group version","default":0.25,"model":{"type":"mock"}}]}}`,
+			expectedErrs: []error{
+				errors.New("no floor for imp 3"),
+			},
+			expectedFloors:   map[string]float64{"1": 2.1235, "2": 1, "3": 1},
+			expectedImpExts:  map[string]string{"1": `{"prebid":{"floors":{"floorrulevalue":2.1235,"floorvalue":2.1235,"modelversion":"group version"}}}`, "2": `{"prebid":{"floors":{"floorrulevalue":0.5,"floorvalue":1,"modelversion":"group version"}}}`},
+			expectedFloorCur: "EUR",
+		},
+		{
+			name:             "gbt_provider",
+			floors:           `{"data":{"modelgroups":[{"modelversion":"group version","model":{"type":"gbt","definition":{"version":"gbt-v1","features":["mediaType"],"trees":[[{"feature":0,"values":["banner"],"yes":1,"no":2},{"leaf":1.5},{"leaf":3}]]}}}]}}`,
+			expectedFloors:   map[string]float64{"1": 1.5, "2": 1.5, "3": 3},
+			expectedImpExts:  map[string]string{"1": `{"prebid":{"floors":{"floorrulevalue":1.5,"floorvalue":1.5,"modelversion":"gbt-v1"}}}`, "2": `{"prebid":{"floors":{"floorrulevalue":1.5,"floorvalue":1.5,"modelversion":"gbt-v1"}}}`, "3": `{"prebid":{"floors":{"floorrulevalue":3,"floorvalue":3,"modelversion":"gbt-v1"}}}`},
+			expectedFloorCur: "USD",
+		},
+		{
+			name:           "invalid_model",
+			floors:         `{"data":{"modelgroups":[{"modelversion":"group version","default":0.25,"model":{"type":"mockInvalid"}}]}}`,
+			expectedErrs:   []error{errors.New("invalid model")},
+			expectedFloors: map[string]float64{"1": 0, "2": 0, "3": 0},
+		},
+		{
+			name:           "unknown_model_type",
+			floors:         `{"data":{"modelgroups":[{"modelversion":"group version","model":{"type":"unknown"}}]}}`,
+			expectedErrs:   []error{errors.New("Invalid Floor Model = 'group version' due to Unknown floor model type = 'unknown'")},
+			expectedFloors: map[string]float64{"1": 0, "2": 0, "3": 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Ext: json.RawMessage(`{"prebid":{"floors":` + tc.floors + `}}`),
+				},
+			}
+			assert.NoError(t, jsonutil.UnmarshalValid(json.RawMessage(imps), &request.Imp))
+
+			errs := EnrichWithPriceFloors(request, account, currency.NewRates(nil), nil)
+			assert.Equal(t, tc.expectedErrs, errs)
+
+			for _, imp := range request.Imp {
+				assert.Equal(t, tc.expectedFloors[imp.ID], imp.BidFloor, "bidfloor of imp %s", imp.ID)
+				if tc.expectedFloors[imp.ID] > 0 {
+					assert.Equal(t, tc.expectedFloorCur, imp.BidFloorCur, "bidfloorcur of imp %s", imp.ID)
+				}
+				if ext, ok := tc.expectedImpExts[imp.ID]; ok {
+					assert.JSONEq(t, ext, string(imp.Ext), "ext of imp %s", imp.ID)
+				} else {
+					assert.Empty(t, imp.Ext, "ext of imp %s", imp.ID)
+				}
+			}
+		})
+	}
+}
+
+type MockFetchStale struct {
+	MockFetch
+}
+
+func (m *MockFetchStale) Fetch(configs config.AccountPriceFloors) (*openrtb_ext.PriceFloorRules, string) {
+	priceFloors, _ := m.MockFetch.Fetch(configs)
+	return priceFloors, openrtb_ext.FetchStale
+}
+
+func TestResolveFloorsStaleFetchedData(t *testing.T) {
+	bidRequestWrapper := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Site: &openrtb2.Site{
+				Publisher: &openrtb2.Publisher{Domain: "www.website.com"},
+			},
+			Imp: []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}},
+		},
+	}
+	account := config.Account{
+		PriceFloors: config.AccountPriceFloors{
+			Enabled:        true,
+			UseDynamicData: true,
+		},
+	}
+
+	resolvedFloors, errs := resolveFloors(account, bidRequestWrapper, getCurrencyRates(map[string]map[string]float64{}), &MockFetchStale{})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, openrtb_ext.FetchStale, resolvedFloors.FetchStatus)
+	assert.Equal(t, openrtb_ext.FetchLocation, resolvedFloors.PriceFloorLocation)
+	if assert.NotNil(t, resolvedFloors.Data) {
+		assert.Equal(t, "Version 101", resolvedFloors.Data.ModelGroups[0].ModelVersion)
+	}
+}
@@ -0,0 +1,186 @@
+package floors
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	// GBTModelType is the type of the gradient boosted trees models evaluated by the built-in floor provider.
+	GBTModelType string = "gbt"
+
+	gbtTransformIdentity string = "identity"
+	gbtTransformExp      string = "exp"
+
+	// gbtMaxCachedModels bounds the number of parsed fetched models kept by the provider, the cache is cleared
+	// when full
+	gbtMaxCachedModels int = 64
+)
+
+// gbtModel is a gradient boosted trees model exported to JSON. The features are floors schema dimensions, each
+// tree is a list of nodes with its root first. The floor is the base score plus the sum of the leaves reached in
+// every tree, optionally transformed by exp for the models trained on the log of the floor.
+//
+//	{
+//	  "version": "gbt-v1",
+//	  "features": ["country", "mediaType"],
+//	  "base_score": 0.5,
+//	  "transform": "identity",
+//	  "trees": [
+//	    [{"feature": 0, "values": ["usa", "can"], "yes": 1, "no": 2}, {"leaf": 0.8}, {"leaf": -0.1}]
+//	  ]
+//	}
+type gbtModel struct {
+	ModelVersion string      `json:"version"`
+	Features     []string    `json:"features"`
+	BaseScore    float64     `json:"base_score"`
+	Transform    string      `json:"transform"`
+	Trees        [][]gbtNode `json:"trees"`
+
+	schema openrtb_ext.PriceFloorSchema
+}
+
+// gbtNode is either a leaf holding a score or a split sending the requests whose feature value is one of the
+// values to the yes node and the others to the no node.
+type gbtNode struct {
+	Leaf    *float64 `json:"leaf,omitempty"`
+	Feature int      `json:"feature"`
+	Values  []string `json:"values"`
+	Yes     int      `json:"yes"`
+	No      int      `json:"no"`
+}
+
+type gbtFloorProvider struct {
+	mutex  sync.RWMutex
+	models map[[sha256.Size]byte]*gbtModel
+}
+
+// NewGBTFloorProvider returns the floor provider evaluating the gradient boosted trees models. The parsed models
+// of the fetched floors files are cached by the digest of their definition so a model is parsed once per fetch.
+func NewGBTFloorProvider() FloorProvider {
+	return &gbtFloorProvider{models: make(map[[sha256.Size]byte]*gbtModel)}
+}
+
+func (p *gbtFloorProvider) Model(definition json.RawMessage, cacheable bool) (FloorModel, error) {
+	if !cacheable {
+		return newGBTModel(definition)
+	}
+	key := sha256.Sum256(definition)
+
+	p.mutex.RLock()
+	model, ok := p.models[key]
+	p.mutex.RUnlock()
+	if ok {
+		return model, nil
+	}
+
+	model, err := newGBTModel(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	if len(p.models) >= gbtMaxCachedModels {
+		clear(p.models)
+	}
+	p.models[key] = model
+	p.mutex.Unlock()
+	return model, nil
+}
+
+// newGBTModel parses and validates a gradient boosted trees model, the nodes of a tree must point to the nodes
+// after them so evaluating a tree always ends on a leaf.
+func newGBTModel(definition json.RawMessage) (*gbtModel, error) {
+	model := &gbtModel{}
+	if err := jsonutil.UnmarshalValid(definition, model); err != nil {
+		return nil, fmt.Errorf("Invalid gbt floor model: %v", err)
+	}
+
+	if err := validateSchemaDimensions(model.Features); err != nil {
+		return nil, fmt.Errorf("Invalid gbt floor model: %v", err)
+	}
+	model.schema = openrtb_ext.PriceFloorSchema{Fields: model.Features}
+
+	switch model.Transform {
+	case "":
+		model.Transform = gbtTransformIdentity
+	case gbtTransformIdentity, gbtTransformExp:
+	default:
+		return nil, fmt.Errorf("Invalid gbt floor model: unknown transform = '%s'", model.Transform)
+	}
+
+	if len(model.Trees) == 0 {
+		return nil, errors.New("Invalid gbt floor model: no trees")
+	}
+	for t, tree := range model.Trees {
+		if len(tree) == 0 {
+			return nil, fmt.Errorf("Invalid gbt floor model: tree %d has no nodes", t)
+		}
+		for n := range tree {
+			node := &tree[n]
+			if node.Leaf != nil {
+				continue
+			}
+			if node.Feature < 0 || node.Feature >= len(model.Features) {
+				return nil, fmt.Errorf("Invalid gbt floor model: node %d of tree %d splits on unknown feature %d", n, t, node.Feature)
+			}
+			if node.Yes <= n || node.Yes >= len(tree) || node.No <= n || node.No >= len(tree) {
+				return nil, fmt.Errorf("Invalid gbt floor model: node %d of tree %d has invalid children", n, t)
+			}
+			for i := range node.Values {
+				node.Values[i] = strings.ToLower(node.Values[i])
+			}
+		}
+	}
+	return model, nil
+}
+
+func (m *gbtModel) Version() string {
+	return m.ModelVersion
+}
+
+// Floor evaluates the trees with the feature values of the imp, the features are computed as for the rule keys
+// with the catch all value "*" for a feature missing from the request.
+func (m *gbtModel) Floor(request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) (float64, error) {
+	values := createRuleKey(m.schema, request, imp)
+	for i := range values {
+		values[i] = strings.ToLower(values[i])
+	}
+
+	score := m.BaseScore
+	for _, tree := range m.Trees {
+		score += evaluateTree(tree, values)
+	}
+
+	if m.Transform == gbtTransformExp {
+		score = math.Exp(score)
+	}
+	if score < 0 || math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, nil
+	}
+	return score, nil
+}
+
+// evaluateTree walks a tree from its root to a leaf and returns the score of the leaf
+func evaluateTree(tree []gbtNode, values []string) float64 {
+	n := 0
+	for tree[n].Leaf == nil {
+		node := tree[n]
+		n = node.No
+		for _, value := range node.Values {
+			if value == values[node.Feature] {
+				n = node.Yes
+				break
+			}
+		}
+	}
+	return *tree[n].Leaf
+}
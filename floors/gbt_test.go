@@ -0,0 +1,180 @@
+package floors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGBTModel = `{
+	"version": "gbt-v1",
+	"features": ["country", "mediaType"],
+	"base_score": 0.5,
+	"trees": [
+		[{"feature": 0, "values": ["USA", "can"], "yes": 1, "no": 2}, {"leaf": 1.25}, {"leaf": -0.25}],
+		[{"feature": 1, "values": ["video"], "yes": 2, "no": 1}, {"leaf": 0}, {"feature": 0, "values": ["usa"], "yes": 3, "no": 4}, {"leaf": 2}, {"leaf": 0.5}]
+	]
+}`
+
+func TestNewGBTModel(t *testing.T) {
+	testCases := []struct {
+		name          string
+		definition    string
+		expectedError string
+	}{
+		{
+			name:       "valid",
+			definition: testGBTModel,
+		},
+		{
+			name:          "malformed",
+			definition:    `{"trees": 1}`,
+			expectedError: "Invalid gbt floor model: cannot unmarshal floors.gbtModel.Trees: decode slice: expect [ or n, but found 1",
+		},
+		{
+			name:          "unknown_feature",
+			definition:    `{"features": ["hour"], "trees": [[{"leaf": 1}]]}`,
+			expectedError: "Invalid gbt floor model: Invalid schema dimension provided = 'hour' in Schema Fields = '[hour]'",
+		},
+		{
+			name:          "unknown_transform",
+			definition:    `{"features": ["country"], "transform": "log", "trees": [[{"leaf": 1}]]}`,
+			expectedError: "Invalid gbt floor model: unknown transform = 'log'",
+		},
+		{
+			name:          "no_trees",
+			definition:    `{"features": ["country"]}`,
+			expectedError: "Invalid gbt floor model: no trees",
+		},
+		{
+			name:          "empty_tree",
+			definition:    `{"features": ["country"], "trees": [[]]}`,
+			expectedError: "Invalid gbt floor model: tree 0 has no nodes",
+		},
+		{
+			name:          "split_on_unknown_feature",
+			definition:    `{"features": ["country"], "trees": [[{"feature": 1, "values": ["usa"], "yes": 1, "no": 2}, {"leaf": 1}, {"leaf": 0}]]}`,
+			expectedError: "Invalid gbt floor model: node 0 of tree 0 splits on unknown feature 1",
+		},
+		{
+			name:          "child_before_node",
+			definition:    `{"features": ["country"], "trees": [[{"leaf": 1}, {"feature": 0, "values": ["usa"], "yes": 0, "no": 2}, {"leaf": 0}]]}`,
+			expectedError: "Invalid gbt floor model: node 1 of tree 0 has invalid children",
+		},
+		{
+			name:          "child_out_of_tree",
+			definition:    `{"features": ["country"], "trees": [[{"feature": 0, "values": ["usa"], "yes": 1, "no": 3}, {"leaf": 1}, {"leaf": 0}]]}`,
+			expectedError: "Invalid gbt floor model: node 0 of tree 0 has invalid children",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model, err := newGBTModel(json.RawMessage(tc.definition))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, model)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "gbt-v1", model.Version())
+			assert.Equal(t, gbtTransformIdentity, model.Transform)
+			assert.Equal(t, []string{"usa", "can"}, model.Trees[0][0].Values)
+		})
+	}
+}
+
+func TestGBTModelFloor(t *testing.T) {
+	newRequest := func(country string) *openrtb_ext.RequestWrapper {
+		request := &openrtb2.BidRequest{}
+		if country != "" {
+			request.Device = &openrtb2.Device{Geo: &openrtb2.Geo{Country: country}}
+		}
+		return &openrtb_ext.RequestWrapper{BidRequest: request}
+	}
+	bannerImp := &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}}}
+	videoImp := &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{ID: "2", Video: &openrtb2.Video{Placement: 1}}}
+
+	testCases := []struct {
+		name          string
+		definition    string
+		request       *openrtb_ext.RequestWrapper
+		imp           *openrtb_ext.ImpWrapper
+		expectedFloor float64
+	}{
+		{
+			name:          "first_branches",
+			definition:    testGBTModel,
+			request:       newRequest("USA"),
+			imp:           bannerImp,
+			expectedFloor: 1.75,
+		},
+		{
+			name:          "nested_split",
+			definition:    testGBTModel,
+			request:       newRequest("usa"),
+			imp:           videoImp,
+			expectedFloor: 3.75,
+		},
+		{
+			name:          "missing_feature",
+			definition:    testGBTModel,
+			request:       newRequest(""),
+			imp:           videoImp,
+			expectedFloor: 0.75,
+		},
+		{
+			name:          "negative_score",
+			definition:    `{"features": ["country"], "trees": [[{"leaf": -1}]]}`,
+			request:       newRequest("USA"),
+			imp:           bannerImp,
+			expectedFloor: 0,
+		},
+		{
+			name:          "exp_transform",
+			definition:    `{"features": ["country"], "transform": "exp", "trees": [[{"leaf": 0}]]}`,
+			request:       newRequest("USA"),
+			imp:           bannerImp,
+			expectedFloor: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model, err := newGBTModel(json.RawMessage(tc.definition))
+			assert.NoError(t, err)
+
+			floor, err := model.Floor(tc.request, tc.imp)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFloor, floor)
+		})
+	}
+}
+
+func TestGBTFloorProviderModel(t *testing.T) {
+	provider := NewGBTFloorProvider().(*gbtFloorProvider)
+
+	model, err := provider.Model(json.RawMessage(testGBTModel), true)
+	assert.NoError(t, err)
+	cachedModel, err := provider.Model(json.RawMessage(testGBTModel), true)
+	assert.NoError(t, err)
+	assert.Same(t, model, cachedModel, "the parsed model should be cached")
+
+	_, err = provider.Model(json.RawMessage(`{}`), true)
+	assert.EqualError(t, err, "Invalid gbt floor model: no trees")
+	assert.Len(t, provider.models, 1, "an invalid model should not be cached")
+
+	requestModel, err := provider.Model(json.RawMessage(`{"version": "request", "trees": [[{"leaf": 1}]]}`), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "request", requestModel.Version())
+	assert.Len(t, provider.models, 1, "a model of the request should not be cached")
+
+	for i := 0; i < gbtMaxCachedModels; i++ {
+		_, err = provider.Model(json.RawMessage(`{"version": "`+string(rune('a'+i))+`", "trees": [[{"leaf": 1}]]}`), true)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, provider.models, 1, "the cache should be cleared when full")
+}
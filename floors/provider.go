@@ -0,0 +1,46 @@
+package floors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// FloorProvider computes the floors of a model group from the request features at enforcement time, in place of
+// the rules of the model group. A provider evaluates the models of the type it's registered for, see
+// openrtb_ext.PriceFloorModel.
+type FloorProvider interface {
+	// Model parses the definition of a model, it's called once per auction for the model group selected. Only
+	// cacheable models may be kept past the auction, the models supplied by the request are not.
+	Model(definition json.RawMessage, cacheable bool) (FloorModel, error)
+}
+
+// FloorModel is a model of a floor provider ready to be evaluated.
+type FloorModel interface {
+	// Version identifies the model in imp.ext.prebid.floors, an empty version falls back to the model version of
+	// the model group.
+	Version() string
+	// Floor returns the floor of the imp in the currency of the model group, a floor of 0 falls back to the default
+	// floor of the model group.
+	Floor(request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) (float64, error)
+}
+
+var floorProviders = map[string]FloorProvider{
+	GBTModelType: NewGBTFloorProvider(),
+}
+
+// RegisterFloorProvider makes a floor provider available for the models of the given type. It isn't safe for
+// concurrent use and must be called before the server starts.
+func RegisterFloorProvider(modelType string, provider FloorProvider) {
+	floorProviders[modelType] = provider
+}
+
+// getFloorProvider returns the floor provider registered for the type of the model.
+func getFloorProvider(model *openrtb_ext.PriceFloorModel) (FloorProvider, error) {
+	provider, ok := floorProviders[model.Type]
+	if !ok {
+		return nil, fmt.Errorf("Unknown floor model type = '%s'", model.Type)
+	}
+	return provider, nil
+}
@@ -111,8 +111,9 @@ func getFloorMinAndCurFromImp(imp *openrtb_ext.ImpWrapper) (float64, string, err
 	return floorMin, floorMinCur, err
 }
 
-// updateImpExtWithFloorDetails updates floors related details into imp.ext.prebid.floors
-func updateImpExtWithFloorDetails(imp *openrtb_ext.ImpWrapper, matchedRule string, floorRuleVal, floorVal float64) error {
+// updateImpExtWithFloorDetails updates floors related details into imp.ext.prebid.floors, the model version is
+// reported for the floors computed by a floor provider
+func updateImpExtWithFloorDetails(imp *openrtb_ext.ImpWrapper, matchedRule string, floorRuleVal, floorVal float64, modelVersion string) error {
 	impExt, err := imp.GetImpExt()
 	if err != nil {
 		return err
@@ -125,6 +126,7 @@ func updateImpExtWithFloorDetails(imp *openrtb_ext.ImpWrapper, matchedRule strin
 		FloorRule:      matchedRule,
 		FloorRuleValue: floorRuleVal,
 		FloorValue:     floorVal,
+		ModelVersion:   modelVersion,
 	}
 	impExt.SetPrebid(extImpPrebid)
 	return err
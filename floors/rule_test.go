@@ -218,7 +218,7 @@ func TestUpdateImpExtWithFloorDetails(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			updateImpExtWithFloorDetails(tc.imp, tc.matchedRule, tc.floorRuleVal, tc.floorVal)
+			updateImpExtWithFloorDetails(tc.imp, tc.matchedRule, tc.floorRuleVal, tc.floorVal, "")
 			_ = tc.imp.RebuildImp()
 			if tc.imp.Ext != nil {
 				assert.Equal(t, tc.imp.Ext, tc.expected, tc.name)
@@ -0,0 +1,90 @@
+package floors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/fileutil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const snapshotFileExtension = ".json"
+
+// FloorsSnapshot is the last floor data fetched successfully from a URL.
+type FloorsSnapshot struct {
+	URL       string          `json:"url"`
+	AccountID string          `json:"accountId,omitempty"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// FloorsSnapshotStore persists the last-known-good floor data so it can be served right after a restart.
+type FloorsSnapshotStore interface {
+	// Load returns the snapshots persisted, the invalid ones are skipped.
+	Load() ([]FloorsSnapshot, error)
+	// Save persists the snapshot, replacing the previous snapshot of the URL.
+	Save(snapshot FloorsSnapshot) error
+}
+
+type fileSnapshotStore struct {
+	dir string
+}
+
+// NewFileSnapshotStore returns a store persisting a snapshot file per URL in the directory, created if missing.
+// The files are replaced atomically so a directory shared by several instances never exposes a partial snapshot.
+func NewFileSnapshotStore(dir string) (FloorsSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create the floors snapshot directory %s: %v", dir, err)
+	}
+	return &fileSnapshotStore{dir: dir}, nil
+}
+
+func (s *fileSnapshotStore) Load() ([]FloorsSnapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]FloorsSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), snapshotFileExtension) {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			glog.Errorf("Unable to read the floors snapshot %s: %v", path, err)
+			continue
+		}
+
+		var snapshot FloorsSnapshot
+		if err := jsonutil.UnmarshalValid(content, &snapshot); err != nil || snapshot.URL == "" {
+			glog.Errorf("Invalid floors snapshot %s", path)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (s *fileSnapshotStore) Save(snapshot FloorsSnapshot) error {
+	content, err := jsonutil.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomically(s.path(snapshot.URL), content)
+}
+
+// path returns the path of the snapshot of the URL, named after the hash of the URL
+func (s *fileSnapshotStore) path(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+snapshotFileExtension)
+}
@@ -0,0 +1,54 @@
+package floors
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSnapshotStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "floors")
+	store, err := NewFileSnapshotStore(dir)
+	require.NoError(t, err)
+
+	snapshots, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := FloorsSnapshot{URL: "https://floors.example.com/1234.json", AccountID: "1234", FetchedAt: fetchedAt, Data: json.RawMessage(`{"data":{"modelgroups":[]}}`)}
+	second := FloorsSnapshot{URL: "https://floors.example.com/5678.json", FetchedAt: fetchedAt, Data: json.RawMessage(`{"data":{}}`)}
+	assert.NoError(t, store.Save(first))
+	assert.NoError(t, store.Save(second))
+
+	updated := first
+	updated.FetchedAt = fetchedAt.Add(time.Hour)
+	assert.NoError(t, store.Save(updated))
+
+	// invalid files are skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "no_url.json"), []byte(`{"data":{}}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte(`{}`), 0644))
+
+	snapshots, err = store.Load()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []FloorsSnapshot{updated, second}, snapshots)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5, "the snapshot of a url should be replaced and no temporary file left")
+}
+
+func TestNewFileSnapshotStoreError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+
+	store, err := NewFileSnapshotStore(filepath.Join(file, "floors"))
+	assert.Error(t, err)
+	assert.Nil(t, store)
+}
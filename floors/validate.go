@@ -113,6 +113,13 @@ func selectValidFloorModelGroups(modelGroups []openrtb_ext.PriceFloorModelGroup,
 			continue
 		}
 
+		if modelGroup.Model != nil {
+			if _, err := getFloorProvider(modelGroup.Model); err != nil {
+				errs = append(errs, fmt.Errorf("Invalid Floor Model = '%v' due to %v", modelGroup.ModelVersion, err))
+				continue
+			}
+		}
+
 		validModelGroups = append(validModelGroups, modelGroup)
 	}
 	return validModelGroups, errs
@@ -289,6 +289,21 @@ func TestSelectValidFloorModelGroups(t *testing.T) {
 				ModelGroups: []openrtb_ext.PriceFloorModelGroup{}}},
 			Err: []error{errors.New("No model group present in floors.data")},
 		},
+		{
+			name: "Unknown floor model type",
+			floorExt: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+					{
+						ModelVersion: "Version 1",
+						Model:        &openrtb_ext.PriceFloorModel{Type: "unknown"},
+					},
+					{
+						ModelVersion: "Version 2",
+						Model:        &openrtb_ext.PriceFloorModel{Type: GBTModelType},
+					},
+				}}},
+			Err: []error{errors.New("Invalid Floor Model = 'Version 1' due to Unknown floor model type = 'unknown'")},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
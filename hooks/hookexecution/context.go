@@ -1,6 +1,7 @@
 package hookexecution
 
 import (
+	"context"
 	"sync"
 
 	"github.com/golang/glog"
@@ -17,10 +18,12 @@ type executionContext struct {
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	// traceCtx carries the span hook stage spans are attached to
+	traceCtx context.Context
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
-	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint}
+	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint, ActivityControl: ctx.activityControl}
 	if ctx.moduleContexts != nil {
 		if mc, ok := ctx.moduleContexts.get(moduleName); ok {
 			moduleInvocationCtx.ModuleContext = mc
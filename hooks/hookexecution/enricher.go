@@ -1,6 +1,7 @@
 package hookexecution
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/buger/jsonparser"
@@ -65,6 +66,38 @@ func EnrichExtBidResponse(
 	return response, warnings, err
 }
 
+// EnrichResponseBody sets debug and trace information returned from executing hooks
+// under the given path of an already serialized response.
+// It allows to report the outcome of stages running after serialization, such as exitpoint.
+//
+// The body is returned unchanged if there is nothing to report or if it's not a JSON object,
+// for example because a hook converted the response to a different format.
+func EnrichResponseBody(
+	body []byte,
+	path []string,
+	stageOutcomes []StageOutcome,
+	bidRequest *openrtb2.BidRequest,
+	account *config.Account,
+) ([]byte, error) {
+	trimmed := bytes.TrimRight(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' || !json.Valid(trimmed) {
+		return body, nil
+	}
+
+	// warnings are ignored as they have already been reported when the response was built
+	modules, _, err := GetModulesJSON(stageOutcomes, bidRequest, account)
+	if err != nil || modules == nil {
+		return body, err
+	}
+
+	enriched, err := jsonparser.Set(bytes.Clone(trimmed), modules, path...)
+	if err != nil {
+		return body, err
+	}
+
+	return append(enriched, body[len(trimmed):]...), nil
+}
+
 // GetModulesJSON returns debug and trace information produced from executing hooks.
 // Debug information is returned only if the debug mode is enabled by request and allowed by account (if provided).
 // The details of the trace output depends on the value in the bidRequest.ext.prebid.trace field.
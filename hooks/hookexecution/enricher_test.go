@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/buger/jsonparser"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
@@ -144,6 +146,77 @@ func TestEnrichBidResponse(t *testing.T) {
 	}
 }
 
+func TestEnrichResponseBody(t *testing.T) {
+	stageOutcomes := getStageOutcomes(t, "test/complete-stage-outcomes/stage-outcomes.json")
+	debugRequest := &openrtb2.BidRequest{Ext: []byte(`{"prebid": {"debug": true}}`)}
+
+	testCases := []struct {
+		description   string
+		body          []byte
+		path          []string
+		stageOutcomes []StageOutcome
+		bidRequest    *openrtb2.BidRequest
+		expectedPath  []string
+		expectedTail  string
+		expectedSame  bool
+	}{
+		{
+			description:   "Modules outcome set at path and trailing newline preserved",
+			body:          []byte("{\"id\":\"some-id\"}\n"),
+			path:          []string{"ext", "prebid", "modules"},
+			stageOutcomes: stageOutcomes,
+			bidRequest:    debugRequest,
+			expectedPath:  []string{"ext", "prebid", "modules"},
+			expectedTail:  "\n",
+		},
+		{
+			description:   "Body not changed if it is not a JSON object",
+			body:          []byte(`<VAST version="4.0"></VAST>`),
+			path:          []string{"ext", "prebid", "modules"},
+			stageOutcomes: stageOutcomes,
+			bidRequest:    debugRequest,
+			expectedSame:  true,
+		},
+		{
+			description:   "Body not changed if there are no stage outcomes",
+			body:          []byte(`{"id":"some-id"}`),
+			path:          []string{"ext", "prebid", "modules"},
+			stageOutcomes: nil,
+			bidRequest:    debugRequest,
+			expectedSame:  true,
+		},
+		{
+			description:   "Body not changed if debug and trace are not requested",
+			body:          []byte(`{"id":"some-id"}`),
+			path:          []string{"ext", "prebid", "modules"},
+			stageOutcomes: stageOutcomes,
+			bidRequest:    &openrtb2.BidRequest{},
+			expectedSame:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			body, err := EnrichResponseBody(test.body, test.path, test.stageOutcomes, test.bidRequest, &config.Account{DebugAllow: true})
+			require.NoError(t, err)
+
+			if test.expectedSame {
+				assert.Equal(t, test.body, body)
+				return
+			}
+
+			assert.True(t, strings.HasSuffix(string(body), test.expectedTail), "Trailing whitespace not preserved")
+			modules, _, _, err := jsonparser.Get(body, test.expectedPath...)
+			require.NoError(t, err)
+			assert.Contains(t, string(modules), `"errors"`)
+
+			id, err := jsonparser.GetString(body, "id")
+			require.NoError(t, err)
+			assert.Equal(t, "some-id", id)
+		})
+	}
+}
+
 func TestGetModulesJSON(t *testing.T) {
 	testCases := []struct {
 		description             string
@@ -16,6 +16,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 )
 
@@ -45,12 +46,19 @@ func executeStage[H any, P any](
 	stageModuleCtx := stageModuleContext{}
 	stageModuleCtx.groupCtx = make([]groupModuleContext, 0, len(plan))
 
+	traceCtx, span := tracing.StartSpan(executionCtx.traceCtx, "hooks."+executionCtx.stage, tracing.SpanKindInternal,
+		tracing.String("hook.stage", executionCtx.stage),
+		tracing.String("hook.endpoint", executionCtx.endpoint))
+	defer span.End()
+	executionCtx.traceCtx = traceCtx
+
 	for _, group := range plan {
 		groupOutcome, newPayload, moduleContexts, rejectErr := executeGroup(executionCtx, group, payload, hookHandler, metricEngine)
 		stageOutcome.ExecutionTimeMillis += groupOutcome.ExecutionTimeMillis
 		stageOutcome.Groups = append(stageOutcome.Groups, groupOutcome)
 		stageModuleCtx.groupCtx = append(stageModuleCtx.groupCtx, moduleContexts)
 		if rejectErr != nil {
+			span.SetError(rejectErr)
 			return stageOutcome, payload, stageModuleCtx, rejectErr
 		}
 
@@ -78,7 +86,7 @@ func executeGroup[H any, P any](
 		wg.Add(1)
 		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
 			defer wg.Done()
-			executeHook(moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
+			executeHook(executionCtx.traceCtx, moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
 		}(hook, mCtx)
 	}
 
@@ -93,6 +101,7 @@ func executeGroup[H any, P any](
 }
 
 func executeHook[H any, P any](
+	traceCtx context.Context,
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
 	payload P,
@@ -105,6 +114,14 @@ func executeHook[H any, P any](
 	startTime := time.Now()
 	hookId := HookID{ModuleCode: hw.Module, HookImplCode: hw.Code}
 
+	if traceCtx == nil {
+		traceCtx = context.Background()
+	}
+	traceCtx, span := tracing.StartSpan(traceCtx, "hooks."+hw.Module+"."+hw.Code, tracing.SpanKindInternal,
+		tracing.String("hook.module", hw.Module),
+		tracing.String("hook.code", hw.Code))
+	defer span.End()
+
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -113,7 +130,7 @@ func executeHook[H any, P any](
 			}
 		}()
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := context.WithTimeout(traceCtx, timeout)
 		defer cancel()
 		result, err := hookHandler(ctx, moduleCtx, hw.Hook, payload)
 		hookRespCh <- hookResponse[P]{
@@ -126,8 +143,10 @@ func executeHook[H any, P any](
 	case res := <-hookRespCh:
 		res.HookID = hookId
 		res.ExecutionTime = time.Since(startTime)
+		span.SetError(res.Err)
 		resp <- res
 	case <-time.After(timeout):
+		span.SetError(TimeoutError{})
 		resp <- hookResponse[P]{
 			Err:           TimeoutError{},
 			ExecutionTime: time.Since(startTime),
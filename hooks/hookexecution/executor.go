@@ -19,6 +19,7 @@ import (
 const (
 	EndpointAuction = "/openrtb2/auction"
 	EndpointAmp     = "/openrtb2/amp"
+	EndpointVideo   = "/openrtb2/video"
 )
 
 // An entity specifies the type of object that was processed during the execution of the stage.
@@ -29,6 +30,7 @@ const (
 	entityAuctionRequest           entity = "auction-request"
 	entityAuctionResponse          entity = "auction_response"
 	entityAllProcessedBidResponses entity = "all_processed_bid_responses"
+	entityHttpResponse             entity = "http-response"
 )
 
 type StageExecutor interface {
@@ -36,15 +38,17 @@ type StageExecutor interface {
 	ExecuteRawAuctionStage(body []byte) ([]byte, *RejectError)
 	ExecuteProcessedAuctionStage(req *openrtb_ext.RequestWrapper) error
 	ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper, bidder string) *RejectError
-	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
+	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, request *openrtb_ext.RequestWrapper, bidder string) *RejectError
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
+	ExecuteExitpointStage(body []byte, headers http.Header) ([]byte, http.Header)
 }
 
 type HookStageExecutor interface {
 	StageExecutor
 	SetAccount(account *config.Account)
 	SetActivityControl(activityControl privacy.ActivityControl)
+	SetTraceContext(ctx context.Context)
 	GetOutcomes() []StageOutcome
 }
 
@@ -57,6 +61,7 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	traceCtx        context.Context
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -84,6 +89,11 @@ func (e *hookExecutor) SetActivityControl(activityControl privacy.ActivityContro
 	e.activityControl = activityControl
 }
 
+// SetTraceContext sets the context holding the span which stage and hook spans are recorded under.
+func (e *hookExecutor) SetTraceContext(ctx context.Context) {
+	e.traceCtx = ctx
+}
+
 func (e *hookExecutor) GetOutcomes() []StageOutcome {
 	return e.stageOutcomes
 }
@@ -202,7 +212,11 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper
 	stageName := hooks.StageBidderRequest.String()
 	executionCtx := e.newContext(stageName)
 	payload := hookstage.BidderRequestPayload{Request: req, Bidder: bidder}
-	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	// the hooks may replace the request rather than update it, the returned one is the request of the bidder
+	if payload.Request != nil && payload.Request != req {
+		*req = *payload.Request
+	}
 	outcome.Entity = entity(bidder)
 	outcome.Stage = stageName
 
@@ -212,7 +226,7 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper
 	return reject
 }
 
-func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError {
+func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, request *openrtb_ext.RequestWrapper, bidder string) *RejectError {
 	plan := e.planBuilder.PlanForRawBidderResponseStage(e.endpoint, e.account)
 	if len(plan) == 0 {
 		return nil
@@ -229,7 +243,7 @@ func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderRe
 
 	stageName := hooks.StageRawBidderResponse.String()
 	executionCtx := e.newContext(stageName)
-	payload := hookstage.RawBidderResponsePayload{BidderResponse: response, Bidder: bidder}
+	payload := hookstage.RawBidderResponsePayload{BidderResponse: response, Request: request, Bidder: bidder}
 
 	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	response = payload.BidderResponse
@@ -295,7 +309,41 @@ func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidRespons
 	e.pushStageOutcome(outcome)
 }
 
+func (e *hookExecutor) ExecuteExitpointStage(body []byte, headers http.Header) ([]byte, http.Header) {
+	plan := e.planBuilder.PlanForExitpointStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return body, headers
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.Exitpoint,
+		payload hookstage.ExitpointPayload,
+	) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+		return hook.HandleExitpointHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageExitpoint.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.ExitpointPayload{Body: body, Headers: headers}
+
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityHttpResponse
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload.Body, payload.Headers
+}
+
 func (e *hookExecutor) newContext(stage string) executionContext {
+	traceCtx := e.traceCtx
+	if traceCtx == nil {
+		traceCtx = context.Background()
+	}
+
 	return executionContext{
 		account:         e.account,
 		accountID:       e.accountID,
@@ -303,6 +351,7 @@ func (e *hookExecutor) newContext(stage string) executionContext {
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
 		activityControl: e.activityControl,
+		traceCtx:        traceCtx,
 	}
 }
 
@@ -326,6 +375,8 @@ func (executor EmptyHookExecutor) SetAccount(_ *config.Account) {}
 
 func (executor EmptyHookExecutor) SetActivityControl(_ privacy.ActivityControl) {}
 
+func (executor EmptyHookExecutor) SetTraceContext(_ context.Context) {}
+
 func (executor EmptyHookExecutor) GetOutcomes() []StageOutcome {
 	return []StageOutcome{}
 }
@@ -346,7 +397,7 @@ func (executor EmptyHookExecutor) ExecuteBidderRequestStage(_ *openrtb_ext.Reque
 	return nil
 }
 
-func (executor EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ string) *RejectError {
+func (executor EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ *openrtb_ext.RequestWrapper, _ string) *RejectError {
 	return nil
 }
 
@@ -354,3 +405,7 @@ func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[ope
 }
 
 func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {}
+
+func (executor EmptyHookExecutor) ExecuteExitpointStage(body []byte, headers http.Header) ([]byte, http.Header) {
+	return body, headers
+}
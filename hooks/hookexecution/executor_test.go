@@ -40,6 +40,7 @@ func TestEmptyHookExecutor(t *testing.T) {
 	processedAuctionRejectErr := executor.ExecuteProcessedAuctionStage(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
 	bidderRequestRejectErr := executor.ExecuteBidderRequestStage(&openrtb_ext.RequestWrapper{BidRequest: bidderRequest}, "bidder-name")
 	executor.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
+	exitpointBody, exitpointHeaders := executor.ExecuteExitpointStage(body, http.Header{"Content-Type": []string{"application/json"}})
 
 	outcomes := executor.GetOutcomes()
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
@@ -54,6 +55,9 @@ func TestEmptyHookExecutor(t *testing.T) {
 	assert.Nil(t, processedAuctionRejectErr, "EmptyHookExecutor shouldn't return reject error at processed-auction stage.")
 	assert.Nil(t, bidderRequestRejectErr, "EmptyHookExecutor shouldn't return reject error at bidder-request stage.")
 	assert.Equal(t, expectedBidderRequest, bidderRequest, "EmptyHookExecutor shouldn't change payload at bidder-request stage.")
+
+	assert.Equal(t, body, exitpointBody, "EmptyHookExecutor shouldn't change body at exitpoint stage.")
+	assert.Equal(t, http.Header{"Content-Type": []string{"application/json"}}, exitpointHeaders, "EmptyHookExecutor shouldn't change headers at exitpoint stage.")
 }
 
 func TestExecuteEntrypointStage(t *testing.T) {
@@ -1176,6 +1180,33 @@ func TestExecuteBidderRequestStage(t *testing.T) {
 	}
 }
 
+func TestExecuteBidderRequestStageReplacedRequest(t *testing.T) {
+	exec := NewHookExecutor(TestReplaceBidderRequestBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	givenBidderRequest := &openrtb2.BidRequest{ID: "some-id"}
+	request := &openrtb_ext.RequestWrapper{BidRequest: givenBidderRequest}
+
+	reject := exec.ExecuteBidderRequestStage(request, "the-bidder")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Equal(t, &openrtb2.BidRequest{ID: "replaced-id"}, request.BidRequest, "The replaced request should be applied.")
+	assert.Equal(t, &openrtb2.BidRequest{ID: "some-id"}, givenBidderRequest, "The given request should not be modified.")
+}
+
+type TestReplaceBidderRequestBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestReplaceBidderRequestBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockReplaceBidderRequestHook{}},
+			},
+		},
+	}
+}
+
 func getModuleActivities(componentName string, allowTransmitUserFPD, allowTransmitPreciseGeo bool) *config.AccountPrivacy {
 	return &config.AccountPrivacy{
 		AllowActivities: &config.AllowActivities{
@@ -1417,7 +1448,7 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 			ac := privacy.NewActivityControl(privacyConfig)
 			exec.SetActivityControl(ac)
 
-			reject := exec.ExecuteRawBidderResponseStage(&test.givenBidderResponse, "the-bidder")
+			reject := exec.ExecuteRawBidderResponseStage(&test.givenBidderResponse, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, "the-bidder")
 
 			assert.Equal(ti, test.expectedReject, reject, "Unexpected stage reject.")
 			assert.Equal(ti, test.expectedBidderResponse, test.givenBidderResponse, "Incorrect response update.")
@@ -1952,6 +1983,84 @@ func TestExecuteAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestExecuteExitpointStage(t *testing.T) {
+	body := []byte(`{"id":"some-id"}`)
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedBody          []byte
+		expectedHeaders       http.Header
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Payload not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedBody:          body,
+			expectedHeaders:       http.Header{"Content-Type": []string{"application/json"}},
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Payload changed if hooks return mutations, rejection is ignored",
+			givenPlanBuilder: TestExitpointPlanBuilder{},
+			expectedBody:     []byte(`<VAST version="4.0"></VAST>`),
+			expectedHeaders:  http.Header{"Content-Type": []string{"application/xml"}},
+			expectedStageOutcomes: []StageOutcome{
+				{
+					Entity: entityHttpResponse,
+					Stage:  hooks.StageExitpoint.String(),
+					Groups: []GroupOutcome{
+						{
+							InvocationResults: []HookOutcome{
+								{
+									AnalyticsTags: hookanalytics.Analytics{},
+									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+									Status:        StatusExecutionFailure,
+									Errors: []string{
+										fmt.Sprintf("Module (name: foobar, hook code: foo) tried to reject request on the %s stage that does not support rejection", hooks.StageExitpoint),
+									},
+								},
+							},
+						},
+						{
+							InvocationResults: []HookOutcome{
+								{
+									AnalyticsTags: hookanalytics.Analytics{},
+									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+									Status:        StatusSuccess,
+									Action:        ActionUpdate,
+									DebugMessages: []string{
+										fmt.Sprintf("Hook mutation successfully applied, affected key: exitpoint.body, mutation type: %s", hookstage.MutationUpdate),
+										fmt.Sprintf("Hook mutation successfully applied, affected key: exitpoint.headers.content-type, mutation type: %s", hookstage.MutationUpdate),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointVideo, &metricsConfig.NilMetricsEngine{})
+
+			newBody, newHeaders := exec.ExecuteExitpointStage(body, http.Header{"Content-Type": []string{"application/json"}})
+
+			assert.Equal(t, test.expectedBody, newBody, "Incorrect body update.")
+			assert.Equal(t, test.expectedHeaders, newHeaders, "Incorrect headers update.")
+
+			stageOutcomes := exec.GetOutcomes()
+			if len(test.expectedStageOutcomes) == 0 {
+				assert.Empty(t, stageOutcomes, "Incorrect stage outcomes.")
+			} else {
+				assertEqualStageOutcomes(t, test.expectedStageOutcomes[0], stageOutcomes[0])
+			}
+		})
+	}
+}
+
 func TestInterStageContextCommunication(t *testing.T) {
 	body := []byte(`{"foo": "bar"}`)
 	reader := bytes.NewReader(body)
@@ -2011,7 +2120,7 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing processed-auction hook.")
 
 	// test that context added at the raw bidder response stage merged with existing module contexts
-	reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "some-bidder")
+	reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, "some-bidder")
 	assert.Nil(t, reject, "Unexpected reject from raw-bidder-response stage.")
 	assert.Equal(t, &moduleContexts{ctxs: map[string]hookstage.ModuleContext{
 		"module-1": {
@@ -2169,6 +2278,27 @@ func (e TestApplyHookMutationsBuilder) PlanForAuctionResponseStage(_ string, _ *
 	}
 }
 
+type TestExitpointPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestExitpointPlanBuilder) PlanForExitpointStage(_ string, _ *config.Account) hooks.Plan[hookstage.Exitpoint] {
+	return hooks.Plan[hookstage.Exitpoint]{
+		hooks.Group[hookstage.Exitpoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Exitpoint]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectHook{}},
+			},
+		},
+		hooks.Group[hookstage.Exitpoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Exitpoint]{
+				{Module: "foobar", Code: "bar", Hook: mockUpdateExitpointHook{}},
+			},
+		},
+	}
+}
+
 type TestRejectPlanBuilder struct {
 	hooks.EmptyPlanBuilder
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
@@ -100,6 +101,10 @@ func (e mockRejectHook) HandleAuctionResponseHook(_ context.Context, _ hookstage
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{Reject: true}, nil
 }
 
+func (e mockRejectHook) HandleExitpointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ExitpointPayload) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	return hookstage.HookResult[hookstage.ExitpointPayload]{Reject: true}, nil
+}
+
 type mockTimeoutHook struct{}
 
 func (e mockTimeoutHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
@@ -385,3 +390,34 @@ func (e mockUpdateBidResponseHook) HandleAuctionResponseHook(_ context.Context,
 
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ChangeSet: c}, nil
 }
+
+type mockUpdateExitpointHook struct{}
+
+func (e mockUpdateExitpointHook) HandleExitpointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ExitpointPayload) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	c := hookstage.ChangeSet[hookstage.ExitpointPayload]{}
+	c.AddMutation(
+		func(payload hookstage.ExitpointPayload) (hookstage.ExitpointPayload, error) {
+			payload.Body = []byte(`<VAST version="4.0"></VAST>`)
+			return payload, nil
+		}, hookstage.MutationUpdate, "exitpoint", "body",
+	).AddMutation(
+		func(payload hookstage.ExitpointPayload) (hookstage.ExitpointPayload, error) {
+			payload.Headers.Set("Content-Type", "application/xml")
+			return payload, nil
+		}, hookstage.MutationUpdate, "exitpoint", "headers.content-type",
+	)
+
+	return hookstage.HookResult[hookstage.ExitpointPayload]{ChangeSet: c}, nil
+}
+
+type mockReplaceBidderRequestHook struct{}
+
+func (e mockReplaceBidderRequestHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.BidderRequestPayload]{}
+	c.AddMutation(func(payload hookstage.BidderRequestPayload) (hookstage.BidderRequestPayload, error) {
+		payload.Request = &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "replaced-id"}}
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidRequest")
+
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{ChangeSet: c}, nil
+}
@@ -0,0 +1,32 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// Exitpoint hooks are invoked at the very end of request processing,
+// after the response is serialized and right before it is written to the client.
+// The hooks are invoked even if the request was rejected at earlier stages.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection has no effect and is completely ignored at this stage.
+type Exitpoint interface {
+	HandleExitpointHook(
+		context.Context,
+		ModuleInvocationContext,
+		ExitpointPayload,
+	) (HookResult[ExitpointPayload], error)
+}
+
+// ExitpointPayload consists of the serialized response body and the HTTP headers
+// that will be sent back to the requester.
+// Hooks are allowed to modify both using mutations, for example to convert
+// the response to a different format and update the Content-Type header accordingly.
+type ExitpointPayload struct {
+	Body    []byte
+	Headers http.Header
+}
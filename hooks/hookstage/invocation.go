@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
@@ -31,6 +32,9 @@ type ModuleInvocationContext struct {
 	ModuleContext ModuleContext
 	// HookImplCode is the hook_impl_code for a module instance to differentiate between multiple hooks
 	HookImplCode string
+	// ActivityControl allows modules to check privacy activities for the request.
+	// It is available once the account is resolved, starting with the raw_auction_request stage.
+	ActivityControl privacy.ActivityControl
 }
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
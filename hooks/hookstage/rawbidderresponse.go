@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
 // RawBidderResponse hooks are invoked for each bidder participating in auction.
@@ -21,9 +22,12 @@ type RawBidderResponse interface {
 	) (HookResult[RawBidderResponsePayload], error)
 }
 
-// RawBidderResponsePayload consists of a bidder response returned by a particular bidder.
-// Hooks are allowed to modify bidder response using mutations.
+// RawBidderResponsePayload consists of a bidder response returned by a particular bidder
+// and the bid request sent to the bidder.
+// Hooks are allowed to modify bidder response using mutations,
+// the bid request is provided for reading only.
 type RawBidderResponsePayload struct {
 	BidderResponse *adapters.BidderResponse
+	Request        *openrtb_ext.RequestWrapper
 	Bidder         string
 }
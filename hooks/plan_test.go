@@ -770,6 +770,63 @@ func TestPlanForAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestPlanForExitpointStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "vast", "hook_impl_code": "convert"}]}`
+	const hostPlanData string = `{"endpoints": {"/openrtb2/video": {"stages": {"exitpoint": {"groups": [` + group1 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/openrtb2/video": {"stages": {"exitpoint": {"groups": [` + group2 + `]}}}, "/openrtb2/amp": {"stages": {"auction_response": {"groups": [` + group1 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeExitpointHook{},
+		"vast":   fakeExitpointHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint       string
+		giveAccountPlanData []byte
+		expectedPlan        Plan[hookstage.Exitpoint]
+	}{
+		"Host and account-specific plans are merged": {
+			givenEndpoint:       "/openrtb2/video",
+			giveAccountPlanData: []byte(accountPlanData),
+			expectedPlan: Plan[hookstage.Exitpoint]{
+				Group[hookstage.Exitpoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeExitpointHook{}},
+					},
+				},
+				Group[hookstage.Exitpoint]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "vast", Code: "convert", Hook: fakeExitpointHook{}},
+					},
+				},
+			},
+		},
+		"Plan is empty for endpoint without exitpoint stage": {
+			givenEndpoint:       "/openrtb2/amp",
+			giveAccountPlanData: []byte(accountPlanData),
+			expectedPlan:        Plan[hookstage.Exitpoint]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(`{}`))
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForExitpointStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
 func getPlanBuilder(
 	moduleHooks map[string]interface{},
 	hostPlanData, accountPlanData []byte,
@@ -870,3 +927,13 @@ func (f fakeAuctionResponseHook) HandleAuctionResponseHook(
 ) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{}, nil
 }
+
+type fakeExitpointHook struct{}
+
+func (f fakeExitpointHook) HandleExitpointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.ExitpointPayload,
+) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	return hookstage.HookResult[hookstage.ExitpointPayload]{}, nil
+}
@@ -58,16 +58,45 @@ func main() {
 const configFileName = "pbs"
 const infoDirectory = "./static/bidder-info"
 
+// currencyRatesFetchTimeout bounds the fetch of the currency rates from a source, so a hung source falls back to the next one
+const currencyRatesFetchTimeout = 10 * time.Second
+
 func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
 	v := viper.New()
 	config.SetupViper(v, configFileName, bidderInfos)
 	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
 }
 
+// newCurrencyRatesSources returns the currency rates sources by priority, the fetch_url is the only source when none is configured
+func newCurrencyRatesSources(cfg config.CurrencyConverter, httpClient *http.Client) []currency.RatesSource {
+	if len(cfg.Sources) == 0 {
+		return []currency.RatesSource{
+			{
+				Fetcher:             currency.NewHTTPRatesFetcher(httpClient, cfg.FetchURL),
+				StaleRatesThreshold: time.Duration(cfg.StaleRatesSeconds) * time.Second,
+			},
+		}
+	}
+
+	sources := make([]currency.RatesSource, 0, len(cfg.Sources))
+	for _, sourceCfg := range cfg.Sources {
+		source := currency.RatesSource{StaleRatesThreshold: time.Duration(sourceCfg.StaleRatesSeconds) * time.Second}
+		switch sourceCfg.Type {
+		case config.CurrencyRatesSourceHTTP:
+			source.Fetcher = currency.NewHTTPRatesFetcher(httpClient, sourceCfg.URL)
+		case config.CurrencyRatesSourceFile:
+			source.Fetcher = currency.NewFileRatesFetcher(sourceCfg.Path)
+		case config.CurrencyRatesSourceStatic:
+			source.Fetcher = currency.NewStaticRatesFetcher(sourceCfg.Rates)
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
 func serve(cfg *config.Configuration) error {
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
-	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
-	currencyConverter := currency.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
+	currencyConverter := currency.NewMultiSourceRateConverter(newCurrencyRatesSources(cfg.CurrencyConverter, &http.Client{Timeout: currencyRatesFetchTimeout}), cfg.CurrencyConverter.SnapshotPath)
 
 	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
 	currencyConverterTickerTask.Start()
@@ -77,8 +106,16 @@ func serve(cfg *config.Configuration) error {
 		return err
 	}
 
+	if cfg.BidderInfoReload.Enabled && cfg.BidderInfoReload.PollIntervalSeconds > 0 {
+		bidderInfoReloadInterval := time.Duration(cfg.BidderInfoReload.PollIntervalSeconds) * time.Second
+		bidderInfoReloadTickerTask := task.NewTickerTask(bidderInfoReloadInterval, r.BidderInfoReloader)
+		bidderInfoReloadTickerTask.Start()
+		defer bidderInfoReloadTickerTask.Stop()
+	}
+
 	corsRouter := router.SupportCORS(r)
-	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine); err != nil {
+	adminRouter := router.Admin(currencyConverter, fetchingInterval, r.StoredCaches, r.BidderInfoReloader, cfg.BidderInfoReload.Enabled, r.BidderHealth, r.PriceFloorFetcher)
+	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, adminRouter, r.MetricsEngine); err != nil {
 		glog.Fatalf("prebid-server returned an error: %v", err)
 	}
 
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +67,28 @@ func TestViperEnv(t *testing.T) {
 	assert.Equal(t, 60, v.Get("host_cookie.ttl_days"), "Config With Underscores")
 	assert.ElementsMatch(t, []string{"1.1.1.1/24", "2.2.2.2/24"}, v.Get("request_validation.ipv4_private_networks"), "Arrays")
 }
+
+func TestNewCurrencyRatesSources(t *testing.T) {
+	httpClient := &http.Client{}
+
+	sources := newCurrencyRatesSources(config.CurrencyConverter{FetchURL: "https://currency.prebid.org", StaleRatesSeconds: 60}, httpClient)
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, "https://currency.prebid.org", sources[0].Fetcher.Source())
+		assert.Equal(t, time.Minute, sources[0].StaleRatesThreshold)
+	}
+
+	sources = newCurrencyRatesSources(config.CurrencyConverter{
+		FetchURL: "https://currency.prebid.org",
+		Sources: []config.CurrencyRatesSource{
+			{Type: config.CurrencyRatesSourceHTTP, URL: "https://rates.test.com", StaleRatesSeconds: 3600},
+			{Type: config.CurrencyRatesSourceFile, Path: "/etc/pbs/rates.json"},
+			{Type: config.CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+		},
+	}, httpClient)
+	if assert.Len(t, sources, 3) {
+		assert.Equal(t, "https://rates.test.com", sources[0].Fetcher.Source())
+		assert.Equal(t, time.Hour, sources[0].StaleRatesThreshold)
+		assert.Equal(t, "file:///etc/pbs/rates.json", sources[1].Fetcher.Source())
+		assert.Equal(t, "static", sources[2].Fetcher.Source())
+	}
+}
@@ -378,6 +378,41 @@ func (me *MultiMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderN
 	}
 }
 
+// RecordAdapterVASTTrackerInjectionError across all engines
+func (me *MultiMetricsEngine) RecordAdapterVASTTrackerInjectionError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterVASTTrackerInjectionError(adapter)
+	}
+}
+
+// RecordAdapterHealthVectors across all engines
+func (me *MultiMetricsEngine) RecordAdapterHealthVectors(adapter openrtb_ext.BidderName, state metrics.AdapterHealthState, count int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterHealthVectors(adapter, state, count)
+	}
+}
+
+// RecordAnalyticsQueueDepth across all engines
+func (me *MultiMetricsEngine) RecordAnalyticsQueueDepth(module string, depth int) {
+	for _, thisME := range *me {
+		thisME.RecordAnalyticsQueueDepth(module, depth)
+	}
+}
+
+// RecordAnalyticsObjectDropped across all engines
+func (me *MultiMetricsEngine) RecordAnalyticsObjectDropped(module string) {
+	for _, thisME := range *me {
+		thisME.RecordAnalyticsObjectDropped(module)
+	}
+}
+
+// RecordUIDCookieVerificationFailure across all engines
+func (me *MultiMetricsEngine) RecordUIDCookieVerificationFailure(reason metrics.UIDCookieFailure) {
+	for _, thisME := range *me {
+		thisME.RecordUIDCookieVerificationFailure(reason)
+	}
+}
+
 // NilMetricsEngine implements the MetricsEngine interface where no metrics are actually captured. This is
 // used if no metric backend is configured and also for tests.
 type NilMetricsEngine struct{}
@@ -557,3 +592,23 @@ func (me *NilMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 // RecordAdapterThrottled as a noop
 func (me *NilMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderName) {
 }
+
+// RecordAdapterVASTTrackerInjectionError as a noop
+func (me *NilMetricsEngine) RecordAdapterVASTTrackerInjectionError(adapter openrtb_ext.BidderName) {
+}
+
+// RecordAdapterHealthVectors as a noop
+func (me *NilMetricsEngine) RecordAdapterHealthVectors(adapter openrtb_ext.BidderName, state metrics.AdapterHealthState, count int) {
+}
+
+// RecordAnalyticsQueueDepth as a noop
+func (me *NilMetricsEngine) RecordAnalyticsQueueDepth(module string, depth int) {
+}
+
+// RecordAnalyticsObjectDropped as a noop
+func (me *NilMetricsEngine) RecordAnalyticsObjectDropped(module string) {
+}
+
+// RecordUIDCookieVerificationFailure as a noop
+func (me *NilMetricsEngine) RecordUIDCookieVerificationFailure(reason metrics.UIDCookieFailure) {
+}
@@ -45,6 +45,7 @@ type Metrics struct {
 	SetUidMeter           metrics.Meter
 	SetUidStatusMeter     map[SetUidStatus]metrics.Meter
 	SyncerSetsMeter       map[string]map[SyncerSetUidStatus]metrics.Meter
+	UIDCookieFailureMeter map[UIDCookieFailure]metrics.Meter
 
 	// Media types found in the "imp" JSON object
 	ImpsTypeBanner metrics.Meter
@@ -103,6 +104,10 @@ type AdapterMetrics struct {
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
 
+	VASTTrackerInjectionErrorMeter metrics.Meter
+
+	HealthVectorsGauges map[AdapterHealthState]metrics.Gauge
+
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
 
@@ -181,6 +186,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SetUidMeter:                    blankMeter,
 		SetUidStatusMeter:              make(map[SetUidStatus]metrics.Meter),
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
+		UIDCookieFailureMeter:          make(map[UIDCookieFailure]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 
 		ImpsTypeBanner: blankMeter,
@@ -327,6 +333,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.SetUidStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_requests.%s", s), registry)
 	}
 
+	for _, r := range UIDCookieFailures() {
+		newMetrics.UIDCookieFailureMeter[r] = metrics.GetOrRegisterMeter(fmt.Sprintf("uid_cookie_verification_failures.%s", r), registry)
+	}
+
 	for _, syncerKey := range syncerKeys {
 		newMetrics.SyncerRequestsMeter[syncerKey] = make(map[SyncerCookieSyncStatus]metrics.Meter)
 		for _, status := range SyncerRequestStatuses() {
@@ -403,6 +413,10 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+
+		VASTTrackerInjectionErrorMeter: blankMeter,
+
+		HealthVectorsGauges: make(map[AdapterHealthState]metrics.Gauge),
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -418,6 +432,9 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	for _, state := range AdapterHealthStates() {
+		newAdapter.HealthVectorsGauges[state] = metrics.NilGauge{}
+	}
 	return newAdapter
 }
 
@@ -493,6 +510,10 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
 	am.ThrottledMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.throttled", adapterOrAccount, exchange), registry)
+	am.VASTTrackerInjectionErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.vast_tracker_injection.err", adapterOrAccount, exchange), registry)
+	for state := range am.HealthVectorsGauges {
+		am.HealthVectorsGauges[state] = metrics.GetOrRegisterGauge(fmt.Sprintf("%s.%s.health_vectors.%s", adapterOrAccount, exchange, state), registry)
+	}
 
 	am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
 	am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
@@ -1178,3 +1199,42 @@ func (me *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 
 	am.ThrottledMeter.Mark(1)
 }
+
+func (me *Metrics) RecordAdapterVASTTrackerInjectionError(adapterName openrtb_ext.BidderName) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter VAST tracker injection error metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.VASTTrackerInjectionErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordAdapterHealthVectors(adapterName openrtb_ext.BidderName, state AdapterHealthState, count int) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter health vectors metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	if gauge, ok := am.HealthVectorsGauges[state]; ok {
+		gauge.Update(int64(count))
+	}
+}
+
+func (me *Metrics) RecordAnalyticsQueueDepth(module string, depth int) {
+	metrics.GetOrRegisterGauge(fmt.Sprintf("analytics.%s.queue_depth", module), me.MetricsRegistry).Update(int64(depth))
+}
+
+func (me *Metrics) RecordAnalyticsObjectDropped(module string) {
+	metrics.GetOrRegisterMeter(fmt.Sprintf("analytics.%s.dropped", module), me.MetricsRegistry).Mark(1)
+}
+
+// RecordUIDCookieVerificationFailure implements a part of the MetricsEngine interface. Records a uids cookie failing its verification
+func (me *Metrics) RecordUIDCookieVerificationFailure(reason UIDCookieFailure) {
+	if meter, exists := me.UIDCookieFailureMeter[reason]; exists {
+		meter.Mark(1)
+	}
+}
@@ -35,6 +35,8 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.opt_out", m.SetUidStatusMeter[SetUidOptOut])
 	ensureContains(t, registry, "setuid_requests.gdpr_blocked_host_cookie", m.SetUidStatusMeter[SetUidGDPRHostCookieBlocked])
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
+	ensureContains(t, registry, "uid_cookie_verification_failures.invalid", m.UIDCookieFailureMeter[UIDCookieInvalid])
+	ensureContains(t, registry, "uid_cookie_verification_failures.plaintext_rejected", m.UIDCookieFailureMeter[UIDCookiePlaintextRejected])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
@@ -832,6 +834,65 @@ func TestRecordAdapterBuyerUIDScrubbed(t *testing.T) {
 	}
 }
 
+func TestRecordAdapterVASTTrackerInjectionError(t *testing.T) {
+	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
+	adapter := "AnyName"
+	lowerCaseAdapterName := "anyname"
+
+	tests := []struct {
+		name          string
+		adapterName   openrtb_ext.BidderName
+		expectedCount int64
+	}{
+		{
+			name:          "bidder_found",
+			adapterName:   openrtb_ext.BidderName(adapter),
+			expectedCount: 1,
+		},
+		{
+			name:          "bidder_not_found",
+			adapterName:   fakeBidder,
+			expectedCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter)}, config.DisabledMetrics{}, nil, nil)
+
+			m.RecordAdapterVASTTrackerInjectionError(tt.adapterName)
+
+			assert.Equal(t, tt.expectedCount, m.AdapterMetrics[lowerCaseAdapterName].VASTTrackerInjectionErrorMeter.Count())
+		})
+	}
+}
+
+func TestRecordAdapterHealthVectors(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{"AnyName"}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterHealthVectors("AnyName", AdapterHealthOpen, 3)
+	m.RecordAdapterHealthVectors("AnyName", AdapterHealthHalfOpen, 1)
+	m.RecordAdapterHealthVectors("fooAdvertising", AdapterHealthOpen, 5)
+
+	assert.Equal(t, int64(0), m.AdapterMetrics["anyname"].HealthVectorsGauges[AdapterHealthClosed].Value())
+	assert.Equal(t, int64(3), m.AdapterMetrics["anyname"].HealthVectorsGauges[AdapterHealthOpen].Value())
+	assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].HealthVectorsGauges[AdapterHealthHalfOpen].Value())
+}
+
+func TestRecordAnalyticsQueue(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{"AnyName"}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAnalyticsQueueDepth("pubstack", 3)
+	m.RecordAnalyticsQueueDepth("pubstack", 2)
+	m.RecordAnalyticsObjectDropped("pubstack")
+	m.RecordAnalyticsObjectDropped("pubstack")
+
+	assert.Equal(t, int64(2), registry.Get("analytics.pubstack.queue_depth").(metrics.Gauge).Value())
+	assert.Equal(t, int64(2), registry.Get("analytics.pubstack.dropped").(metrics.Meter).Count())
+}
+
 func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
 	adapter := "AnyName"
@@ -929,6 +990,17 @@ func TestRecordSetUid(t *testing.T) {
 	assert.Equal(t, m.SetUidStatusMeter[SetUidSyncerUnknown].Count(), int64(0))
 }
 
+func TestRecordUIDCookieVerificationFailure(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordUIDCookieVerificationFailure(UIDCookieUnknownKey)
+	m.RecordUIDCookieVerificationFailure(UIDCookieFailure("unknown reason"))
+
+	assert.Equal(t, int64(1), m.UIDCookieFailureMeter[UIDCookieUnknownKey].Count())
+	assert.Equal(t, int64(0), m.UIDCookieFailureMeter[UIDCookieInvalid].Count())
+}
+
 func TestRecordSyncerSet(t *testing.T) {
 	registry := metrics.NewRegistry()
 	syncerKeys := []string{"foo"}
@@ -287,6 +287,24 @@ func AdapterErrors() []AdapterError {
 	}
 }
 
+// AdapterHealthState : circuit breaker state of a bidder health vector
+type AdapterHealthState string
+
+// The adapter health states
+const (
+	AdapterHealthClosed   AdapterHealthState = "closed"
+	AdapterHealthOpen     AdapterHealthState = "open"
+	AdapterHealthHalfOpen AdapterHealthState = "half_open"
+)
+
+func AdapterHealthStates() []AdapterHealthState {
+	return []AdapterHealthState{
+		AdapterHealthClosed,
+		AdapterHealthOpen,
+		AdapterHealthHalfOpen,
+	}
+}
+
 const (
 	// CacheHit represents a cache hit i.e the key was found in cache
 	CacheHit CacheResult = "hit"
@@ -419,6 +437,26 @@ func SyncerSetUidStatuses() []SyncerSetUidStatus {
 	}
 }
 
+// UIDCookieFailure is the reason a uids cookie failed its verification.
+type UIDCookieFailure string
+
+const (
+	UIDCookieMalformed         UIDCookieFailure = "malformed"
+	UIDCookieUnknownKey        UIDCookieFailure = "unknown_key"
+	UIDCookieInvalid           UIDCookieFailure = "invalid"
+	UIDCookiePlaintextRejected UIDCookieFailure = "plaintext_rejected"
+)
+
+// UIDCookieFailures returns possible uids cookie verification failures.
+func UIDCookieFailures() []UIDCookieFailure {
+	return []UIDCookieFailure{
+		UIDCookieMalformed,
+		UIDCookieUnknownKey,
+		UIDCookieInvalid,
+		UIDCookiePlaintextRejected,
+	}
+}
+
 // MetricsEngine is a generic interface to record PBS metrics into the desired backend
 // The first three metrics function fire off once per incoming request, so total metrics
 // will equal the total number of incoming requests. The remaining 5 fire off per outgoing
@@ -473,4 +511,9 @@ type MetricsEngine interface {
 	RecordModuleExecutionError(labels ModuleLabels)
 	RecordModuleTimeout(labels ModuleLabels)
 	RecordAdapterThrottled(adapterName openrtb_ext.BidderName)
+	RecordAdapterVASTTrackerInjectionError(adapterName openrtb_ext.BidderName)
+	RecordAdapterHealthVectors(adapterName openrtb_ext.BidderName, state AdapterHealthState, count int)
+	RecordAnalyticsQueueDepth(module string, depth int)
+	RecordAnalyticsObjectDropped(module string)
+	RecordUIDCookieVerificationFailure(reason UIDCookieFailure)
 }
@@ -230,3 +230,23 @@ func (me *MetricsEngineMock) RecordModuleTimeout(labels ModuleLabels) {
 func (me *MetricsEngineMock) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 	me.Called(adapterName)
 }
+
+func (me *MetricsEngineMock) RecordAdapterVASTTrackerInjectionError(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+func (me *MetricsEngineMock) RecordAdapterHealthVectors(adapterName openrtb_ext.BidderName, state AdapterHealthState, count int) {
+	me.Called(adapterName, state, count)
+}
+
+func (me *MetricsEngineMock) RecordAnalyticsQueueDepth(module string, depth int) {
+	me.Called(module, depth)
+}
+
+func (me *MetricsEngineMock) RecordAnalyticsObjectDropped(module string) {
+	me.Called(module)
+}
+
+func (me *MetricsEngineMock) RecordUIDCookieVerificationFailure(reason UIDCookieFailure) {
+	me.Called(reason)
+}
@@ -26,6 +26,7 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		syncerRequestStatusValues = enumAsString(metrics.SyncerRequestStatuses())
 		syncerSetsStatusValues    = enumAsString(metrics.SyncerSetUidStatuses())
 		tcfVersionValues          = enumAsString(metrics.TCFVersions())
+		uidCookieFailureValues    = enumAsString(metrics.UIDCookieFailures())
 	)
 
 	preloadLabelValuesForCounter(m.connectionsError, map[string][]string{
@@ -40,6 +41,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		statusLabel: setUidStatusValues,
 	})
 
+	preloadLabelValuesForCounter(m.uidCookieFailures, map[string][]string{
+		reasonLabel: uidCookieFailureValues,
+	})
+
 	preloadLabelValuesForCounter(m.impressions, map[string][]string{
 		isBannerLabel: boolValues,
 		isVideoLabel:  boolValues,
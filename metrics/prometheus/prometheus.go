@@ -25,6 +25,7 @@ type Metrics struct {
 	connectionsOpened            prometheus.Counter
 	cookieSync                   *prometheus.CounterVec
 	setUid                       *prometheus.CounterVec
+	uidCookieFailures            *prometheus.CounterVec
 	impressions                  *prometheus.CounterVec
 	prebidCacheWriteTimer        *prometheus.HistogramVec
 	requests                     *prometheus.CounterVec
@@ -77,6 +78,12 @@ type Metrics struct {
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
 	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
 	adapterThrottled                      *prometheus.CounterVec
+	adapterVASTTrackerInjectionErrors     *prometheus.CounterVec
+	adapterHealthVectors                  *prometheus.GaugeVec
+
+	// Analytics Metrics
+	analyticsQueueDepth     *prometheus.GaugeVec
+	analyticsObjectsDropped *prometheus.CounterVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
@@ -109,6 +116,8 @@ const (
 	actionLabel          = "action"
 	adapterErrorLabel    = "adapter_error"
 	adapterLabel         = "adapter"
+	analyticsModuleLabel = "analytics_module"
+	healthStateLabel     = "health_state"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
@@ -123,6 +132,7 @@ const (
 	overheadTypeLabel    = "overhead_type"
 	privacyBlockedLabel  = "privacy_blocked"
 	requestStatusLabel   = "request_status"
+	reasonLabel          = "reason"
 	requestTypeLabel     = "request_type"
 	stageLabel           = "stage"
 	statusLabel          = "status"
@@ -200,6 +210,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of set uid requests to Prebid Server.",
 		[]string{statusLabel})
 
+	metrics.uidCookieFailures = newCounter(cfg, reg,
+		"uid_cookie_verification_failures",
+		"Count of uids cookies ignored because they failed their verification.",
+		[]string{reasonLabel})
+
 	metrics.impressions = newCounter(cfg, reg,
 		"impressions_requests",
 		"Count of requested impressions to Prebid Server labeled by type.",
@@ -433,6 +448,26 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of requests throttled labeled by adapter.",
 		[]string{adapterLabel})
 
+	metrics.adapterVASTTrackerInjectionErrors = newCounter(cfg, reg,
+		"adapter_vast_tracker_injection_err",
+		"Count of video bids whose VAST could not be injected with the account event trackers labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterHealthVectors = newGaugeVec(cfg, reg,
+		"adapter_health_vectors",
+		"Number of health vectors tracked labeled by adapter and circuit breaker state.",
+		[]string{adapterLabel, healthStateLabel})
+
+	metrics.analyticsQueueDepth = newGaugeVec(cfg, reg,
+		"analytics_queue_depth",
+		"Number of objects waiting in the queue of the analytics module.",
+		[]string{analyticsModuleLabel})
+
+	metrics.analyticsObjectsDropped = newCounter(cfg, reg,
+		"analytics_objects_dropped",
+		"Count of objects dropped because the queue of the analytics module was full.",
+		[]string{analyticsModuleLabel})
+
 	metrics.overheadTimer = newHistogramVec(cfg, reg,
 		"overhead_time_seconds",
 		"Seconds to prepare adapter request or resolve adapter response",
@@ -599,6 +634,18 @@ func newCounter(cfg config.PrometheusMetrics, registry *prometheus.Registry, nam
 	return counter
 }
 
+func newGaugeVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string) prometheus.Counter {
 	opts := prometheus.CounterOpts{
 		Namespace: cfg.Namespace,
@@ -883,6 +930,12 @@ func (m *Metrics) RecordSetUid(status metrics.SetUidStatus) {
 	}).Inc()
 }
 
+func (m *Metrics) RecordUIDCookieVerificationFailure(reason metrics.UIDCookieFailure) {
+	m.uidCookieFailures.With(prometheus.Labels{
+		reasonLabel: string(reason),
+	}).Inc()
+}
+
 func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
 	m.syncerSets.With(prometheus.Labels{
 		syncerLabel: key,
@@ -1101,3 +1154,28 @@ func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 		adapterLabel: strings.ToLower(string(adapterName)),
 	}).Inc()
 }
+
+func (m *Metrics) RecordAdapterVASTTrackerInjectionError(adapterName openrtb_ext.BidderName) {
+	m.adapterVASTTrackerInjectionErrors.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterHealthVectors(adapterName openrtb_ext.BidderName, state metrics.AdapterHealthState, count int) {
+	m.adapterHealthVectors.With(prometheus.Labels{
+		adapterLabel:     strings.ToLower(string(adapterName)),
+		healthStateLabel: string(state),
+	}).Set(float64(count))
+}
+
+func (m *Metrics) RecordAnalyticsQueueDepth(module string, depth int) {
+	m.analyticsQueueDepth.With(prometheus.Labels{
+		analyticsModuleLabel: module,
+	}).Set(float64(depth))
+}
+
+func (m *Metrics) RecordAnalyticsObjectDropped(module string) {
+	m.analyticsObjectsDropped.With(prometheus.Labels{
+		analyticsModuleLabel: module,
+	}).Inc()
+}
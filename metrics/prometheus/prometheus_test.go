@@ -1294,6 +1294,20 @@ func TestSetUidMetric(t *testing.T) {
 	}
 }
 
+func TestUIDCookieVerificationFailureMetric(t *testing.T) {
+	for _, reason := range metrics.UIDCookieFailures() {
+		m := createMetricsForTesting()
+
+		m.RecordUIDCookieVerificationFailure(reason)
+
+		assertCounterVecValue(t, "", "uid_cookie_verification_failures:"+string(reason), m.uidCookieFailures,
+			float64(1),
+			prometheus.Labels{
+				reasonLabel: string(reason),
+			})
+	}
+}
+
 func TestRecordSyncerSetMetric(t *testing.T) {
 	key := "anyKey"
 
@@ -1846,6 +1860,50 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 		})
 }
 
+func TestRecordAdapterVASTTrackerInjectionError(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	m.RecordAdapterVASTTrackerInjectionError(adapterName)
+
+	assertCounterVecValue(t,
+		"Increment adapter VAST tracker injection error counter",
+		"adapter_vast_tracker_injection_err",
+		m.adapterVASTTrackerInjectionErrors,
+		1,
+		prometheus.Labels{
+			adapterLabel: lowerCasedAdapterName,
+		})
+}
+
+func TestRecordAdapterHealthVectors(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	m.RecordAdapterHealthVectors(adapterName, metrics.AdapterHealthOpen, 3)
+	m.RecordAdapterHealthVectors(adapterName, metrics.AdapterHealthOpen, 2)
+
+	gauge := dto.Metric{}
+	m.adapterHealthVectors.With(prometheus.Labels{adapterLabel: "anyname", healthStateLabel: "open"}).Write(&gauge)
+	assert.Equal(t, float64(2), gauge.GetGauge().GetValue(), "Set adapter health vectors gauge")
+}
+
+func TestRecordAnalyticsQueue(t *testing.T) {
+	m := createMetricsForTesting()
+	m.RecordAnalyticsQueueDepth("pubstack", 3)
+	m.RecordAnalyticsQueueDepth("pubstack", 2)
+	m.RecordAnalyticsObjectDropped("pubstack")
+
+	gauge := dto.Metric{}
+	m.analyticsQueueDepth.With(prometheus.Labels{analyticsModuleLabel: "pubstack"}).Write(&gauge)
+	assert.Equal(t, float64(2), gauge.GetGauge().GetValue(), "Set analytics queue depth gauge")
+
+	assertCounterVecValue(t, "", "analytics objects dropped", m.analyticsObjectsDropped,
+		float64(1),
+		prometheus.Labels{
+			analyticsModuleLabel: "pubstack",
+		})
+}
+
 func TestStoredResponsesMetric(t *testing.T) {
 	testCases := []struct {
 		description                           string
@@ -2,8 +2,10 @@ package modules
 
 import (
 	fiftyonedegreesDevicedetection "github.com/prebid/prebid-server/v3/modules/fiftyonedegrees/devicedetection"
+	prebidGeoip "github.com/prebid/prebid-server/v3/modules/prebid/geoip"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v3/modules/prebid/ortb2blocking"
 	prebidRulesengine "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
+	prebidTrafficshaping "github.com/prebid/prebid-server/v3/modules/prebid/trafficshaping"
 )
 
 // builders returns mapping between module name and its builder
@@ -14,8 +16,10 @@ func builders() ModuleBuilders {
 			"devicedetection": fiftyonedegreesDevicedetection.Builder,
 		},
 		"prebid": {
-			"ortb2blocking": prebidOrtb2blocking.Builder,
-			"rulesengine":   prebidRulesengine.Builder,
+			"geoip":          prebidGeoip.Builder,
+			"ortb2blocking":  prebidOrtb2blocking.Builder,
+			"rulesengine":    prebidRulesengine.Builder,
+			"trafficshaping": prebidTrafficshaping.Builder,
 		},
 	}
 }
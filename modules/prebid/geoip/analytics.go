@@ -0,0 +1,53 @@
+package geoip
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+// geoip module has only 1 activity: `geoip_lookup`, its result tells whether device.geo was updated
+const lookupActivity = "geoip_lookup"
+
+const (
+	reasonNoIP            = "no_ip"
+	reasonNotFound        = "not_found"
+	reasonGeoPresent      = "geo_present"
+	reasonCountryMismatch = "country_mismatch"
+)
+
+func newLookupTags(status hookanalytics.ActivityStatus, result hookanalytics.Result) hookanalytics.Analytics {
+	result.AppliedTo = hookanalytics.AppliedTo{Request: true}
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:    lookupActivity,
+				Status:  status,
+				Results: []hookanalytics.Result{result},
+			},
+		},
+	}
+}
+
+func newSkippedTags(reason string) hookanalytics.Analytics {
+	return newLookupTags(hookanalytics.ActivityStatusSuccess, hookanalytics.Result{
+		Status: hookanalytics.ResultStatusAllow,
+		Values: map[string]interface{}{"reason": reason},
+	})
+}
+
+func newModifiedTags(ipSource string, fields []string, preciseGeoAllowed bool) hookanalytics.Analytics {
+	return newLookupTags(hookanalytics.ActivityStatusSuccess, hookanalytics.Result{
+		Status: hookanalytics.ResultStatusModify,
+		Values: map[string]interface{}{
+			"source":      ipSource,
+			"fields":      fields,
+			"precise_geo": preciseGeoAllowed,
+		},
+	})
+}
+
+func newErrorTags(err error) hookanalytics.Analytics {
+	return newLookupTags(hookanalytics.ActivityStatusError, hookanalytics.Result{
+		Status: hookanalytics.ResultStatusError,
+		Values: map[string]interface{}{"error": err.Error()},
+	})
+}
@@ -0,0 +1,42 @@
+package geoip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const defaultReloadIntervalSec = 60
+
+type config struct {
+	// DatabasePath is the path to the MaxMind DB format database file, for example GeoIP2-City.mmdb
+	DatabasePath string `json:"database_path"`
+	// ReloadIntervalSec is how often the database file is checked for changes, a negative value disables the reload
+	ReloadIntervalSec int `json:"reload_interval_sec"`
+}
+
+func (c config) reloadInterval() time.Duration {
+	if c.ReloadIntervalSec < 0 {
+		return 0
+	}
+	if c.ReloadIntervalSec == 0 {
+		return defaultReloadIntervalSec * time.Second
+	}
+	return time.Duration(c.ReloadIntervalSec) * time.Second
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if cfg.DatabasePath == "" {
+		return cfg, errors.New("database_path is required")
+	}
+
+	return cfg, nil
+}
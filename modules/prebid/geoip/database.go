@@ -0,0 +1,110 @@
+package geoip
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// fileVersion identifies a version of the database file, a file failing to load
+// is not retried until it changes again.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// databaseLoader keeps the latest version of the database file in memory.
+// The file is polled for changes and reloaded when its modification time or size changes,
+// so it can be replaced on disk without restarting the server. A file failing to load
+// is logged and the previous version of the database is kept in use.
+type databaseLoader struct {
+	path    string
+	current atomic.Pointer[mmdbReader]
+	// version is only accessed by the goroutine watching the file
+	version  fileVersion
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newDatabaseLoader(path string, reloadInterval time.Duration) (*databaseLoader, error) {
+	l := &databaseLoader{
+		path: path,
+		done: make(chan struct{}),
+	}
+
+	if _, err := l.reload(); err != nil {
+		return nil, err
+	}
+
+	if reloadInterval > 0 {
+		l.wg.Add(1)
+		go l.watch(reloadInterval)
+	}
+
+	return l, nil
+}
+
+// get returns the reader of the most recently loaded database file.
+func (l *databaseLoader) get() *mmdbReader {
+	return l.current.Load()
+}
+
+func (l *databaseLoader) watch(reloadInterval time.Duration) {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := l.reload()
+			if err != nil {
+				glog.Errorf("[geoip] Failed to reload database %s: %v", l.path, err)
+			} else if reloaded {
+				glog.Infof("[geoip] Reloaded database %s", l.path)
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// reload loads the database file if it changed since the last attempt to load it.
+func (l *databaseLoader) reload() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat database file: %w", err)
+	}
+
+	version := fileVersion{modTime: info.ModTime(), size: info.Size()}
+	if version.modTime.Equal(l.version.modTime) && version.size == l.version.size {
+		return false, nil
+	}
+	l.version = version
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read database file: %w", err)
+	}
+
+	reader, err := newMMDBReader(data)
+	if err != nil {
+		return false, err
+	}
+
+	l.current.Store(reader)
+	return true, nil
+}
+
+func (l *databaseLoader) stop() {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		l.wg.Wait()
+	})
+}
@@ -0,0 +1,71 @@
+package geoip
+
+import (
+	"strconv"
+	"time"
+)
+
+// geoInfo holds the fields of the OpenRTB geo object resolved from an ip address.
+type geoInfo struct {
+	country   string
+	region    string
+	metro     string
+	zip       string
+	utcOffset *int64
+}
+
+// newGeoInfo extracts the geo fields from a record of a GeoIP2/GeoLite2 City or Country database.
+// Fields not present in the record are left empty.
+func newGeoInfo(record map[string]any, now time.Time) geoInfo {
+	var info geoInfo
+
+	if isoCode, ok := getString(record, "country", "iso_code"); ok {
+		info.country = countryAlpha3[isoCode]
+	}
+
+	if subdivisions, ok := record["subdivisions"].([]any); ok && len(subdivisions) > 0 {
+		if subdivision, ok := subdivisions[0].(map[string]any); ok {
+			info.region, _ = getString(subdivision, "iso_code")
+		}
+	}
+
+	if location, ok := record["location"].(map[string]any); ok {
+		if metroCode, ok := location["metro_code"].(uint64); ok && metroCode > 0 {
+			info.metro = strconv.FormatUint(metroCode, 10)
+		}
+		if timeZone, ok := location["time_zone"].(string); ok && timeZone != "" {
+			if loc, err := time.LoadLocation(timeZone); err == nil {
+				_, offsetSec := now.In(loc).Zone()
+				offsetMin := int64(offsetSec / 60)
+				info.utcOffset = &offsetMin
+			}
+		}
+	}
+
+	info.zip, _ = getString(record, "postal", "code")
+
+	return info
+}
+
+// scrubPrecise removes the fields precise enough to locate the user, it leaves country, region and utc offset.
+func (g geoInfo) scrubPrecise() geoInfo {
+	g.metro = ""
+	g.zip = ""
+	return g
+}
+
+func getString(record map[string]any, path ...string) (string, bool) {
+	var value any = record
+	for _, key := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+
+	s, ok := value.(string)
+	return s, ok && s != ""
+}
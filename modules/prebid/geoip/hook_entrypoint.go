@@ -0,0 +1,31 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/util/httputil"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+)
+
+const clientIPKey = "client_ip"
+
+// publicIPValidator accepts ip addresses that can be located, skipping private, loopback and link local addresses.
+type publicIPValidator struct{}
+
+func (publicIPValidator) IsValid(ip net.IP, ver iputil.IPVersion) bool {
+	return ver != iputil.IPvUnknown && ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+func handleEntrypointHook(payload hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	var result hookstage.HookResult[hookstage.EntrypointPayload]
+	if payload.Request == nil {
+		return result, nil
+	}
+
+	if ip, _ := httputil.FindIP(payload.Request, publicIPValidator{}); ip != nil {
+		result.ModuleContext = hookstage.ModuleContext{clientIPKey: ip.String()}
+	}
+
+	return result, nil
+}
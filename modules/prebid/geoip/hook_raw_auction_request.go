@@ -0,0 +1,166 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	ipSourceDevice   = "device.ip"
+	ipSourceDeviceV6 = "device.ipv6"
+	ipSourceClient   = "client"
+)
+
+// geoField is a field of device.geo, set is the raw JSON value the field is populated with.
+type geoField struct {
+	name string
+	set  []byte
+}
+
+func handleRawAuctionHook(
+	reader *mmdbReader,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawAuctionRequestPayload,
+	now time.Time,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	var result hookstage.HookResult[hookstage.RawAuctionRequestPayload]
+
+	ip, ipSource := findIP(payload, miCtx.ModuleContext)
+	if ip == nil {
+		result.AnalyticsTags = newSkippedTags(reasonNoIP)
+		return result, nil
+	}
+
+	record, err := reader.lookup(ip)
+	if errors.Is(err, errMMDBNotFound) {
+		result.AnalyticsTags = newSkippedTags(reasonNotFound)
+		return result, nil
+	} else if err != nil {
+		result.AnalyticsTags = newErrorTags(err)
+		return result, err
+	}
+
+	info := newGeoInfo(record, now)
+
+	// the module only populates fields, location provided by the request always takes priority
+	if country, _ := jsonparser.GetString(payload, "device", "geo", "country"); country != "" && info.country != "" && country != info.country {
+		result.AnalyticsTags = newSkippedTags(reasonCountryMismatch)
+		return result, nil
+	}
+
+	// the request is read for the GPP sections the activity rules are conditioned on
+	var bidRequest openrtb2.BidRequest
+	if err := jsonutil.Unmarshal(payload, &bidRequest); err != nil {
+		result.AnalyticsTags = newErrorTags(err)
+		return result, err
+	}
+	activityRequest := privacy.NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &bidRequest})
+
+	component := privacy.Component{Type: privacy.ComponentTypeGeneral, Name: miCtx.HookImplCode}
+	preciseGeoAllowed := miCtx.ActivityControl.Allow(privacy.ActivityTransmitPreciseGeo, component, activityRequest)
+	if !preciseGeoAllowed {
+		info = info.scrubPrecise()
+	}
+
+	fields, err := missingGeoFields(payload, info)
+	if err != nil {
+		result.AnalyticsTags = newErrorTags(err)
+		return result, err
+	}
+	if len(fields) == 0 {
+		result.AnalyticsTags = newSkippedTags(reasonGeoPresent)
+		return result, nil
+	}
+
+	fieldNames := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldNames = append(fieldNames, field.name)
+	}
+
+	result.ChangeSet.AddMutation(
+		func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+			var err error
+			for _, field := range fields {
+				if payload, err = jsonparser.Set(payload, field.set, "device", "geo", field.name); err != nil {
+					return payload, err
+				}
+			}
+			return payload, nil
+		}, hookstage.MutationUpdate, "device", "geo",
+	)
+	result.AnalyticsTags = newModifiedTags(ipSource, fieldNames, preciseGeoAllowed)
+
+	return result, nil
+}
+
+// findIP returns the ip address of the device, falling back to the ip address of the client
+// that sent the request as the device ip is not set implicitly before this stage.
+func findIP(payload []byte, moduleCtx hookstage.ModuleContext) (net.IP, string) {
+	if ip, _ := jsonparser.GetString(payload, "device", "ip"); ip != "" {
+		if parsed := net.ParseIP(ip); parsed != nil {
+			return parsed, ipSourceDevice
+		}
+	}
+
+	if ip, _ := jsonparser.GetString(payload, "device", "ipv6"); ip != "" {
+		if parsed := net.ParseIP(ip); parsed != nil {
+			return parsed, ipSourceDeviceV6
+		}
+	}
+
+	if ip, ok := moduleCtx[clientIPKey].(string); ok {
+		if parsed := net.ParseIP(ip); parsed != nil {
+			return parsed, ipSourceClient
+		}
+	}
+
+	return nil, ""
+}
+
+// missingGeoFields returns the fields resolved from the ip address that are missing in device.geo.
+func missingGeoFields(payload []byte, info geoInfo) ([]geoField, error) {
+	var fields []geoField
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{name: "country", value: info.country},
+		{name: "region", value: info.region},
+		{name: "metro", value: info.metro},
+		{name: "zip", value: info.zip},
+	} {
+		if field.value == "" || hasGeoField(payload, field.name) {
+			continue
+		}
+
+		value, err := jsonutil.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, geoField{name: field.name, set: value})
+	}
+
+	if info.utcOffset != nil && !hasGeoField(payload, "utcoffset") {
+		fields = append(fields, geoField{name: "utcoffset", set: []byte(strconv.FormatInt(*info.utcOffset, 10))})
+	}
+
+	return fields, nil
+}
+
+func hasGeoField(payload []byte, name string) bool {
+	value, dataType, _, err := jsonparser.Get(payload, "device", "geo", name)
+	if err != nil || dataType == jsonparser.Null {
+		return false
+	}
+	return dataType != jsonparser.String || len(value) > 0
+}
@@ -0,0 +1,412 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// mmdbMetadataMarker precedes the metadata section at the end of a MaxMind DB file.
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// mmdbMetadataMaxSize is the maximum size of the metadata section allowed by the format.
+const mmdbMetadataMaxSize = 128 * 1024
+
+// mmdbDataSeparatorSize is the size of the zero filled gap between the search tree and the data section.
+const mmdbDataSeparatorSize = 16
+
+const (
+	mmdbExtended  = 0
+	mmdbPointer   = 1
+	mmdbString    = 2
+	mmdbDouble    = 3
+	mmdbBytes     = 4
+	mmdbUint16    = 5
+	mmdbUint32    = 6
+	mmdbMap       = 7
+	mmdbInt32     = 8
+	mmdbUint64    = 9
+	mmdbUint128   = 10
+	mmdbArray     = 11
+	mmdbContainer = 12
+	mmdbEndMarker = 13
+	mmdbBool      = 14
+	mmdbFloat     = 15
+)
+
+// mmdbMaxDepth guards the decoder against corrupted files with cyclic pointers or deeply nested values.
+const mmdbMaxDepth = 32
+
+var errMMDBNotFound = errors.New("address not found")
+
+// mmdbReader looks up IP addresses in a database using the MaxMind DB file format,
+// see https://maxmind.github.io/MaxMind-DB/.
+type mmdbReader struct {
+	buffer       []byte
+	nodeCount    uint
+	recordSize   uint
+	nodeSize     uint
+	ipVersion    uint
+	databaseType string
+	dataSection  []byte
+	ipv4Start    uint
+}
+
+func newMMDBReader(buffer []byte) (*mmdbReader, error) {
+	searchFrom := 0
+	if len(buffer) > mmdbMetadataMaxSize {
+		searchFrom = len(buffer) - mmdbMetadataMaxSize
+	}
+	markerIndex := bytes.LastIndex(buffer[searchFrom:], mmdbMetadataMarker)
+	if markerIndex < 0 {
+		return nil, errors.New("invalid MaxMind DB file: metadata section not found")
+	}
+	metadataStart := searchFrom + markerIndex + len(mmdbMetadataMarker)
+
+	metadataDecoder := mmdbDecoder{buffer: buffer[metadataStart:]}
+	value, _, err := metadataDecoder.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+	metadata, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	r := &mmdbReader{buffer: buffer}
+	if r.nodeCount, ok = metadataUint(metadata, "node_count"); !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: node_count is missing")
+	}
+	if r.recordSize, ok = metadataUint(metadata, "record_size"); !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: record_size is missing")
+	}
+	if r.ipVersion, ok = metadataUint(metadata, "ip_version"); !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: ip_version is missing")
+	}
+	if major, _ := metadataUint(metadata, "binary_format_major_version"); major != 2 {
+		return nil, fmt.Errorf("unsupported MaxMind DB format version %d", major)
+	}
+	r.databaseType, _ = metadata["database_type"].(string)
+
+	switch r.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB ip version %d", r.ipVersion)
+	}
+
+	r.nodeSize = r.recordSize / 4
+	searchTreeSize := r.nodeCount * r.nodeSize
+	dataStart := searchTreeSize + mmdbDataSeparatorSize
+	if dataStart > uint(metadataStart-len(mmdbMetadataMarker)) {
+		return nil, errors.New("invalid MaxMind DB file: search tree exceeds file size")
+	}
+	r.dataSection = buffer[dataStart : metadataStart-len(mmdbMetadataMarker)]
+
+	// IPv4 addresses are stored in IPv6 databases under ::/96, so the lookups start at the node reached after 96 zero bits
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node = r.readRecord(node, 0)
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+// lookup returns the data record of the network containing the ip.
+// errMMDBNotFound is returned if the database has no data for the ip.
+func (r *mmdbReader) lookup(ip net.IP) (map[string]any, error) {
+	node, address, err := r.startNode(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	bitCount := uint(len(address) * 8)
+	for i := uint(0); i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(address[i>>3]>>(7-(i&7))) & 1
+		node = r.readRecord(node, bit)
+	}
+
+	if node == r.nodeCount {
+		return nil, errMMDBNotFound
+	}
+	if node < r.nodeCount {
+		return nil, errors.New("invalid MaxMind DB file: search tree is deeper than the address")
+	}
+
+	offset := node - r.nodeCount - mmdbDataSeparatorSize
+	if offset >= uint(len(r.dataSection)) {
+		return nil, errors.New("invalid MaxMind DB file: record points outside of the data section")
+	}
+
+	decoder := mmdbDecoder{buffer: r.dataSection}
+	value, _, err := decoder.decode(offset, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB file: record is not a map")
+	}
+	return record, nil
+}
+
+func (r *mmdbReader) startNode(ip net.IP) (uint, net.IP, error) {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		if r.ipVersion == 6 {
+			return r.ipv4Start, ipv4, nil
+		}
+		return 0, ipv4, nil
+	}
+
+	if ipv6 := ip.To16(); ipv6 != nil {
+		if r.ipVersion == 4 {
+			return 0, nil, errors.New("IPv6 address lookup in an IPv4 only database")
+		}
+		return 0, ipv6, nil
+	}
+
+	return 0, nil, fmt.Errorf("invalid ip address %q", ip)
+}
+
+func (r *mmdbReader) readRecord(node, bit uint) uint {
+	b := r.buffer[node*r.nodeSize:]
+
+	switch r.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5])
+	case 28:
+		if bit == 0 {
+			return (uint(b[3])&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return (uint(b[3])&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4]))
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8]))
+	}
+}
+
+func metadataUint(metadata map[string]any, key string) (uint, bool) {
+	switch v := metadata[key].(type) {
+	case uint64:
+		return uint(v), true
+	default:
+		return 0, false
+	}
+}
+
+// mmdbDecoder decodes values of the MaxMind DB data section format.
+// Integers are decoded as uint64 or int64, floating point numbers as float64,
+// maps as map[string]any and arrays as []any.
+type mmdbDecoder struct {
+	buffer []byte
+}
+
+// decode returns the value at offset and the offset following it.
+func (d *mmdbDecoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > mmdbMaxDepth {
+		return nil, 0, errors.New("invalid MaxMind DB data: maximum depth exceeded")
+	}
+
+	typeNum, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typeNum == mmdbPointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	return d.decodeValue(typeNum, size, offset, depth)
+}
+
+func (d *mmdbDecoder) decodeControl(offset uint) (int, uint, uint, error) {
+	ctrl, offset, err := d.readByte(offset)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	typeNum := int(ctrl >> 5)
+	if typeNum == mmdbExtended {
+		var extended byte
+		if extended, offset, err = d.readByte(offset); err != nil {
+			return 0, 0, 0, err
+		}
+		typeNum = int(extended) + 7
+	}
+
+	size := uint(ctrl & 0x1F)
+	if typeNum == mmdbPointer || size < 29 {
+		return typeNum, size, offset, nil
+	}
+
+	extraBytes := size - 28
+	sizeBytes, offset, err := d.readBytes(offset, extraBytes)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	extra := uintFromBytes(sizeBytes)
+	switch size {
+	case 29:
+		size = 29 + extra
+	case 30:
+		size = 285 + extra
+	default:
+		size = 65821 + extra
+	}
+
+	return typeNum, size, offset, nil
+}
+
+// decodePointer decodes a pointer, size holds the 5 low bits of the control byte.
+func (d *mmdbDecoder) decodePointer(size, offset uint) (uint, uint, error) {
+	pointerSize := ((size >> 3) & 0x3) + 1
+	pointerBytes, next, err := d.readBytes(offset, pointerSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var prefix uint
+	if pointerSize != 4 {
+		prefix = size & 0x7
+	}
+	pointer := prefix<<(8*pointerSize) | uintFromBytes(pointerBytes)
+
+	switch pointerSize {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+
+	return pointer, next, nil
+}
+
+func (d *mmdbDecoder) decodeValue(typeNum int, size, offset uint, depth int) (any, uint, error) {
+	switch typeNum {
+	case mmdbMap:
+		return d.decodeMap(size, offset, depth)
+	case mmdbArray:
+		return d.decodeArray(size, offset, depth)
+	case mmdbBool:
+		if size > 1 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: boolean of size %d", size)
+		}
+		return size == 1, offset, nil
+	}
+
+	value, next, err := d.readBytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch typeNum {
+	case mmdbString:
+		return string(value), next, nil
+	case mmdbBytes:
+		return bytes.Clone(value), next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: double of size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(value)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: float of size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(value))), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: unsigned integer of size %d", size)
+		}
+		return uint64(uintFromBytes(value)), next, nil
+	case mmdbInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: int32 of size %d", size)
+		}
+		return int64(int32(uintFromBytes(value))), next, nil
+	case mmdbUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("invalid MaxMind DB data: uint128 of size %d", size)
+		}
+		return bytes.Clone(value), next, nil
+	default:
+		return nil, 0, fmt.Errorf("invalid MaxMind DB data: unexpected type %d", typeNum)
+	}
+}
+
+func (d *mmdbDecoder) decodeMap(size, offset uint, depth int) (any, uint, error) {
+	values := make(map[string]any, size)
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decode(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("invalid MaxMind DB data: map key is not a string")
+		}
+
+		value, next, err := d.decode(next, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		values[keyString] = value
+		offset = next
+	}
+	return values, offset, nil
+}
+
+func (d *mmdbDecoder) decodeArray(size, offset uint, depth int) (any, uint, error) {
+	values := make([]any, 0, size)
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decode(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		values = append(values, value)
+		offset = next
+	}
+	return values, offset, nil
+}
+
+func (d *mmdbDecoder) readByte(offset uint) (byte, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, errors.New("invalid MaxMind DB data: unexpected end of data")
+	}
+	return d.buffer[offset], offset + 1, nil
+}
+
+func (d *mmdbDecoder) readBytes(offset, size uint) ([]byte, uint, error) {
+	end := offset + size
+	if end < offset || end > uint(len(d.buffer)) {
+		return nil, 0, errors.New("invalid MaxMind DB data: unexpected end of data")
+	}
+	return d.buffer[offset:end], end, nil
+}
+
+func uintFromBytes(b []byte) uint {
+	var v uint
+	for _, c := range b {
+		v = v<<8 | uint(c)
+	}
+	return v
+}
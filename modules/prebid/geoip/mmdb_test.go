@@ -0,0 +1,356 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPointer is encoded as a pointer to the data section offset of a previously written value.
+type testPointer uint
+
+// testMMDB builds MaxMind DB files for tests, it maps networks in CIDR notation to their records.
+type testMMDB struct {
+	ipVersion  int
+	recordSize int
+	networks   map[string]any
+}
+
+func (db testMMDB) build(t *testing.T) []byte {
+	t.Helper()
+
+	type node struct{ records [2]int }
+	const (
+		emptyRecord = -1
+		dataRecord  = -2
+	)
+
+	// records are node indexes, or emptyRecord, or dataRecord - offset for data pointers
+	nodes := []node{{records: [2]int{emptyRecord, emptyRecord}}}
+	var data []byte
+
+	cidrs := make([]string, 0, len(db.networks))
+	for cidr := range db.networks {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+
+		address := []byte(network.IP)
+		ones, _ := network.Mask.Size()
+		if db.ipVersion == 6 && len(address) == net.IPv4len {
+			address = append(make([]byte, 12), address...)
+			ones += 96
+		}
+
+		offset := len(data)
+		data = append(data, encodeTestValue(t, db.networks[cidr])...)
+
+		current := 0
+		for i := 0; i < ones; i++ {
+			bit := (address[i>>3] >> (7 - (i & 7))) & 1
+			if i == ones-1 {
+				nodes[current].records[bit] = dataRecord - offset
+				break
+			}
+			next := nodes[current].records[bit]
+			if next < 0 {
+				nodes = append(nodes, node{records: [2]int{emptyRecord, emptyRecord}})
+				next = len(nodes) - 1
+				nodes[current].records[bit] = next
+			}
+			current = next
+		}
+	}
+
+	nodeCount := len(nodes)
+	nodeSize := db.recordSize / 4
+	tree := make([]byte, nodeCount*nodeSize)
+	for i, n := range nodes {
+		var values [2]uint32
+		for bit, record := range n.records {
+			switch {
+			case record == emptyRecord:
+				values[bit] = uint32(nodeCount)
+			case record <= dataRecord:
+				values[bit] = uint32(nodeCount + mmdbDataSeparatorSize + dataRecord - record)
+			default:
+				values[bit] = uint32(record)
+			}
+		}
+
+		b := tree[i*nodeSize:]
+		switch db.recordSize {
+		case 24:
+			b[0], b[1], b[2] = byte(values[0]>>16), byte(values[0]>>8), byte(values[0])
+			b[3], b[4], b[5] = byte(values[1]>>16), byte(values[1]>>8), byte(values[1])
+		case 28:
+			b[0], b[1], b[2] = byte(values[0]>>16), byte(values[0]>>8), byte(values[0])
+			b[3] = byte((values[0]>>24)<<4) | byte(values[1]>>24&0x0F)
+			b[4], b[5], b[6] = byte(values[1]>>16), byte(values[1]>>8), byte(values[1])
+		default:
+			binary.BigEndian.PutUint32(b[0:4], values[0])
+			binary.BigEndian.PutUint32(b[4:8], values[1])
+		}
+	}
+
+	metadata := encodeTestValue(t, map[string]any{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(db.recordSize),
+		"ip_version":                  uint16(db.ipVersion),
+		"database_type":               "Test-City",
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+	})
+
+	file := append(tree, make([]byte, mmdbDataSeparatorSize)...)
+	file = append(file, data...)
+	file = append(file, mmdbMetadataMarker...)
+	return append(file, metadata...)
+}
+
+func encodeTestValue(t *testing.T, value any) []byte {
+	t.Helper()
+
+	switch v := value.(type) {
+	case string:
+		return append(encodeTestControl(mmdbString, len(v)), v...)
+	case float64:
+		b := binary.BigEndian.AppendUint64(nil, math.Float64bits(v))
+		return append(encodeTestControl(mmdbDouble, 8), b...)
+	case uint16:
+		b := trimLeadingZeros(binary.BigEndian.AppendUint16(nil, v))
+		return append(encodeTestControl(mmdbUint16, len(b)), b...)
+	case uint32:
+		b := trimLeadingZeros(binary.BigEndian.AppendUint32(nil, v))
+		return append(encodeTestControl(mmdbUint32, len(b)), b...)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		return encodeTestControl(mmdbBool, size)
+	case testPointer:
+		// 2 bytes pointers cover offsets from 2048 to 526335, smaller offsets use the 1 byte form
+		if v < 2048 {
+			return []byte{mmdbPointer<<5 | byte(v>>8), byte(v)}
+		}
+		p := uint(v) - 2048
+		return []byte{mmdbPointer<<5 | 1<<3 | byte(p>>16), byte(p >> 8), byte(p)}
+	case []any:
+		encoded := encodeTestControl(mmdbArray, len(v))
+		for _, item := range v {
+			encoded = append(encoded, encodeTestValue(t, item)...)
+		}
+		return encoded
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		encoded := encodeTestControl(mmdbMap, len(v))
+		for _, key := range keys {
+			encoded = append(encoded, encodeTestValue(t, key)...)
+			encoded = append(encoded, encodeTestValue(t, v[key])...)
+		}
+		return encoded
+	default:
+		require.Failf(t, "unsupported test value", "%T", value)
+		return nil
+	}
+}
+
+func encodeTestControl(typeNum int, size int) []byte {
+	// the extended type byte follows the control byte, before the size bytes
+	var ctrl []byte
+	if typeNum > 7 {
+		ctrl = []byte{0, byte(typeNum - 7)}
+	} else {
+		ctrl = []byte{byte(typeNum << 5)}
+	}
+
+	switch {
+	case size < 29:
+		ctrl[0] |= byte(size)
+	case size < 285:
+		ctrl[0] |= 29
+		ctrl = append(ctrl, byte(size-29))
+	default:
+		ctrl[0] |= 30
+		ctrl = append(ctrl, byte((size-285)>>8), byte(size-285))
+	}
+
+	return ctrl
+}
+
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func testCityRecord(country, region string) map[string]any {
+	return map[string]any{
+		"country":      map[string]any{"iso_code": country},
+		"subdivisions": []any{map[string]any{"iso_code": region}},
+		"location": map[string]any{
+			"metro_code": uint16(501),
+			"time_zone":  "America/New_York",
+			"latitude":   40.7,
+		},
+		"postal": map[string]any{"code": "10001"},
+	}
+}
+
+func TestMMDBReaderLookup(t *testing.T) {
+	networks := map[string]any{
+		"1.2.3.0/24":     testCityRecord("US", "NY"),
+		"81.2.69.0/23":   map[string]any{"country": map[string]any{"iso_code": "GB"}},
+		"2001:db8::/32":  map[string]any{"country": map[string]any{"iso_code": "DE"}, "in_eu": true},
+		"2001:db9::/120": map[string]any{"country": map[string]any{"iso_code": "FR"}, "long": string(make([]byte, 300))},
+	}
+
+	testCases := []struct {
+		description     string
+		ipVersion       int
+		recordSize      int
+		ip              string
+		expectedCountry string
+		expectedError   error
+	}{
+		{description: "ipv4_in_ipv6_tree_24", ipVersion: 6, recordSize: 24, ip: "1.2.3.4", expectedCountry: "US"},
+		{description: "ipv4_in_ipv6_tree_28", ipVersion: 6, recordSize: 28, ip: "81.2.68.10", expectedCountry: "GB"},
+		{description: "ipv4_in_ipv6_tree_32", ipVersion: 6, recordSize: 32, ip: "1.2.3.255", expectedCountry: "US"},
+		{description: "ipv6_24", ipVersion: 6, recordSize: 24, ip: "2001:db8::1", expectedCountry: "DE"},
+		{description: "ipv6_28", ipVersion: 6, recordSize: 28, ip: "2001:db9::ff", expectedCountry: "FR"},
+		{description: "ipv6_not_found", ipVersion: 6, recordSize: 24, ip: "2001:dba::1", expectedError: errMMDBNotFound},
+		{description: "ipv4_not_found", ipVersion: 6, recordSize: 28, ip: "8.8.8.8", expectedError: errMMDBNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			file := testMMDB{ipVersion: tc.ipVersion, recordSize: tc.recordSize, networks: networks}.build(t)
+			reader, err := newMMDBReader(file)
+			require.NoError(t, err)
+			assert.Equal(t, "Test-City", reader.databaseType)
+
+			record, err := reader.lookup(net.ParseIP(tc.ip))
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			country, _ := getString(record, "country", "iso_code")
+			assert.Equal(t, tc.expectedCountry, country)
+		})
+	}
+}
+
+func TestMMDBReaderIPv4Database(t *testing.T) {
+	file := testMMDB{ipVersion: 4, recordSize: 24, networks: map[string]any{
+		"10.0.0.0/8": map[string]any{"country": map[string]any{"iso_code": "CA"}},
+	}}.build(t)
+
+	reader, err := newMMDBReader(file)
+	require.NoError(t, err)
+
+	record, err := reader.lookup(net.ParseIP("10.1.2.3"))
+	require.NoError(t, err)
+	country, _ := getString(record, "country", "iso_code")
+	assert.Equal(t, "CA", country)
+
+	_, err = reader.lookup(net.ParseIP("2001:db8::1"))
+	assert.EqualError(t, err, "IPv6 address lookup in an IPv4 only database")
+}
+
+func TestMMDBDecoder(t *testing.T) {
+	shared := encodeTestValue(t, "shared value")
+	record := encodeTestValue(t, map[string]any{
+		"pointer": testPointer(0),
+		"double":  1.5,
+		"uint32":  uint32(70000),
+		"false":   false,
+		"array":   []any{uint16(1), "two"},
+	})
+	buffer := append(shared, record...)
+
+	decoder := mmdbDecoder{buffer: buffer}
+	value, next, err := decoder.decode(uint(len(shared)), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(buffer)), next)
+	assert.Equal(t, map[string]any{
+		"pointer": "shared value",
+		"double":  1.5,
+		"uint32":  uint64(70000),
+		"false":   false,
+		"array":   []any{uint64(1), "two"},
+	}, value)
+}
+
+func TestMMDBDecoderLargePointer(t *testing.T) {
+	padding := encodeTestValue(t, string(make([]byte, 3000)))
+	buffer := append(padding, encodeTestValue(t, "far")...)
+	buffer = append(buffer, encodeTestValue(t, testPointer(len(padding)))...)
+
+	decoder := mmdbDecoder{buffer: buffer}
+	value, _, err := decoder.decode(uint(len(buffer)-3), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "far", value)
+}
+
+func TestMMDBDecoderInvalidData(t *testing.T) {
+	testCases := []struct {
+		description   string
+		buffer        []byte
+		expectedError string
+	}{
+		{
+			description:   "truncated_string",
+			buffer:        []byte{mmdbString<<5 | 5, 'a'},
+			expectedError: "invalid MaxMind DB data: unexpected end of data",
+		},
+		{
+			description:   "cyclic_pointer",
+			buffer:        []byte{mmdbPointer << 5, 0},
+			expectedError: "invalid MaxMind DB data: maximum depth exceeded",
+		},
+		{
+			description:   "non_string_map_key",
+			buffer:        []byte{mmdbMap<<5 | 1, mmdbUint16<<5 | 1, 1, mmdbUint16<<5 | 1, 1},
+			expectedError: "invalid MaxMind DB data: map key is not a string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			decoder := mmdbDecoder{buffer: tc.buffer}
+			_, _, err := decoder.decode(0, 0)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestNewMMDBReaderInvalidFile(t *testing.T) {
+	_, err := newMMDBReader([]byte("not a database"))
+	assert.EqualError(t, err, "invalid MaxMind DB file: metadata section not found")
+
+	metadata := encodeTestValue(t, map[string]any{
+		"node_count":                  uint32(1),
+		"record_size":                 uint16(20),
+		"ip_version":                  uint16(6),
+		"binary_format_major_version": uint16(2),
+	})
+	_, err = newMMDBReader(append(append(make([]byte, 32), mmdbMetadataMarker...), metadata...))
+	assert.EqualError(t, err, "unsupported MaxMind DB record size 20")
+}
@@ -0,0 +1,62 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// Builder loads the database file configured for the module
+// and starts watching it for changes.
+func Builder(rawConfig json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := newDatabaseLoader(cfg.DatabasePath, cfg.reloadInterval())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database: %w", err)
+	}
+
+	return Module{
+		db:   db,
+		time: &timeutil.RealTime{},
+	}, nil
+}
+
+// Module resolves the ip address of the device to its geo location.
+type Module struct {
+	db   *databaseLoader
+	time timeutil.Time
+}
+
+// HandleEntrypointHook saves the ip address of the client, it is used for the lookup
+// if the request doesn't provide the ip address of the device.
+func (m Module) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return handleEntrypointHook(payload)
+}
+
+// HandleRawAuctionHook populates the missing fields of device.geo with the location of the device ip address.
+// Precise location fields are only populated if the transmitPreciseGeo activity is allowed for the module.
+func (m Module) HandleRawAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	return handleRawAuctionHook(m.db.get(), miCtx, payload, m.time.Now())
+}
+
+// Shutdown stops watching the database file for changes.
+func (m Module) Shutdown() error {
+	m.db.stop()
+	return nil
+}
@@ -0,0 +1,274 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pbsconfig "github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
+
+func writeTestDatabase(t *testing.T, path string, networks map[string]any) {
+	t.Helper()
+	file := testMMDB{ipVersion: 6, recordSize: 28, networks: networks}.build(t)
+	require.NoError(t, os.WriteFile(path, file, 0644))
+}
+
+func newTestModule(t *testing.T) Module {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, map[string]any{
+		"1.2.3.0/24":    testCityRecord("US", "NY"),
+		"2001:db8::/32": map[string]any{"country": map[string]any{"iso_code": "DE"}},
+	})
+
+	db, err := newDatabaseLoader(path, 0)
+	require.NoError(t, err)
+	t.Cleanup(db.stop)
+
+	return Module{db: db, time: &fakeTime{time: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)}}
+}
+
+func TestBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, map[string]any{"1.2.3.0/24": testCityRecord("US", "NY")})
+
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "valid_config",
+			config:      json.RawMessage(`{"database_path": "` + path + `", "reload_interval_sec": 1}`),
+		},
+		{
+			description:   "missing_database_path",
+			config:        json.RawMessage(`{}`),
+			expectedError: "database_path is required",
+		},
+		{
+			description:   "invalid_config",
+			config:        json.RawMessage(`{"database_path": 1}`),
+			expectedError: "failed to parse config: cannot unmarshal geoip.config.DatabasePath",
+		},
+		{
+			description:   "missing_database_file",
+			config:        json.RawMessage(`{"database_path": "` + path + `.missing"}`),
+			expectedError: "failed to load database: failed to stat database file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			module, err := Builder(tc.config, moduledeps.ModuleDeps{})
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, module.(Module).Shutdown())
+		})
+	}
+}
+
+func TestHandleEntrypointHook(t *testing.T) {
+	testCases := []struct {
+		description     string
+		headers         http.Header
+		remoteAddr      string
+		expectedContext hookstage.ModuleContext
+	}{
+		{
+			description:     "forwarded_public_ip",
+			headers:         http.Header{"X-Forwarded-For": []string{"1.2.3.4"}},
+			remoteAddr:      "10.0.0.1:1234",
+			expectedContext: hookstage.ModuleContext{clientIPKey: "1.2.3.4"},
+		},
+		{
+			description:     "remote_addr",
+			remoteAddr:      "[2001:db8::1]:1234",
+			expectedContext: hookstage.ModuleContext{clientIPKey: "2001:db8::1"},
+		},
+		{
+			description: "private_ip_skipped",
+			remoteAddr:  "192.168.1.1:1234",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+			require.NoError(t, err)
+			request.Header = tc.headers
+			request.RemoteAddr = tc.remoteAddr
+
+			result, err := newTestModule(t).HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.EntrypointPayload{Request: request})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedContext, result.ModuleContext)
+		})
+	}
+}
+
+func TestHandleRawAuctionHook(t *testing.T) {
+	denyPreciseGeo := privacy.NewActivityControl(&pbsconfig.AccountPrivacy{
+		AllowActivities: &pbsconfig.AllowActivities{
+			TransmitPreciseGeo: pbsconfig.Activity{Default: ptrutil.ToPtr(false)},
+		},
+	})
+
+	usNat := privacy.NewActivityControl(&pbsconfig.AccountPrivacy{USNat: pbsconfig.AccountUSNat{Enabled: true}})
+
+	testCases := []struct {
+		description     string
+		payload         string
+		moduleContext   hookstage.ModuleContext
+		activityControl privacy.ActivityControl
+		expectedPayload string
+		expectedTags    hookanalytics.Analytics
+	}{
+		{
+			description:     "geo_populated_from_device_ip",
+			payload:         `{"id":"req","device":{"ip":"1.2.3.4"}}`,
+			expectedPayload: `{"id":"req","device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","metro":"501","zip":"10001","utcoffset":-300}}}`,
+			expectedTags:    newModifiedTags(ipSourceDevice, []string{"country", "region", "metro", "zip", "utcoffset"}, true),
+		},
+		{
+			description:     "geo_populated_from_device_ipv6",
+			payload:         `{"device":{"ipv6":"2001:db8::1"}}`,
+			expectedPayload: `{"device":{"ipv6":"2001:db8::1","geo":{"country":"DEU"}}}`,
+			expectedTags:    newModifiedTags(ipSourceDeviceV6, []string{"country"}, true),
+		},
+		{
+			description:     "geo_populated_from_client_ip",
+			payload:         `{"site":{}}`,
+			moduleContext:   hookstage.ModuleContext{clientIPKey: "2001:db8::1"},
+			expectedPayload: `{"site":{},"device":{"geo":{"country":"DEU"}}}`,
+			expectedTags:    newModifiedTags(ipSourceClient, []string{"country"}, true),
+		},
+		{
+			description:     "existing_fields_kept",
+			payload:         `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"CA","utcoffset":0}}}`,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"CA","utcoffset":0,"metro":"501","zip":"10001"}}}`,
+			expectedTags:    newModifiedTags(ipSourceDevice, []string{"metro", "zip"}, true),
+		},
+		{
+			description:     "precise_geo_not_populated_if_activity_denied",
+			payload:         `{"device":{"ip":"1.2.3.4"}}`,
+			activityControl: denyPreciseGeo,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","utcoffset":-300}}}`,
+			expectedTags:    newModifiedTags(ipSourceDevice, []string{"country", "region", "utcoffset"}, false),
+		},
+		{
+			description:     "precise_geo_not_populated_if_denied_by_gpp",
+			payload:         `{"device":{"ip":"1.2.3.4"},"regs":{"gpp":"DBABBgA~xlgWEYCZAA","gpp_sid":[8]}}`,
+			activityControl: usNat,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","utcoffset":-300}},"regs":{"gpp":"DBABBgA~xlgWEYCZAA","gpp_sid":[8]}}`,
+			expectedTags:    newModifiedTags(ipSourceDevice, []string{"country", "region", "utcoffset"}, false),
+		},
+		{
+			description:     "precise_geo_populated_if_allowed_by_gpp",
+			payload:         `{"device":{"ip":"1.2.3.4"},"regs":{"gpp":"DBABLA~BVQqAAAAAgA.QA","gpp_sid":[7]}}`,
+			activityControl: usNat,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","metro":"501","zip":"10001","utcoffset":-300}},"regs":{"gpp":"DBABLA~BVQqAAAAAgA.QA","gpp_sid":[7]}}`,
+			expectedTags:    newModifiedTags(ipSourceDevice, []string{"country", "region", "metro", "zip", "utcoffset"}, true),
+		},
+		{
+			description:     "all_fields_present",
+			payload:         `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","metro":"1","zip":"1","utcoffset":1}}}`,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"USA","region":"NY","metro":"1","zip":"1","utcoffset":1}}}`,
+			expectedTags:    newSkippedTags(reasonGeoPresent),
+		},
+		{
+			description:     "country_mismatch",
+			payload:         `{"device":{"ip":"1.2.3.4","geo":{"country":"CAN"}}}`,
+			expectedPayload: `{"device":{"ip":"1.2.3.4","geo":{"country":"CAN"}}}`,
+			expectedTags:    newSkippedTags(reasonCountryMismatch),
+		},
+		{
+			description:     "ip_not_found",
+			payload:         `{"device":{"ip":"8.8.8.8"}}`,
+			expectedPayload: `{"device":{"ip":"8.8.8.8"}}`,
+			expectedTags:    newSkippedTags(reasonNotFound),
+		},
+		{
+			description:     "no_ip",
+			payload:         `{"device":{"ip":"invalid"}}`,
+			expectedPayload: `{"device":{"ip":"invalid"}}`,
+			expectedTags:    newSkippedTags(reasonNoIP),
+		},
+	}
+
+	module := newTestModule(t)
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			miCtx := hookstage.ModuleInvocationContext{
+				HookImplCode:    "code",
+				ModuleContext:   tc.moduleContext,
+				ActivityControl: tc.activityControl,
+			}
+
+			result, err := module.HandleRawAuctionHook(context.Background(), miCtx, []byte(tc.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTags, result.AnalyticsTags)
+
+			payload := hookstage.RawAuctionRequestPayload(tc.payload)
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.JSONEq(t, tc.expectedPayload, string(payload))
+		})
+	}
+}
+
+func TestDatabaseLoaderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDatabase(t, path, map[string]any{"1.2.3.0/24": testCityRecord("US", "NY")})
+
+	loader, err := newDatabaseLoader(path, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer loader.stop()
+
+	lookupCountry := func() string {
+		record, err := loader.get().lookup([]byte{1, 2, 3, 4})
+		if err != nil {
+			return ""
+		}
+		country, _ := getString(record, "country", "iso_code")
+		return country
+	}
+	assert.Equal(t, "US", lookupCountry())
+
+	// an invalid file is ignored and the previous database is kept
+	require.NoError(t, os.WriteFile(path, []byte("corrupted"), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "US", lookupCountry())
+
+	writeTestDatabase(t, path, map[string]any{"1.2.3.0/24": testCityRecord("CA", "ON")})
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+	assert.Eventually(t, func() bool { return lookupCountry() == "CA" }, time.Second, 10*time.Millisecond)
+
+	loader.stop()
+	loader.stop()
+}
@@ -0,0 +1,133 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+type BidderRequestResultFunc = rules.ResultFunction[rules.BidderRequestPayload, hs.HookResult[hs.BidderRequestPayload]]
+type RawBidderResponseResultFunc = rules.ResultFunction[rules.BidPayload, hs.HookResult[hs.RawBidderResponsePayload]]
+
+const (
+	ModifyBidderRequestName = "modifyBidderRequest"
+	RejectBidName           = "rejectBid"
+)
+
+// NewBidderRequestResultFunction is a factory function that creates a new result function based on the
+// provided name and parameters for the bidder request stage.
+func NewBidderRequestResultFunction(name string, params json.RawMessage) (BidderRequestResultFunc, error) {
+	switch name {
+	case ModifyBidderRequestName:
+		return NewModifyBidderRequest(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewRawBidderResponseResultFunction is a factory function that creates a new result function based on the
+// provided name and parameters for the raw bidder response stage.
+func NewRawBidderResponseResultFunction(name string, params json.RawMessage) (RawBidderResponseResultFunc, error) {
+	switch name {
+	case RejectBidName:
+		return NewRejectBid(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewModifyBidderRequest is a factory function that creates a new ModifyBidderRequest result function.
+func NewModifyBidderRequest(params json.RawMessage) (BidderRequestResultFunc, error) {
+	var modifyParams config.ModifyBidderRequestParams
+	if err := jsonutil.Unmarshal(params, &modifyParams); err != nil {
+		return nil, err
+	}
+
+	var patch map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(modifyParams.Patch, &patch); err != nil || len(patch) == 0 {
+		return nil, errors.New("modifyBidderRequest requires a non empty patch object")
+	}
+	return &ModifyBidderRequest{Args: modifyParams}, nil
+}
+
+// ModifyBidderRequest applies a JSON merge patch to the request of a bidder, e.g. {"user": {"eids": null}}
+// removes the EIDs from the request of the bidder.
+type ModifyBidderRequest struct {
+	Args config.ModifyBidderRequestParams
+}
+
+// Call adds a mutation applying the patch to the bidder request to the ChangeSet.
+func (mbr *ModifyBidderRequest) Call(payload *rules.BidderRequestPayload, result *hs.HookResult[hs.BidderRequestPayload], meta rules.ResultFunctionMeta) error {
+	patch := mbr.Args.Patch
+	result.ChangeSet.AddMutation(func(p hs.BidderRequestPayload) (hs.BidderRequestPayload, error) {
+		if p.Request == nil || p.Request.BidRequest == nil {
+			return p, errors.New("payload contains a nil bid request")
+		}
+		if err := p.Request.RebuildRequest(); err != nil {
+			return p, err
+		}
+
+		bidRequest := *p.Request.BidRequest
+		if err := jsonutil.MergeClone(&bidRequest, patch); err != nil {
+			return p, fmt.Errorf("failed to apply the patch to the request of bidder %s: %w", p.Bidder, err)
+		}
+		// the request is replaced rather than updated as the wrapper given to the hook is shared
+		p.Request = &openrtb_ext.RequestWrapper{BidRequest: &bidRequest}
+		return p, nil
+	}, hs.MutationUpdate, "bidrequest")
+
+	addDebugMessage(result, meta, ModifyBidderRequestName, "patch %s applied to the request of bidder %s", patch, payload.Bidder)
+	return nil
+}
+
+func (mbr *ModifyBidderRequest) Name() string {
+	return ModifyBidderRequestName
+}
+
+// NewRejectBid is a factory function that creates a new RejectBid result function.
+func NewRejectBid(params json.RawMessage) (RawBidderResponseResultFunc, error) {
+	if len(params) > 0 && string(params) != "null" && string(params) != "{}" {
+		return nil, fmt.Errorf("%s expects 0 arguments", RejectBidName)
+	}
+	return &RejectBid{}, nil
+}
+
+// RejectBid removes a bid from the bidder response.
+type RejectBid struct{}
+
+// Call adds a mutation removing the bid from the bidder response to the ChangeSet.
+func (rb *RejectBid) Call(payload *rules.BidPayload, result *hs.HookResult[hs.RawBidderResponsePayload], meta rules.ResultFunctionMeta) error {
+	bid := payload.Bid
+	if bid == nil {
+		return nil
+	}
+
+	result.ChangeSet.AddMutation(func(p hs.RawBidderResponsePayload) (hs.RawBidderResponsePayload, error) {
+		if p.BidderResponse == nil {
+			return p, errors.New("payload contains a nil bidder response")
+		}
+		p.BidderResponse.Bids = slices.DeleteFunc(slices.Clone(p.BidderResponse.Bids), func(b *adapters.TypedBid) bool {
+			return b == bid
+		})
+		return p, nil
+	}, hs.MutationDelete, "bids")
+
+	var bidID string
+	if bid.Bid != nil {
+		bidID = bid.Bid.ID
+	}
+	addDebugMessage(result, meta, RejectBidName, "bid %s of bidder %s rejected", bidID, payload.Bidder)
+	return nil
+}
+
+func (rb *RejectBid) Name() string {
+	return RejectBidName
+}
@@ -0,0 +1,181 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBidderRequestResultFunction(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcName   string
+		params     json.RawMessage
+		expectType BidderRequestResultFunc
+		expectErr  bool
+	}{
+		{
+			name:       "valid_modifyBidderRequest",
+			funcName:   ModifyBidderRequestName,
+			params:     json.RawMessage(`{"patch":{"user":{"eids":null}}}`),
+			expectType: &ModifyBidderRequest{},
+		},
+		{
+			name:      "modifyBidderRequest_empty_patch",
+			funcName:  ModifyBidderRequestName,
+			params:    json.RawMessage(`{"patch":{}}`),
+			expectErr: true,
+		},
+		{
+			name:      "modifyBidderRequest_patch_not_an_object",
+			funcName:  ModifyBidderRequestName,
+			params:    json.RawMessage(`{"patch":[1]}`),
+			expectErr: true,
+		},
+		{
+			name:      "processed_auction_request_function",
+			funcName:  ExcludeBiddersName,
+			params:    json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewBidderRequestResultFunction(tt.funcName, tt.params)
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got nil")
+			} else {
+				assert.IsType(t, tt.expectType, v)
+			}
+		})
+	}
+}
+
+func TestNewRawBidderResponseResultFunction(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcName   string
+		params     json.RawMessage
+		expectType RawBidderResponseResultFunc
+		expectErr  bool
+	}{
+		{
+			name:       "valid_rejectBid",
+			funcName:   RejectBidName,
+			expectType: &RejectBid{},
+		},
+		{
+			name:       "valid_rejectBid_empty_args",
+			funcName:   RejectBidName,
+			params:     json.RawMessage(`{}`),
+			expectType: &RejectBid{},
+		},
+		{
+			name:      "rejectBid_with_args",
+			funcName:  RejectBidName,
+			params:    json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectErr: true,
+		},
+		{
+			name:      "bidder_request_function",
+			funcName:  ModifyBidderRequestName,
+			params:    json.RawMessage(`{"patch":{"tmax":100}}`),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewRawBidderResponseResultFunction(tt.funcName, tt.params)
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got nil")
+			} else {
+				assert.IsType(t, tt.expectType, v)
+			}
+		})
+	}
+}
+
+func TestModifyBidderRequestCall(t *testing.T) {
+	user := &openrtb2.User{ID: "user", EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}}
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", User: user, TMax: 500}}
+	mbr := &ModifyBidderRequest{Args: config.ModifyBidderRequestParams{Patch: json.RawMessage(`{"user":{"eids":null},"tmax":200}`)}}
+	result := &hs.HookResult[hs.BidderRequestPayload]{}
+
+	err := mbr.Call(&rules.BidderRequestPayload{Request: request, Bidder: "bidder1"}, result, rules.ResultFunctionMeta{RuleFired: "bidder1|true"})
+	require.NoError(t, err)
+	require.Len(t, result.ChangeSet.Mutations(), 1)
+	assert.Equal(t, []string{`modifyBidderRequest: patch {"user":{"eids":null},"tmax":200} applied to the request of bidder bidder1 (rule fired: bidder1|true)`}, result.DebugMessages)
+
+	mutation := result.ChangeSet.Mutations()[0]
+	assert.Equal(t, hs.MutationUpdate, mutation.Type())
+	assert.Equal(t, []string{"bidrequest"}, mutation.Key())
+
+	payload, err := mutation.Apply(hs.BidderRequestPayload{Request: request, Bidder: "bidder1"})
+	require.NoError(t, err)
+	assert.Equal(t, &openrtb2.BidRequest{ID: "req", User: &openrtb2.User{ID: "user"}, TMax: 200}, payload.Request.BidRequest)
+	assert.Equal(t, &openrtb2.BidRequest{ID: "req", User: user, TMax: 500}, request.BidRequest, "the request given to the hook must not be modified")
+	assert.Len(t, user.EIDs, 1, "the user shared with the other bidders must not be modified")
+}
+
+func TestModifyBidderRequestCallNilRequest(t *testing.T) {
+	mbr := &ModifyBidderRequest{Args: config.ModifyBidderRequestParams{Patch: json.RawMessage(`{"tmax":200}`)}}
+	result := &hs.HookResult[hs.BidderRequestPayload]{}
+
+	require.NoError(t, mbr.Call(&rules.BidderRequestPayload{}, result, rules.ResultFunctionMeta{}))
+	_, err := result.ChangeSet.Mutations()[0].Apply(hs.BidderRequestPayload{})
+	assert.EqualError(t, err, "payload contains a nil bid request")
+}
+
+func TestRejectBidCall(t *testing.T) {
+	bid1 := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid1"}}
+	bid2 := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid2"}}
+	bid3 := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid3"}}
+	bidderResponse := &adapters.BidderResponse{Bids: []*adapters.TypedBid{bid1, bid2, bid3}}
+	result := &hs.HookResult[hs.RawBidderResponsePayload]{}
+	rb := &RejectBid{}
+
+	for _, bid := range []*adapters.TypedBid{bid1, bid3} {
+		payload := &rules.BidPayload{BidderRequestPayload: rules.BidderRequestPayload{Bidder: "bidder1"}, Bid: bid}
+		require.NoError(t, rb.Call(payload, result, rules.ResultFunctionMeta{RuleFired: "true"}))
+	}
+	require.Len(t, result.ChangeSet.Mutations(), 2)
+	assert.Equal(t, []string{
+		"rejectBid: bid bid1 of bidder bidder1 rejected (rule fired: true)",
+		"rejectBid: bid bid3 of bidder bidder1 rejected (rule fired: true)",
+	}, result.DebugMessages)
+
+	payload := hs.RawBidderResponsePayload{BidderResponse: bidderResponse, Bidder: "bidder1"}
+	for _, mutation := range result.ChangeSet.Mutations() {
+		assert.Equal(t, hs.MutationDelete, mutation.Type())
+		assert.Equal(t, []string{"bids"}, mutation.Key())
+
+		var err error
+		payload, err = mutation.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*adapters.TypedBid{bid2}, bidderResponse.Bids)
+}
+
+func TestRejectBidCallNilBidderResponse(t *testing.T) {
+	result := &hs.HookResult[hs.RawBidderResponsePayload]{}
+	rb := &RejectBid{}
+
+	require.NoError(t, rb.Call(&rules.BidPayload{Bid: &adapters.TypedBid{}}, result, rules.ResultFunctionMeta{}))
+	_, err := result.ChangeSet.Mutations()[0].Apply(hs.RawBidderResponsePayload{})
+	assert.EqualError(t, err, "payload contains a nil bidder response")
+}
+
+func TestBidderResultFunctionsName(t *testing.T) {
+	assert.Equal(t, ModifyBidderRequestName, (&ModifyBidderRequest{}).Name())
+	assert.Equal(t, RejectBidName, (&RejectBid{}).Name())
+}
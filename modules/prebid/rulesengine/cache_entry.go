@@ -21,6 +21,8 @@ type cacheEntry struct {
 	timestamp                               time.Time
 	hashedConfig                            hash
 	ruleSetsForProcessedAuctionRequestStage []cacheRuleSet[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]
+	ruleSetsForBidderRequestStage           []cacheRuleSet[rules.BidderRequestPayload, hs.HookResult[hs.BidderRequestPayload]]
+	ruleSetsForRawBidderResponseStage       []cacheRuleSet[rules.BidPayload, hs.HookResult[hs.RawBidderResponsePayload]]
 }
 type cacheRuleSet[T1 any, T2 any] struct {
 	name        string
@@ -34,8 +36,8 @@ type cacheModelGroup[T1 any, T2 any] struct {
 }
 
 // NewCacheEntry creates a new cache object for the given configuration
-// It builds the tree structures for the rule sets for the processed auction request stage
-// and stores them in the cache object
+// It builds the tree structures for the rule sets for the processed auction request, bidder request
+// and raw bidder response stages and stores them in the cache object
 func NewCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage) (cacheEntry, error) {
 	if cfg == nil {
 		return cacheEntry{}, errors.New("no rules engine configuration provided")
@@ -53,47 +55,71 @@ func NewCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage) (cacheEnt
 	}
 
 	for _, ruleSet := range cfg.RuleSets {
-		if ruleSet.Stage != hooks.StageProcessedAuctionRequest {
+		switch ruleSet.Stage {
+		case hooks.StageProcessedAuctionRequest:
+			crs, err := createCacheRuleSet(&ruleSet)
+			if err != nil {
+				// TODO: log error / metric -->
+				continue
+			}
+			newCacheObj.ruleSetsForProcessedAuctionRequestStage = append(newCacheObj.ruleSetsForProcessedAuctionRequestStage, crs)
+		case hooks.StageBidderRequest:
+			crs, err := newCacheRuleSet(&ruleSet, rules.NewBidderRequestSchemaFunction, NewBidderRequestResultFunction)
+			if err != nil {
+				// TODO: log error / metric -->
+				continue
+			}
+			newCacheObj.ruleSetsForBidderRequestStage = append(newCacheObj.ruleSetsForBidderRequestStage, crs)
+		case hooks.StageRawBidderResponse:
+			crs, err := newCacheRuleSet(&ruleSet, rules.NewBidSchemaFunction, NewRawBidderResponseResultFunction)
+			if err != nil {
+				// TODO: log error / metric -->
+				continue
+			}
+			newCacheObj.ruleSetsForRawBidderResponseStage = append(newCacheObj.ruleSetsForRawBidderResponseStage, crs)
+		default:
 			// TODO: log error / metric --> stage not supported
-			continue
 		}
-		crs, err := createCacheRuleSet(&ruleSet)
-		if err != nil {
-			// TODO: log error / metric -->
-			continue
-		}
-
-		newCacheObj.ruleSetsForProcessedAuctionRequestStage = append(newCacheObj.ruleSetsForProcessedAuctionRequestStage, crs)
 	}
 
 	return newCacheObj, nil
 }
 
-// createCacheRuleSet creates a new cache rule set for the given configuration
-// It builds the tree structures for the model groups and stores them in the cache rule set
+// createCacheRuleSet creates a new cache rule set for the given processed auction request stage configuration
 func createCacheRuleSet(cfg *config.RuleSet) (cacheRuleSet[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]], error) {
+	return newCacheRuleSet(cfg, rules.NewRequestSchemaFunction, NewProcessedAuctionRequestResultFunction)
+}
+
+// newCacheRuleSet creates a new cache rule set for the given configuration
+// It builds the tree structures for the model groups using the schema and result functions of the stage
+// and stores them in the cache rule set
+func newCacheRuleSet[T1 any, T2 any](
+	cfg *config.RuleSet,
+	schemaFuncFactory rules.SchemaFuncFactory[T1],
+	resultFuncFactory rules.ResultFuncFactory[T1, T2],
+) (cacheRuleSet[T1, T2], error) {
 	if cfg == nil {
-		return cacheRuleSet[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]{}, errors.New("no rules engine configuration provided")
+		return cacheRuleSet[T1, T2]{}, errors.New("no rules engine configuration provided")
 	}
 
-	crs := cacheRuleSet[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]{
+	crs := cacheRuleSet[T1, T2]{
 		name:        cfg.Name,
-		modelGroups: []cacheModelGroup[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]{},
+		modelGroups: []cacheModelGroup[T1, T2]{},
 	}
 
 	for _, modelGroup := range cfg.ModelGroups {
-		tree, err := rules.NewTree[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]](
-			&treeBuilder[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]{
+		tree, err := rules.NewTree[T1, T2](
+			&treeBuilder[T1, T2]{
 				Config:            modelGroup,
-				SchemaFuncFactory: rules.NewRequestSchemaFunction,
-				ResultFuncFactory: NewProcessedAuctionRequestResultFunction,
+				SchemaFuncFactory: schemaFuncFactory,
+				ResultFuncFactory: resultFuncFactory,
 			},
 		)
 		if err != nil {
 			return crs, err
 		}
 
-		cmg := cacheModelGroup[openrtb_ext.RequestWrapper, hs.HookResult[hs.ProcessedAuctionRequestPayload]]{
+		cmg := cacheModelGroup[T1, T2]{
 			weight:       modelGroup.Weight,
 			version:      modelGroup.Version,
 			analyticsKey: modelGroup.AnalyticsKey,
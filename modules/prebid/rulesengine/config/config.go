@@ -61,6 +61,53 @@ type ResultFuncParams struct {
 	IfSyncedId     bool     `json:"ifsyncedid,omitempty"`
 }
 
+// SetTmaxParams holds the parameters of the setTmax result function.
+type SetTmaxParams struct {
+	Tmax int64 `json:"tmax"`
+}
+
+// SetBidFloorParams holds the parameters of the setBidFloor result function. The floor applies to all the imps
+// if no imp ids are specified.
+type SetBidFloorParams struct {
+	Floor    float64  `json:"floor"`
+	Currency string   `json:"currency,omitempty"`
+	ImpIDs   []string `json:"impIds,omitempty"`
+}
+
+// AddBcatParams holds the parameters of the addBcat result function.
+type AddBcatParams struct {
+	Categories []string `json:"categories"`
+}
+
+// AddBadvParams holds the parameters of the addBadv result function.
+type AddBadvParams struct {
+	Domains []string `json:"domains"`
+}
+
+// LogATagParams holds the parameters of the logATag result function.
+type LogATagParams struct {
+	AnalyticsValue string `json:"analyticsValue"`
+}
+
+// RemoveEidsParams holds the parameters of the removeEids result function. The EIDs are removed for all the
+// bidders if no bidders are specified.
+type RemoveEidsParams struct {
+	Sources []string `json:"sources"`
+	Bidders []string `json:"bidders,omitempty"`
+}
+
+// RemoveUserFpdParams holds the parameters of the removeUserFpd result function. The user FPD is removed for
+// all the bidders if no bidders are specified.
+type RemoveUserFpdParams struct {
+	Bidders []string `json:"bidders,omitempty"`
+}
+
+// ModifyBidderRequestParams holds the parameters of the modifyBidderRequest result function. The patch is a
+// JSON merge patch applied to the request of the bidder, a null value removes a field.
+type ModifyBidderRequestParams struct {
+	Patch json.RawMessage `json:"patch"`
+}
+
 func CreateSchemaValidator(jsonSchemaFile string) (*gojsonschema.Schema, error) {
 	jsonSchemaFilePath, err := filepath.Abs(jsonSchemaFile)
 	if err != nil {
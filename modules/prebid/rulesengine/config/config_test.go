@@ -265,7 +265,7 @@ func TestValidateConfig(t *testing.T) {
                       ]
                     }
 					`),
-					"[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"channel\", \"dataCenter\", \"dataCenterIn\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gppSidAvailable\", \"gppSidIn\", \"percent\", \"tcfInScope\", \"userFpdAvailable\"] ",
+					"[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"channel\", \"dataCenter\", \"dataCenterIn\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gppSidAvailable\", \"gppSidIn\", \"percent\", \"tcfInScope\", \"userFpdAvailable\", \"bidder\", \"bidderIn\", \"mediaType\", \"bidPrice\", \"dealPresent\", \"adomain\", \"adomainIn\"] ",
 				},
 				{ //13
 					json.RawMessage(`
@@ -340,13 +340,58 @@ func TestValidateConfig(t *testing.T) {
                       ]
                     }
 					`),
-					"[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"excludeBidders\", \"includeBidders\", \"logATag\"] ",
+					"[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"excludeBidders\", \"includeBidders\", \"logATag\", \"setTmax\", \"setBidFloor\", \"addBcat\", \"addBadv\", \"removeEids\", \"removeUserFpd\", \"modifyBidderRequest\", \"rejectBid\"] ",
+				},
+			},
+		},
+		{
+			"result function args fail schema validation",
+			[]testInput{
+				{ //0
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "setTmax"}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.function: Must not validate the schema (not)] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //1
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "setTmax", "args": {"tmax": 0}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args.tmax: Must be greater than or equal to 1] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //2
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "setBidFloor", "args": {"floor": 1, "currency": "usd"}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args.currency: Does not match pattern '^[A-Z]{3}$'] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //3
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "addBcat", "args": {"categories": []}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args.categories: Array must have at least 1 items] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //4
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "addBadv", "args": {}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args: domains is required] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //5
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "logATag", "args": {"analyticsValue": ""}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args.analyticsValue: String length must be greater than or equal to 1] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //6
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "removeEids", "args": {"bidders": ["bidder1"]}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args: sources is required] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //7
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "removeUserFpd", "args": {"bidders": "bidder1"}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.function: Must not validate the schema (not)] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
+				},
+				{ //8
+					json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "bidder_request", "name": "someName", "modelgroups": [{"schema": [{"function": "bidder"}], "rules": [{"conditions": ["bidder1"], "results": [{"function": "modifyBidderRequest", "args": {"patch": {}}}]}]}]}]}`),
+					"[rulesets.0.modelgroups.0.rules.0.results.0: Must validate at least one schema (anyOf)] [rulesets.0.modelgroups.0.rules.0.results.0.args.patch: Must have at least 1 properties] [rulesets.0.modelgroups.0.rules.0.results.0: Must validate all the schemas (allOf)] ",
 				},
 			},
 		},
 		{
 			"successful rules engine schema validation",
-			[]testInput{{getValidJsonConfig(), ""}},
+			[]testInput{
+				{getValidJsonConfig(), ""},
+				{json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "bidder_request", "name": "bidderRequest", "modelgroups": [{"schema": [{"function": "bidderIn", "args": {"bidders": ["bidder1"]}}, {"function": "tcfInScope"}], "rules": [{"conditions": ["true", "true"], "results": [{"function": "modifyBidderRequest", "args": {"patch": {"user": {"eids": null}}}}]}]}]}, {"stage": "raw_bidder_response", "name": "rawBidderResponse", "modelgroups": [{"schema": [{"function": "bidder"}, {"function": "bidPrice", "args": {"operator": "gt", "value": 10}}, {"function": "dealPresent"}, {"function": "mediaType"}, {"function": "deviceCountry"}], "rules": [{"conditions": ["bidder1", "true", "false", "video", "USA"], "results": [{"function": "rejectBid"}]}]}]}]}`), ""},
+				{json.RawMessage(`{"enabled": true, "rulesets": [{"stage": "processed_auction_request", "name": "someName", "modelgroups": [{"schema": [{"function": "channel"}], "rules": [{"conditions": ["web"], "results": [{"function": "setTmax", "args": {"tmax": 500}}, {"function": "setBidFloor", "args": {"floor": 1.5, "currency": "EUR", "impIds": ["imp1"]}}, {"function": "addBcat", "args": {"categories": ["IAB25"]}}, {"function": "addBadv", "args": {"domains": ["example.com"]}}, {"function": "logATag", "args": {"analyticsValue": "bucket-a"}}, {"function": "removeEids", "args": {"sources": ["id5-sync.com"], "bidders": ["bidder1"]}}, {"function": "removeUserFpd"}, {"function": "excludeBidders", "args": {"bidders": ["bidder1"]}}]}]}]}]}`), ""},
+			},
 		},
 	}
 
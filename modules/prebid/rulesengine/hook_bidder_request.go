@@ -0,0 +1,37 @@
+package rulesengine
+
+import (
+	"fmt"
+
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+func handleBidderRequestHook(
+	ruleSets []cacheRuleSet[rules.BidderRequestPayload, hs.HookResult[hs.BidderRequestPayload]],
+	payload hs.BidderRequestPayload) (hs.HookResult[hs.BidderRequestPayload], error) {
+
+	result := hs.HookResult[hs.BidderRequestPayload]{
+		ChangeSet: hs.ChangeSet[hs.BidderRequestPayload]{},
+	}
+
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, fmt.Errorf("payload contains a nil bid request")
+	}
+	rulesPayload := rules.BidderRequestPayload{Request: payload.Request, Bidder: payload.Bidder}
+
+	for _, ruleSet := range ruleSets {
+		selectedGroup, err := selectModelGroup(ruleSet.modelGroups, randomutil.RandomNumberGenerator{})
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to select model group: %s", err))
+			continue
+		}
+
+		if err := selectedGroup.tree.Run(&rulesPayload, &result); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/currency"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bidderStagesConfig = `{
+	"enabled": true,
+	"rulesets": [
+		{
+			"stage": "bidder_request",
+			"name": "strip-eids",
+			"modelgroups": [{
+				"schema": [{"function": "bidder"}, {"function": "tcfInScope"}],
+				"rules": [{"conditions": ["bidder1", "true"], "results": [{"function": "modifyBidderRequest", "args": {"patch": {"user": {"eids": null}}}}]}]
+			}]
+		},
+		{
+			"stage": "raw_bidder_response",
+			"name": "drop-expensive-video",
+			"modelgroups": [{
+				"schema": [{"function": "bidderIn", "args": {"bidders": ["bidder1"]}}, {"function": "bidPrice", "args": {"operator": "gt", "value": 10}}, {"function": "dealPresent"}, {"function": "mediaType"}, {"function": "deviceCountry"}],
+				"rules": [{"conditions": ["true", "true", "false", "video", "USA"], "results": [{"function": "rejectBid"}]}]
+			}]
+		},
+		{
+			"stage": "raw_bidder_response",
+			"name": "wrong-functions",
+			"modelgroups": [{
+				"schema": [{"function": "bidder"}],
+				"rules": [{"conditions": ["bidder1"], "results": [{"function": "excludeBidders", "args": {"bidders": ["bidder1"]}}]}]
+			}]
+		}
+	]
+}`
+
+func newBidderStagesCacheEntry(t *testing.T) cacheEntry {
+	cfgRaw := json.RawMessage(bidderStagesConfig)
+	var cfg config.PbRulesEngine
+	require.NoError(t, jsonutil.Unmarshal(cfgRaw, &cfg))
+
+	entry, err := NewCacheEntry(&cfg, &cfgRaw)
+	require.NoError(t, err)
+	return entry
+}
+
+func TestNewCacheEntryBidderStages(t *testing.T) {
+	entry := newBidderStagesCacheEntry(t)
+
+	assert.Empty(t, entry.ruleSetsForProcessedAuctionRequestStage)
+	require.Len(t, entry.ruleSetsForBidderRequestStage, 1)
+	assert.Equal(t, "strip-eids", entry.ruleSetsForBidderRequestStage[0].name)
+	require.Len(t, entry.ruleSetsForRawBidderResponseStage, 1, "the rule set with functions of another stage is skipped")
+	assert.Equal(t, "drop-expensive-video", entry.ruleSetsForRawBidderResponseStage[0].name)
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	ruleSets := newBidderStagesCacheEntry(t).ruleSetsForBidderRequestStage
+	gdpr := int8(1)
+
+	tests := []struct {
+		name            string
+		payload         hs.BidderRequestPayload
+		expectedRequest *openrtb2.BidRequest
+		expectedError   string
+	}{
+		{
+			name: "rule-fired",
+			payload: hs.BidderRequestPayload{
+				Bidder:  "bidder1",
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", Regs: &openrtb2.Regs{GDPR: &gdpr}, User: &openrtb2.User{ID: "user", EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}}}},
+			},
+			expectedRequest: &openrtb2.BidRequest{ID: "req", Regs: &openrtb2.Regs{GDPR: &gdpr}, User: &openrtb2.User{ID: "user"}},
+		},
+		{
+			name: "other-bidder",
+			payload: hs.BidderRequestPayload{
+				Bidder:  "bidder2",
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", Regs: &openrtb2.Regs{GDPR: &gdpr}, User: &openrtb2.User{ID: "user", EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}}}},
+			},
+			expectedRequest: &openrtb2.BidRequest{ID: "req", Regs: &openrtb2.Regs{GDPR: &gdpr}, User: &openrtb2.User{ID: "user", EIDs: []openrtb2.EID{{Source: "id5-sync.com"}}}},
+		},
+		{
+			name:          "nil-request",
+			payload:       hs.BidderRequestPayload{Bidder: "bidder1"},
+			expectedError: "payload contains a nil bid request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handleBidderRequestHook(ruleSets, tt.payload)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, result.Errors)
+
+			payload := tt.payload
+			for _, mutation := range result.ChangeSet.Mutations() {
+				payload, err = mutation.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedRequest, payload.Request.BidRequest)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	ruleSets := newBidderStagesCacheEntry(t).ruleSetsForRawBidderResponseStage
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 2}})
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}}}}
+
+	cheapVideo := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "cheap-video", Price: 4}, BidType: openrtb_ext.BidTypeVideo}
+	expensiveVideo := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "expensive-video", Price: 6}, BidType: openrtb_ext.BidTypeVideo}
+	expensiveDeal := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "expensive-deal", Price: 6, DealID: "deal"}, BidType: openrtb_ext.BidTypeVideo}
+	expensiveBanner := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "expensive-banner", Price: 6}, BidType: openrtb_ext.BidTypeBanner}
+
+	tests := []struct {
+		name                  string
+		payload               hs.RawBidderResponsePayload
+		expectedBids          []*adapters.TypedBid
+		expectedDebugMessages []string
+		expectedError         string
+	}{
+		{
+			name: "rule-fired",
+			payload: hs.RawBidderResponsePayload{
+				Bidder:         "bidder1",
+				Request:        request,
+				BidderResponse: &adapters.BidderResponse{Currency: "EUR", Bids: []*adapters.TypedBid{cheapVideo, expensiveVideo, expensiveDeal, expensiveBanner}},
+			},
+			expectedBids:          []*adapters.TypedBid{cheapVideo, expensiveDeal, expensiveBanner},
+			expectedDebugMessages: []string{"rejectBid: bid expensive-video of bidder bidder1 rejected (rule fired: true|true|false|video|USA)"},
+		},
+		{
+			name: "other-bidder",
+			payload: hs.RawBidderResponsePayload{
+				Bidder:         "bidder2",
+				Request:        request,
+				BidderResponse: &adapters.BidderResponse{Currency: "EUR", Bids: []*adapters.TypedBid{expensiveVideo}},
+			},
+			expectedBids: []*adapters.TypedBid{expensiveVideo},
+		},
+		{
+			name: "nil-request",
+			payload: hs.RawBidderResponsePayload{
+				Bidder:         "bidder1",
+				BidderResponse: &adapters.BidderResponse{Currency: "EUR", Bids: []*adapters.TypedBid{expensiveVideo}},
+			},
+			expectedBids: []*adapters.TypedBid{expensiveVideo},
+		},
+		{
+			name:          "nil-bidder-response",
+			payload:       hs.RawBidderResponsePayload{Bidder: "bidder1"},
+			expectedError: "payload contains a nil bidder response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handleRawBidderResponseHook(ruleSets, tt.payload, conversions)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, result.Errors)
+			assert.Equal(t, tt.expectedDebugMessages, result.DebugMessages)
+
+			payload := tt.payload
+			for _, mutation := range result.ChangeSet.Mutations() {
+				payload, err = mutation.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedBids, tt.payload.BidderResponse.Bids)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHookSchemaFunctionError(t *testing.T) {
+	ruleSets := newBidderStagesCacheEntry(t).ruleSetsForRawBidderResponseStage
+	payload := hs.RawBidderResponsePayload{
+		Bidder:         "bidder1",
+		BidderResponse: &adapters.BidderResponse{Currency: "EUR", Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid", Price: 6}}}},
+	}
+
+	result, err := handleRawBidderResponseHook(ruleSets, payload, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bidPrice schema function can't convert the bid price from EUR to USD without currency rates"}, result.Errors)
+	assert.Empty(t, result.ChangeSet.Mutations())
+}
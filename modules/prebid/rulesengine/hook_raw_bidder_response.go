@@ -0,0 +1,54 @@
+package rulesengine
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/currency"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+// handleRawBidderResponseHook runs the rule sets for each bid of the bidder response. The model group of a
+// rule set is selected once for all the bids of the response.
+func handleRawBidderResponseHook(
+	ruleSets []cacheRuleSet[rules.BidPayload, hs.HookResult[hs.RawBidderResponsePayload]],
+	payload hs.RawBidderResponsePayload,
+	conversions currency.Conversions) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+
+	result := hs.HookResult[hs.RawBidderResponsePayload]{
+		ChangeSet: hs.ChangeSet[hs.RawBidderResponsePayload]{},
+	}
+
+	if payload.BidderResponse == nil {
+		return result, fmt.Errorf("payload contains a nil bidder response")
+	}
+	request := payload.Request
+	if request == nil {
+		request = &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+	}
+
+	for _, ruleSet := range ruleSets {
+		selectedGroup, err := selectModelGroup(ruleSet.modelGroups, randomutil.RandomNumberGenerator{})
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to select model group: %s", err))
+			continue
+		}
+
+		for _, bid := range payload.BidderResponse.Bids {
+			rulesPayload := rules.BidPayload{
+				BidderRequestPayload: rules.BidderRequestPayload{Request: request, Bidder: payload.Bidder},
+				Bid:                  bid,
+				Currency:             payload.BidderResponse.Currency,
+				Conversions:          conversions,
+			}
+			if err := selectedGroup.tree.Run(&rulesPayload, &result); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+	}
+
+	return result, nil
+}
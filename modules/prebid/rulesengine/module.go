@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/prebid/prebid-server/v3/currency"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
@@ -15,10 +16,13 @@ import (
 
 const fiveMinutes = time.Duration(300) * time.Second
 
+// buildQueueSize is the number of build instructions queued for the tree manager
+const buildQueueSize = 100
+
 // Builder configures the rules engine module initiating an in-memory cache and kicking
 // off a go routine that builds tree structures that represent rule sets optimized for finding
 // a rule to applies for a given request.
-func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+func Builder(_ json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
 	schemaValidator, err := config.CreateSchemaValidator(config.RulesEngineSchemaFilePath)
 	if err != nil {
 		return nil, err
@@ -26,7 +30,7 @@ func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
 
 	tm := treeManager{
 		done:            make(chan struct{}),
-		requests:        make(chan buildInstruction),
+		requests:        make(chan buildInstruction, buildQueueSize),
 		schemaValidator: schemaValidator,
 		monitor:         &treeManagerLogger{},
 	}
@@ -35,8 +39,9 @@ func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
 	go tm.Run(c)
 
 	return Module{
-		Cache:       c,
-		TreeManager: &tm,
+		Cache:         c,
+		TreeManager:   &tm,
+		RateConverter: deps.RateConvertor,
 	}, nil
 }
 
@@ -44,6 +49,8 @@ func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
 type Module struct {
 	Cache       cacher
 	TreeManager *treeManager
+	// RateConverter provides the currency rates the bid prices are converted with at the raw bidder response stage
+	RateConverter *currency.RateConverter
 }
 
 // HandleProcessedAuctionHook updates field on openrtb2.BidRequest.
@@ -53,12 +60,65 @@ func (m Module) HandleProcessedAuctionHook(
 	miCtx hs.ModuleInvocationContext,
 	payload hs.ProcessedAuctionRequestPayload,
 ) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
-	result := hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{Message: message}, nil
+	}
+
+	ruleSets := co.ruleSetsForProcessedAuctionRequestStage
+
+	return handleProcessedAuctionHook(ruleSets, payload)
+}
 
+// HandleBidderRequestHook updates fields on the openrtb2.BidRequest of a bidder.
+// Fields are updated only if the bidder request satisfies conditions provided by the module config.
+func (m Module) HandleBidderRequestHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.BidderRequestPayload,
+) (hs.HookResult[hs.BidderRequestPayload], error) {
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.BidderRequestPayload]{Message: message}, nil
+	}
+	if len(co.ruleSetsForBidderRequestStage) == 0 {
+		return hs.HookResult[hs.BidderRequestPayload]{}, nil
+	}
+
+	return handleBidderRequestHook(co.ruleSetsForBidderRequestStage, payload)
+}
+
+// HandleRawBidderResponseHook removes bids from the response of a bidder.
+// Bids are removed only if they satisfy conditions provided by the module config.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.RawBidderResponsePayload,
+) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.RawBidderResponsePayload]{Message: message}, nil
+	}
+	if len(co.ruleSetsForRawBidderResponseStage) == 0 {
+		return hs.HookResult[hs.RawBidderResponsePayload]{}, nil
+	}
+
+	var conversions currency.Conversions
+	if m.RateConverter != nil {
+		conversions = m.RateConverter.Rates()
+	}
+
+	return handleRawBidderResponseHook(co.ruleSetsForRawBidderResponseStage, payload, conversions)
+}
+
+// accountCacheEntry returns the cache entry holding the trees of the account, requesting the trees to be
+// built if they are missing or outdated. If the rules can't be run, no cache entry is returned along with
+// a message explaining why.
+func (m Module) accountCacheEntry(miCtx hs.ModuleInvocationContext) (*cacheEntry, string) {
 	// AccountConfig will either be an account-specific config or the default account config
 	// AccountConfig only contains the config block for this module
 	if len(miCtx.AccountConfig) == 0 {
-		return result, nil
+		return nil, ""
 	}
 
 	co := m.Cache.Get(miCtx.AccountID)
@@ -69,12 +129,10 @@ func (m Module) HandleProcessedAuctionHook(
 			accountID: miCtx.AccountID,
 			config:    &miCtx.AccountConfig,
 		}
-		m.TreeManager.requests <- bi
+		m.TreeManager.requestBuild(bi)
 
 		// TODO: return with reject or no reject, possible config option
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, loading rules engine account configuration for future requests",
-		}, nil
+		return nil, "skipped, loading rules engine account configuration for future requests"
 	}
 	// cache hit
 	if rebuildTrees(co, &miCtx.AccountConfig) {
@@ -82,18 +140,14 @@ func (m Module) HandleProcessedAuctionHook(
 			accountID: miCtx.AccountID,
 			config:    &miCtx.AccountConfig,
 		}
-		m.TreeManager.requests <- bi
+		m.TreeManager.requestBuild(bi)
 	}
 
 	if !co.enabled {
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, rules engine is disabled for this account",
-		}, nil
+		return nil, "skipped, rules engine is disabled for this account"
 	}
 
-	ruleSets := co.ruleSetsForProcessedAuctionRequestStage
-
-	return handleProcessedAuctionHook(ruleSets, payload)
+	return co, ""
 }
 
 // Shutdown signals the module to stop processing and waits for the tree manager to finish
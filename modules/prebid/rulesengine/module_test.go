@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/util/timeutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -123,3 +124,25 @@ func TestRebuildTrees(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleAccountCacheEntryDoesNotBlock(t *testing.T) {
+	module := Module{
+		Cache:       NewCache(),
+		TreeManager: &treeManager{requests: make(chan buildInstruction)},
+	}
+	miCtx := hs.ModuleInvocationContext{AccountID: "a", AccountConfig: json.RawMessage(`{}`)}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			module.accountCacheEntry(miCtx)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the hooks waited for the tree manager")
+	}
+}
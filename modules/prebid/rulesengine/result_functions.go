@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"slices"
+	"strings"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 )
 
 // ProcessedAuctionResultFunc is a type alias for a result function that runs in the processed auction request stage.
@@ -19,8 +23,18 @@ type ProcessedAuctionResultFunc = rules.ResultFunction[openrtb_ext.RequestWrappe
 const (
 	ExcludeBiddersName = "excludeBidders"
 	IncludeBiddersName = "includeBidders"
+	SetTmaxName        = "setTmax"
+	SetBidFloorName    = "setBidFloor"
+	AddBcatName        = "addBcat"
+	AddBadvName        = "addBadv"
+	LogATagName        = "logATag"
+	RemoveEidsName     = "removeEids"
+	RemoveUserFpdName  = "removeUserFpd"
 )
 
+// AnalyticsActivityName is the name of the analytics activity the logATag result function reports.
+const AnalyticsActivityName = "pb-rules-engine"
+
 // NewProcessedAuctionRequestResultFunction is a factory function that creates a new result function based on the provided name and parameters.
 // It returns an error if the function name is not recognized or if there is an issue with the parameters.
 // The function name is case insensitive.
@@ -34,6 +48,20 @@ func NewProcessedAuctionRequestResultFunction(name string, params json.RawMessag
 		return NewExcludeBidders(params)
 	case IncludeBiddersName:
 		return NewIncludeBidders(params)
+	case SetTmaxName:
+		return NewSetTmax(params)
+	case SetBidFloorName:
+		return NewSetBidFloor(params)
+	case AddBcatName:
+		return NewAddBcat(params)
+	case AddBadvName:
+		return NewAddBadv(params)
+	case LogATagName:
+		return NewLogATag(params)
+	case RemoveEidsName:
+		return NewRemoveEids(params)
+	case RemoveUserFpdName:
+		return NewRemoveUserFpd(params)
 	default:
 		return nil, fmt.Errorf("result function %s was not created", name)
 	}
@@ -173,3 +201,508 @@ func buildExcludeBidders(req *openrtb_ext.RequestWrapper, argBidders []string) (
 	}
 	return impIdToBidders, nil
 }
+
+// addRequestMutation adds a mutation of the bid request to the change set. Unlike excludeBidders and
+// includeBidders, the changes are computed when the mutation is applied, so the result functions of a rule
+// changing the same fields build on each other.
+func addRequestMutation(result *hs.HookResult[hs.ProcessedAuctionRequestPayload], apply func(req *openrtb_ext.RequestWrapper) error, key ...string) {
+	result.ChangeSet.AddMutation(func(p hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		if p.Request == nil || p.Request.BidRequest == nil {
+			return p, errors.New("payload contains a nil bid request")
+		}
+		return p, apply(p.Request)
+	}, hs.MutationUpdate, key...)
+}
+
+// addDebugMessage describes the effect of a result function in the hook trace.
+func addDebugMessage[T any](result *hs.HookResult[T], meta rules.ResultFunctionMeta, name string, format string, args ...interface{}) {
+	message := fmt.Sprintf("%s: %s", name, fmt.Sprintf(format, args...))
+	if meta.RuleFired != "" {
+		message += fmt.Sprintf(" (rule fired: %s)", meta.RuleFired)
+	}
+	result.DebugMessages = append(result.DebugMessages, message)
+}
+
+// NewSetTmax is a factory function that creates a new SetTmax result function.
+func NewSetTmax(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setTmaxParams config.SetTmaxParams
+	if err := jsonutil.Unmarshal(params, &setTmaxParams); err != nil {
+		return nil, err
+	}
+	if setTmaxParams.Tmax <= 0 {
+		return nil, errors.New("setTmax requires a tmax greater than 0")
+	}
+	return &SetTmax{Args: setTmaxParams}, nil
+}
+
+// SetTmax sets the tmax of the auction. The auction deadline is shortened accordingly, a tmax longer than the
+// one the auction started with cannot extend it.
+type SetTmax struct {
+	Args config.SetTmaxParams
+}
+
+// Call adds a mutation setting request.tmax to the ChangeSet.
+func (st *SetTmax) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	tmax := st.Args.Tmax
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		req.TMax = tmax
+		return nil
+	}, "bidrequest", "tmax")
+	addDebugMessage(result, meta, SetTmaxName, "tmax set to %d", tmax)
+	return nil
+}
+
+func (st *SetTmax) Name() string {
+	return SetTmaxName
+}
+
+// NewSetBidFloor is a factory function that creates a new SetBidFloor result function.
+// The currency defaults to USD.
+func NewSetBidFloor(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setBidFloorParams config.SetBidFloorParams
+	if err := jsonutil.Unmarshal(params, &setBidFloorParams); err != nil {
+		return nil, err
+	}
+	if setBidFloorParams.Floor < 0 {
+		return nil, errors.New("setBidFloor requires a floor greater than or equal to 0")
+	}
+	if setBidFloorParams.Currency == "" {
+		setBidFloorParams.Currency = "USD"
+	}
+	return &SetBidFloor{Args: setBidFloorParams}, nil
+}
+
+// SetBidFloor overrides the bid floor of the imps. Floors enforced by the price floors feature take
+// precedence as they are resolved after the processed auction request stage.
+type SetBidFloor struct {
+	Args config.SetBidFloorParams
+}
+
+// Call adds a mutation setting imp.bidfloor and imp.bidfloorcur to the ChangeSet.
+func (sbf *SetBidFloor) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	args := sbf.Args
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		for _, impWrapper := range req.GetImp() {
+			if len(args.ImpIDs) == 0 || slices.Contains(args.ImpIDs, impWrapper.ID) {
+				impWrapper.BidFloor = args.Floor
+				impWrapper.BidFloorCur = args.Currency
+			}
+		}
+		return nil
+	}, "bidrequest", "imp", "bidfloor")
+
+	imps := "all imps"
+	if len(args.ImpIDs) > 0 {
+		imps = "imps " + strings.Join(args.ImpIDs, ", ")
+	}
+	addDebugMessage(result, meta, SetBidFloorName, "bid floor set to %v %s for %s", args.Floor, args.Currency, imps)
+	return nil
+}
+
+func (sbf *SetBidFloor) Name() string {
+	return SetBidFloorName
+}
+
+// NewAddBcat is a factory function that creates a new AddBcat result function.
+func NewAddBcat(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var addBcatParams config.AddBcatParams
+	if err := jsonutil.Unmarshal(params, &addBcatParams); err != nil {
+		return nil, err
+	}
+	if len(addBcatParams.Categories) == 0 {
+		return nil, errors.New("addBcat requires at least one category to be specified")
+	}
+	return &AddBcat{Args: addBcatParams}, nil
+}
+
+// AddBcat adds categories to the blocked advertiser categories of the request.
+type AddBcat struct {
+	Args config.AddBcatParams
+}
+
+// Call adds a mutation appending the missing categories to request.bcat to the ChangeSet.
+func (ab *AddBcat) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	categories := ab.Args.Categories
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		req.BCat = appendMissing(req.BCat, categories)
+		return nil
+	}, "bidrequest", "bcat")
+	addDebugMessage(result, meta, AddBcatName, "categories %s added to bcat", strings.Join(categories, ", "))
+	return nil
+}
+
+func (ab *AddBcat) Name() string {
+	return AddBcatName
+}
+
+// NewAddBadv is a factory function that creates a new AddBadv result function.
+func NewAddBadv(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var addBadvParams config.AddBadvParams
+	if err := jsonutil.Unmarshal(params, &addBadvParams); err != nil {
+		return nil, err
+	}
+	if len(addBadvParams.Domains) == 0 {
+		return nil, errors.New("addBadv requires at least one domain to be specified")
+	}
+	return &AddBadv{Args: addBadvParams}, nil
+}
+
+// AddBadv adds domains to the blocked advertiser domains of the request.
+type AddBadv struct {
+	Args config.AddBadvParams
+}
+
+// Call adds a mutation appending the missing domains to request.badv to the ChangeSet.
+func (ab *AddBadv) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	domains := ab.Args.Domains
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		req.BAdv = appendMissing(req.BAdv, domains)
+		return nil
+	}, "bidrequest", "badv")
+	addDebugMessage(result, meta, AddBadvName, "domains %s added to badv", strings.Join(domains, ", "))
+	return nil
+}
+
+func (ab *AddBadv) Name() string {
+	return AddBadvName
+}
+
+// appendMissing returns a copy of values with the missing additions appended.
+func appendMissing(values []string, additions []string) []string {
+	result := slices.Clone(values)
+	for _, addition := range additions {
+		if !slices.Contains(result, addition) {
+			result = append(result, addition)
+		}
+	}
+	return result
+}
+
+// NewLogATag is a factory function that creates a new LogATag result function.
+func NewLogATag(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var logATagParams config.LogATagParams
+	if err := jsonutil.Unmarshal(params, &logATagParams); err != nil {
+		return nil, err
+	}
+	if logATagParams.AnalyticsValue == "" {
+		return nil, errors.New("logATag requires an analyticsValue to be specified")
+	}
+	return &LogATag{Args: logATagParams}, nil
+}
+
+// LogATag reports a value, like the name of an A/B testing bucket, in the analytics tags of the module.
+type LogATag struct {
+	Args config.LogATagParams
+}
+
+// Call adds an analytics activity with the analytics value, the analytics key and version of the model group
+// and the rule fired to the result.
+func (lt *LogATag) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	result.AnalyticsTags.Activities = append(result.AnalyticsTags.Activities, hookanalytics.Activity{
+		Name:   AnalyticsActivityName,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{
+			{
+				Status: hookanalytics.ResultStatusAllow,
+				Values: map[string]interface{}{
+					"analyticsKey":   meta.AnalyticsKey,
+					"analyticsValue": lt.Args.AnalyticsValue,
+					"modelVersion":   meta.ModelVersion,
+					"conditionFired": meta.RuleFired,
+					"resultFunction": LogATagName,
+				},
+				AppliedTo: hookanalytics.AppliedTo{Request: true},
+			},
+		},
+	})
+	addDebugMessage(result, meta, LogATagName, "analytics value %s logged", lt.Args.AnalyticsValue)
+	return nil
+}
+
+func (lt *LogATag) Name() string {
+	return LogATagName
+}
+
+// NewRemoveEids is a factory function that creates a new RemoveEids result function.
+func NewRemoveEids(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var removeEidsParams config.RemoveEidsParams
+	if err := jsonutil.Unmarshal(params, &removeEidsParams); err != nil {
+		return nil, err
+	}
+	if len(removeEidsParams.Sources) == 0 {
+		return nil, errors.New("removeEids requires at least one source to be specified")
+	}
+	return &RemoveEids{Args: removeEidsParams}, nil
+}
+
+// RemoveEids removes the EIDs of the sources from the request, or only for some bidders using the
+// request.ext.prebid.data.eidpermissions.
+type RemoveEids struct {
+	Args config.RemoveEidsParams
+}
+
+// Call adds a mutation removing the EIDs from request.user.eids, or restricting them in
+// request.ext.prebid.data.eidpermissions if bidders are specified, to the ChangeSet.
+func (re *RemoveEids) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	args := re.Args
+	sources := strings.Join(args.Sources, ", ")
+
+	if len(args.Bidders) == 0 {
+		addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+			if req.User == nil || len(req.User.EIDs) == 0 {
+				return nil
+			}
+			user := ptrutil.Clone(req.User)
+			user.EIDs = slices.DeleteFunc(slices.Clone(user.EIDs), func(eid openrtb2.EID) bool {
+				return slices.Contains(args.Sources, eid.Source)
+			})
+			req.User = user
+			return nil
+		}, "bidrequest", "user", "eids")
+		addDebugMessage(result, meta, RemoveEidsName, "eids of sources %s removed", sources)
+		return nil
+	}
+
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		return removeEidsForBidders(req, args.Sources, args.Bidders)
+	}, "bidrequest", "ext", "prebid", "data", "eidpermissions")
+	addDebugMessage(result, meta, RemoveEidsName, "eids of sources %s removed for bidders %s", sources, strings.Join(args.Bidders, ", "))
+	return nil
+}
+
+func (re *RemoveEids) Name() string {
+	return RemoveEidsName
+}
+
+// removeEidsForBidders removes the bidders from the eid permissions of the sources. A source without eid
+// permissions is permitted to all the bidders of the request but the removed ones.
+func removeEidsForBidders(req *openrtb_ext.RequestWrapper, sources []string, removedBidders []string) error {
+	reqExt, err := req.GetRequestExt()
+	if err != nil {
+		return err
+	}
+	prebid := reqExt.GetPrebid()
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtRequestPrebid{}
+	}
+	data := ptrutil.Clone(prebid.Data)
+	if data == nil {
+		data = &openrtb_ext.ExtRequestPrebidData{}
+	}
+	data.EidPermissions = slices.Clone(data.EidPermissions)
+
+	var requestBidders []string
+	for _, source := range sources {
+		i := slices.IndexFunc(data.EidPermissions, func(p openrtb_ext.ExtRequestPrebidDataEidPermission) bool {
+			return p.Source == source
+		})
+
+		var permitted []string
+		if i < 0 || slices.Contains(data.EidPermissions[i].Bidders, "*") {
+			if requestBidders == nil {
+				if requestBidders, err = impBidders(req); err != nil {
+					return err
+				}
+			}
+			permitted = requestBidders
+		}
+		if i >= 0 {
+			permitted = appendMissing(permitted, slices.DeleteFunc(slices.Clone(data.EidPermissions[i].Bidders), func(bidder string) bool {
+				return bidder == "*"
+			}))
+		}
+
+		permission := openrtb_ext.ExtRequestPrebidDataEidPermission{
+			Source:  source,
+			Bidders: withoutBidders(permitted, removedBidders),
+		}
+		if i < 0 {
+			data.EidPermissions = append(data.EidPermissions, permission)
+		} else {
+			data.EidPermissions[i] = permission
+		}
+	}
+
+	prebid.Data = data
+	reqExt.SetPrebid(prebid)
+	return nil
+}
+
+// impBidders returns the sorted bidders of the imps of the request.
+func impBidders(req *openrtb_ext.RequestWrapper) ([]string, error) {
+	bidders := []string{}
+	for _, impWrapper := range req.GetImp() {
+		impExt, err := impWrapper.GetImpExt()
+		if err != nil {
+			return nil, err
+		}
+		if impPrebid := impExt.GetPrebid(); impPrebid != nil {
+			for bidder := range impPrebid.Bidder {
+				if !slices.Contains(bidders, bidder) {
+					bidders = append(bidders, bidder)
+				}
+			}
+		}
+	}
+	slices.Sort(bidders)
+	return bidders, nil
+}
+
+// withoutBidders returns the bidders which are not removed, bidder names are case insensitive.
+func withoutBidders(bidders []string, removedBidders []string) []string {
+	result := make([]string, 0, len(bidders))
+	for _, bidder := range bidders {
+		if !slices.ContainsFunc(removedBidders, func(removed string) bool { return strings.EqualFold(removed, bidder) }) {
+			result = append(result, bidder)
+		}
+	}
+	return result
+}
+
+// NewRemoveUserFpd is a factory function that creates a new RemoveUserFpd result function.
+func NewRemoveUserFpd(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var removeUserFpdParams config.RemoveUserFpdParams
+	if len(params) > 0 {
+		if err := jsonutil.Unmarshal(params, &removeUserFpdParams); err != nil {
+			return nil, err
+		}
+	}
+	return &RemoveUserFpd{Args: removeUserFpdParams}, nil
+}
+
+// RemoveUserFpd removes the user first party data, user.data, user.ext.data, user.keywords, user.kwarray,
+// user.yob and user.gender, from the request, or only for some bidders using the
+// request.ext.prebid.bidderconfig.
+type RemoveUserFpd struct {
+	Args config.RemoveUserFpdParams
+}
+
+// userFpdRemoval is the bidder config user object removing the user first party data.
+var userFpdRemoval = map[string]json.RawMessage{
+	"data":     json.RawMessage(`null`),
+	"keywords": json.RawMessage(`null`),
+	"kwarray":  json.RawMessage(`null`),
+	"yob":      json.RawMessage(`0`),
+	"gender":   json.RawMessage(`null`),
+}
+
+// Call adds a mutation removing the user first party data from request.user, or overriding it in
+// request.ext.prebid.bidderconfig if bidders are specified, to the ChangeSet.
+func (ruf *RemoveUserFpd) Call(req *openrtb_ext.RequestWrapper, result *hs.HookResult[hs.ProcessedAuctionRequestPayload], meta rules.ResultFunctionMeta) error {
+	bidders := ruf.Args.Bidders
+
+	if len(bidders) == 0 {
+		addRequestMutation(result, removeUserFpd, "bidrequest", "user")
+		addDebugMessage(result, meta, RemoveUserFpdName, "user fpd removed")
+		return nil
+	}
+
+	addRequestMutation(result, func(req *openrtb_ext.RequestWrapper) error {
+		return removeUserFpdForBidders(req, bidders)
+	}, "bidrequest", "ext", "prebid", "bidderconfig")
+	addDebugMessage(result, meta, RemoveUserFpdName, "user fpd removed for bidders %s", strings.Join(bidders, ", "))
+	return nil
+}
+
+func (ruf *RemoveUserFpd) Name() string {
+	return RemoveUserFpdName
+}
+
+func removeUserFpd(req *openrtb_ext.RequestWrapper) error {
+	if req.User == nil {
+		return nil
+	}
+
+	userExt, err := req.GetUserExt()
+	if err != nil {
+		return err
+	}
+	if ext := userExt.GetExt(); ext["data"] != nil {
+		delete(ext, "data")
+		userExt.SetExt(ext)
+	}
+
+	user := ptrutil.Clone(req.User)
+	user.Data = nil
+	user.Keywords = ""
+	user.KwArray = nil
+	user.Yob = 0
+	user.Gender = ""
+	req.User = user
+	return nil
+}
+
+// removeUserFpdForBidders overrides the user first party data in the bidder configs of the bidders. A bidder
+// sharing a bidder config with other bidders gets its own copy.
+func removeUserFpdForBidders(req *openrtb_ext.RequestWrapper, bidders []string) error {
+	reqExt, err := req.GetRequestExt()
+	if err != nil {
+		return err
+	}
+	prebid := reqExt.GetPrebid()
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtRequestPrebid{}
+	}
+
+	bidderConfigs := slices.Clone(prebid.BidderConfigs)
+	for _, bidder := range bidders {
+		var config openrtb_ext.Config
+		i := slices.IndexFunc(bidderConfigs, func(bc openrtb_ext.BidderConfig) bool {
+			return slices.ContainsFunc(bc.Bidders, func(b string) bool { return strings.EqualFold(b, bidder) })
+		})
+		if i >= 0 {
+			if bidderConfigs[i].Config != nil {
+				config = *bidderConfigs[i].Config
+			}
+			bidderConfigs[i].Bidders = withoutBidders(bidderConfigs[i].Bidders, []string{bidder})
+			if len(bidderConfigs[i].Bidders) == 0 {
+				bidderConfigs = slices.Delete(bidderConfigs, i, i+1)
+			}
+		}
+
+		ortb2 := openrtb_ext.ORTB2{}
+		if config.ORTB2 != nil {
+			ortb2 = *config.ORTB2
+		}
+		user, err := withoutUserFpd(ortb2.User)
+		if err != nil {
+			return fmt.Errorf("failed to remove the user fpd of bidder %s: %w", bidder, err)
+		}
+		ortb2.User = user
+		config.ORTB2 = &ortb2
+
+		bidderConfigs = append(bidderConfigs, openrtb_ext.BidderConfig{Bidders: []string{bidder}, Config: &config})
+	}
+
+	prebid.BidderConfigs = bidderConfigs
+	reqExt.SetPrebid(prebid)
+	return nil
+}
+
+// withoutUserFpd returns the bidder config user object with the user first party data removed.
+func withoutUserFpd(user json.RawMessage) (json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(user) > 0 {
+		if err := jsonutil.Unmarshal(user, &fields); err != nil {
+			return nil, err
+		}
+	}
+	for field, value := range userFpdRemoval {
+		fields[field] = value
+	}
+
+	ext := make(map[string]json.RawMessage)
+	if len(fields["ext"]) > 0 {
+		if err := jsonutil.Unmarshal(fields["ext"], &ext); err != nil {
+			return nil, err
+		}
+	}
+	ext["data"] = json.RawMessage(`null`)
+	extJSON, err := jsonutil.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	fields["ext"] = extJSON
+
+	return jsonutil.Marshal(fields)
+}
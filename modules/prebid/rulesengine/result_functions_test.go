@@ -3,11 +3,13 @@ package rulesengine
 import (
 	"encoding/json"
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -45,6 +47,89 @@ func TestNewProcessedAuctionRequestResultFunction(t *testing.T) {
 			params:    json.RawMessage(`{"bidders":null}`),
 			expectErr: true,
 		},
+		{
+			name:       "valid_setTmax",
+			funcName:   SetTmaxName,
+			params:     json.RawMessage(`{"tmax":500}`),
+			expectType: &SetTmax{},
+		},
+		{
+			name:      "invalid_setTmax_zero",
+			funcName:  SetTmaxName,
+			params:    json.RawMessage(`{"tmax":0}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_setBidFloor",
+			funcName:   SetBidFloorName,
+			params:     json.RawMessage(`{"floor":1.5,"impIds":["imp1"]}`),
+			expectType: &SetBidFloor{},
+		},
+		{
+			name:      "invalid_setBidFloor_negative",
+			funcName:  SetBidFloorName,
+			params:    json.RawMessage(`{"floor":-1}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_addBcat",
+			funcName:   AddBcatName,
+			params:     json.RawMessage(`{"categories":["IAB25"]}`),
+			expectType: &AddBcat{},
+		},
+		{
+			name:      "invalid_addBcat_no_categories",
+			funcName:  AddBcatName,
+			params:    json.RawMessage(`{"categories":[]}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_addBadv",
+			funcName:   AddBadvName,
+			params:     json.RawMessage(`{"domains":["example.com"]}`),
+			expectType: &AddBadv{},
+		},
+		{
+			name:      "invalid_addBadv_no_domains",
+			funcName:  AddBadvName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_logATag",
+			funcName:   LogATagName,
+			params:     json.RawMessage(`{"analyticsValue":"bucket-a"}`),
+			expectType: &LogATag{},
+		},
+		{
+			name:      "invalid_logATag_no_value",
+			funcName:  LogATagName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_removeEids",
+			funcName:   RemoveEidsName,
+			params:     json.RawMessage(`{"sources":["id5-sync.com"],"bidders":["bidder1"]}`),
+			expectType: &RemoveEids{},
+		},
+		{
+			name:      "invalid_removeEids_no_sources",
+			funcName:  RemoveEidsName,
+			params:    json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_removeUserFpd_no_params",
+			funcName:   RemoveUserFpdName,
+			expectType: &RemoveUserFpd{},
+		},
+		{
+			name:      "invalid-remove-user-fpd-params",
+			funcName:  RemoveUserFpdName,
+			params:    json.RawMessage(`invalid-json`),
+			expectErr: true,
+		},
 		{
 			name:      "invalid_function_name",
 			funcName:  "invalidFunction",
@@ -532,3 +617,176 @@ func compareMaps(a, b map[string]map[string]json.RawMessage) bool {
 	}
 	return true
 }
+
+func TestRequestResultFunctionsCall(t *testing.T) {
+	meta := rules.ResultFunctionMeta{RuleFired: "USA|web", AnalyticsKey: "ab-test", ModelVersion: "v1"}
+
+	tests := []struct {
+		name                  string
+		resultFunc            ProcessedAuctionResultFunc
+		request               string
+		expectedRequest       string
+		expectedKey           []string
+		expectedDebugMessages []string
+	}{
+		{
+			name:                  "set_tmax",
+			resultFunc:            &SetTmax{Args: config.SetTmaxParams{Tmax: 300}},
+			request:               `{"id":"req","imp":[{"id":"imp1"}],"tmax":1000}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"tmax":300}`,
+			expectedKey:           []string{"bidrequest", "tmax"},
+			expectedDebugMessages: []string{"setTmax: tmax set to 300 (rule fired: USA|web)"},
+		},
+		{
+			name:                  "set_bid_floor_all_imps",
+			resultFunc:            &SetBidFloor{Args: config.SetBidFloorParams{Floor: 1.5, Currency: "USD"}},
+			request:               `{"id":"req","imp":[{"id":"imp1"},{"id":"imp2","bidfloor":0.5,"bidfloorcur":"EUR"}]}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1","bidfloor":1.5,"bidfloorcur":"USD"},{"id":"imp2","bidfloor":1.5,"bidfloorcur":"USD"}]}`,
+			expectedKey:           []string{"bidrequest", "imp", "bidfloor"},
+			expectedDebugMessages: []string{"setBidFloor: bid floor set to 1.5 USD for all imps (rule fired: USA|web)"},
+		},
+		{
+			name:                  "set_bid_floor_some_imps",
+			resultFunc:            &SetBidFloor{Args: config.SetBidFloorParams{Floor: 2, Currency: "EUR", ImpIDs: []string{"imp2"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1"},{"id":"imp2"}]}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"},{"id":"imp2","bidfloor":2,"bidfloorcur":"EUR"}]}`,
+			expectedKey:           []string{"bidrequest", "imp", "bidfloor"},
+			expectedDebugMessages: []string{"setBidFloor: bid floor set to 2 EUR for imps imp2 (rule fired: USA|web)"},
+		},
+		{
+			name:                  "add_bcat",
+			resultFunc:            &AddBcat{Args: config.AddBcatParams{Categories: []string{"IAB25", "IAB26"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1"}],"bcat":["IAB25"]}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"bcat":["IAB25","IAB26"]}`,
+			expectedKey:           []string{"bidrequest", "bcat"},
+			expectedDebugMessages: []string{"addBcat: categories IAB25, IAB26 added to bcat (rule fired: USA|web)"},
+		},
+		{
+			name:                  "add_badv",
+			resultFunc:            &AddBadv{Args: config.AddBadvParams{Domains: []string{"example.com"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1"}]}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"badv":["example.com"]}`,
+			expectedKey:           []string{"bidrequest", "badv"},
+			expectedDebugMessages: []string{"addBadv: domains example.com added to badv (rule fired: USA|web)"},
+		},
+		{
+			name:                  "remove_eids",
+			resultFunc:            &RemoveEids{Args: config.RemoveEidsParams{Sources: []string{"id5-sync.com"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1"}],"user":{"id":"user","eids":[{"source":"id5-sync.com","uids":[{"id":"1"}]},{"source":"uidapi.com","uids":[{"id":"2"}]}]}}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"user":{"id":"user","eids":[{"source":"uidapi.com","uids":[{"id":"2"}]}]}}`,
+			expectedKey:           []string{"bidrequest", "user", "eids"},
+			expectedDebugMessages: []string{"removeEids: eids of sources id5-sync.com removed (rule fired: USA|web)"},
+		},
+		{
+			name:                  "remove_eids_for_bidders_without_permissions",
+			resultFunc:            &RemoveEids{Args: config.RemoveEidsParams{Sources: []string{"id5-sync.com"}, Bidders: []string{"Bidder1"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1","ext":{"prebid":{"bidder":{"bidder1":{},"bidder2":{}}}}},{"id":"imp2","ext":{"prebid":{"bidder":{"bidder3":{}}}}}]}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1","ext":{"prebid":{"bidder":{"bidder1":{},"bidder2":{}}}}},{"id":"imp2","ext":{"prebid":{"bidder":{"bidder3":{}}}}}],"ext":{"prebid":{"data":{"eidpermissions":[{"source":"id5-sync.com","bidders":["bidder2","bidder3"]}]}}}}`,
+			expectedKey:           []string{"bidrequest", "ext", "prebid", "data", "eidpermissions"},
+			expectedDebugMessages: []string{"removeEids: eids of sources id5-sync.com removed for bidders Bidder1 (rule fired: USA|web)"},
+		},
+		{
+			name:                  "remove_eids_for_bidders_with_permissions",
+			resultFunc:            &RemoveEids{Args: config.RemoveEidsParams{Sources: []string{"id5-sync.com", "uidapi.com"}, Bidders: []string{"bidder1"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1","ext":{"prebid":{"bidder":{"bidder1":{},"bidder2":{}}}}}],"ext":{"prebid":{"data":{"eidpermissions":[{"source":"id5-sync.com","bidders":["bidder1","bidder4"]},{"source":"uidapi.com","bidders":["*"]}]}}}}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1","ext":{"prebid":{"bidder":{"bidder1":{},"bidder2":{}}}}}],"ext":{"prebid":{"data":{"eidpermissions":[{"source":"id5-sync.com","bidders":["bidder4"]},{"source":"uidapi.com","bidders":["bidder2"]}]}}}}`,
+			expectedKey:           []string{"bidrequest", "ext", "prebid", "data", "eidpermissions"},
+			expectedDebugMessages: []string{"removeEids: eids of sources id5-sync.com, uidapi.com removed for bidders bidder1 (rule fired: USA|web)"},
+		},
+		{
+			name:                  "remove_user_fpd",
+			resultFunc:            &RemoveUserFpd{},
+			request:               `{"id":"req","imp":[{"id":"imp1"}],"user":{"id":"user","yob":1980,"gender":"F","keywords":"k","kwarray":["k"],"data":[{"id":"data"}],"ext":{"data":{"k":"v"},"consent":"c"}}}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"user":{"id":"user","ext":{"consent":"c"}}}`,
+			expectedKey:           []string{"bidrequest", "user"},
+			expectedDebugMessages: []string{"removeUserFpd: user fpd removed (rule fired: USA|web)"},
+		},
+		{
+			name:                  "remove_user_fpd_for_bidders",
+			resultFunc:            &RemoveUserFpd{Args: config.RemoveUserFpdParams{Bidders: []string{"bidder1", "bidder3"}}},
+			request:               `{"id":"req","imp":[{"id":"imp1"}],"ext":{"prebid":{"bidderconfig":[{"bidders":["bidder1","bidder2"],"config":{"ortb2":{"user":{"id":"user","ext":{"consent":"c"}}}}}]}}}`,
+			expectedRequest:       `{"id":"req","imp":[{"id":"imp1"}],"ext":{"prebid":{"bidderconfig":[{"bidders":["bidder2"],"config":{"ortb2":{"user":{"id":"user","ext":{"consent":"c"}}}}},{"bidders":["bidder1"],"config":{"ortb2":{"user":{"data":null,"ext":{"consent":"c","data":null},"gender":null,"id":"user","keywords":null,"kwarray":null,"yob":0}}}},{"bidders":["bidder3"],"config":{"ortb2":{"user":{"data":null,"ext":{"data":null},"gender":null,"keywords":null,"kwarray":null,"yob":0}}}}]}}}`,
+			expectedKey:           []string{"bidrequest", "ext", "prebid", "bidderconfig"},
+			expectedDebugMessages: []string{"removeUserFpd: user fpd removed for bidders bidder1, bidder3 (rule fired: USA|web)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+			require.NoError(t, json.Unmarshal([]byte(tt.request), req.BidRequest))
+			result := &hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+
+			err := tt.resultFunc.Call(req, result, meta)
+			require.NoError(t, err)
+			require.Len(t, result.ChangeSet.Mutations(), 1)
+
+			mutation := result.ChangeSet.Mutations()[0]
+			assert.Equal(t, hs.MutationUpdate, mutation.Type())
+			assert.Equal(t, tt.expectedKey, mutation.Key())
+			assert.Equal(t, tt.expectedDebugMessages, result.DebugMessages)
+
+			_, err = mutation.Apply(hs.ProcessedAuctionRequestPayload{Request: req})
+			require.NoError(t, err)
+			require.NoError(t, req.RebuildRequest())
+			actualRequest, err := json.Marshal(req.BidRequest)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedRequest, string(actualRequest))
+		})
+	}
+}
+
+func TestRequestResultFunctionsCallNilRequest(t *testing.T) {
+	result := &hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+	st := &SetTmax{Args: config.SetTmaxParams{Tmax: 300}}
+
+	err := st.Call(nil, result, rules.ResultFunctionMeta{})
+	require.NoError(t, err)
+	require.Len(t, result.ChangeSet.Mutations(), 1)
+
+	_, err = result.ChangeSet.Mutations()[0].Apply(hs.ProcessedAuctionRequestPayload{})
+	assert.EqualError(t, err, "payload contains a nil bid request")
+}
+
+func TestLogATagCall(t *testing.T) {
+	lt := &LogATag{Args: config.LogATagParams{AnalyticsValue: "bucket-a"}}
+	result := &hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+	meta := rules.ResultFunctionMeta{RuleFired: "USA|web", AnalyticsKey: "ab-test", ModelVersion: "v1"}
+
+	err := lt.Call(mockRequestWrapperWithBidders(t, []string{"bidder1"}), result, meta)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations())
+	assert.Equal(t, []string{"logATag: analytics value bucket-a logged (rule fired: USA|web)"}, result.DebugMessages)
+	assert.Equal(t, hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   "pb-rules-engine",
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status: hookanalytics.ResultStatusAllow,
+						Values: map[string]interface{}{
+							"analyticsKey":   "ab-test",
+							"analyticsValue": "bucket-a",
+							"modelVersion":   "v1",
+							"conditionFired": "USA|web",
+							"resultFunction": "logATag",
+						},
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}, result.AnalyticsTags)
+}
+
+func TestRequestResultFunctionsName(t *testing.T) {
+	assert.Equal(t, SetTmaxName, (&SetTmax{}).Name())
+	assert.Equal(t, SetBidFloorName, (&SetBidFloor{}).Name())
+	assert.Equal(t, AddBcatName, (&AddBcat{}).Name())
+	assert.Equal(t, AddBadvName, (&AddBadv{}).Name())
+	assert.Equal(t, LogATagName, (&LogATag{}).Name())
+	assert.Equal(t, RemoveEidsName, (&RemoveEids{}).Name())
+	assert.Equal(t, RemoveUserFpdName, (&RemoveUserFpd{}).Name())
+}
@@ -27,6 +27,8 @@ type treeBuilder[T1 any, T2 any] struct {
 // Build function assumes the config is valid and the number of schema functions matches the number of conditions.
 func (tb *treeBuilder[T1, T2]) Build(tree *rules.Tree[T1, T2]) error {
 	currNode := tree.Root
+	tree.AnalyticsKey = tb.Config.AnalyticsKey
+	tree.ModelVersion = tb.Config.Version
 
 	defaultFunctions, err := tb.buildDefaultFunctions()
 	if err != nil {
@@ -121,6 +121,8 @@ func TestBuildTreeFullConfigNoErrors(t *testing.T) {
 	err = builder.Build(&tree)
 	assert.NoError(t, err, "tree builder error not expected")
 
+	assert.Equal(t, "ab-test", tree.AnalyticsKey, "analytics key mismatch")
+	assert.Equal(t, "1.0", tree.ModelVersion, "model version mismatch")
 	assert.Equal(t, 1, len(tree.DefaultFunctions), "default functions count mismatch")
 
 	assert.Equal(t, ExcludeBiddersName, tree.DefaultFunctions[0].Name(), "default function name mismatch")
@@ -159,6 +161,8 @@ func GetFullConf() json.RawMessage {
 
 	return json.RawMessage(`
  {
+     "analyticsKey": "ab-test",
+     "version": "1.0",
      "schema": [
      {
        "function": "deviceCountryIn",
@@ -3,6 +3,7 @@ package rulesengine
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/xeipuuv/gojsonschema"
@@ -20,6 +21,23 @@ type treeManager struct {
 	requests        chan buildInstruction
 	schemaValidator *gojsonschema.Schema
 	monitor         RulesEngineObserver
+	// pending holds the accounts whose build instruction is queued but not yet read
+	pending sync.Map
+}
+
+// requestBuild queues the build instruction of an account without waiting for the tree manager.
+// The hooks of every stage and bidder ask for the trees of an account missing from the cache, so
+// the instruction is only queued once per account and dropped if the queue is full, a later hook
+// call asking again.
+func (tm *treeManager) requestBuild(bi buildInstruction) {
+	if _, queued := tm.pending.LoadOrStore(bi.accountID, struct{}{}); queued {
+		return
+	}
+	select {
+	case tm.requests <- bi:
+	default:
+		tm.pending.Delete(bi.accountID)
+	}
 }
 
 // Run reads build instructions from a channel, and if the trees for the rule sets for a given account
@@ -28,6 +46,7 @@ func (tm *treeManager) Run(c cacher) error {
 	for {
 		select {
 		case req := <-tm.requests:
+			tm.pending.Delete(req.accountID)
 			if req.config == nil {
 				break
 			}
@@ -432,3 +432,23 @@ func getMalformedJsonConfig() *json.RawMessage {
 	rv := json.RawMessage(`malformed`)
 	return &rv
 }
+
+func TestTreeManagerRequestBuild(t *testing.T) {
+	config := json.RawMessage(`{}`)
+	tm := &treeManager{
+		requests: make(chan buildInstruction, 1),
+	}
+
+	tm.requestBuild(buildInstruction{accountID: "a", config: &config})
+	tm.requestBuild(buildInstruction{accountID: "a", config: &config})
+	assert.Len(t, tm.requests, 1, "the instruction of an account is queued once")
+
+	tm.requestBuild(buildInstruction{accountID: "b", config: &config})
+	assert.Len(t, tm.requests, 1, "the instruction is dropped when the queue is full")
+
+	<-tm.requests
+	tm.pending.Delete("a")
+
+	tm.requestBuild(buildInstruction{accountID: "b", config: &config})
+	assert.Len(t, tm.requests, 1, "a dropped instruction is queued again by a later call")
+}
@@ -0,0 +1,87 @@
+package trafficshaping
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+// trafficshaping module has only 1 activity: `traffic_shaping`, its results tell which bidders were filtered
+// or explored for each imp
+const shapingActivity = "traffic_shaping"
+
+const (
+	reasonDisabled       = "disabled"
+	reasonModelNotLoaded = "model_not_loaded"
+)
+
+// impDecision is the outcome of the model for the bidders of an imp.
+type impDecision struct {
+	impID    string
+	filtered []string
+	explored []string
+}
+
+func newShapingTags(modelVersion string, decisions []impDecision) hookanalytics.Analytics {
+	results := make([]hookanalytics.Result, 0, len(decisions))
+	for _, decision := range decisions {
+		status := hookanalytics.ResultStatusAllow
+		values := map[string]interface{}{"model_version": modelVersion}
+		if len(decision.filtered) > 0 {
+			status = hookanalytics.ResultStatusModify
+			values["filtered"] = decision.filtered
+		}
+		if len(decision.explored) > 0 {
+			values["explored"] = decision.explored
+		}
+		results = append(results, hookanalytics.Result{
+			Status:    status,
+			Values:    values,
+			AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{decision.impID}},
+		})
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:    shapingActivity,
+				Status:  hookanalytics.ActivityStatusSuccess,
+				Results: results,
+			},
+		},
+	}
+}
+
+func newSkippedTags(reason string) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusAllow,
+						Values:    map[string]interface{}{"reason": reason},
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newErrorTags(err error) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusError,
+				Results: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusError,
+						Values:    map[string]interface{}{"error": err.Error()},
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}
+}
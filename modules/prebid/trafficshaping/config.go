@@ -0,0 +1,130 @@
+package trafficshaping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+const (
+	defaultRefreshIntervalSec = 300
+	defaultFetchTimeoutMs     = 5000
+	defaultExplorationRate    = 0.05
+	defaultThreshold          = 0.01
+)
+
+type config struct {
+	// ModelPath is the path to the model file, exclusive with ModelURL
+	ModelPath string `json:"model_path"`
+	// ModelURL is the HTTP URL the model file is fetched from, exclusive with ModelPath
+	ModelURL string `json:"model_url"`
+	// RefreshIntervalSec is how often the model is checked for changes, a negative value disables the refresh
+	RefreshIntervalSec int `json:"refresh_interval_sec"`
+	// FetchTimeoutMs is the timeout of the requests fetching the model from ModelURL
+	FetchTimeoutMs int `json:"fetch_timeout_ms"`
+	// ExplorationRate is the share of the filtered imp and bidder pairs which are requested anyway, so the model
+	// can learn from their outcome
+	ExplorationRate *float64 `json:"exploration_rate"`
+	// Threshold is the bid likelihood below which a bidder isn't requested for an imp
+	Threshold *float64 `json:"threshold"`
+}
+
+// accountConfig is the account level config of the module, its values override the host config.
+type accountConfig struct {
+	Enabled         *bool    `json:"enabled"`
+	ExplorationRate *float64 `json:"exploration_rate"`
+	Threshold       *float64 `json:"threshold"`
+}
+
+func (c config) refreshInterval() time.Duration {
+	if c.RefreshIntervalSec < 0 {
+		return 0
+	}
+	if c.RefreshIntervalSec == 0 {
+		return defaultRefreshIntervalSec * time.Second
+	}
+	return time.Duration(c.RefreshIntervalSec) * time.Second
+}
+
+func (c config) fetchTimeout() time.Duration {
+	if c.FetchTimeoutMs <= 0 {
+		return defaultFetchTimeoutMs * time.Millisecond
+	}
+	return time.Duration(c.FetchTimeoutMs) * time.Millisecond
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if cfg.ModelPath == "" && cfg.ModelURL == "" {
+		return cfg, errors.New("one of model_path or model_url is required")
+	}
+	if cfg.ModelPath != "" && cfg.ModelURL != "" {
+		return cfg, errors.New("model_path and model_url cannot both be set")
+	}
+	if cfg.ExplorationRate == nil {
+		cfg.ExplorationRate = ptrutil.ToPtr(defaultExplorationRate)
+	}
+	if cfg.Threshold == nil {
+		cfg.Threshold = ptrutil.ToPtr(defaultThreshold)
+	}
+	if err := validateRates(*cfg.ExplorationRate, *cfg.Threshold); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// resolve returns the settings in effect for an account, the account config overrides the host config.
+func (c config) resolve(data json.RawMessage) (settings, error) {
+	s := settings{
+		enabled:         true,
+		explorationRate: *c.ExplorationRate,
+		threshold:       *c.Threshold,
+	}
+	if len(data) == 0 {
+		return s, nil
+	}
+
+	var account accountConfig
+	if err := jsonutil.UnmarshalValid(data, &account); err != nil {
+		return s, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	if account.Enabled != nil {
+		s.enabled = *account.Enabled
+	}
+	if account.ExplorationRate != nil {
+		s.explorationRate = *account.ExplorationRate
+	}
+	if account.Threshold != nil {
+		s.threshold = *account.Threshold
+	}
+	if err := validateRates(s.explorationRate, s.threshold); err != nil {
+		return s, fmt.Errorf("invalid account config: %s", err)
+	}
+	return s, nil
+}
+
+// settings are the settings of the module in effect for a request.
+type settings struct {
+	enabled         bool
+	explorationRate float64
+	threshold       float64
+}
+
+func validateRates(explorationRate, threshold float64) error {
+	if explorationRate < 0 || explorationRate > 1 {
+		return fmt.Errorf("exploration_rate must be between 0 and 1, got %v", explorationRate)
+	}
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %v", threshold)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package trafficshaping
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+// explorationPrecision is the precision of the exploration rate, 0.01%
+const explorationPrecision = 10000
+
+func handleProcessedAuctionHook(
+	m *model,
+	s settings,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+	rg randomutil.RandomGenerator,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	var result hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]
+
+	if !s.enabled {
+		result.AnalyticsTags = newSkippedTags(reasonDisabled)
+		return result, nil
+	}
+	if m == nil {
+		result.AnalyticsTags = newSkippedTags(reasonModelNotLoaded)
+		return result, nil
+	}
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		err := fmt.Errorf("payload contains a nil bid request")
+		result.AnalyticsTags = newErrorTags(err)
+		return result, err
+	}
+
+	keys := m.newKeyBuilder(payload.Request.BidRequest, miCtx.AccountID)
+	impIdToBidders := make(map[string]map[string]json.RawMessage)
+	var decisions []impDecision
+
+	for _, impWrapper := range payload.Request.GetImp() {
+		impExt, err := impWrapper.GetImpExt()
+		if err != nil {
+			result.AnalyticsTags = newErrorTags(err)
+			return result, err
+		}
+		impPrebid := impExt.GetPrebid()
+		if impPrebid == nil || len(impPrebid.Bidder) == 0 {
+			continue
+		}
+
+		key := keys.key(impWrapper.Imp)
+		decision := impDecision{impID: impWrapper.ID}
+		bidders := make(map[string]json.RawMessage, len(impPrebid.Bidder))
+		// sorted so the decisions of the imp are reported in the same order
+		for _, bidder := range sortedBidders(impPrebid.Bidder) {
+			if m.probability(bidder, key) < s.threshold {
+				if !explore(s.explorationRate, rg) {
+					decision.filtered = append(decision.filtered, bidder)
+					continue
+				}
+				decision.explored = append(decision.explored, bidder)
+			}
+			bidders[bidder] = impPrebid.Bidder[bidder]
+		}
+
+		if len(decision.filtered) > 0 {
+			impIdToBidders[impWrapper.ID] = bidders
+		}
+		if len(decision.filtered) > 0 || len(decision.explored) > 0 {
+			decisions = append(decisions, decision)
+		}
+	}
+
+	if len(impIdToBidders) > 0 {
+		result.ChangeSet.ProcessedAuctionRequest().Bidders().Update(impIdToBidders)
+	}
+	if len(decisions) > 0 {
+		result.AnalyticsTags = newShapingTags(m.version, decisions)
+	}
+	return result, nil
+}
+
+func sortedBidders(bidders map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(bidders))
+	for name := range bidders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// explore returns true if a bidder the model filtered should be requested anyway.
+func explore(explorationRate float64, rg randomutil.RandomGenerator) bool {
+	if explorationRate <= 0 {
+		return false
+	}
+	return rg.Intn(explorationPrecision) < int(explorationRate*explorationPrecision)
+}
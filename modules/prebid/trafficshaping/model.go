@@ -0,0 +1,241 @@
+package trafficshaping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Features of the model, the values of a feature missing from a request are unknownFeature.
+const (
+	featureAccount   = "account"
+	featurePublisher = "publisher"
+	featureCountry   = "country"
+	featurePlatform  = "platform"
+	featureSize      = "size"
+	featureMediaType = "media_type"
+
+	unknownFeature = "unknown"
+	keySeparator   = "|"
+)
+
+// impFeatures are the features whose values depend on the imp rather than the request.
+var impFeatures = map[string]bool{
+	featureSize:      true,
+	featureMediaType: true,
+}
+
+var requestFeatures = map[string]func(*openrtb2.BidRequest, string) string{
+	featureAccount:   func(_ *openrtb2.BidRequest, accountID string) string { return accountID },
+	featurePublisher: publisherFeature,
+	featureCountry:   countryFeature,
+	featurePlatform:  platformFeature,
+}
+
+// modelFile is the format of the model file.
+type modelFile struct {
+	// Version identifies the model in the analytics tags
+	Version string `json:"version"`
+	// Features are the features making up the keys of the probabilities, in order
+	Features []string `json:"features"`
+	// DefaultProbability is the bid likelihood of the bidders missing from the model, defaults to 1
+	DefaultProbability *float64 `json:"default_probability"`
+	// Bidders are the bid likelihoods by bidder
+	Bidders map[string]bidderModel `json:"bidders"`
+}
+
+type bidderModel struct {
+	// Default is the bid likelihood of the keys missing from Probabilities, defaults to the DefaultProbability
+	// of the model
+	Default *float64 `json:"default"`
+	// Probabilities are the bid likelihoods by key, the values of the features joined with "|"
+	Probabilities map[string]float64 `json:"probabilities"`
+}
+
+// model predicts the likelihood of a bidder to bid for an imp.
+type model struct {
+	version            string
+	features           []string
+	defaultProbability float64
+	bidders            map[string]bidderModel
+}
+
+func newModel(data []byte) (*model, error) {
+	var file modelFile
+	if err := jsonutil.UnmarshalValid(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %s", err)
+	}
+
+	if len(file.Features) == 0 {
+		return nil, fmt.Errorf("model has no features")
+	}
+	for _, feature := range file.Features {
+		if _, ok := requestFeatures[feature]; !ok && !impFeatures[feature] {
+			return nil, fmt.Errorf("model has an unknown feature %s", feature)
+		}
+	}
+
+	m := &model{
+		version:            file.Version,
+		features:           file.Features,
+		defaultProbability: 1,
+		bidders:            file.Bidders,
+	}
+	if file.DefaultProbability != nil {
+		m.defaultProbability = *file.DefaultProbability
+	}
+	if err := validateProbability(m.defaultProbability); err != nil {
+		return nil, fmt.Errorf("model default_probability %s", err)
+	}
+
+	for bidder, bidderModel := range file.Bidders {
+		if bidderModel.Default != nil {
+			if err := validateProbability(*bidderModel.Default); err != nil {
+				return nil, fmt.Errorf("model default of bidder %s %s", bidder, err)
+			}
+		}
+		for key, probability := range bidderModel.Probabilities {
+			if parts := strings.Count(key, keySeparator) + 1; parts != len(file.Features) {
+				return nil, fmt.Errorf("model key %s of bidder %s has %d values, expected %d", key, bidder, parts, len(file.Features))
+			}
+			if err := validateProbability(probability); err != nil {
+				return nil, fmt.Errorf("model probability of key %s of bidder %s %s", key, bidder, err)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func validateProbability(probability float64) error {
+	if probability < 0 || probability > 1 {
+		return fmt.Errorf("must be between 0 and 1, got %v", probability)
+	}
+	return nil
+}
+
+// probability returns the likelihood of the bidder to bid for the key.
+func (m *model) probability(bidder, key string) float64 {
+	bidderModel, ok := m.bidders[bidder]
+	if !ok {
+		return m.defaultProbability
+	}
+	if probability, ok := bidderModel.Probabilities[key]; ok {
+		return probability
+	}
+	if bidderModel.Default != nil {
+		return *bidderModel.Default
+	}
+	return m.defaultProbability
+}
+
+// keyBuilder builds the keys of the imps of a request, the values of the request features are computed once.
+type keyBuilder struct {
+	features []string
+	values   []string
+}
+
+func (m *model) newKeyBuilder(request *openrtb2.BidRequest, accountID string) keyBuilder {
+	values := make([]string, len(m.features))
+	for i, feature := range m.features {
+		if requestFeature, ok := requestFeatures[feature]; ok {
+			values[i] = orUnknown(requestFeature(request, accountID))
+		}
+	}
+	return keyBuilder{features: m.features, values: values}
+}
+
+// key returns the key of the imp, the values of the features joined with "|".
+func (b keyBuilder) key(imp *openrtb2.Imp) string {
+	var key strings.Builder
+	for i, feature := range b.features {
+		if i > 0 {
+			key.WriteString(keySeparator)
+		}
+		switch feature {
+		case featureSize:
+			key.WriteString(orUnknown(sizeFeature(imp)))
+		case featureMediaType:
+			key.WriteString(orUnknown(mediaTypeFeature(imp)))
+		default:
+			key.WriteString(b.values[i])
+		}
+	}
+	return key.String()
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return unknownFeature
+	}
+	return value
+}
+
+func publisherFeature(request *openrtb2.BidRequest, _ string) string {
+	switch {
+	case request.Site != nil && request.Site.Publisher != nil:
+		return request.Site.Publisher.ID
+	case request.App != nil && request.App.Publisher != nil:
+		return request.App.Publisher.ID
+	case request.DOOH != nil && request.DOOH.Publisher != nil:
+		return request.DOOH.Publisher.ID
+	}
+	return ""
+}
+
+func countryFeature(request *openrtb2.BidRequest, _ string) string {
+	if request.Device != nil && request.Device.Geo != nil {
+		return request.Device.Geo.Country
+	}
+	return ""
+}
+
+func platformFeature(request *openrtb2.BidRequest, _ string) string {
+	switch {
+	case request.App != nil:
+		return "app"
+	case request.DOOH != nil:
+		return "dooh"
+	case request.Site != nil:
+		return "site"
+	}
+	return ""
+}
+
+// sizeFeature returns the first size of the imp, e.g. 300x250.
+func sizeFeature(imp *openrtb2.Imp) string {
+	switch {
+	case imp.Banner != nil && len(imp.Banner.Format) > 0:
+		return formatSize(imp.Banner.Format[0].W, imp.Banner.Format[0].H)
+	case imp.Banner != nil && imp.Banner.W != nil && imp.Banner.H != nil:
+		return formatSize(*imp.Banner.W, *imp.Banner.H)
+	case imp.Video != nil && imp.Video.W != nil && imp.Video.H != nil:
+		return formatSize(*imp.Video.W, *imp.Video.H)
+	}
+	return ""
+}
+
+func formatSize(w, h int64) string {
+	return strconv.FormatInt(w, 10) + "x" + strconv.FormatInt(h, 10)
+}
+
+// mediaTypeFeature returns the media types of the imp joined with "+", e.g. banner+video.
+func mediaTypeFeature(imp *openrtb2.Imp) string {
+	var mediaTypes []string
+	if imp.Audio != nil {
+		mediaTypes = append(mediaTypes, "audio")
+	}
+	if imp.Banner != nil {
+		mediaTypes = append(mediaTypes, "banner")
+	}
+	if imp.Native != nil {
+		mediaTypes = append(mediaTypes, "native")
+	}
+	if imp.Video != nil {
+		mediaTypes = append(mediaTypes, "video")
+	}
+	return strings.Join(mediaTypes, "+")
+}
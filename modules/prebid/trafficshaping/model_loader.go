@@ -0,0 +1,180 @@
+package trafficshaping
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// fileVersion identifies a version of the model file, a file failing to load is not retried until it changes again.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// modelLoader keeps the latest version of the model in memory.
+// The model is fetched from a local file or an HTTP URL and periodically refreshed, so a new model can be
+// published without restarting the server. A model failing to load is logged and the previous version is kept
+// in use. Until a model is loaded, the bidders are not filtered.
+type modelLoader struct {
+	path    string
+	url     string
+	client  *http.Client
+	timeout time.Duration
+	current atomic.Pointer[model]
+	// fileVersion and etag are only accessed by the goroutine refreshing the model
+	fileVersion fileVersion
+	etag        string
+	done        chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup
+}
+
+func newModelLoader(cfg config, client *http.Client) *modelLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	l := &modelLoader{
+		path:    cfg.ModelPath,
+		url:     cfg.ModelURL,
+		client:  client,
+		timeout: cfg.fetchTimeout(),
+		done:    make(chan struct{}),
+	}
+
+	if _, err := l.refresh(); err != nil {
+		glog.Errorf("[trafficshaping] Failed to load model %s: %v", l.source(), err)
+	}
+
+	if refreshInterval := cfg.refreshInterval(); refreshInterval > 0 {
+		l.wg.Add(1)
+		go l.watch(refreshInterval)
+	}
+
+	return l
+}
+
+// get returns the most recently loaded model, nil if no model has been loaded yet.
+func (l *modelLoader) get() *model {
+	return l.current.Load()
+}
+
+func (l *modelLoader) source() string {
+	if l.path != "" {
+		return l.path
+	}
+	return l.url
+}
+
+func (l *modelLoader) watch(refreshInterval time.Duration) {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			refreshed, err := l.refresh()
+			if err != nil {
+				glog.Errorf("[trafficshaping] Failed to refresh model %s: %v", l.source(), err)
+			} else if refreshed {
+				glog.Infof("[trafficshaping] Refreshed model %s", l.source())
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// refresh loads the model if it changed since the last attempt to load it.
+func (l *modelLoader) refresh() (bool, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if l.path != "" {
+		data, err = l.readFile()
+	} else {
+		data, err = l.fetch()
+	}
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	m, err := newModel(data)
+	if err != nil {
+		return false, err
+	}
+
+	l.current.Store(m)
+	return true, nil
+}
+
+// readFile returns the model file, nil if it didn't change.
+func (l *modelLoader) readFile() ([]byte, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat model file: %w", err)
+	}
+
+	version := fileVersion{modTime: info.ModTime(), size: info.Size()}
+	if version.modTime.Equal(l.fileVersion.modTime) && version.size == l.fileVersion.size {
+		return nil, nil
+	}
+	l.fileVersion = version
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+	return data, nil
+}
+
+// fetch returns the model fetched from the URL, nil if it didn't change according to its ETag.
+func (l *modelLoader) fetch() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model request: %w", err)
+	}
+	if l.etag != "" {
+		req.Header.Set("If-None-Match", l.etag)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch model: unexpected status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model response: %w", err)
+	}
+	l.etag = resp.Header.Get("ETag")
+	return data, nil
+}
+
+func (l *modelLoader) stop() {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		l.wg.Wait()
+	})
+}
@@ -0,0 +1,180 @@
+package trafficshaping
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModel = `{
+	"version": "2026-10-18",
+	"features": ["account", "country", "size"],
+	"bidders": {
+		"appnexus": {
+			"probabilities": {"1234|USA|300x250": 0.001, "1234|USA|728x90": 0.5}
+		},
+		"rubicon": {
+			"default": 0.002,
+			"probabilities": {"1234|FRA|300x250": 0.3}
+		}
+	}
+}`
+
+func TestNewModel(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          string
+		expectedModel *model
+		expectedError string
+	}{
+		{
+			description: "valid",
+			data:        `{"version": "1", "features": ["platform", "media_type"], "default_probability": 0.5, "bidders": {"appnexus": {"probabilities": {"app|video": 0.1}}}}`,
+			expectedModel: &model{
+				version:            "1",
+				features:           []string{"platform", "media_type"},
+				defaultProbability: 0.5,
+				bidders:            map[string]bidderModel{"appnexus": {Probabilities: map[string]float64{"app|video": 0.1}}},
+			},
+		},
+		{
+			description:   "invalid_json",
+			data:          `{"features": "account"}`,
+			expectedError: "failed to parse model: cannot unmarshal trafficshaping.modelFile.Features",
+		},
+		{
+			description:   "no_features",
+			data:          `{"bidders": {}}`,
+			expectedError: "model has no features",
+		},
+		{
+			description:   "unknown_feature",
+			data:          `{"features": ["account", "os"]}`,
+			expectedError: "model has an unknown feature os",
+		},
+		{
+			description:   "invalid_default_probability",
+			data:          `{"features": ["account"], "default_probability": 2}`,
+			expectedError: "model default_probability must be between 0 and 1, got 2",
+		},
+		{
+			description:   "invalid_bidder_default",
+			data:          `{"features": ["account"], "bidders": {"appnexus": {"default": -1}}}`,
+			expectedError: "model default of bidder appnexus must be between 0 and 1, got -1",
+		},
+		{
+			description:   "invalid_key",
+			data:          `{"features": ["account", "country"], "bidders": {"appnexus": {"probabilities": {"1234": 0.1}}}}`,
+			expectedError: "model key 1234 of bidder appnexus has 1 values, expected 2",
+		},
+		{
+			description:   "invalid_probability",
+			data:          `{"features": ["account"], "bidders": {"appnexus": {"probabilities": {"1234": 1.5}}}}`,
+			expectedError: "model probability of key 1234 of bidder appnexus must be between 0 and 1, got 1.5",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m, err := newModel([]byte(test.data))
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				assert.Nil(t, m)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedModel, m)
+		})
+	}
+}
+
+func TestModelProbability(t *testing.T) {
+	m, err := newModel([]byte(testModel))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description         string
+		bidder              string
+		key                 string
+		expectedProbability float64
+	}{
+		{
+			description:         "key",
+			bidder:              "appnexus",
+			key:                 "1234|USA|300x250",
+			expectedProbability: 0.001,
+		},
+		{
+			description:         "missing_key_model_default",
+			bidder:              "appnexus",
+			key:                 "5678|USA|300x250",
+			expectedProbability: 1,
+		},
+		{
+			description:         "missing_key_bidder_default",
+			bidder:              "rubicon",
+			key:                 "1234|USA|300x250",
+			expectedProbability: 0.002,
+		},
+		{
+			description:         "missing_bidder",
+			bidder:              "pubmatic",
+			key:                 "1234|USA|300x250",
+			expectedProbability: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedProbability, m.probability(test.bidder, test.key))
+		})
+	}
+}
+
+func TestModelKey(t *testing.T) {
+	m := &model{features: []string{"account", "publisher", "country", "platform", "size", "media_type"}}
+
+	testCases := []struct {
+		description string
+		request     *openrtb2.BidRequest
+		imp         *openrtb2.Imp
+		expectedKey string
+	}{
+		{
+			description: "site_banner_format",
+			request: &openrtb2.BidRequest{
+				Site:   &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: "pub"}},
+				Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+			},
+			imp:         &openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 300, H: 600}}}},
+			expectedKey: "1234|pub|USA|site|300x250|banner",
+		},
+		{
+			description: "app_banner_and_video",
+			request:     &openrtb2.BidRequest{App: &openrtb2.App{Publisher: &openrtb2.Publisher{ID: "pub"}}},
+			imp:         &openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}, Video: &openrtb2.Video{}},
+			expectedKey: "1234|pub|unknown|app|320x50|banner+video",
+		},
+		{
+			description: "dooh_video",
+			request:     &openrtb2.BidRequest{DOOH: &openrtb2.DOOH{}},
+			imp:         &openrtb2.Imp{Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](1920), H: ptrutil.ToPtr[int64](1080)}},
+			expectedKey: "1234|unknown|unknown|dooh|1920x1080|video",
+		},
+		{
+			description: "unknown",
+			request:     &openrtb2.BidRequest{},
+			imp:         &openrtb2.Imp{Native: &openrtb2.Native{}},
+			expectedKey: "1234|unknown|unknown|unknown|unknown|native",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedKey, m.newKeyBuilder(test.request, "1234").key(test.imp))
+		})
+	}
+}
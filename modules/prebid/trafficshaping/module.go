@@ -0,0 +1,52 @@
+package trafficshaping
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+// Builder starts loading the model configured for the module and refreshing it periodically.
+func Builder(rawConfig json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return Module{
+		cfg:    cfg,
+		model:  newModelLoader(cfg, deps.HTTPClient),
+		random: randomutil.RandomNumberGenerator{},
+	}, nil
+}
+
+// Module filters the bidders which are unlikely to bid for an imp, according to a model predicting the bid
+// likelihood per bidder and combination of request features.
+type Module struct {
+	cfg    config
+	model  *modelLoader
+	random randomutil.RandomGenerator
+}
+
+// HandleProcessedAuctionHook removes the bidders whose bid likelihood is below the threshold from the imps,
+// except for a share of them defined by the exploration rate.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	s, err := m.cfg.resolve(miCtx.AccountConfig)
+	if err != nil {
+		return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{AnalyticsTags: newErrorTags(err)}, err
+	}
+	return handleProcessedAuctionHook(m.model.get(), s, miCtx, payload, m.random)
+}
+
+// Shutdown stops refreshing the model.
+func (m Module) Shutdown() error {
+	m.model.stop()
+	return nil
+}
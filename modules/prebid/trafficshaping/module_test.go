@@ -0,0 +1,365 @@
+package trafficshaping
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRandomGenerator implements randomutil.RandomGenerator for testing
+type fakeRandomGenerator struct {
+	values []int
+}
+
+func (g *fakeRandomGenerator) Intn(n int) int {
+	value := g.values[0]
+	g.values = g.values[1:]
+	return value
+}
+
+func (g *fakeRandomGenerator) GenerateInt63() int64 {
+	return 0
+}
+
+func writeTestModel(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.json")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func TestBuilder(t *testing.T) {
+	path := writeTestModel(t, testModel)
+
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedModel bool
+		expectedError string
+	}{
+		{
+			description:   "valid_config",
+			config:        json.RawMessage(`{"model_path": "` + path + `", "refresh_interval_sec": 1}`),
+			expectedModel: true,
+		},
+		{
+			description: "missing_model_file",
+			config:      json.RawMessage(`{"model_path": "` + path + `.missing"}`),
+		},
+		{
+			description:   "missing_model",
+			config:        json.RawMessage(`{}`),
+			expectedError: "one of model_path or model_url is required",
+		},
+		{
+			description:   "path_and_url",
+			config:        json.RawMessage(`{"model_path": "` + path + `", "model_url": "http://models.com/model.json"}`),
+			expectedError: "model_path and model_url cannot both be set",
+		},
+		{
+			description:   "invalid_exploration_rate",
+			config:        json.RawMessage(`{"model_path": "` + path + `", "exploration_rate": 2}`),
+			expectedError: "exploration_rate must be between 0 and 1, got 2",
+		},
+		{
+			description:   "invalid_config",
+			config:        json.RawMessage(`{"model_path": 1}`),
+			expectedError: "failed to parse config: cannot unmarshal trafficshaping.config.ModelPath",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module, err := Builder(test.config, moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			m := module.(Module)
+			defer m.Shutdown()
+			assert.Equal(t, test.expectedModel, m.model.get() != nil, "a model failing to load should not prevent the module from starting")
+		})
+	}
+}
+
+func TestConfigResolve(t *testing.T) {
+	cfg, err := newConfig(json.RawMessage(`{"model_url": "http://models.com/model.json"}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description      string
+		accountConfig    json.RawMessage
+		expectedSettings settings
+		expectedError    string
+	}{
+		{
+			description:      "host_defaults",
+			expectedSettings: settings{enabled: true, explorationRate: 0.05, threshold: 0.01},
+		},
+		{
+			description:      "account_overrides",
+			accountConfig:    json.RawMessage(`{"enabled": false, "exploration_rate": 0.1, "threshold": 0.2}`),
+			expectedSettings: settings{enabled: false, explorationRate: 0.1, threshold: 0.2},
+		},
+		{
+			description:   "invalid_account_config",
+			accountConfig: json.RawMessage(`{"enabled": "yes"}`),
+			expectedError: "failed to parse account config",
+		},
+		{
+			description:   "invalid_account_threshold",
+			accountConfig: json.RawMessage(`{"threshold": -1}`),
+			expectedError: "invalid account config: threshold must be between 0 and 1, got -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			s, err := cfg.resolve(test.accountConfig)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedSettings, s)
+		})
+	}
+}
+
+func newTestPayload() hookstage.ProcessedAuctionRequestPayload {
+	return hookstage.ProcessedAuctionRequestPayload{
+		Request: &openrtb_ext.RequestWrapper{
+			BidRequest: &openrtb2.BidRequest{
+				Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+				Imp: []openrtb2.Imp{
+					{
+						ID:     "imp1",
+						Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+						Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1},"rubicon":{"zoneId":2},"pubmatic":{"adSlot":"3"}}}}`),
+					},
+					{
+						ID:     "imp2",
+						Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 728, H: 90}}},
+						Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":4}}}}`),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	m, err := newModel([]byte(testModel))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description     string
+		model           *model
+		settings        settings
+		random          []int
+		expectedBidders map[string][]string
+		expectedTags    hookanalytics.Analytics
+	}{
+		{
+			description:     "disabled",
+			model:           m,
+			settings:        settings{enabled: false, threshold: 0.01},
+			expectedBidders: map[string][]string{"imp1": {"appnexus", "pubmatic", "rubicon"}, "imp2": {"appnexus"}},
+			expectedTags:    newSkippedTags(reasonDisabled),
+		},
+		{
+			description:     "model_not_loaded",
+			settings:        settings{enabled: true, threshold: 0.01},
+			expectedBidders: map[string][]string{"imp1": {"appnexus", "pubmatic", "rubicon"}, "imp2": {"appnexus"}},
+			expectedTags:    newSkippedTags(reasonModelNotLoaded),
+		},
+		{
+			description:     "filtered",
+			model:           m,
+			settings:        settings{enabled: true, threshold: 0.01},
+			expectedBidders: map[string][]string{"imp1": {"pubmatic"}, "imp2": {"appnexus"}},
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{{
+					Status:    hookanalytics.ResultStatusModify,
+					Values:    map[string]interface{}{"model_version": "2026-10-18", "filtered": []string{"appnexus", "rubicon"}},
+					AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp1"}},
+				}},
+			}}},
+		},
+		{
+			description:     "explored",
+			model:           m,
+			settings:        settings{enabled: true, explorationRate: 0.1, threshold: 0.01},
+			random:          []int{999, 1000},
+			expectedBidders: map[string][]string{"imp1": {"appnexus", "pubmatic"}, "imp2": {"appnexus"}},
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{{
+					Status:    hookanalytics.ResultStatusModify,
+					Values:    map[string]interface{}{"model_version": "2026-10-18", "filtered": []string{"rubicon"}, "explored": []string{"appnexus"}},
+					AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp1"}},
+				}},
+			}}},
+		},
+		{
+			description:     "all_explored",
+			model:           m,
+			settings:        settings{enabled: true, explorationRate: 1, threshold: 0.01},
+			random:          []int{0, 0},
+			expectedBidders: map[string][]string{"imp1": {"appnexus", "pubmatic", "rubicon"}, "imp2": {"appnexus"}},
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{{
+					Status:    hookanalytics.ResultStatusAllow,
+					Values:    map[string]interface{}{"model_version": "2026-10-18", "explored": []string{"appnexus", "rubicon"}},
+					AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp1"}},
+				}},
+			}}},
+		},
+		{
+			description:     "threshold",
+			model:           m,
+			settings:        settings{enabled: true, threshold: 0.6},
+			expectedBidders: map[string][]string{"imp1": {"pubmatic"}, "imp2": {}},
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   shapingActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusModify,
+						Values:    map[string]interface{}{"model_version": "2026-10-18", "filtered": []string{"appnexus", "rubicon"}},
+						AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp1"}},
+					},
+					{
+						Status:    hookanalytics.ResultStatusModify,
+						Values:    map[string]interface{}{"model_version": "2026-10-18", "filtered": []string{"appnexus"}},
+						AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp2"}},
+					},
+				},
+			}}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := newTestPayload()
+			miCtx := hookstage.ModuleInvocationContext{AccountID: "1234"}
+
+			result, err := handleProcessedAuctionHook(test.model, test.settings, miCtx, payload, &fakeRandomGenerator{values: test.random})
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedTags, result.AnalyticsTags)
+
+			for _, mutation := range result.ChangeSet.Mutations() {
+				payload, err = mutation.Apply(payload)
+				require.NoError(t, err)
+			}
+			bidders := make(map[string][]string)
+			for _, imp := range payload.Request.GetImp() {
+				impExt, err := imp.GetImpExt()
+				require.NoError(t, err)
+				bidders[imp.ID] = sortedBidders(impExt.GetPrebid().Bidder)
+			}
+			assert.Equal(t, test.expectedBidders, bidders)
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHookAccountConfig(t *testing.T) {
+	module, err := Builder(json.RawMessage(`{"model_path": "`+writeTestModel(t, testModel)+`", "refresh_interval_sec": -1}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	m := module.(Module)
+	defer m.Shutdown()
+
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{AccountID: "1234", AccountConfig: json.RawMessage(`{"enabled": false}`)}, newTestPayload())
+	assert.NoError(t, err)
+	assert.Equal(t, newSkippedTags(reasonDisabled), result.AnalyticsTags)
+
+	_, err = m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{AccountID: "1234", AccountConfig: json.RawMessage(`{"threshold": 2}`)}, newTestPayload())
+	assert.EqualError(t, err, "invalid account config: threshold must be between 0 and 1, got 2")
+}
+
+func TestModelLoaderFile(t *testing.T) {
+	path := writeTestModel(t, testModel)
+	l := newModelLoader(config{ModelPath: path, RefreshIntervalSec: -1}, nil)
+	defer l.stop()
+
+	first := l.get()
+	require.NotNil(t, first)
+	assert.Equal(t, "2026-10-18", first.version)
+
+	refreshed, err := l.refresh()
+	assert.NoError(t, err)
+	assert.False(t, refreshed, "the unchanged file should not be reloaded")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "2", "features": "invalid"}`), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	_, err = l.refresh()
+	assert.ErrorContains(t, err, "failed to parse model")
+	assert.Same(t, first, l.get(), "the previous model should be kept if the file fails to load")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "3", "features": ["account"]}`), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+	refreshed, err = l.refresh()
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+	assert.Equal(t, "3", l.get().version)
+}
+
+func TestModelLoaderURL(t *testing.T) {
+	body := testModel
+	status := http.StatusOK
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == `"v1"` && body == testModel {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	l := newModelLoader(config{ModelURL: server.URL, RefreshIntervalSec: -1}, server.Client())
+	defer l.stop()
+	require.NotNil(t, l.get())
+	assert.Equal(t, "2026-10-18", l.get().version)
+
+	refreshed, err := l.refresh()
+	assert.NoError(t, err)
+	assert.False(t, refreshed, "the model should not be reloaded if not modified")
+	require.Len(t, requests, 2)
+	assert.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"))
+
+	status = http.StatusInternalServerError
+	body = "error"
+	_, err = l.refresh()
+	assert.EqualError(t, err, "failed to fetch model: unexpected status code 500")
+	assert.Equal(t, "2026-10-18", l.get().version, "the previous model should be kept if the fetch fails")
+
+	status = http.StatusOK
+	body = `{"version": "2", "features": ["country"]}`
+	refreshed, err = l.refresh()
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+	assert.Equal(t, "2", l.get().version)
+}
@@ -128,6 +128,7 @@ var coreBidderNames []BidderName = []BidderName{
 	BidderFRVRAdNetwork,
 	BidderGamma,
 	BidderGamoshi,
+	BidderGenericORTB,
 	BidderGlobalsun,
 	BidderGothamads,
 	BidderGrid,
@@ -496,6 +497,7 @@ const (
 	BidderFRVRAdNetwork     BidderName = "frvradn"
 	BidderGamma             BidderName = "gamma"
 	BidderGamoshi           BidderName = "gamoshi"
+	BidderGenericORTB       BidderName = "genericortb"
 	BidderGlobalsun         BidderName = "globalsun"
 	BidderGothamads         BidderName = "gothamads"
 	BidderGrid              BidderName = "grid"
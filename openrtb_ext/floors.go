@@ -1,6 +1,7 @@
 package openrtb_ext
 
 import (
+	"encoding/json"
 	"maps"
 	"slices"
 
@@ -14,6 +15,7 @@ const (
 	FetchError      = "error"
 	FetchInprogress = "inprogress"
 	FetchNone       = "none"
+	FetchStale      = "stale"
 )
 
 // Defines strings for PriceFloorLocation
@@ -100,6 +102,25 @@ type PriceFloorModelGroup struct {
 	Schema       PriceFloorSchema   `json:"schema,omitempty"`
 	Values       map[string]float64 `json:"values,omitempty"`
 	Default      float64            `json:"default,omitempty"`
+	Model        *PriceFloorModel   `json:"model,omitempty"`
+}
+
+// PriceFloorModel defines a model computing the floors of a model group from the request features at enforcement
+// time, in place of the rules of the model group. Type is the name of the floor provider evaluating the model and
+// Definition is the model in the format of the provider.
+type PriceFloorModel struct {
+	Type       string          `json:"type,omitempty"`
+	Definition json.RawMessage `json:"definition,omitempty"`
+}
+
+func (m *PriceFloorModel) DeepCopy() *PriceFloorModel {
+	if m == nil {
+		return nil
+	}
+	return &PriceFloorModel{
+		Type:       m.Type,
+		Definition: slices.Clone(m.Definition),
+	}
 }
 
 func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
@@ -108,6 +129,7 @@ func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
 	newMg.ModelVersion = mg.ModelVersion
 	newMg.SkipRate = mg.SkipRate
 	newMg.Default = mg.Default
+	newMg.Model = mg.Model.DeepCopy()
 	if mg.ModelWeight != nil {
 		newMg.ModelWeight = new(int)
 		*newMg.ModelWeight = *mg.ModelWeight
@@ -185,6 +207,7 @@ func (data *PriceFloorData) DeepCopy() *PriceFloorData {
 		eachGroup.SkipRate = data.ModelGroups[i].SkipRate
 		eachGroup.Values = maps.Clone(data.ModelGroups[i].Values)
 		eachGroup.Default = data.ModelGroups[i].Default
+		eachGroup.Model = data.ModelGroups[i].Model.DeepCopy()
 		eachGroup.Schema = PriceFloorSchema{
 			Fields:    slices.Clone(data.ModelGroups[i].Schema.Fields),
 			Delimiter: data.ModelGroups[i].Schema.Delimiter,
@@ -1,6 +1,7 @@
 package openrtb_ext
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -480,3 +481,19 @@ func TestFloorRuleDeepCopyNil(t *testing.T) {
 		t.Errorf("PriceFloorRules.DeepCopy() = %v, want %v", got, nil)
 	}
 }
+
+func TestPriceFloorModelDeepCopy(t *testing.T) {
+	model := &PriceFloorModel{Type: "gbt", Definition: json.RawMessage(`{"version":"v1"}`)}
+	mg := PriceFloorModelGroup{ModelVersion: "v1", Model: model}
+
+	data := (&PriceFloorData{ModelGroups: []PriceFloorModelGroup{mg}}).DeepCopy()
+	copied := mg.Copy()
+
+	for _, got := range []*PriceFloorModel{data.ModelGroups[0].Model, copied.Model} {
+		assert.Equal(t, model, got)
+		assert.NotSame(t, model, got)
+		got.Definition[0] = '['
+		assert.Equal(t, json.RawMessage(`{"version":"v1"}`), model.Definition)
+	}
+	assert.Nil(t, (*PriceFloorModel)(nil).DeepCopy())
+}
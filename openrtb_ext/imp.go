@@ -69,6 +69,7 @@ type ExtImpPrebidFloors struct {
 	FloorValue     float64 `json:"floorvalue,omitempty"`
 	FloorMin       float64 `json:"floormin,omitempty"`
 	FloorMinCur    string  `json:"floorminCur,omitempty"`
+	ModelVersion   string  `json:"modelversion,omitempty"`
 }
 
 // ExtStoredRequest defines the contract for bidrequest.imp[i].ext.prebid.storedrequest
@@ -68,6 +68,7 @@ type ExtRequestPrebid struct {
 	StoredRequest        *ExtStoredRequest               `json:"storedrequest,omitempty"`
 	SupportDeals         bool                            `json:"supportdeals,omitempty"`
 	Targeting            *ExtRequestTargeting            `json:"targeting,omitempty"`
+	UserSync             *ExtRequestPrebidUserSync       `json:"usersync,omitempty"`
 
 	//AlternateBidderCodes is populated with host's AlternateBidderCodes config if not defined in request
 	AlternateBidderCodes *ExtAlternateBidderCodes `json:"alternatebiddercodes,omitempty"`
@@ -156,6 +157,12 @@ type ExtRequestPrebidServer struct {
 	DataCenter  string `json:"datacenter"`
 }
 
+// ExtRequestPrebidUserSync defines the contract for bidrequest.ext.prebid.usersync, asking for the user syncs
+// of the requested bidders without a UID to be returned in bidresponse.ext.prebid.usersync
+type ExtRequestPrebidUserSync struct {
+	Limit *int `json:"limit,omitempty"`
+}
+
 // ExtRequestPrebidCacheBids defines the contract for bidrequest.ext.prebid.cache.bids
 type ExtRequestPrebidCacheBids struct {
 	ReturnCreative *bool `json:"returnCreative,omitempty"`
@@ -513,6 +520,10 @@ func (erp *ExtRequestPrebid) Clone() *ExtRequestPrebid {
 		clone.Targeting = newTargeting
 	}
 
+	if erp.UserSync != nil {
+		clone.UserSync = &ExtRequestPrebidUserSync{Limit: ptrutil.Clone(erp.UserSync.Limit)}
+	}
+
 	clone.NoSale = slices.Clone(erp.NoSale)
 
 	if erp.AlternateBidderCodes != nil {
@@ -763,6 +763,19 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 				prebid.AdServerTargeting = nil
 			},
 		},
+		{
+			name: "UserSync",
+			prebid: &ExtRequestPrebid{
+				UserSync: &ExtRequestPrebidUserSync{Limit: ptrutil.ToPtr(2)},
+			},
+			prebidCopy: &ExtRequestPrebid{
+				UserSync: &ExtRequestPrebidUserSync{Limit: ptrutil.ToPtr(2)},
+			},
+			mutator: func(t *testing.T, prebid *ExtRequestPrebid) {
+				*prebid.UserSync.Limit = 5
+				prebid.UserSync = nil
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -31,6 +31,21 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest json.RawMessage `json:"resolvedrequest,omitempty"`
+	// Privacy defines the contract for bidresponse.ext.debug.privacy
+	Privacy *ExtResponsePrivacyDebug `json:"privacy,omitempty"`
+}
+
+// ExtResponsePrivacyDebug defines the contract for bidresponse.ext.debug.privacy
+type ExtResponsePrivacyDebug struct {
+	USNat *ExtResponseUSNatDebug `json:"usnat,omitempty"`
+}
+
+// ExtResponseUSNatDebug defines the contract for bidresponse.ext.debug.privacy.usnat, the enforcement
+// of the GPP US National or state section applicable to the request
+type ExtResponseUSNatDebug struct {
+	SID    int8     `json:"sid"`
+	Denied []string `json:"denied,omitempty"`
+	Errors []string `json:"errors,omitempty"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -49,6 +64,16 @@ type ExtResponsePrebid struct {
 	Targeting        map[string]string `json:"targeting,omitempty"`
 	// SeatNonBid holds the array of Bids which are either rejected, no bids inside bidresponse.ext.prebid.seatnonbid
 	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
+	// UserSync holds the user syncs of the bidders without a UID, when requested by bidrequest.ext.prebid.usersync
+	UserSync []*ExtResponseUserSync `json:"usersync,omitempty"`
+}
+
+// ExtResponseUserSync defines the contract for bidresponse.ext.prebid.usersync
+type ExtResponseUserSync struct {
+	Bidder      string `json:"bidder"`
+	URL         string `json:"url"`
+	Type        string `json:"type"`
+	SupportCORS bool   `json:"supportCORS,omitempty"`
 }
 
 // FledgeResponse defines the contract for bidresponse.ext.fledge
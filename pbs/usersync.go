@@ -23,6 +23,7 @@ type UserSyncDeps struct {
 	RecaptchaSecret  string
 	HostCookieConfig *config.HostCookie
 	PriorityGroups   [][]string
+	UIDCodec         usersync.Codec
 }
 
 // Struct for parsing json in google's response
@@ -59,8 +60,10 @@ func (deps *UserSyncDeps) VerifyRecaptcha(response string) error {
 func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	optout := r.FormValue("optout")
 	rr := r.FormValue("g-recaptcha-response")
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+	var codec usersync.Codec = usersync.Base64Codec{}
+	if deps.UIDCodec != nil {
+		codec = deps.UIDCodec
+	}
 
 	if rr == "" {
 		http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), http.StatusMovedPermanently)
@@ -77,12 +80,12 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 	}
 
 	// Read Cookie
-	pc := usersync.ReadCookie(r, decoder, deps.HostCookieConfig)
+	pc := usersync.ReadCookie(r, codec, deps.HostCookieConfig)
 	usersync.SyncHostCookie(r, pc, deps.HostCookieConfig)
 	pc.SetOptOut(optout != "")
 
 	// Write Cookie
-	encodedCookie, err := encoder.Encode(pc)
+	encodedCookie, err := codec.Encode(pc)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
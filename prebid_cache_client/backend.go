@@ -0,0 +1,299 @@
+package prebid_cache_client
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+
+	"github.com/golang/glog"
+)
+
+// Entry is a value stored in a Backend.
+type Entry struct {
+	Key  string          `json:"-"`
+	Type PayloadType     `json:"type"`
+	Data json.RawMessage `json:"value"`
+	// TTL is the duration the entry is kept for
+	TTL time.Duration `json:"-"`
+}
+
+// Backend stores the cache entries when PBS caches the values itself instead of calling Prebid Cache.
+// The entries are served by the GET /cache endpoint of PBS.
+type Backend interface {
+	// Put stores the entries, replacing the entries having the same keys.
+	Put(ctx context.Context, entries []Entry) error
+	// Get returns the entry of the key, false if it is missing or expired.
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	// Shutdown releases the resources of the backend.
+	Shutdown()
+}
+
+// NewBackend returns the backend configured, nil if the values are cached in Prebid Cache.
+func NewBackend(cfg *config.CacheBackend) Backend {
+	switch cfg.Type {
+	case config.CacheBackendLocal:
+		glog.Infof("Caching the bids in memory, up to %d entries", cfg.Local.MaxEntries)
+		return NewLocalBackend(cfg.Local.MaxEntries)
+	case config.CacheBackendRedis:
+		glog.Infof("Caching the bids in the Redis server %s", cfg.Redis.Address)
+		return NewRedisBackend(redis.NewClient(cfg.Redis), cfg.Redis.Namespace+":cache:")
+	default:
+		return nil
+	}
+}
+
+// NewBackendClient returns a Client storing the values in the backend. The values without key get a random UUID,
+// and the TTL of the values is the default TTL if unset, capped to the max TTL.
+func NewBackendClient(backend Backend, conf *config.CacheBackend, extCache *config.ExternalCache, metrics metrics.MetricsEngine) Client {
+	return &backendClient{
+		backend:             backend,
+		uuidGenerator:       uuidutil.UUIDRandomGenerator{},
+		defaultTTL:          time.Duration(conf.TTLSeconds) * time.Second,
+		maxTTL:              time.Duration(conf.MaxTTLSeconds) * time.Second,
+		externalCacheScheme: extCache.Scheme,
+		externalCacheHost:   extCache.Host,
+		externalCachePath:   extCache.Path,
+		metrics:             metrics,
+	}
+}
+
+type backendClient struct {
+	backend             Backend
+	uuidGenerator       uuidutil.UUIDGenerator
+	defaultTTL          time.Duration
+	maxTTL              time.Duration
+	externalCacheScheme string
+	externalCacheHost   string
+	externalCachePath   string
+	metrics             metrics.MetricsEngine
+}
+
+func (c *backendClient) GetExtCacheData() (string, string, string) {
+	return getExtCacheData(c.externalCacheScheme, c.externalCacheHost, c.externalCachePath)
+}
+
+func (c *backendClient) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
+	errs = make([]error, 0, 1)
+	if len(values) < 1 {
+		return nil, errs
+	}
+
+	uuidsToReturn := make([]string, len(values))
+
+	ctx, span := tracing.StartSpan(ctx, "prebid_cache.put", tracing.SpanKindInternal,
+		tracing.Int("pbs.cache_items", len(values)))
+	defer func() {
+		if len(errs) > 0 {
+			span.SetError(errs[0])
+		}
+		span.End()
+	}()
+
+	entries := make([]Entry, 0, len(values))
+	keys := make([]string, len(values))
+	for i, value := range values {
+		key := value.Key
+		if key == "" {
+			var err error
+			if key, err = c.uuidGenerator.Generate(); err != nil {
+				logError(&errs, "Error generating the cache key: %v", err)
+				continue
+			}
+		}
+		keys[i] = key
+		entries = append(entries, Entry{
+			Key:  key,
+			Type: value.Type,
+			Data: value.Data,
+			TTL:  c.ttl(value.TTLSeconds),
+		})
+	}
+
+	startTime := time.Now()
+	err := c.backend.Put(ctx, entries)
+	elapsedTime := time.Since(startTime)
+	if err != nil {
+		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
+		logError(&errs, "Error caching the values: %v; Duration=%v, Items=%v", err, elapsedTime, len(values))
+		return uuidsToReturn, errs
+	}
+	c.metrics.RecordPrebidCacheRequestTime(true, elapsedTime)
+
+	return keys, errs
+}
+
+func (c *backendClient) ttl(ttlSeconds int64) time.Duration {
+	if ttlSeconds <= 0 {
+		return c.defaultTTL
+	}
+	if ttl := time.Duration(ttlSeconds) * time.Second; ttl < c.maxTTL {
+		return ttl
+	}
+	return c.maxTTL
+}
+
+var errLocalBackendFull = errors.New("the local cache is full")
+
+type localEntry struct {
+	Entry
+	expiresAt time.Time
+}
+
+// expiration is the time an entry of the local backend expires at
+type expiration struct {
+	key       string
+	expiresAt time.Time
+}
+
+// expirationQueue orders the expirations of the local backend, the earliest first
+type expirationQueue []expiration
+
+func (q expirationQueue) Len() int {
+	return len(q)
+}
+
+func (q expirationQueue) Less(i, j int) bool {
+	return q[i].expiresAt.Before(q[j].expiresAt)
+}
+
+func (q expirationQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *expirationQueue) Push(element interface{}) {
+	*q = append(*q, element.(expiration))
+}
+
+func (q *expirationQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	element := old[n-1]
+	*q = old[0 : n-1]
+	return element
+}
+
+// NewLocalBackend returns a Backend keeping up to maxEntries entries in memory.
+// When full, the expired entries are evicted and the entries which don't fit are rejected.
+func NewLocalBackend(maxEntries int) Backend {
+	return &localBackend{
+		entries:    make(map[string]localEntry),
+		maxEntries: maxEntries,
+		time:       &timeutil.RealTime{},
+	}
+}
+
+type localBackend struct {
+	mutex   sync.RWMutex
+	entries map[string]localEntry
+	// expirations holds the expiration of every entry stored, including the entries replaced since, so the
+	// expired entries are evicted without scanning all the entries
+	expirations expirationQueue
+	maxEntries  int
+	time        timeutil.Time
+}
+
+func (b *localBackend) Put(_ context.Context, entries []Entry) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.time.Now()
+	if len(b.entries)+len(entries) > b.maxEntries {
+		b.evictExpired(now)
+		if len(b.entries)+len(entries) > b.maxEntries {
+			return errLocalBackendFull
+		}
+	}
+
+	for _, entry := range entries {
+		expiresAt := now.Add(entry.TTL)
+		b.entries[entry.Key] = localEntry{Entry: entry, expiresAt: expiresAt}
+		heap.Push(&b.expirations, expiration{key: entry.Key, expiresAt: expiresAt})
+	}
+	return nil
+}
+
+func (b *localBackend) Get(_ context.Context, key string) (Entry, bool, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	entry, ok := b.entries[key]
+	if !ok || !b.time.Now().Before(entry.expiresAt) {
+		return Entry{}, false, nil
+	}
+	return entry.Entry, true, nil
+}
+
+// evictExpired removes the entries expired, in the order they expire. The expiration of an entry replaced since
+// is dropped without removing the entry.
+func (b *localBackend) evictExpired(now time.Time) {
+	for len(b.expirations) > 0 && !now.Before(b.expirations[0].expiresAt) {
+		expired := heap.Pop(&b.expirations).(expiration)
+		if entry, ok := b.entries[expired.key]; ok && entry.expiresAt.Equal(expired.expiresAt) {
+			delete(b.entries, expired.key)
+		}
+	}
+}
+
+func (b *localBackend) Shutdown() {}
+
+// NewRedisBackend returns a Backend storing the entries in a Redis-compatible server, under the keys prefixed with keyPrefix.
+// The entries are shared by all the PBS instances using the server.
+func NewRedisBackend(client *redis.Client, keyPrefix string) Backend {
+	return &redisBackend{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+type redisBackend struct {
+	client    redis.KeyValueClient
+	keyPrefix string
+}
+
+func (b *redisBackend) Put(ctx context.Context, entries []Entry) error {
+	values := make([]redis.KeyValue, 0, len(entries))
+	for _, entry := range entries {
+		value, err := jsonutil.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("unable to marshal the entry %s: %v", entry.Key, err)
+		}
+		values = append(values, redis.KeyValue{
+			Key:        b.keyPrefix + entry.Key,
+			Value:      value,
+			TTLSeconds: int(entry.TTL.Seconds()),
+		})
+	}
+	return b.client.Set(ctx, values)
+}
+
+func (b *redisBackend) Get(ctx context.Context, key string) (Entry, bool, error) {
+	values, err := b.client.Get(ctx, []string{b.keyPrefix + key})
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if values[0] == nil {
+		return Entry{}, false, nil
+	}
+
+	entry := Entry{Key: key}
+	if err := jsonutil.UnmarshalValid(values[0], &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("invalid cache entry %s: %v", key, err)
+	}
+	return entry, true, nil
+}
+
+func (b *redisBackend) Shutdown() {
+	b.client.Close()
+}
@@ -0,0 +1,233 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (t *fakeTime) Now() time.Time {
+	return t.time
+}
+
+type fakeUUIDGenerator struct {
+	ids []string
+	err error
+}
+
+func (g *fakeUUIDGenerator) Generate() (string, error) {
+	if g.err != nil {
+		return "", g.err
+	}
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+	return id, nil
+}
+
+func TestNewBackend(t *testing.T) {
+	assert.Nil(t, NewBackend(&config.CacheBackend{Type: config.CacheBackendPrebidCache}))
+	assert.IsType(t, &localBackend{}, NewBackend(&config.CacheBackend{Type: config.CacheBackendLocal, Local: config.LocalCacheBackend{MaxEntries: 10}}))
+
+	backend := NewBackend(&config.CacheBackend{Type: config.CacheBackendRedis, Redis: config.RedisCache{Address: "localhost:6379", Namespace: "pbs", PoolSize: 1}})
+	if assert.IsType(t, &redisBackend{}, backend) {
+		assert.Equal(t, "pbs:cache:", backend.(*redisBackend).keyPrefix)
+		backend.Shutdown()
+	}
+}
+
+func TestBackendClientPutJson(t *testing.T) {
+	backend := NewLocalBackend(10)
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", true, mock.Anything).Once()
+
+	client := NewBackendClient(backend, &config.CacheBackend{TTLSeconds: 300, MaxTTLSeconds: 600}, &config.ExternalCache{Host: "pbs.prebid.org", Path: "cache"}, metricsMock).(*backendClient)
+	client.uuidGenerator = &fakeUUIDGenerator{ids: []string{"uuid-1", "uuid-2"}}
+
+	ids, errs := client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeJSON, Data: json.RawMessage(`{"id":"bid"}`)},
+		{Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`), TTLSeconds: 3600},
+		{Type: TypeJSON, Data: json.RawMessage(`true`), Key: "custom-key", TTLSeconds: 60},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"uuid-1", "uuid-2", "custom-key"}, ids)
+
+	entries := backend.(*localBackend).entries
+	assert.Equal(t, 300*time.Second, entries["uuid-1"].TTL, "the entries without TTL should get the default TTL")
+	assert.Equal(t, 600*time.Second, entries["uuid-2"].TTL, "the TTL should be capped")
+	assert.Equal(t, 60*time.Second, entries["custom-key"].TTL)
+
+	entry, found, err := backend.Get(context.Background(), "uuid-2")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Entry{Key: "uuid-2", Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`), TTL: 600 * time.Second}, entry)
+
+	scheme, host, path := client.GetExtCacheData()
+	assert.Equal(t, []string{"", "pbs.prebid.org", "/cache"}, []string{scheme, host, path})
+	metricsMock.AssertExpectations(t)
+}
+
+func TestBackendClientPutJsonErrors(t *testing.T) {
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", false, mock.Anything).Once()
+
+	client := NewBackendClient(NewLocalBackend(1), &config.CacheBackend{TTLSeconds: 300, MaxTTLSeconds: 600}, &config.ExternalCache{}, metricsMock).(*backendClient)
+	client.uuidGenerator = &fakeUUIDGenerator{ids: []string{"uuid-1", "uuid-2"}}
+
+	ids, errs := client.PutJson(context.Background(), nil)
+	assert.Empty(t, ids)
+	assert.Empty(t, errs)
+
+	ids, errs = client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeJSON, Data: json.RawMessage(`true`)},
+		{Type: TypeJSON, Data: json.RawMessage(`false`)},
+	})
+	assert.Equal(t, []string{"", ""}, ids)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "Error caching the values: the local cache is full")
+	}
+	metricsMock.AssertExpectations(t)
+
+	metricsMock.On("RecordPrebidCacheRequestTime", true, mock.Anything).Once()
+	client.uuidGenerator = &fakeUUIDGenerator{err: errors.New("no entropy")}
+	ids, errs = client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeJSON, Data: json.RawMessage(`true`)},
+		{Type: TypeJSON, Data: json.RawMessage(`false`), Key: "custom-key"},
+	})
+	assert.Equal(t, []string{"", "custom-key"}, ids, "only the value failing to get a key should fail")
+	assert.Len(t, errs, 1)
+}
+
+func TestLocalBackend(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeTime{time: now}
+	backend := NewLocalBackend(2).(*localBackend)
+	backend.time = clock
+
+	assert.NoError(t, backend.Put(ctx, []Entry{
+		{Key: "one", Type: TypeJSON, Data: json.RawMessage(`1`), TTL: time.Minute},
+		{Key: "two", Type: TypeJSON, Data: json.RawMessage(`2`), TTL: time.Hour},
+	}))
+
+	entry, found, err := backend.Get(ctx, "one")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, json.RawMessage(`1`), entry.Data)
+
+	_, found, _ = backend.Get(ctx, "three")
+	assert.False(t, found, "a missing entry should not be found")
+
+	assert.Equal(t, errLocalBackendFull, backend.Put(ctx, []Entry{{Key: "three", TTL: time.Minute}}))
+
+	clock.time = now.Add(time.Minute)
+	_, found, _ = backend.Get(ctx, "one")
+	assert.False(t, found, "an expired entry should not be found")
+
+	assert.NoError(t, backend.Put(ctx, []Entry{{Key: "three", Data: json.RawMessage(`3`), TTL: time.Minute}}), "the expired entries should be evicted when full")
+	assert.Len(t, backend.entries, 2)
+	_, found, _ = backend.Get(ctx, "three")
+	assert.True(t, found)
+}
+
+func TestLocalBackendEvictsReplacedEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeTime{time: now}
+	backend := NewLocalBackend(2).(*localBackend)
+	backend.time = clock
+
+	assert.NoError(t, backend.Put(ctx, []Entry{{Key: "one", Data: json.RawMessage(`1`), TTL: time.Minute}}))
+	assert.NoError(t, backend.Put(ctx, []Entry{{Key: "one", Data: json.RawMessage(`2`), TTL: time.Hour}}))
+
+	clock.time = now.Add(time.Minute)
+	assert.Equal(t, errLocalBackendFull, backend.Put(ctx, []Entry{{Key: "two", TTL: time.Minute}, {Key: "three", TTL: time.Minute}}))
+	entry, found, _ := backend.Get(ctx, "one")
+	assert.True(t, found, "the entry replaced should not be evicted on the expiration of its previous value")
+	assert.Equal(t, json.RawMessage(`2`), entry.Data)
+	assert.Len(t, backend.expirations, 1, "the expiration of the previous value should be dropped")
+
+	clock.time = now.Add(time.Hour)
+	assert.NoError(t, backend.Put(ctx, []Entry{{Key: "two", TTL: time.Minute}, {Key: "three", TTL: time.Minute}}))
+	_, found, _ = backend.Get(ctx, "one")
+	assert.False(t, found)
+	assert.Len(t, backend.entries, 2)
+	assert.Len(t, backend.expirations, 2)
+}
+
+type mockRedisClient struct {
+	values map[string]redis.KeyValue
+	err    error
+	closed bool
+}
+
+func (c *mockRedisClient) Set(_ context.Context, values []redis.KeyValue) error {
+	if c.err != nil {
+		return c.err
+	}
+	for _, value := range values {
+		c.values[value.Key] = value
+	}
+	return nil
+}
+
+func (c *mockRedisClient) Get(_ context.Context, keys []string) ([][]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if value, ok := c.values[key]; ok {
+			values[i] = value.Value
+		}
+	}
+	return values, nil
+}
+
+func (c *mockRedisClient) Close() {
+	c.closed = true
+}
+
+func TestRedisBackend(t *testing.T) {
+	ctx := context.Background()
+	client := &mockRedisClient{values: make(map[string]redis.KeyValue)}
+	backend := &redisBackend{client: client, keyPrefix: "pbs:cache:"}
+
+	assert.NoError(t, backend.Put(ctx, []Entry{
+		{Key: "one", Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`), TTL: 5 * time.Minute},
+	}))
+	assert.Equal(t, redis.KeyValue{Key: "pbs:cache:one", Value: []byte(`{"type":"xml","value":"<VAST></VAST>"}`), TTLSeconds: 300}, client.values["pbs:cache:one"])
+
+	entry, found, err := backend.Get(ctx, "one")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Entry{Key: "one", Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`)}, entry)
+
+	_, found, err = backend.Get(ctx, "two")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	client.values["pbs:cache:invalid"] = redis.KeyValue{Value: []byte("invalid")}
+	_, _, err = backend.Get(ctx, "invalid")
+	assert.Error(t, err)
+
+	client.err = errors.New("redis: server marked as unavailable")
+	assert.Error(t, backend.Put(ctx, []Entry{{Key: "one"}}))
+	_, _, err = backend.Get(ctx, "one")
+	assert.Error(t, err)
+
+	backend.Shutdown()
+	assert.True(t, client.closed)
+}
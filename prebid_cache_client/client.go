@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/tracing"
 
 	"github.com/buger/jsonparser"
 	"github.com/golang/glog"
@@ -51,20 +53,32 @@ type Cacheable struct {
 	Timestamp int64  `json:"timestamp,omitempty"` // this is "/vtrack" specific
 }
 
+// NewClient returns a Client sending the values to Prebid Cache. The failed requests are retried and hedged
+// to a secondary Prebid Cache as configured, which must share the store of the primary one as the cached
+// values are always read from the external cache host.
 func NewClient(httpClient *http.Client, conf *config.Cache, extCache *config.ExternalCache, metrics metrics.MetricsEngine) Client {
-	return &clientImpl{
+	c := &clientImpl{
 		httpClient:          httpClient,
 		putUrl:              conf.GetBaseURL() + "/cache",
+		retry:               conf.Retry,
 		externalCacheScheme: extCache.Scheme,
 		externalCacheHost:   extCache.Host,
 		externalCachePath:   extCache.Path,
 		metrics:             metrics,
 	}
+	if conf.Hedge.SecondaryURL != "" {
+		c.hedgeUrl = strings.TrimSuffix(conf.Hedge.SecondaryURL, "/") + "/cache"
+		c.hedgeDelay = time.Duration(conf.Hedge.DelayMs) * time.Millisecond
+	}
+	return c
 }
 
 type clientImpl struct {
 	httpClient          *http.Client
 	putUrl              string
+	hedgeUrl            string
+	hedgeDelay          time.Duration
+	retry               config.CacheRetry
 	externalCacheScheme string
 	externalCacheHost   string
 	externalCachePath   string
@@ -72,7 +86,10 @@ type clientImpl struct {
 }
 
 func (c *clientImpl) GetExtCacheData() (string, string, string) {
-	path := c.externalCachePath
+	return getExtCacheData(c.externalCacheScheme, c.externalCacheHost, c.externalCachePath)
+}
+
+func getExtCacheData(scheme, host, path string) (string, string, string) {
 	if path == "/" {
 		// Only the slash for the path, remove it to empty
 		path = ""
@@ -81,7 +98,7 @@ func (c *clientImpl) GetExtCacheData() (string, string, string) {
 		path = "/" + path
 	}
 
-	return c.externalCacheScheme, c.externalCacheHost, path
+	return scheme, host, path
 }
 
 func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
@@ -92,35 +109,36 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 
 	uuidsToReturn := make([]string, len(values))
 
+	ctx, span := tracing.StartSpan(ctx, "prebid_cache.put", tracing.SpanKindClient,
+		tracing.String("http.url", c.putUrl),
+		tracing.Int("pbs.cache_items", len(values)))
+	defer func() {
+		if len(errs) > 0 {
+			span.SetError(errs[0])
+		}
+		span.End()
+	}()
+
 	postBody, err := encodeValues(values)
 	if err != nil {
 		logError(&errs, "Error creating JSON for prebid cache: %v", err)
 		return uuidsToReturn, errs
 	}
 
-	httpReq, err := http.NewRequest("POST", c.putUrl, bytes.NewReader(postBody))
-	if err != nil {
-		logError(&errs, "Error creating POST request to prebid cache: %v", err)
-		return uuidsToReturn, errs
-	}
-
-	httpReq.Header.Add("Content-Type", "application/json;charset=utf-8")
-	httpReq.Header.Add("Accept", "application/json")
-
 	startTime := time.Now()
-	anResp, err := ctxhttp.Do(ctx, c.httpClient, httpReq)
+	response := c.send(ctx, postBody)
 	elapsedTime := time.Since(startTime)
-	if err != nil {
+	if response.err != nil {
 		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
-		logError(&errs, "Error sending the request to Prebid Cache: %v; Duration=%v, Items=%v, Payload Size=%v", err, elapsedTime, len(values), len(postBody))
+		logError(&errs, "Error sending the request to Prebid Cache: %v; Duration=%v, Items=%v, Payload Size=%v", response.err, elapsedTime, len(values), len(postBody))
 		return uuidsToReturn, errs
 	}
-	defer anResp.Body.Close()
+	span.SetAttributes(tracing.Int("http.status_code", response.statusCode))
 
-	responseBody, err := io.ReadAll(anResp.Body)
-	if anResp.StatusCode != 200 || err != nil {
+	responseBody := response.body
+	if response.statusCode != 200 {
 		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
-		logError(&errs, "Prebid Cache call to %s returned %d: %s", c.putUrl, anResp.StatusCode, responseBody)
+		logError(&errs, "Prebid Cache call to %s returned %d: %s", response.url, response.statusCode, responseBody)
 		return uuidsToReturn, errs
 	}
 	c.metrics.RecordPrebidCacheRequestTime(true, elapsedTime)
@@ -148,6 +166,134 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 	return uuidsToReturn, errs
 }
 
+// cacheResponse is the outcome of a request to Prebid Cache
+type cacheResponse struct {
+	url        string
+	statusCode int
+	body       []byte
+	err        error
+}
+
+func (r cacheResponse) succeeded() bool {
+	return r.err == nil && r.statusCode == http.StatusOK
+}
+
+// send posts the body to Prebid Cache, retrying the requests failing or returning a 5xx status
+// as long as the backoff fits in the time left.
+func (c *clientImpl) send(ctx context.Context, body []byte) cacheResponse {
+	response := c.hedgedPost(ctx, body)
+	for retry := 1; retry <= c.retry.MaxRetries && isRetryable(ctx, response); retry++ {
+		backoff := c.backoff(retry)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response
+		case <-timer.C:
+		}
+		response = c.hedgedPost(ctx, body)
+	}
+	return response
+}
+
+func isRetryable(ctx context.Context, response cacheResponse) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return response.err != nil || response.statusCode >= http.StatusInternalServerError
+}
+
+// backoff returns a random duration up to the exponential backoff of the retry, capped to the max backoff
+func (c *clientImpl) backoff(retry int) time.Duration {
+	backoff := time.Duration(c.retry.BackoffMs) * time.Millisecond << (retry - 1)
+	maxBackoff := time.Duration(c.retry.MaxBackoffMs) * time.Millisecond
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// hedgedPost posts the body to the primary Prebid Cache and, if hedging is enabled, to the secondary one
+// when the primary hasn't responded after the hedge delay or failed. The first success is returned,
+// or the first failure if both fail.
+func (c *clientImpl) hedgedPost(ctx context.Context, body []byte) cacheResponse {
+	if c.hedgeUrl == "" {
+		return c.post(ctx, c.putUrl, body)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make(chan cacheResponse, 2)
+	go func() {
+		responses <- c.post(ctx, c.putUrl, body)
+	}()
+
+	hedgeTimer := time.NewTimer(c.hedgeDelay)
+	defer hedgeTimer.Stop()
+
+	pending, hedged := 1, false
+	hedge := func() {
+		hedged = true
+		pending++
+		go func() {
+			responses <- c.post(ctx, c.hedgeUrl, body)
+		}()
+	}
+
+	var firstFailure *cacheResponse
+	for {
+		select {
+		case <-hedgeTimer.C:
+			if !hedged {
+				hedge()
+			}
+		case response := <-responses:
+			pending--
+			if response.succeeded() {
+				return response
+			}
+			if firstFailure == nil {
+				firstFailure = &response
+			}
+			if !hedged && ctx.Err() == nil {
+				hedge()
+			} else if pending == 0 {
+				return *firstFailure
+			}
+		}
+	}
+}
+
+func (c *clientImpl) post(ctx context.Context, url string, body []byte) cacheResponse {
+	response := cacheResponse{url: url}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		response.err = fmt.Errorf("error creating POST request to prebid cache: %v", err)
+		return response
+	}
+
+	httpReq.Header.Add("Content-Type", "application/json;charset=utf-8")
+	httpReq.Header.Add("Accept", "application/json")
+	tracing.Inject(ctx, httpReq.Header)
+
+	httpResp, err := ctxhttp.Do(ctx, c.httpClient, httpReq)
+	if err != nil {
+		response.err = err
+		return response
+	}
+	defer httpResp.Body.Close()
+
+	response.statusCode = httpResp.StatusCode
+	response.body, response.err = io.ReadAll(httpResp.Body)
+	return response
+}
+
 func logError(errs *[]error, format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
 	glog.Error(msg)
@@ -5,10 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
@@ -169,6 +173,234 @@ func TestSuccessfulPut(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+func TestNewClientHedge(t *testing.T) {
+	client := NewClient(&http.Client{}, &config.Cache{
+		Scheme: "https",
+		Host:   "cache.prebid.org",
+		Hedge:  config.CacheHedge{SecondaryURL: "https://cache2.prebid.org/", DelayMs: 30},
+	}, &config.ExternalCache{}, &metricsConf.NilMetricsEngine{}).(*clientImpl)
+
+	assert.Equal(t, "https://cache.prebid.org/cache", client.putUrl)
+	assert.Equal(t, "https://cache2.prebid.org/cache", client.hedgeUrl)
+	assert.Equal(t, 30*time.Millisecond, client.hedgeDelay)
+}
+
+func TestPutRetries(t *testing.T) {
+	testCases := []struct {
+		description     string
+		statuses        []int
+		maxRetries      int
+		expectedCalls   int
+		expectedSuccess bool
+	}{
+		{
+			description:     "server_error_retried",
+			statuses:        []int{500, 503, 200},
+			maxRetries:      2,
+			expectedCalls:   3,
+			expectedSuccess: true,
+		},
+		{
+			description:     "retries_exhausted",
+			statuses:        []int{500, 500, 500},
+			maxRetries:      1,
+			expectedCalls:   2,
+			expectedSuccess: false,
+		},
+		{
+			description:     "client_error_not_retried",
+			statuses:        []int{400, 200},
+			maxRetries:      2,
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+		{
+			description:     "no_retries",
+			statuses:        []int{500, 200},
+			maxRetries:      0,
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := test.statuses[calls.Add(1)-1]
+				if status != http.StatusOK {
+					w.WriteHeader(status)
+					return
+				}
+				newHandler(1)(w, r)
+			}))
+			defer server.Close()
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordPrebidCacheRequestTime", test.expectedSuccess, mock.Anything).Once()
+
+			client := &clientImpl{
+				httpClient: server.Client(),
+				putUrl:     server.URL,
+				retry:      config.CacheRetry{MaxRetries: test.maxRetries, BackoffMs: 1, MaxBackoffMs: 2},
+				metrics:    metricsMock,
+			}
+			ids, errs := client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+
+			assert.Equal(t, test.expectedCalls, int(calls.Load()))
+			if test.expectedSuccess {
+				assert.Equal(t, []string{"0"}, ids)
+				assert.Empty(t, errs)
+			} else {
+				assert.Equal(t, []string{""}, ids)
+				assert.Len(t, errs, 1)
+			}
+			metricsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPutRetryWithinDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &clientImpl{
+		httpClient: server.Client(),
+		putUrl:     server.URL,
+		retry:      config.CacheRetry{MaxRetries: 3, BackoffMs: 10000, MaxBackoffMs: 10000},
+		metrics:    &metricsConf.NilMetricsEngine{},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+	_, errs := client.PutJson(ctx, []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+	assert.Len(t, errs, 1)
+	assert.Less(t, time.Since(startTime), 500*time.Millisecond, "a retry whose backoff may exceed the deadline should not be waited for")
+	assert.Equal(t, 1, int(calls.Load()))
+}
+
+func TestBackoff(t *testing.T) {
+	client := &clientImpl{retry: config.CacheRetry{MaxRetries: 5, BackoffMs: 10, MaxBackoffMs: 25}}
+	for i := 0; i < 20; i++ {
+		assert.LessOrEqual(t, client.backoff(1), 10*time.Millisecond)
+		assert.LessOrEqual(t, client.backoff(2), 20*time.Millisecond)
+		assert.LessOrEqual(t, client.backoff(3), 25*time.Millisecond)
+		assert.LessOrEqual(t, client.backoff(64), 25*time.Millisecond)
+	}
+}
+
+func TestPutHedged(t *testing.T) {
+	testCases := []struct {
+		description string
+		primary     http.HandlerFunc
+		hedgeDelay  time.Duration
+		expectedIDs []string
+	}{
+		{
+			description: "primary_responds_before_delay",
+			primary:     newHandler(1),
+			hedgeDelay:  time.Second,
+			expectedIDs: []string{"0"},
+		},
+		{
+			description: "primary_slow",
+			primary: func(w http.ResponseWriter, r *http.Request) {
+				// the request is only canceled once the body is read
+				io.ReadAll(r.Body)
+				<-r.Context().Done()
+			},
+			hedgeDelay:  10 * time.Millisecond,
+			expectedIDs: []string{"secondary"},
+		},
+		{
+			description: "primary_fails",
+			primary: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			hedgeDelay:  time.Second,
+			expectedIDs: []string{"secondary"},
+		},
+	}
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responses":[{"uuid":"secondary"}]}`))
+	}))
+	defer secondary.Close()
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			primary := httptest.NewServer(test.primary)
+			defer primary.Close()
+
+			client := &clientImpl{
+				httpClient: &http.Client{},
+				putUrl:     primary.URL,
+				hedgeUrl:   secondary.URL,
+				hedgeDelay: test.hedgeDelay,
+				metrics:    &metricsConf.NilMetricsEngine{},
+			}
+			ids, errs := client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+			assert.Equal(t, test.expectedIDs, ids)
+			assert.Empty(t, errs)
+		})
+	}
+}
+
+func TestPutHedgedExtCacheData(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responses":[{"uuid":"secondary"}]}`))
+	}))
+	defer secondary.Close()
+
+	client := NewClient(&http.Client{}, &config.Cache{
+		Scheme: "http",
+		Host:   strings.TrimPrefix(primary.URL, "http://"),
+		Hedge:  config.CacheHedge{SecondaryURL: secondary.URL, DelayMs: 1000},
+	}, &config.ExternalCache{Scheme: "https", Host: "cache.prebid.org", Path: "/cache"}, &metricsConf.NilMetricsEngine{})
+
+	ids, errs := client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+	assert.Equal(t, []string{"secondary"}, ids)
+	assert.Empty(t, errs)
+
+	scheme, host, path := client.GetExtCacheData()
+	assert.Equal(t, "https", scheme)
+	assert.Equal(t, "cache.prebid.org", host, "the values cached by the secondary cache should be read from the shared external host")
+	assert.Equal(t, "/cache", path)
+}
+
+func TestPutHedgedBothFail(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer secondary.Close()
+
+	client := &clientImpl{
+		httpClient: &http.Client{},
+		putUrl:     primary.URL,
+		hedgeUrl:   secondary.URL,
+		hedgeDelay: time.Second,
+		metrics:    &metricsConf.NilMetricsEngine{},
+	}
+	ids, errs := client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+	assert.Equal(t, []string{""}, ids)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "Prebid Cache call to "+primary.URL+" returned 502", "the failure of the primary cache should be reported")
+	}
+}
+
 func TestEncodeValueToBuffer(t *testing.T) {
 	buf := new(bytes.Buffer)
 	testCache := Cacheable{
@@ -40,24 +40,41 @@ type ActivityControl struct {
 	plans      map[Activity]ActivityPlan
 	IPv6Config config.IPv6
 	IPv4Config config.IPv4
+	usNat      *usNatEvaluator
 }
 
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil || (cfg.AllowActivities == nil && !cfg.USNat.Enabled) {
 		return ac
 	}
 
-	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
+	var activities config.AllowActivities
+	if cfg.AllowActivities != nil {
+		activities = *cfg.AllowActivities
+	}
+
+	plans := make(map[Activity]ActivityPlan, 9)
+	plans[ActivitySyncUser] = buildPlan(activities.SyncUser)
+	plans[ActivityFetchBids] = buildPlan(activities.FetchBids)
+	plans[ActivityEnrichUserFPD] = buildPlan(activities.EnrichUserFPD)
+	plans[ActivityReportAnalytics] = buildPlan(activities.ReportAnalytics)
+	plans[ActivityTransmitUserFPD] = buildPlan(activities.TransmitUserFPD)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(activities.TransmitPreciseGeo)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(activities.TransmitUniqueRequestIds)
+	plans[ActivityTransmitTIDs] = buildPlan(activities.TransmitTids)
+	plans[ActivityTransmitEIDs] = buildPlan(activities.TransmitEIDs)
+
+	// the US sections are enforced after the configured rules, which take precedence
+	if cfg.USNat.Enabled {
+		ac.usNat = newUSNatEvaluator(cfg.USNat.SkipSIDs)
+		for _, activity := range usNatActivities {
+			plan := plans[activity]
+			plan.rules = append(plan.rules, usNatRule{activity: activity, evaluator: ac.usNat})
+			plans[activity] = plan
+		}
+	}
 	ac.plans = plans
 
 	ac.IPv4Config = cfg.IPv4Config
@@ -109,6 +126,15 @@ func (e ActivityControl) Allow(activity Activity, target Component, request Acti
 	return plan.Evaluate(target, request)
 }
 
+// USNat returns the enforcement decision of the GPP US National and state sections for the request.
+// The second return value is false if the enforcement is disabled for the account.
+func (e ActivityControl) USNat(request ActivityRequest) (USNatDecision, bool) {
+	if e.usNat == nil {
+		return USNatDecision{}, false
+	}
+	return e.usNat.evaluate(request), true
+}
+
 type ActivityPlan struct {
 	defaultResult bool
 	rules         []Rule
@@ -32,6 +32,7 @@ func TestNewActivityControl(t *testing.T) {
 					TransmitPreciseGeo:       getTestActivityConfig(false),
 					TransmitUniqueRequestIds: getTestActivityConfig(true),
 					TransmitTids:             getTestActivityConfig(true),
+					TransmitEIDs:             getTestActivityConfig(false),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
@@ -46,6 +47,7 @@ func TestNewActivityControl(t *testing.T) {
 					ActivityTransmitPreciseGeo:       getTestActivityPlan(ActivityDeny),
 					ActivityTransmitUniqueRequestIDs: getTestActivityPlan(ActivityAllow),
 					ActivityTransmitTIDs:             getTestActivityPlan(ActivityAllow),
+					ActivityTransmitEIDs:             getTestActivityPlan(ActivityDeny),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
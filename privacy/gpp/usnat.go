@@ -0,0 +1,187 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// Values of the notice, opt-out and consent fields of the US sections.
+const (
+	USNatNotApplicable byte = 0
+	// USNatYes means the notice was provided, the user opted out or, for consent fields, did not consent.
+	USNatYes byte = 1
+	// USNatNo means the notice was not provided, the user did not opt out or, for consent fields, consented.
+	USNatNo byte = 2
+)
+
+// usNatPreciseGeoIndex maps the US sections to the index of the precise geolocation
+// field in their sensitive data processing list. Colorado doesn't treat it as sensitive data.
+var usNatPreciseGeoIndex = map[gppConstants.SectionID]int{
+	gppConstants.SectionUSPNAT: 7,
+	gppConstants.SectionUSPCA:  2,
+	gppConstants.SectionUSPVA:  7,
+	gppConstants.SectionUSPCO:  -1,
+	gppConstants.SectionUSPUT:  7,
+	gppConstants.SectionUSPCT:  7,
+}
+
+// IsUSNatSection returns true if sid is the US National section or one of the US state sections.
+func IsUSNatSection(sid gppConstants.SectionID) bool {
+	_, ok := usNatPreciseGeoIndex[sid]
+	return ok
+}
+
+// USNatSignals holds the fields of a US National or state section relevant to activities,
+// normalized across sections. Fields a section doesn't define are left as USNatNotApplicable.
+type USNatSignals struct {
+	SectionID                           gppConstants.SectionID
+	SharingNotice                       byte
+	SaleOptOutNotice                    byte
+	SharingOptOutNotice                 byte
+	TargetedAdvertisingOptOutNotice     byte
+	SensitiveDataProcessingOptOutNotice byte
+	SensitiveDataLimitUseNotice         byte
+	SaleOptOut                          byte
+	SharingOptOut                       byte
+	TargetedAdvertisingOptOut           byte
+	SensitiveDataProcessing             []byte
+	KnownChildSensitiveDataConsents     []byte
+	PersonalDataConsents                byte
+	MspaServiceProviderMode             byte
+	GPC                                 bool
+}
+
+// PreciseGeo returns the value of the precise geolocation sensitive data field.
+func (s USNatSignals) PreciseGeo() byte {
+	index, ok := usNatPreciseGeoIndex[s.SectionID]
+	if !ok || index < 0 || index >= len(s.SensitiveDataProcessing) {
+		return USNatNotApplicable
+	}
+	return s.SensitiveDataProcessing[index]
+}
+
+// SensitiveDataExceptPreciseGeo returns the values of the sensitive data fields other than precise geolocation.
+func (s USNatSignals) SensitiveDataExceptPreciseGeo() []byte {
+	index, ok := usNatPreciseGeoIndex[s.SectionID]
+	if !ok || index < 0 || index >= len(s.SensitiveDataProcessing) {
+		return s.SensitiveDataProcessing
+	}
+
+	values := make([]byte, 0, len(s.SensitiveDataProcessing)-1)
+	values = append(values, s.SensitiveDataProcessing[:index]...)
+	return append(values, s.SensitiveDataProcessing[index+1:]...)
+}
+
+// SelectUSNatSection returns the signals of the first US section listed in gppSIDs, skipping the
+// sections listed in skipSIDs. The second return value is false if no US section applies to the request.
+// A section listed in gppSIDs but missing from the GPP string is returned as nil signals with true,
+// as the request states a US law applies without providing the choices of the user.
+func SelectUSNatSection(gpp gpplib.GppContainer, gppSIDs []int8, skipSIDs []int8) (*USNatSignals, bool) {
+	for _, sid := range gppSIDs {
+		sectionID := gppConstants.SectionID(sid)
+		if !IsUSNatSection(sectionID) || IsSIDInList(skipSIDs, sectionID) {
+			continue
+		}
+
+		i := IndexOfSID(gpp, sectionID)
+		if i < 0 || i >= len(gpp.Sections) {
+			return nil, true
+		}
+
+		signals, ok := NewUSNatSignals(gpp.Sections[i])
+		if !ok {
+			return nil, true
+		}
+		return &signals, true
+	}
+	return nil, false
+}
+
+// NewUSNatSignals normalizes a parsed US National or state section. It returns false
+// for other sections or sections the library failed to parse.
+func NewUSNatSignals(section gpplib.Section) (USNatSignals, bool) {
+	// sections failing to parse are returned by the library without their id
+	if section == nil || !IsUSNatSection(section.GetID()) {
+		return USNatSignals{}, false
+	}
+
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		c := s.CoreSegment
+		return USNatSignals{
+			SectionID:                           gppConstants.SectionUSPNAT,
+			SharingNotice:                       c.SharingNotice,
+			SaleOptOutNotice:                    c.SaleOptOutNotice,
+			SharingOptOutNotice:                 c.SharingOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     c.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: c.SensitiveDataProcessingOptOutNotice,
+			SensitiveDataLimitUseNotice:         c.SensitiveDataLimitUseNotice,
+			SaleOptOut:                          c.SaleOptOut,
+			SharingOptOut:                       c.SharingOptOut,
+			TargetedAdvertisingOptOut:           c.TargetedAdvertisingOptOut,
+			SensitiveDataProcessing:             c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents:     c.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:                c.PersonalDataConsents,
+			MspaServiceProviderMode:             c.MspaServiceProviderMode,
+			GPC:                                 s.GPCSegment.Gpc,
+		}, true
+	case uspca.USPCA:
+		c := s.CoreSegment
+		return USNatSignals{
+			SectionID:                       gppConstants.SectionUSPCA,
+			SaleOptOutNotice:                c.SaleOptOutNotice,
+			SharingOptOutNotice:             c.SharingOptOutNotice,
+			SensitiveDataLimitUseNotice:     c.SensitiveDataLimitUseNotice,
+			SaleOptOut:                      c.SaleOptOut,
+			SharingOptOut:                   c.SharingOptOut,
+			SensitiveDataProcessing:         c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents: c.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:            c.PersonalDataConsents,
+			MspaServiceProviderMode:         c.MspaServiceProviderMode,
+			GPC:                             s.GPCSegment.Gpc,
+		}, true
+	case uspva.USPVA:
+		return newCommonUSNatSignals(gppConstants.SectionUSPVA, s.CoreSegment, sections.CommonUSGPCSegment{}), true
+	case uspco.USPCO:
+		return newCommonUSNatSignals(gppConstants.SectionUSPCO, s.CoreSegment, s.GPCSegment), true
+	case uspct.USPCT:
+		return newCommonUSNatSignals(gppConstants.SectionUSPCT, s.CoreSegment, s.GPCSegment), true
+	case usput.USPUT:
+		c := s.CoreSegment
+		return USNatSignals{
+			SectionID:                           gppConstants.SectionUSPUT,
+			SharingNotice:                       c.SharingNotice,
+			SaleOptOutNotice:                    c.SaleOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     c.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: c.SensitiveDataProcessingOptOutNotice,
+			SaleOptOut:                          c.SaleOptOut,
+			TargetedAdvertisingOptOut:           c.TargetedAdvertisingOptOut,
+			SensitiveDataProcessing:             c.SensitiveDataProcessing,
+			KnownChildSensitiveDataConsents:     []byte{c.KnownChildSensitiveDataConsents},
+			MspaServiceProviderMode:             c.MspaServiceProviderMode,
+		}, true
+	}
+	return USNatSignals{}, false
+}
+
+func newCommonUSNatSignals(sectionID gppConstants.SectionID, c sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment) USNatSignals {
+	return USNatSignals{
+		SectionID:                       sectionID,
+		SharingNotice:                   c.SharingNotice,
+		SaleOptOutNotice:                c.SaleOptOutNotice,
+		TargetedAdvertisingOptOutNotice: c.TargetedAdvertisingOptOutNotice,
+		SaleOptOut:                      c.SaleOptOut,
+		TargetedAdvertisingOptOut:       c.TargetedAdvertisingOptOut,
+		SensitiveDataProcessing:         c.SensitiveDataProcessing,
+		KnownChildSensitiveDataConsents: c.KnownChildSensitiveDataConsents,
+		MspaServiceProviderMode:         c.MspaServiceProviderMode,
+		GPC:                             gpc.Gpc,
+	}
+}
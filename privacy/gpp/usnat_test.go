@@ -0,0 +1,131 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectUSNatSection(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		gpp             string
+		gppSIDs         []int8
+		skipSIDs        []int8
+		expectedSection gppConstants.SectionID
+		expectedApplies bool
+	}{
+		{
+			desc:            "no_us_sid",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSIDs:         []int8{2, 6},
+			expectedApplies: false,
+		},
+		{
+			desc:            "usnat_section",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSIDs:         []int8{7},
+			expectedSection: gppConstants.SectionUSPNAT,
+			expectedApplies: true,
+		},
+		{
+			desc:            "state_section",
+			gpp:             "DBABBgA~xlgWEYCZAA",
+			gppSIDs:         []int8{8},
+			expectedSection: gppConstants.SectionUSPCA,
+			expectedApplies: true,
+		},
+		{
+			desc:            "section_skipped",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSIDs:         []int8{7},
+			skipSIDs:        []int8{7},
+			expectedApplies: false,
+		},
+		{
+			desc:            "section_missing",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSIDs:         []int8{8},
+			expectedApplies: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			container, errs := gpplib.Parse(tc.gpp)
+			require.Empty(t, errs)
+
+			signals, applies := SelectUSNatSection(container, tc.gppSIDs, tc.skipSIDs)
+			assert.Equal(t, tc.expectedApplies, applies)
+			if tc.expectedSection == 0 {
+				assert.Nil(t, signals)
+				return
+			}
+			require.NotNil(t, signals)
+			assert.Equal(t, tc.expectedSection, signals.SectionID)
+		})
+	}
+}
+
+func TestNewUSNatSignals(t *testing.T) {
+	container, errs := gpplib.Parse("DBABBgA~xlgWEYCZAA")
+	require.Empty(t, errs)
+
+	signals, ok := NewUSNatSignals(container.Sections[0])
+	require.True(t, ok)
+	assert.Equal(t, USNatSignals{
+		SectionID:                       gppConstants.SectionUSPCA,
+		SaleOptOutNotice:                USNatNo,
+		SharingOptOutNotice:             USNatYes,
+		SensitiveDataLimitUseNotice:     USNatYes,
+		SaleOptOut:                      USNatNo,
+		SensitiveDataProcessing:         []byte{0, 1, 1, 2, 0, 1, 0, 1, 2},
+		KnownChildSensitiveDataConsents: []byte{0, 0},
+		MspaServiceProviderMode:         USNatNo,
+	}, signals)
+
+	_, ok = NewUSNatSignals(nil)
+	assert.False(t, ok)
+}
+
+func TestUSNatSignalsPreciseGeo(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		signals               USNatSignals
+		expectedPreciseGeo    byte
+		expectedSensitiveData []byte
+	}{
+		{
+			desc:                  "usnat",
+			signals:               USNatSignals{SectionID: gppConstants.SectionUSPNAT, SensitiveDataProcessing: []byte{0, 1, 2, 0, 1, 2, 0, 1, 2, 0, 1, 2}},
+			expectedPreciseGeo:    USNatYes,
+			expectedSensitiveData: []byte{0, 1, 2, 0, 1, 2, 0, 2, 0, 1, 2},
+		},
+		{
+			desc:                  "california",
+			signals:               USNatSignals{SectionID: gppConstants.SectionUSPCA, SensitiveDataProcessing: []byte{0, 1, 2, 0, 1, 2, 0, 1, 2}},
+			expectedPreciseGeo:    USNatNo,
+			expectedSensitiveData: []byte{0, 1, 0, 1, 2, 0, 1, 2},
+		},
+		{
+			desc:                  "colorado_has_no_precise_geo",
+			signals:               USNatSignals{SectionID: gppConstants.SectionUSPCO, SensitiveDataProcessing: []byte{1, 1, 1, 1, 1, 1, 1}},
+			expectedPreciseGeo:    USNatNotApplicable,
+			expectedSensitiveData: []byte{1, 1, 1, 1, 1, 1, 1},
+		},
+		{
+			desc:                  "truncated_sensitive_data",
+			signals:               USNatSignals{SectionID: gppConstants.SectionUSPNAT, SensitiveDataProcessing: []byte{1}},
+			expectedPreciseGeo:    USNatNotApplicable,
+			expectedSensitiveData: []byte{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expectedPreciseGeo, tc.signals.PreciseGeo())
+			assert.Equal(t, tc.expectedSensitiveData, tc.signals.SensitiveDataExceptPreciseGeo())
+		})
+	}
+}
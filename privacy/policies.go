@@ -2,5 +2,6 @@ package privacy
 
 // Policies contains privacy signals and consent for non-OpenRTB activities.
 type Policies struct {
+	GPP    string
 	GPPSID []int8
 }
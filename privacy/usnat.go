@@ -0,0 +1,160 @@
+package privacy
+
+import (
+	"slices"
+	"sync"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// usNatActivities are the activities the GPP US National and state sections are enforced for.
+var usNatActivities = []Activity{
+	ActivitySyncUser,
+	ActivityTransmitUserFPD,
+	ActivityTransmitPreciseGeo,
+	ActivityTransmitEIDs,
+}
+
+// USNatDecision is the outcome of the enforcement of the GPP US National and state sections for a request.
+type USNatDecision struct {
+	// SectionID is the US section applied to the request, zero if no US section applies.
+	SectionID gppConstants.SectionID
+	// Denied lists the activities denied by the section.
+	Denied []Activity
+	// Errors holds the errors parsing the GPP string.
+	Errors []error
+}
+
+func (d USNatDecision) denies(activity Activity) bool {
+	return slices.Contains(d.Denied, activity)
+}
+
+// usNatRule denies an activity if the US section applicable to the request doesn't allow it, it abstains otherwise.
+type usNatRule struct {
+	activity  Activity
+	evaluator *usNatEvaluator
+}
+
+func (r usNatRule) Evaluate(_ Component, request ActivityRequest) ActivityResult {
+	if r.evaluator.evaluate(request).denies(r.activity) {
+		return ActivityDeny
+	}
+	return ActivityAbstain
+}
+
+// usNatEvaluator decodes the GPP US National and state sections of a request into activity decisions.
+// The last decision is cached since activities are evaluated many times per request for the same GPP string.
+type usNatEvaluator struct {
+	skipSIDs []int8
+
+	mu        sync.Mutex
+	cached    bool
+	cachedGPP string
+	cachedSID []int8
+	decision  USNatDecision
+}
+
+func newUSNatEvaluator(skipSIDs []int8) *usNatEvaluator {
+	return &usNatEvaluator{skipSIDs: skipSIDs}
+}
+
+func (e *usNatEvaluator) evaluate(request ActivityRequest) USNatDecision {
+	gppString, gppSID := getGPP(request)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cached && e.cachedGPP == gppString && slices.Equal(e.cachedSID, gppSID) {
+		return e.decision
+	}
+
+	e.decision = decideUSNat(gppString, gppSID, e.skipSIDs)
+	e.cached = true
+	e.cachedGPP = gppString
+	e.cachedSID = slices.Clone(gppSID)
+	return e.decision
+}
+
+func decideUSNat(gppString string, gppSID []int8, skipSIDs []int8) USNatDecision {
+	var decision USNatDecision
+
+	sectionID := firstUSNatSID(gppSID, skipSIDs)
+	if sectionID == 0 {
+		return decision
+	}
+	decision.SectionID = sectionID
+
+	var container gpplib.GppContainer
+	if gppString != "" {
+		container, decision.Errors = gpplib.Parse(gppString)
+	}
+
+	signals, _ := gpp.SelectUSNatSection(container, gppSID, skipSIDs)
+
+	// the section is missing or invalid, the choices of the user are unknown
+	if signals == nil {
+		decision.Denied = usNatActivities
+		return decision
+	}
+
+	for _, activity := range usNatActivities {
+		if !usNatAllows(*signals, activity) {
+			decision.Denied = append(decision.Denied, activity)
+		}
+	}
+	return decision
+}
+
+// firstUSNatSID returns the first US section listed in gppSID and not skipped, zero if there is none.
+func firstUSNatSID(gppSID []int8, skipSIDs []int8) gppConstants.SectionID {
+	for _, sid := range gppSID {
+		if sectionID := gppConstants.SectionID(sid); gpp.IsUSNatSection(sectionID) && !gpp.IsSIDInList(skipSIDs, sectionID) {
+			return sectionID
+		}
+	}
+	return 0
+}
+
+// usNatAllows maps the opt-out, notice, sensitive data and known child flags of a US section to an activity decision.
+func usNatAllows(s gpp.USNatSignals, activity Activity) bool {
+	if s.MspaServiceProviderMode == gpp.USNatYes || s.GPC {
+		return false
+	}
+
+	if slices.Contains(s.KnownChildSensitiveDataConsents, gpp.USNatYes) || s.PersonalDataConsents == gpp.USNatYes {
+		return false
+	}
+
+	if activity == ActivityTransmitPreciseGeo {
+		return s.PreciseGeo() != gpp.USNatYes &&
+			s.SensitiveDataProcessingOptOutNotice != gpp.USNatNo &&
+			s.SensitiveDataLimitUseNotice != gpp.USNatNo
+	}
+
+	optedOut := s.SaleOptOut == gpp.USNatYes || s.SharingOptOut == gpp.USNatYes || s.TargetedAdvertisingOptOut == gpp.USNatYes
+	noticeMissing := s.SharingNotice == gpp.USNatNo || s.SaleOptOutNotice == gpp.USNatNo ||
+		s.SharingOptOutNotice == gpp.USNatNo || s.TargetedAdvertisingOptOutNotice == gpp.USNatNo
+	if optedOut || noticeMissing {
+		return false
+	}
+
+	if activity == ActivityTransmitUserFPD {
+		return !slices.Contains(s.SensitiveDataExceptPreciseGeo(), gpp.USNatYes)
+	}
+
+	return true
+}
+
+func getGPP(request ActivityRequest) (string, []int8) {
+	if request.IsPolicies() {
+		return request.policies.GPP, request.policies.GPPSID
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.GPP, request.bidRequest.Regs.GPPSID
+	}
+
+	return "", nil
+}
@@ -0,0 +1,204 @@
+package privacy
+
+import (
+	"testing"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy/gpp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecideUSNat(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		gpp             string
+		gppSID          []int8
+		skipSIDs        []int8
+		expectedSection gppConstants.SectionID
+		expectedDenied  []Activity
+		expectedErrors  bool
+	}{
+		{
+			desc:   "no_us_section",
+			gpp:    "DBABLA~BVQqAAAAAgA.QA",
+			gppSID: []int8{2},
+		},
+		{
+			desc:            "usnat_nothing_denied",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSID:          []int8{7},
+			expectedSection: gppConstants.SectionUSPNAT,
+		},
+		{
+			desc:            "california_notice_not_provided_and_precise_geo_opted_out",
+			gpp:             "DBABBgA~xlgWEYCZAA",
+			gppSID:          []int8{8},
+			expectedSection: gppConstants.SectionUSPCA,
+			expectedDenied:  usNatActivities,
+		},
+		{
+			desc:     "section_skipped",
+			gpp:      "DBABBgA~xlgWEYCZAA",
+			gppSID:   []int8{8},
+			skipSIDs: []int8{8},
+		},
+		{
+			desc:            "section_missing",
+			gpp:             "DBABLA~BVQqAAAAAgA.QA",
+			gppSID:          []int8{8},
+			expectedSection: gppConstants.SectionUSPCA,
+			expectedDenied:  usNatActivities,
+		},
+		{
+			desc:            "empty_gpp",
+			gppSID:          []int8{7},
+			expectedSection: gppConstants.SectionUSPNAT,
+			expectedDenied:  usNatActivities,
+		},
+		{
+			desc:            "invalid_gpp",
+			gpp:             "malformed",
+			gppSID:          []int8{7},
+			expectedSection: gppConstants.SectionUSPNAT,
+			expectedDenied:  usNatActivities,
+			expectedErrors:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			decision := decideUSNat(tc.gpp, tc.gppSID, tc.skipSIDs)
+			assert.Equal(t, tc.expectedSection, decision.SectionID)
+			assert.Equal(t, tc.expectedDenied, decision.Denied)
+			assert.Equal(t, tc.expectedErrors, len(decision.Errors) > 0)
+		})
+	}
+}
+
+func TestUSNatAllows(t *testing.T) {
+	allowed := gpp.USNatSignals{
+		SectionID:               gppConstants.SectionUSPNAT,
+		SharingNotice:           gpp.USNatYes,
+		SaleOptOutNotice:        gpp.USNatYes,
+		SaleOptOut:              gpp.USNatNo,
+		SensitiveDataProcessing: make([]byte, 12),
+	}
+
+	testCases := []struct {
+		desc           string
+		modify         func(s *gpp.USNatSignals)
+		expectedDenied []Activity
+	}{
+		{
+			desc:   "nothing_denied",
+			modify: func(s *gpp.USNatSignals) {},
+		},
+		{
+			desc:           "sale_opt_out",
+			modify:         func(s *gpp.USNatSignals) { s.SaleOptOut = gpp.USNatYes },
+			expectedDenied: []Activity{ActivitySyncUser, ActivityTransmitUserFPD, ActivityTransmitEIDs},
+		},
+		{
+			desc:           "targeted_advertising_opt_out",
+			modify:         func(s *gpp.USNatSignals) { s.TargetedAdvertisingOptOut = gpp.USNatYes },
+			expectedDenied: []Activity{ActivitySyncUser, ActivityTransmitUserFPD, ActivityTransmitEIDs},
+		},
+		{
+			desc:           "notice_not_provided",
+			modify:         func(s *gpp.USNatSignals) { s.SharingNotice = gpp.USNatNo },
+			expectedDenied: []Activity{ActivitySyncUser, ActivityTransmitUserFPD, ActivityTransmitEIDs},
+		},
+		{
+			desc:           "gpc",
+			modify:         func(s *gpp.USNatSignals) { s.GPC = true },
+			expectedDenied: usNatActivities,
+		},
+		{
+			desc:           "service_provider_mode",
+			modify:         func(s *gpp.USNatSignals) { s.MspaServiceProviderMode = gpp.USNatYes },
+			expectedDenied: usNatActivities,
+		},
+		{
+			desc:           "known_child",
+			modify:         func(s *gpp.USNatSignals) { s.KnownChildSensitiveDataConsents = []byte{0, 1} },
+			expectedDenied: usNatActivities,
+		},
+		{
+			desc:           "personal_data_not_consented",
+			modify:         func(s *gpp.USNatSignals) { s.PersonalDataConsents = gpp.USNatYes },
+			expectedDenied: usNatActivities,
+		},
+		{
+			desc:           "sensitive_data_opt_out",
+			modify:         func(s *gpp.USNatSignals) { s.SensitiveDataProcessing[0] = gpp.USNatYes },
+			expectedDenied: []Activity{ActivityTransmitUserFPD},
+		},
+		{
+			desc:           "precise_geo_opt_out",
+			modify:         func(s *gpp.USNatSignals) { s.SensitiveDataProcessing[7] = gpp.USNatYes },
+			expectedDenied: []Activity{ActivityTransmitPreciseGeo},
+		},
+		{
+			desc:           "sensitive_data_notice_not_provided",
+			modify:         func(s *gpp.USNatSignals) { s.SensitiveDataProcessingOptOutNotice = gpp.USNatNo },
+			expectedDenied: []Activity{ActivityTransmitPreciseGeo},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			signals := allowed
+			signals.SensitiveDataProcessing = make([]byte, 12)
+			tc.modify(&signals)
+
+			var denied []Activity
+			for _, activity := range usNatActivities {
+				if !usNatAllows(signals, activity) {
+					denied = append(denied, activity)
+				}
+			}
+			assert.Equal(t, tc.expectedDenied, denied)
+		})
+	}
+}
+
+func TestActivityControlUSNat(t *testing.T) {
+	bidder := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	optedOut := NewRequestFromPolicies(Policies{GPP: "DBABBgA~xlgWEYCZAA", GPPSID: []int8{8}})
+
+	t.Run("disabled", func(t *testing.T) {
+		ac := NewActivityControl(&config.AccountPrivacy{})
+		assert.True(t, ac.Allow(ActivitySyncUser, bidder, optedOut))
+
+		_, enabled := ac.USNat(optedOut)
+		assert.False(t, enabled)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		ac := NewActivityControl(&config.AccountPrivacy{USNat: config.AccountUSNat{Enabled: true}})
+		assert.False(t, ac.Allow(ActivitySyncUser, bidder, optedOut))
+		assert.True(t, ac.Allow(ActivityFetchBids, bidder, optedOut))
+
+		decision, enabled := ac.USNat(optedOut)
+		assert.True(t, enabled)
+		assert.Equal(t, gppConstants.SectionUSPCA, decision.SectionID)
+	})
+
+	t.Run("enabled_configured_rule_takes_precedence", func(t *testing.T) {
+		ac := NewActivityControl(&config.AccountPrivacy{
+			AllowActivities: &config.AllowActivities{TransmitEIDs: getTestActivityConfig(true)},
+			USNat:           config.AccountUSNat{Enabled: true},
+		})
+		assert.True(t, ac.Allow(ActivityTransmitEIDs, bidder, optedOut))
+		assert.False(t, ac.Allow(ActivityTransmitEIDs, Component{Type: ComponentTypeBidder, Name: "bidderB"}, optedOut))
+	})
+
+	t.Run("enabled_bid_request", func(t *testing.T) {
+		ac := NewActivityControl(&config.AccountPrivacy{USNat: config.AccountUSNat{Enabled: true}})
+		request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GPP: "DBABLA~BVQqAAAAAgA.QA", GPPSID: []int8{7}},
+		}})
+		assert.True(t, ac.Allow(ActivityTransmitPreciseGeo, bidder, request))
+	})
+}
@@ -7,10 +7,11 @@ import (
 
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
+	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, storedCaches stored_requests.NamedCaches, bidderInfoReloader endpoints.BidderInfoReloader, bidderInfoReloadEnabled bool, bidderHealth endpoints.BidderHealthReporter, floorsFetchReporter endpoints.FloorsFetchReporter) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -23,5 +24,19 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	storedCachesEndpoints := endpoints.NewStoredCachesEndpoints(storedCaches)
+	mux.HandleFunc("GET /stored_requests/caches", storedCachesEndpoints.HandleList)
+	mux.HandleFunc("GET /stored_requests/caches/{cache}", storedCachesEndpoints.HandleEntries)
+	mux.HandleFunc("DELETE /stored_requests/caches/{cache}", storedCachesEndpoints.HandleEntries)
+	mux.HandleFunc("GET /stored_requests/caches/{cache}/{id...}", storedCachesEndpoints.HandleEntry)
+	mux.HandleFunc("DELETE /stored_requests/caches/{cache}/{id...}", storedCachesEndpoints.HandleEntry)
+	bidderInfosEndpoints := endpoints.NewBidderInfosEndpoints(bidderInfoReloader, bidderInfoReloadEnabled)
+	mux.HandleFunc("GET /bidder_infos", bidderInfosEndpoints.HandleList)
+	mux.HandleFunc("GET /bidder_infos/{bidder}", bidderInfosEndpoints.HandleBidder)
+	mux.HandleFunc("POST /bidder_infos/reload", bidderInfosEndpoints.HandleReload)
+	bidderHealthEndpoints := endpoints.NewBidderHealthEndpoints(bidderHealth)
+	mux.HandleFunc("GET /bidders/health", bidderHealthEndpoints.HandleList)
+	mux.HandleFunc("GET /bidders/health/{bidder}", bidderHealthEndpoints.HandleBidder)
+	mux.HandleFunc("GET /price_floors/fetches", endpoints.NewPriceFloorsFetchesEndpoint(floorsFetchReporter))
 	return mux
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	openrtb2model "github.com/prebid/openrtb/v20/openrtb2"
@@ -35,7 +36,9 @@ import (
 	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/router/aspects"
 	"github.com/prebid/prebid-server/v3/server/ssl"
+	"github.com/prebid/prebid-server/v3/stored_requests"
 	storedRequestsConf "github.com/prebid/prebid-server/v3/stored_requests/config"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
@@ -116,6 +119,13 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
+	StoredCaches    stored_requests.NamedCaches
+	// BidderInfoReloader holds the bidder infos in effect and swaps the adapters and syncers when they're reloaded
+	BidderInfoReloader *exchange.BidderInfoReloader
+	// BidderHealth reports the health vectors of the bidders used to throttle them
+	BidderHealth *exchange.BidderHealth
+	// PriceFloorFetcher fetches the floor data of the accounts using dynamic floors, nil if price floors are disabled
+	PriceFloorFetcher *floors.PriceFloorFetcher
 
 	shutdowns []func()
 }
@@ -193,13 +203,17 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, openrtb_ext.CoreBidderNames(), syncerKeys, moduleStageNames)
-	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
+	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher, storedCaches := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
+	r.StoredCaches = storedCaches
 
-	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
+	analyticsRunner := analyticsBuild.New(&cfg.Analytics, r.MetricsEngine)
 
 	// register the analytics runner for shutdown
 	r.shutdowns = append(r.shutdowns, shutdown, analyticsRunner.Shutdown, shutdownModules.Shutdown)
 
+	tracer := tracing.NewTracer(cfg.Tracing, generalHttpClient)
+	r.shutdowns = append(r.shutdowns, tracer.Shutdown)
+
 	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(schemaDirectory)
 	if err != nil {
 		glog.Fatalf("Failed to create the bidder params validator. %v", err)
@@ -216,36 +230,57 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	tcf2CfgBuilder := gdpr.NewTCF2Config
 
 	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	cacheBackend := pbc.NewBackend(&cfg.CacheURL.Backend)
+	if cacheBackend != nil {
+		cacheClient = pbc.NewBackendClient(cacheBackend, &cfg.CacheURL.Backend, &cfg.ExtCacheURL, r.MetricsEngine)
+		r.shutdowns = append(r.shutdowns, cacheBackend.Shutdown)
+	}
+
+	uidStore, err := usersync.NewUIDStore(&cfg.UserSync.UIDStore)
+	if err != nil {
+		glog.Fatalf("Failed to create the UID store: %v", err)
+	}
+	if uidStore != nil {
+		r.shutdowns = append(r.shutdowns, uidStore.Shutdown)
+	}
+	uidCodec, err := usersync.NewCodec(&cfg.HostCookie, &cfg.UserSync.UIDStore, uidStore, r.MetricsEngine)
+	if err != nil {
+		glog.Fatalf("Failed to create the uids cookie codec: %v", err)
+	}
 
 	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
 		errs := errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
 		return nil, errs
 	}
+	r.BidderInfoReloader = exchange.NewBidderInfoReloader(cfg, generalHttpClient, r.MetricsEngine, adapters, syncersByBidder)
+	adapters = r.BidderInfoReloader.Adapters()
+	syncersByBidder = r.BidderInfoReloader.Syncers()
+	r.BidderHealth = exchange.NewBidderHealth(adapters)
 	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
 	if err != nil {
 		glog.Fatalf("Failed to create ads cert signer: %v", err)
 	}
 
 	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
-	priceFloorFetcher := floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
+	r.PriceFloorFetcher = floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
 
 	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
 	macroReplacer := macros.NewStringIndexBasedReplacer()
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters)
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, r.PriceFloorFetcher, singleFormatAdapters, r.BidderInfoReloader)
 	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, uidCodec)
 	if err != nil {
 		glog.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
 	}
 
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, uidCodec)
 	if err != nil {
 		glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
 	}
 
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments)
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, planBuilder, tmaxAdjustments, uidCodec)
 	if err != nil {
 		glog.Fatalf("Failed to create the video endpoint handler. %v", err)
 	}
@@ -255,14 +290,17 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		videoEndpoint = aspects.QueuedRequestTimeout(videoEndpoint, cfg.RequestTimeoutHeaders, r.MetricsEngine, metrics.ReqTypeVideo)
 	}
 
-	r.POST("/openrtb2/auction", openrtbEndpoint)
-	r.POST("/openrtb2/video", videoEndpoint)
-	r.GET("/openrtb2/amp", ampEndpoint)
-	r.GET("/info/bidders", infoEndpoints.NewBiddersEndpoint(cfg.BidderInfos))
-	r.GET("/info/bidders/:bidderName", infoEndpoints.NewBiddersDetailEndpoint(cfg.BidderInfos))
+	r.POST("/openrtb2/auction", tracing.WrapHandle(tracer, "openrtb2.auction", openrtbEndpoint))
+	r.POST("/openrtb2/video", tracing.WrapHandle(tracer, "openrtb2.video", videoEndpoint))
+	r.GET("/openrtb2/amp", tracing.WrapHandle(tracer, "openrtb2.amp", ampEndpoint))
+	r.GET("/info/bidders", newBidderInfosHandle(r.BidderInfoReloader, infoEndpoints.NewBiddersEndpoint))
+	r.GET("/info/bidders/:bidderName", newBidderInfosHandle(r.BidderInfoReloader, infoEndpoints.NewBiddersDetailEndpoint))
 	r.GET("/bidders/params", NewJsonDirectoryServer(schemaDirectory, paramsValidator))
-	r.POST("/cookie_sync", endpoints.NewCookieSyncEndpoint(syncersByBidder, cfg, gdprPermsBuilder, tcf2CfgBuilder, r.MetricsEngine, analyticsRunner, accounts, activeBidders).Handle)
+	r.POST("/cookie_sync", endpoints.NewCookieSyncEndpoint(syncersByBidder, cfg, gdprPermsBuilder, tcf2CfgBuilder, r.MetricsEngine, analyticsRunner, accounts, activeBidders, uidCodec).Handle)
 	r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse))
+	if cacheBackend != nil {
+		r.GET("/cache", endpoints.NewCacheEndpoint(cacheBackend))
+	}
 	r.GET("/", serveIndex)
 	r.Handler("GET", "/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
 	r.ServeFiles("/static/*filepath", http.Dir("static"))
@@ -282,10 +320,11 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		ExternalUrl:      cfg.ExternalURL,
 		RecaptchaSecret:  cfg.RecaptchaSecret,
 		PriorityGroups:   cfg.UserSync.PriorityGroups,
+		UIDCodec:         uidCodec,
 	}
 
-	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, analyticsRunner, accounts, r.MetricsEngine))
-	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie))
+	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, analyticsRunner, accounts, r.MetricsEngine, uidCodec))
+	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie, uidCodec))
 	r.POST("/optout", userSyncDeps.OptOut)
 	r.GET("/optout", userSyncDeps.OptOut)
 
@@ -301,6 +340,22 @@ func (r *Router) Shutdown() {
 	glog.Info("[PBS Router] shut down")
 }
 
+// newBidderInfosHandle returns a handle serving with the endpoint built from the bidder infos in effect, the
+// endpoint is rebuilt when they're reloaded.
+func newBidderInfosHandle(reloader *exchange.BidderInfoReloader, newEndpoint func(config.BidderInfos) httprouter.Handle) httprouter.Handle {
+	var current atomic.Pointer[httprouter.Handle]
+	build := func(infos config.BidderInfos) {
+		handle := newEndpoint(infos)
+		current.Store(&handle)
+	}
+	build(reloader.BidderInfos())
+	reloader.OnReload(build)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		(*current.Load())(w, r, ps)
+	}
+}
+
 func checkSupportedUserSyncEndpoints(bidderInfos config.BidderInfos) error {
 	for name, info := range bidderInfos {
 		if info.Syncer == nil {
@@ -8,11 +8,15 @@ import (
 	"testing"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const adapterDirectory = "../adapters"
@@ -166,6 +170,32 @@ func TestCheckSupportedUserSyncEndpoints(t *testing.T) {
 }
 
 // Prevents #648
+func TestBidderInfosHandle(t *testing.T) {
+	cfg := &config.Configuration{
+		BidderInfos:      config.BidderInfos{"appnexus": {Endpoint: "https://appnexus.com/bid"}},
+		BidderInfoReload: config.BidderInfoReload{Enabled: true},
+	}
+	adapters, _, errs := exchange.BuildAdapters(&http.Client{}, cfg, cfg.BidderInfos, &metricsConf.NilMetricsEngine{})
+	require.Empty(t, errs)
+	reloader := exchange.NewBidderInfoReloader(cfg, &http.Client{}, &metricsConf.NilMetricsEngine{}, adapters, nil)
+	handle := newBidderInfosHandle(reloader, func(infos config.BidderInfos) httprouter.Handle {
+		return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+			w.Write([]byte(infos["appnexus"].Endpoint))
+		}
+	})
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("GET", "/info/bidders", nil), nil)
+	assert.Equal(t, "https://appnexus.com/bid", recorder.Body.String())
+
+	_, err := reloader.Reload(config.BidderInfos{"appnexus": {Endpoint: "https://new.appnexus.com/bid"}})
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("GET", "/info/bidders", nil), nil)
+	assert.Equal(t, "https://new.appnexus.com/bid", recorder.Body.String(), "the endpoint should be rebuilt on reload")
+}
+
 func TestCORSSupport(t *testing.T) {
 	const origin = "https://publisher-domain.com"
 	handler := func(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,354 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	Bidder      = "bidder"
+	BidderIn    = "bidderIn"
+	MediaType   = "mediaType"
+	BidPrice    = "bidPrice"
+	DealPresent = "dealPresent"
+	Adomain     = "adomain"
+	AdomainIn   = "adomainIn"
+)
+
+// BidderRequestPayload is the payload of the schema functions evaluated at the bidder request stage, the
+// request distilled for a bidder.
+type BidderRequestPayload struct {
+	Request *openrtb_ext.RequestWrapper
+	Bidder  string
+}
+
+// BidPayload is the payload of the schema functions evaluated at the raw bidder response stage, a bid of a
+// bidder response along with the request of the bidder. Conversions are used to compare the bid price
+// with a price in another currency.
+type BidPayload struct {
+	BidderRequestPayload
+	Bid         *adapters.TypedBid
+	Currency    string
+	Conversions currency.Conversions
+}
+
+// NewBidderRequestSchemaFunction returns the specified schema function that operates on a bidder request
+// payload. The schema functions operating on a request payload are available as well.
+func NewBidderRequestSchemaFunction(name string, params json.RawMessage) (SchemaFunction[BidderRequestPayload], error) {
+	switch name {
+	case Bidder:
+		return NewBidder(params)
+	case BidderIn:
+		return NewBidderIn(params)
+	case MediaType:
+		return NewRequestMediaType(params)
+	default:
+		f, err := NewRequestSchemaFunction(name, params)
+		if err != nil {
+			return nil, err
+		}
+		return &payloadAdapter[BidderRequestPayload, openrtb_ext.RequestWrapper]{
+			SchemaFunction: f,
+			get:            func(p *BidderRequestPayload) *openrtb_ext.RequestWrapper { return p.Request },
+		}, nil
+	}
+}
+
+// NewBidSchemaFunction returns the specified schema function that operates on a bid payload. The schema
+// functions operating on a bidder request or request payload are available as well.
+func NewBidSchemaFunction(name string, params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	switch name {
+	case MediaType:
+		return NewBidMediaType(params)
+	case BidPrice:
+		return NewBidPrice(params)
+	case DealPresent:
+		return NewDealPresent(params)
+	case Adomain:
+		return NewAdomain(params)
+	case AdomainIn:
+		return NewAdomainIn(params)
+	default:
+		f, err := NewBidderRequestSchemaFunction(name, params)
+		if err != nil {
+			return nil, err
+		}
+		return &payloadAdapter[BidPayload, BidderRequestPayload]{
+			SchemaFunction: f,
+			get:            func(p *BidPayload) *BidderRequestPayload { return &p.BidderRequestPayload },
+		}, nil
+	}
+}
+
+// payloadAdapter calls a schema function on a payload held by the payload of another stage.
+type payloadAdapter[T1 any, T2 any] struct {
+	SchemaFunction[T2]
+	get func(*T1) *T2
+}
+
+func (pa *payloadAdapter[T1, T2]) Call(payload *T1) (string, error) {
+	return pa.SchemaFunction.Call(pa.get(payload))
+}
+
+// ------------bidder-----------------------
+type bidder struct{}
+
+func NewBidder(params json.RawMessage) (SchemaFunction[BidderRequestPayload], error) {
+	if err := checkNilArgs(params, Bidder); err != nil {
+		return nil, err
+	}
+	return &bidder{}, nil
+}
+
+func (b *bidder) Call(payload *BidderRequestPayload) (string, error) {
+	return payload.Bidder, nil
+}
+
+func (b *bidder) Name() string {
+	return Bidder
+}
+
+// ------------bidderIn---------------------
+type bidderIn struct {
+	Bidders []string `json:"bidders"`
+}
+
+func NewBidderIn(params json.RawMessage) (SchemaFunction[BidderRequestPayload], error) {
+	schemaFunc := &bidderIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Bidders) == 0 {
+		return nil, errors.New("Empty bidders argument in bidderIn schema function")
+	}
+
+	return schemaFunc, nil
+}
+
+func (bi *bidderIn) Call(payload *BidderRequestPayload) (string, error) {
+	found := slices.ContainsFunc(bi.Bidders, func(bidder string) bool {
+		return strings.EqualFold(bidder, payload.Bidder)
+	})
+	return fmt.Sprintf("%t", found), nil
+}
+
+func (bi *bidderIn) Name() string {
+	return BidderIn
+}
+
+// ------------mediaType (request)----------
+type requestMediaType struct{}
+
+func NewRequestMediaType(params json.RawMessage) (SchemaFunction[BidderRequestPayload], error) {
+	if err := checkNilArgs(params, MediaType); err != nil {
+		return nil, err
+	}
+	return &requestMediaType{}, nil
+}
+
+// Call returns the media types of the imps of the bidder request, sorted and joined with "+", e.g.
+// "banner+video".
+func (mt *requestMediaType) Call(payload *BidderRequestPayload) (string, error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return "", nil
+	}
+
+	var mediaTypes []string
+	add := func(present bool, mediaType openrtb_ext.BidType) {
+		if present && !slices.Contains(mediaTypes, string(mediaType)) {
+			mediaTypes = append(mediaTypes, string(mediaType))
+		}
+	}
+	for _, imp := range payload.Request.Imp {
+		add(imp.Banner != nil, openrtb_ext.BidTypeBanner)
+		add(imp.Video != nil, openrtb_ext.BidTypeVideo)
+		add(imp.Audio != nil, openrtb_ext.BidTypeAudio)
+		add(imp.Native != nil, openrtb_ext.BidTypeNative)
+	}
+	slices.Sort(mediaTypes)
+	return strings.Join(mediaTypes, "+"), nil
+}
+
+func (mt *requestMediaType) Name() string {
+	return MediaType
+}
+
+// ------------mediaType (bid)--------------
+type bidMediaType struct{}
+
+func NewBidMediaType(params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	if err := checkNilArgs(params, MediaType); err != nil {
+		return nil, err
+	}
+	return &bidMediaType{}, nil
+}
+
+func (mt *bidMediaType) Call(payload *BidPayload) (string, error) {
+	if payload.Bid == nil {
+		return "", nil
+	}
+	return string(payload.Bid.BidType), nil
+}
+
+func (mt *bidMediaType) Name() string {
+	return MediaType
+}
+
+// ------------bidPrice---------------------
+const (
+	operatorGreaterThan        = "gt"
+	operatorGreaterThanOrEqual = "gte"
+	operatorLessThan           = "lt"
+	operatorLessThanOrEqual    = "lte"
+)
+
+type bidPrice struct {
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+func NewBidPrice(params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	schemaFunc := &bidPrice{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	switch schemaFunc.Operator {
+	case operatorGreaterThan, operatorGreaterThanOrEqual, operatorLessThan, operatorLessThanOrEqual:
+	default:
+		return nil, fmt.Errorf("Invalid operator %q in bidPrice schema function, expected one of gt, gte, lt or lte", schemaFunc.Operator)
+	}
+	if schemaFunc.Currency == "" {
+		schemaFunc.Currency = "USD"
+	}
+
+	return schemaFunc, nil
+}
+
+// Call compares the price of the bid, converted to the currency of the args, with the value of the args.
+func (bp *bidPrice) Call(payload *BidPayload) (string, error) {
+	if payload.Bid == nil || payload.Bid.Bid == nil {
+		return "false", nil
+	}
+
+	price := payload.Bid.Bid.Price
+	bidCurrency := payload.Currency
+	if bidCurrency == "" {
+		bidCurrency = "USD"
+	}
+	if bidCurrency != bp.Currency {
+		if payload.Conversions == nil {
+			return "false", fmt.Errorf("bidPrice schema function can't convert the bid price from %s to %s without currency rates", bidCurrency, bp.Currency)
+		}
+		rate, err := payload.Conversions.GetRate(bidCurrency, bp.Currency)
+		if err != nil {
+			return "false", err
+		}
+		price *= rate
+	}
+
+	var result bool
+	switch bp.Operator {
+	case operatorGreaterThan:
+		result = price > bp.Value
+	case operatorGreaterThanOrEqual:
+		result = price >= bp.Value
+	case operatorLessThan:
+		result = price < bp.Value
+	case operatorLessThanOrEqual:
+		result = price <= bp.Value
+	}
+	return fmt.Sprintf("%t", result), nil
+}
+
+func (bp *bidPrice) Name() string {
+	return BidPrice
+}
+
+// ------------dealPresent------------------
+type dealPresent struct{}
+
+func NewDealPresent(params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	if err := checkNilArgs(params, DealPresent); err != nil {
+		return nil, err
+	}
+	return &dealPresent{}, nil
+}
+
+func (dp *dealPresent) Call(payload *BidPayload) (string, error) {
+	if payload.Bid == nil || payload.Bid.Bid == nil {
+		return "false", nil
+	}
+	return fmt.Sprintf("%t", len(payload.Bid.Bid.DealID) > 0), nil
+}
+
+func (dp *dealPresent) Name() string {
+	return DealPresent
+}
+
+// ------------adomain----------------------
+type adomain struct{}
+
+func NewAdomain(params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	if err := checkNilArgs(params, Adomain); err != nil {
+		return nil, err
+	}
+	return &adomain{}, nil
+}
+
+// Call returns the first advertiser domain of the bid.
+func (ad *adomain) Call(payload *BidPayload) (string, error) {
+	if payload.Bid == nil || payload.Bid.Bid == nil || len(payload.Bid.Bid.ADomain) == 0 {
+		return "", nil
+	}
+	return payload.Bid.Bid.ADomain[0], nil
+}
+
+func (ad *adomain) Name() string {
+	return Adomain
+}
+
+// ------------adomainIn--------------------
+type adomainIn struct {
+	Domains []string `json:"domains"`
+}
+
+func NewAdomainIn(params json.RawMessage) (SchemaFunction[BidPayload], error) {
+	schemaFunc := &adomainIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Domains) == 0 {
+		return nil, errors.New("Empty domains argument in adomainIn schema function")
+	}
+
+	return schemaFunc, nil
+}
+
+func (adi *adomainIn) Call(payload *BidPayload) (string, error) {
+	if payload.Bid == nil || payload.Bid.Bid == nil {
+		return "false", nil
+	}
+
+	for _, domain := range payload.Bid.Bid.ADomain {
+		if slices.Contains(adi.Domains, domain) {
+			return "true", nil
+		}
+	}
+	return "false", nil
+}
+
+func (adi *adomainIn) Name() string {
+	return AdomainIn
+}
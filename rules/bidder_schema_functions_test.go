@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBidderRequestSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		funcName      string
+		params        json.RawMessage
+		expectedName  string
+		expectedError string
+	}{
+		{desc: "bidder", funcName: Bidder, expectedName: Bidder},
+		{desc: "bidderIn", funcName: BidderIn, params: json.RawMessage(`{"bidders": ["appnexus"]}`), expectedName: BidderIn},
+		{desc: "mediaType", funcName: MediaType, expectedName: MediaType},
+		{desc: "request_function", funcName: TcfInScope, expectedName: TcfInScope},
+		{desc: "bid_function", funcName: BidPrice, expectedError: "Schema function bidPrice was not created"},
+		{desc: "bidderIn_without_bidders", funcName: BidderIn, params: json.RawMessage(`{}`), expectedError: "Empty bidders argument in bidderIn schema function"},
+		{desc: "request_function_invalid_args", funcName: Channel, params: json.RawMessage(`{"a": 1}`), expectedError: "channel expects 0 arguments"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewBidderRequestSchemaFunction(tc.funcName, tc.params)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, f)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedName, f.Name())
+		})
+	}
+}
+
+func TestNewBidSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		funcName      string
+		params        json.RawMessage
+		expectedName  string
+		expectedError string
+	}{
+		{desc: "mediaType", funcName: MediaType, expectedName: MediaType},
+		{desc: "bidPrice", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 1}`), expectedName: BidPrice},
+		{desc: "dealPresent", funcName: DealPresent, expectedName: DealPresent},
+		{desc: "adomain", funcName: Adomain, expectedName: Adomain},
+		{desc: "adomainIn", funcName: AdomainIn, params: json.RawMessage(`{"domains": ["example.com"]}`), expectedName: AdomainIn},
+		{desc: "bidder_function", funcName: Bidder, expectedName: Bidder},
+		{desc: "request_function", funcName: DeviceCountry, expectedName: DeviceCountry},
+		{desc: "unknown_function", funcName: "unknown", expectedError: "Schema function unknown was not created"},
+		{desc: "bidPrice_invalid_operator", funcName: BidPrice, params: json.RawMessage(`{"operator": "ne", "value": 1}`), expectedError: `Invalid operator "ne" in bidPrice schema function, expected one of gt, gte, lt or lte`},
+		{desc: "adomainIn_without_domains", funcName: AdomainIn, params: json.RawMessage(`{"domains": []}`), expectedError: "Empty domains argument in adomainIn schema function"},
+		{desc: "dealPresent_with_args", funcName: DealPresent, params: json.RawMessage(`{"a": 1}`), expectedError: "dealPresent expects 0 arguments"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewBidSchemaFunction(tc.funcName, tc.params)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, f)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedName, f.Name())
+		})
+	}
+}
+
+func TestBidderRequestSchemaFunctionsCall(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{
+				{ID: "imp1", Video: &openrtb2.Video{}},
+				{ID: "imp2", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}},
+			},
+			Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+		},
+	}
+	payload := &BidderRequestPayload{Request: request, Bidder: "appnexus"}
+
+	testCases := []struct {
+		desc           string
+		funcName       string
+		params         json.RawMessage
+		payload        *BidderRequestPayload
+		expectedResult string
+	}{
+		{desc: "bidder", funcName: Bidder, payload: payload, expectedResult: "appnexus"},
+		{desc: "bidderIn_found", funcName: BidderIn, params: json.RawMessage(`{"bidders": ["rubicon", "AppNexus"]}`), payload: payload, expectedResult: "true"},
+		{desc: "bidderIn_not_found", funcName: BidderIn, params: json.RawMessage(`{"bidders": ["rubicon"]}`), payload: payload, expectedResult: "false"},
+		{desc: "mediaType", funcName: MediaType, payload: payload, expectedResult: "banner+video"},
+		{desc: "mediaType_nil_request", funcName: MediaType, payload: &BidderRequestPayload{}, expectedResult: ""},
+		{desc: "request_function", funcName: TcfInScope, payload: payload, expectedResult: "true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewBidderRequestSchemaFunction(tc.funcName, tc.params)
+			assert.NoError(t, err)
+
+			result, err := f.Call(tc.payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestBidSchemaFunctionsCall(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+		},
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 2}})
+	newPayload := func(bid *openrtb2.Bid, bidType openrtb_ext.BidType, cur string) *BidPayload {
+		return &BidPayload{
+			BidderRequestPayload: BidderRequestPayload{Request: request, Bidder: "appnexus"},
+			Bid:                  &adapters.TypedBid{Bid: bid, BidType: bidType},
+			Currency:             cur,
+			Conversions:          conversions,
+		}
+	}
+	bid := &openrtb2.Bid{ID: "bid1", Price: 5, DealID: "deal", ADomain: []string{"example.com", "example.org"}}
+
+	testCases := []struct {
+		desc           string
+		funcName       string
+		params         json.RawMessage
+		payload        *BidPayload
+		expectedResult string
+		expectedError  string
+	}{
+		{desc: "mediaType", funcName: MediaType, payload: newPayload(bid, openrtb_ext.BidTypeVideo, "USD"), expectedResult: "video"},
+		{desc: "mediaType_no_bid", funcName: MediaType, payload: &BidPayload{}, expectedResult: ""},
+		{desc: "bidPrice_gt", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 4}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "true"},
+		{desc: "bidPrice_gt_equal", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 5}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, ""), expectedResult: "false"},
+		{desc: "bidPrice_gte", funcName: BidPrice, params: json.RawMessage(`{"operator": "gte", "value": 5}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "true"},
+		{desc: "bidPrice_lt", funcName: BidPrice, params: json.RawMessage(`{"operator": "lt", "value": 5}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "false"},
+		{desc: "bidPrice_lte", funcName: BidPrice, params: json.RawMessage(`{"operator": "lte", "value": 5}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "true"},
+		{desc: "bidPrice_converted", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 8, "currency": "USD"}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "EUR"), expectedResult: "true"},
+		{desc: "bidPrice_missing_rate", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 8, "currency": "JPY"}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "EUR"), expectedResult: "false", expectedError: "Currency conversion rate not found: 'EUR' => 'JPY'"},
+		{desc: "bidPrice_no_conversions", funcName: BidPrice, params: json.RawMessage(`{"operator": "gt", "value": 8, "currency": "EUR"}`), payload: &BidPayload{Bid: &adapters.TypedBid{Bid: bid}}, expectedResult: "false", expectedError: "bidPrice schema function can't convert the bid price from USD to EUR without currency rates"},
+		{desc: "dealPresent", funcName: DealPresent, payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "true"},
+		{desc: "dealPresent_no_deal", funcName: DealPresent, payload: newPayload(&openrtb2.Bid{ID: "bid2"}, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "false"},
+		{desc: "adomain", funcName: Adomain, payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "example.com"},
+		{desc: "adomain_none", funcName: Adomain, payload: newPayload(&openrtb2.Bid{ID: "bid2"}, openrtb_ext.BidTypeBanner, "USD"), expectedResult: ""},
+		{desc: "adomainIn_found", funcName: AdomainIn, params: json.RawMessage(`{"domains": ["example.org"]}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "true"},
+		{desc: "adomainIn_not_found", funcName: AdomainIn, params: json.RawMessage(`{"domains": ["example.net"]}`), payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "false"},
+		{desc: "bidder_function", funcName: Bidder, payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "appnexus"},
+		{desc: "request_function", funcName: DeviceCountry, payload: newPayload(bid, openrtb_ext.BidTypeBanner, "USD"), expectedResult: "USA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewBidSchemaFunction(tc.funcName, tc.params)
+			assert.NoError(t, err)
+
+			result, err := f.Call(tc.payload)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/coocood/freecache"
 	"github.com/golang/glog"
@@ -24,7 +26,8 @@ func NewCache(size int, ttl int, dataType string) stored_requests.CacheJSON {
 	if size > 0 {
 		glog.Infof("Using a Stored %s in-memory cache. Max size: %d bytes. TTL: %d seconds.", dataType, size, ttl)
 		return &cache{
-			dataType: dataType,
+			dataType:  dataType,
+			cacheType: "lru",
 			cache: &pbsLRUCache{
 				Cache:      freecache.NewCache(size),
 				ttlSeconds: ttl,
@@ -33,15 +36,19 @@ func NewCache(size int, ttl int, dataType string) stored_requests.CacheJSON {
 	} else {
 		glog.Infof("Using an unbounded Stored %s in-memory cache.", dataType)
 		return &cache{
-			dataType: dataType,
-			cache:    &pbsSyncMap{&sync.Map{}},
+			dataType:  dataType,
+			cacheType: "unbounded",
+			cache:     &pbsSyncMap{Map: &sync.Map{}},
 		}
 	}
 }
 
 type cache struct {
-	dataType string
-	cache    mapLike
+	dataType  string
+	cacheType string
+	cache     mapLike
+	hits      atomic.Uint64
+	misses    atomic.Uint64
 }
 
 func (c *cache) Get(ctx context.Context, ids []string) (data map[string]json.RawMessage) {
@@ -49,6 +56,9 @@ func (c *cache) Get(ctx context.Context, ids []string) (data map[string]json.Raw
 	for _, id := range ids {
 		if val, ok := c.cache.Get(id); ok {
 			data[id] = val
+			c.hits.Add(1)
+		} else {
+			c.misses.Add(1)
 		}
 	}
 	return
@@ -65,3 +75,29 @@ func (c *cache) Invalidate(ctx context.Context, ids []string) {
 		c.cache.Delete(id)
 	}
 }
+
+func (c *cache) Entries() []stored_requests.CacheEntry {
+	var entries []stored_requests.CacheEntry
+	c.cache.Range(func(id string, value json.RawMessage, savedAt time.Time) bool {
+		entries = append(entries, stored_requests.CacheEntry{ID: id, Size: len(value), SavedAt: savedAt})
+		return true
+	})
+	return entries
+}
+
+func (c *cache) Peek(id string) (stored_requests.CacheEntry, json.RawMessage, bool) {
+	value, savedAt, ok := c.cache.Peek(id)
+	if !ok {
+		return stored_requests.CacheEntry{}, nil, false
+	}
+	return stored_requests.CacheEntry{ID: id, Size: len(value), SavedAt: savedAt}, value, true
+}
+
+func (c *cache) Stats() stored_requests.CacheStats {
+	return stored_requests.CacheStats{
+		Type:    c.cacheType,
+		Entries: c.cache.Len(),
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"math/rand"
+	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/cachestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLRURobustness(t *testing.T) {
@@ -34,6 +38,51 @@ func TestRaceUnboundedConcurrency(t *testing.T) {
 	doRaceTest(t, cache)
 }
 
+func TestLRUInspection(t *testing.T) {
+	assertInspection(t, NewCache(256*1024, -1, "TestData"), "lru")
+}
+
+func TestUnboundedInspection(t *testing.T) {
+	assertInspection(t, NewCache(0, -1, "TestData"), "unbounded")
+}
+
+func assertInspection(t *testing.T, c stored_requests.CacheJSON, expectedType string) {
+	t.Helper()
+
+	inspectable, ok := c.(stored_requests.InspectableCache)
+	require.True(t, ok, "in-memory caches must be inspectable")
+
+	before := time.Now()
+	c.Save(context.Background(), map[string]json.RawMessage{"one": json.RawMessage(`{"id":1}`), "two": json.RawMessage(`2`)})
+	c.Get(context.Background(), []string{"one", "three"})
+
+	entries := inspectable.Entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	require.Len(t, entries, 2)
+	assert.Equal(t, "one", entries[0].ID)
+	assert.Equal(t, 8, entries[0].Size)
+	assert.False(t, entries[0].SavedAt.Before(before.Truncate(time.Second)))
+	assert.Equal(t, "two", entries[1].ID)
+	assert.Equal(t, 1, entries[1].Size)
+
+	entry, value, found := inspectable.Peek("one")
+	assert.True(t, found)
+	assert.Equal(t, "one", entry.ID)
+	assert.JSONEq(t, `{"id":1}`, string(value))
+
+	_, _, found = inspectable.Peek("three")
+	assert.False(t, found)
+
+	assert.Equal(t, stored_requests.CacheStats{Type: expectedType, Entries: 2, Hits: 1, Misses: 1}, inspectable.Stats())
+
+	c.Invalidate(context.Background(), []string{"one"})
+	assert.Len(t, inspectable.Entries(), 1)
+
+	c.Save(context.Background(), map[string]json.RawMessage{"two": json.RawMessage(`3`)})
+	c.Invalidate(context.Background(), []string{"one", "three"})
+	assert.Equal(t, 1, inspectable.Stats().Entries, "the values replaced or missing should not be counted")
+}
+
 func doRaceTest(t *testing.T, cache stored_requests.CacheJSON) {
 	done := make(chan struct{})
 	sets := [][]int{rand.Perm(100), rand.Perm(100), rand.Perm(100)}
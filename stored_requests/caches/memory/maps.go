@@ -1,8 +1,11 @@
 package memory
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/coocood/freecache"
 	"github.com/golang/glog"
@@ -16,30 +19,72 @@ type mapLike interface {
 	Get(id string) (json.RawMessage, bool)
 	Set(id string, value json.RawMessage)
 	Delete(id string)
+	// Peek returns a value and the time it was saved without counting as an access
+	Peek(id string) (json.RawMessage, time.Time, bool)
+	// Range calls f for each value until f returns false
+	Range(f func(id string, value json.RawMessage, savedAt time.Time) bool)
+	// Len returns the number of values stored, without ranging over them
+	Len() int
+}
+
+// syncMapEntry is the value stored in the sync.Map
+type syncMapEntry struct {
+	value   json.RawMessage
+	savedAt time.Time
 }
 
 // sync.Map wrapper which implements the interface
 type pbsSyncMap struct {
 	*sync.Map
+	// length counts the values stored, as the sync.Map doesn't
+	length atomic.Int64
 }
 
 func (m *pbsSyncMap) Get(id string) (json.RawMessage, bool) {
 	val, ok := m.Map.Load(id)
 	if ok {
-		return val.(json.RawMessage), ok
+		return val.(syncMapEntry).value, ok
 	} else {
 		return nil, ok
 	}
 }
 
 func (m *pbsSyncMap) Set(id string, value json.RawMessage) {
-	m.Map.Store(id, value)
+	if _, replaced := m.Map.Swap(id, syncMapEntry{value: value, savedAt: time.Now()}); !replaced {
+		m.length.Add(1)
+	}
 }
 
 func (m *pbsSyncMap) Delete(id string) {
-	m.Map.Delete(id)
+	if _, deleted := m.Map.LoadAndDelete(id); deleted {
+		m.length.Add(-1)
+	}
 }
 
+func (m *pbsSyncMap) Len() int {
+	return int(m.length.Load())
+}
+
+func (m *pbsSyncMap) Peek(id string) (json.RawMessage, time.Time, bool) {
+	val, ok := m.Map.Load(id)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	entry := val.(syncMapEntry)
+	return entry.value, entry.savedAt, true
+}
+
+func (m *pbsSyncMap) Range(f func(id string, value json.RawMessage, savedAt time.Time) bool) {
+	m.Map.Range(func(key, val any) bool {
+		entry := val.(syncMapEntry)
+		return f(key.(string), entry.value, entry.savedAt)
+	})
+}
+
+// savedAtSize is the size of the header prepended to the values stored in the freecache,
+// holding the time the value was saved as unix nanoseconds
+const savedAtSize = 8
+
 // lruCache wrapper which implements the interface
 type pbsLRUCache struct {
 	*freecache.Cache
@@ -49,20 +94,59 @@ type pbsLRUCache struct {
 func (m *pbsLRUCache) Get(id string) (json.RawMessage, bool) {
 	val, err := m.Cache.Get([]byte(id))
 	if err == nil {
-		return val, true
+		value, _ := decodeLRUValue(val)
+		return value, true
 	}
 	if err != freecache.ErrNotFound {
 		glog.Errorf("unexpected error from freecache: %v", err)
 	}
-	return val, false
+	return nil, false
 }
 
 func (m *pbsLRUCache) Set(id string, value json.RawMessage) {
-	if err := m.Cache.Set([]byte(id), value, m.ttlSeconds); err != nil {
+	if err := m.Cache.Set([]byte(id), encodeLRUValue(value, time.Now()), m.ttlSeconds); err != nil {
 		glog.Errorf("error saving value in freecache: %v", err)
 	}
 }
 
+func (m *pbsLRUCache) Peek(id string) (json.RawMessage, time.Time, bool) {
+	val, err := m.Cache.Peek([]byte(id))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	value, savedAt := decodeLRUValue(val)
+	return value, savedAt, true
+}
+
+func (m *pbsLRUCache) Range(f func(id string, value json.RawMessage, savedAt time.Time) bool) {
+	it := m.Cache.NewIterator()
+	for entry := it.Next(); entry != nil; entry = it.Next() {
+		value, savedAt := decodeLRUValue(entry.Value)
+		if !f(string(entry.Key), value, savedAt) {
+			return
+		}
+	}
+}
+
+func encodeLRUValue(value json.RawMessage, savedAt time.Time) []byte {
+	encoded := make([]byte, savedAtSize, savedAtSize+len(value))
+	binary.BigEndian.PutUint64(encoded, uint64(savedAt.UnixNano()))
+	return append(encoded, value...)
+}
+
+func decodeLRUValue(encoded []byte) (json.RawMessage, time.Time) {
+	if len(encoded) < savedAtSize {
+		return encoded, time.Time{}
+	}
+	savedAt := time.Unix(0, int64(binary.BigEndian.Uint64(encoded)))
+	return encoded[savedAtSize:], savedAt
+}
+
 func (m *pbsLRUCache) Delete(id string) {
 	m.Cache.Del([]byte(id))
 }
+
+// Len returns the number of values in the freecache, including the expired ones it hasn't evicted yet
+func (m *pbsLRUCache) Len() int {
+	return int(m.Cache.EntryCount())
+}
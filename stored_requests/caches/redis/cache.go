@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+)
+
+// NewCache returns a Cache which saves the values in a Redis-compatible server shared by all PBS instances.
+// The keys are the IDs prefixed with keyPrefix, so the same server can hold several types of data.
+//
+// Failures to reach the server are logged and treated as cache misses, so the data is fetched from
+// the backends. For no TTL, use ttlSeconds <= 0
+func NewCache(client *Client, keyPrefix string, ttlSeconds int, dataType string) stored_requests.CacheJSON {
+	glog.Infof("Using a Stored %s Redis cache. Address: %s. Key prefix: %s. TTL: %d seconds.", dataType, client.address, keyPrefix, ttlSeconds)
+	c := &cache{
+		client:    client,
+		keyPrefix: keyPrefix,
+		dataType:  dataType,
+	}
+	if ttlSeconds > 0 {
+		c.ttl = []byte(strconv.Itoa(ttlSeconds))
+	}
+	return c
+}
+
+type cache struct {
+	client    *Client
+	keyPrefix string
+	// ttl is nil for values without expiration
+	ttl      []byte
+	dataType string
+}
+
+func (c *cache) key(id string) []byte {
+	return []byte(c.keyPrefix + id)
+}
+
+// Get fetches all the ids with a single MGET command.
+func (c *cache) Get(ctx context.Context, ids []string) (data map[string]json.RawMessage) {
+	data = make(map[string]json.RawMessage, len(ids))
+	if len(ids) == 0 {
+		return
+	}
+
+	command := make([][]byte, 0, len(ids)+1)
+	command = append(command, []byte("MGET"))
+	for _, id := range ids {
+		command = append(command, c.key(id))
+	}
+
+	replies, err := c.client.do(ctx, command)
+	if err != nil {
+		c.logError("get", err)
+		return
+	}
+
+	values := replies[0].array
+	for i, id := range ids {
+		if i < len(values) && !values[i].null {
+			data[id] = values[i].str
+		}
+	}
+	return
+}
+
+// Save pipelines a SET command for each value.
+func (c *cache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	if len(data) == 0 {
+		return
+	}
+
+	commands := make([][][]byte, 0, len(data))
+	for id, value := range data {
+		command := [][]byte{[]byte("SET"), c.key(id), value}
+		if c.ttl != nil {
+			command = append(command, []byte("EX"), c.ttl)
+		}
+		commands = append(commands, command)
+	}
+
+	if _, err := c.client.do(ctx, commands...); err != nil {
+		c.logError("save", err)
+	}
+}
+
+// Invalidate deletes all the ids with a single DEL command.
+func (c *cache) Invalidate(ctx context.Context, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	command := make([][]byte, 0, len(ids)+1)
+	command = append(command, []byte("DEL"))
+	for _, id := range ids {
+		command = append(command, c.key(id))
+	}
+
+	if _, err := c.client.do(ctx, command); err != nil {
+		// the other PBS instances may keep getting the stale values until they expire
+		glog.Errorf("Failed to invalidate Stored %s in the Redis cache: %v", c.dataType, err)
+	}
+}
+
+func (c *cache) logError(operation string, err error) {
+	// failures to communicate with the server are logged once by the client when it marks the server down
+	if _, isServerErr := err.(serverError); !isServerErr {
+		return
+	}
+	glog.Warningf("Failed to %s Stored %s in the Redis cache: %v", operation, c.dataType, err)
+}
@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/cachestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClientConfig(address string) config.RedisCache {
+	return config.RedisCache{
+		Enabled:       true,
+		Address:       address,
+		PoolSize:      2,
+		DialTimeout:   500,
+		Timeout:       500,
+		RetryInterval: 60000,
+	}
+}
+
+func TestRedisRobustness(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+
+	cachestest.AssertCacheRobustness(t, func() stored_requests.CacheJSON {
+		return NewCache(client, "pbs:robustness:"+t.Name()+":", -1, "TestData")
+	})
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+
+	cache := NewCache(client, "pbs:stored_requests:requests:", 60, "Requests")
+	ctx := context.Background()
+
+	cache.Save(ctx, map[string]json.RawMessage{
+		"one": json.RawMessage(`{"id":"one"}`),
+		"two": json.RawMessage(`{"id":"two"}`),
+	})
+
+	saved, ok := server.get(0, "pbs:stored_requests:requests:one")
+	require.True(t, ok, "values must be saved under the key prefix")
+	assert.Equal(t, `{"id":"one"}`, string(saved.value))
+	assert.WithinDuration(t, time.Now().Add(60*time.Second), saved.expiresAt, 5*time.Second)
+
+	data := cache.Get(ctx, []string{"one", "two", "three"})
+	assert.Equal(t, map[string]json.RawMessage{
+		"one": json.RawMessage(`{"id":"one"}`),
+		"two": json.RawMessage(`{"id":"two"}`),
+	}, data)
+
+	cache.Invalidate(ctx, []string{"one"})
+	assert.Equal(t, map[string]json.RawMessage{"two": json.RawMessage(`{"id":"two"}`)}, cache.Get(ctx, []string{"one", "two"}))
+
+	// the three ids are fetched with a single command
+	assert.Equal(t, []string{"SET", "SET", "MGET", "DEL", "MGET"}, server.receivedCommands())
+}
+
+func TestClientSetGet(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+	ctx := context.Background()
+
+	err := client.Set(ctx, []KeyValue{
+		{Key: "pbs:cache:one", Value: []byte(`"one"`), TTLSeconds: 300},
+		{Key: "pbs:cache:two", Value: []byte(`"two"`)},
+	})
+	require.NoError(t, err)
+
+	saved, ok := server.get(0, "pbs:cache:one")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(300*time.Second), saved.expiresAt, 5*time.Second)
+	saved, ok = server.get(0, "pbs:cache:two")
+	require.True(t, ok)
+	assert.True(t, saved.expiresAt.IsZero(), "a value without TTL should not expire")
+
+	values, err := client.Get(ctx, []string{"pbs:cache:one", "pbs:cache:three", "pbs:cache:two"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte(`"one"`), nil, []byte(`"two"`)}, values)
+
+	assert.NoError(t, client.Set(ctx, nil))
+	values, err = client.Get(ctx, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+	assert.Equal(t, []string{"SET", "SET", "MGET"}, server.receivedCommands())
+	assert.Equal(t, server.address(), client.Address())
+}
+
+func TestRedisCacheNoTTL(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+
+	NewCache(client, "pbs:", 0, "Accounts").Save(context.Background(), map[string]json.RawMessage{"account": json.RawMessage(`{}`)})
+
+	saved, ok := server.get(0, "pbs:account")
+	require.True(t, ok)
+	assert.True(t, saved.expiresAt.IsZero())
+}
+
+func TestRedisCacheAuthAndDatabase(t *testing.T) {
+	server := newTestServer(t, "secret")
+
+	cfg := testClientConfig(server.address())
+	cfg.Password = "secret"
+	cfg.Database = 3
+	client := NewClient(cfg)
+	defer client.Close()
+
+	cache := NewCache(client, "pbs:", 60, "Accounts")
+	cache.Save(context.Background(), map[string]json.RawMessage{"account": json.RawMessage(`{}`)})
+
+	_, ok := server.get(3, "pbs:account")
+	assert.True(t, ok, "values must be saved in the selected database")
+	assert.Len(t, cache.Get(context.Background(), []string{"account"}), 1)
+}
+
+func TestRedisCacheWrongPassword(t *testing.T) {
+	server := newTestServer(t, "secret")
+
+	cfg := testClientConfig(server.address())
+	cfg.Password = "wrong"
+	client := NewClient(cfg)
+	defer client.Close()
+
+	cache := NewCache(client, "pbs:", 60, "Accounts")
+	cache.Save(context.Background(), map[string]json.RawMessage{"account": json.RawMessage(`{}`)})
+
+	assert.Empty(t, cache.Get(context.Background(), []string{"account"}))
+	_, ok := server.get(0, "pbs:account")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheServerDown(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+
+	cache := NewCache(client, "pbs:", 60, "Requests")
+	ctx := context.Background()
+	cache.Save(ctx, map[string]json.RawMessage{"one": json.RawMessage(`{}`)})
+	require.Len(t, cache.Get(ctx, []string{"one"}), 1)
+
+	server.close()
+
+	assert.Empty(t, cache.Get(ctx, []string{"one"}), "an unavailable server must be treated as a cache miss")
+	assert.False(t, client.available(), "the server must be skipped after a failure")
+
+	// no connection attempt is made while the server is marked down
+	start := time.Now()
+	assert.Empty(t, cache.Get(ctx, []string{"one"}))
+	cache.Save(ctx, map[string]json.RawMessage{"two": json.RawMessage(`{}`)})
+	cache.Invalidate(ctx, []string{"one"})
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRedisCacheServerRecovers(t *testing.T) {
+	server := newTestServer(t, "")
+
+	cfg := testClientConfig(server.address())
+	cfg.RetryInterval = 0
+	client := NewClient(cfg)
+	defer client.Close()
+
+	cache := NewCache(client, "pbs:", 60, "Requests")
+	ctx := context.Background()
+	cache.Save(ctx, map[string]json.RawMessage{"one": json.RawMessage(`{}`)})
+
+	// the pooled connection is dropped by the server, the next command opens a new one
+	server.mu.Lock()
+	for _, c := range server.conns {
+		c.Close()
+	}
+	server.mu.Unlock()
+
+	assert.Empty(t, cache.Get(ctx, []string{"one"}))
+	assert.Len(t, cache.Get(ctx, []string{"one"}), 1)
+}
+
+func TestRedisCacheCanceledContext(t *testing.T) {
+	server := newTestServer(t, "")
+	client := NewClient(testClientConfig(server.address()))
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cache := NewCache(client, "pbs:", 60, "Requests")
+	assert.Empty(t, cache.Get(ctx, []string{"one"}))
+	assert.True(t, client.available(), "requests running out of time must not mark the server down")
+}
@@ -0,0 +1,288 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+var errServerDown = errors.New("redis: server marked as unavailable")
+
+// Client sends pipelined commands to a Redis-compatible server over a pool of connections.
+//
+// A failure to reach the server marks it as unavailable for the configured retry interval, during which
+// commands fail immediately so requests fall back to the stored data backends without waiting on timeouts.
+type Client struct {
+	address       string
+	password      string
+	database      int
+	useTLS        bool
+	dialTimeout   time.Duration
+	timeout       time.Duration
+	retryInterval time.Duration
+
+	idle chan *conn
+
+	mu        sync.Mutex
+	downUntil time.Time
+	closed    bool
+}
+
+// NewClient returns a Client for the server described by the config. Connections are opened on demand.
+func NewClient(cfg config.RedisCache) *Client {
+	return &Client{
+		address:       cfg.Address,
+		password:      cfg.Password,
+		database:      cfg.Database,
+		useTLS:        cfg.TLS,
+		dialTimeout:   time.Duration(cfg.DialTimeout) * time.Millisecond,
+		timeout:       time.Duration(cfg.Timeout) * time.Millisecond,
+		retryInterval: time.Duration(cfg.RetryInterval) * time.Millisecond,
+		idle:          make(chan *conn, cfg.PoolSize),
+	}
+}
+
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	w       *bufio.Writer
+}
+
+// do sends the commands in a single round trip and returns their replies. The error is the first
+// error replied by the server or the failure to communicate with it.
+func (c *Client) do(ctx context.Context, commands ...[][]byte) ([]reply, error) {
+	if !c.available() {
+		return nil, errServerDown
+	}
+
+	cn, err := c.getConn(ctx)
+	if err != nil {
+		c.markDown(ctx, err)
+		return nil, err
+	}
+
+	replies, err := cn.pipeline(ctx, c.timeout, commands)
+	if _, isServerErr := err.(serverError); err != nil && !isServerErr {
+		cn.netConn.Close()
+		c.markDown(ctx, err)
+		return nil, err
+	}
+
+	c.putConn(cn)
+	return replies, err
+}
+
+// KeyValue is a value saved by Set, expiring after TTLSeconds. TTLSeconds <= 0 can be used for "no ttl".
+type KeyValue struct {
+	Key        string
+	Value      []byte
+	TTLSeconds int
+}
+
+// KeyValueClient is the subset of the Client used by the stores keeping their values in the server, such as
+// the cache backend and the UID store.
+type KeyValueClient interface {
+	Set(ctx context.Context, values []KeyValue) error
+	Get(ctx context.Context, keys []string) ([][]byte, error)
+	Close()
+}
+
+// Set pipelines a SET command for each value.
+func (c *Client) Set(ctx context.Context, values []KeyValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	commands := make([][][]byte, 0, len(values))
+	for _, value := range values {
+		command := [][]byte{[]byte("SET"), []byte(value.Key), value.Value}
+		if value.TTLSeconds > 0 {
+			command = append(command, []byte("EX"), []byte(strconv.Itoa(value.TTLSeconds)))
+		}
+		commands = append(commands, command)
+	}
+
+	_, err := c.do(ctx, commands...)
+	return err
+}
+
+// Get fetches all the keys with a single MGET command. The values of the missing keys are nil.
+func (c *Client) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	command := make([][]byte, 0, len(keys)+1)
+	command = append(command, []byte("MGET"))
+	for _, key := range keys {
+		command = append(command, []byte(key))
+	}
+
+	replies, err := c.do(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, len(keys))
+	for i, value := range replies[0].array {
+		if i < len(values) && !value.null {
+			values[i] = value.str
+		}
+	}
+	return values, nil
+}
+
+// Address returns the host:port of the server.
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Close closes the idle connections. Connections in use are closed when they are released.
+func (c *Client) Close() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.netConn.Close()
+		default:
+			return
+		}
+	}
+}
+
+func (c *Client) available() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.closed && !time.Now().Before(c.downUntil)
+}
+
+func (c *Client) markDown(ctx context.Context, err error) {
+	// the request running out of time doesn't tell anything about the server
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	wasUp := !time.Now().Before(c.downUntil)
+	c.downUntil = time.Now().Add(c.retryInterval)
+	c.mu.Unlock()
+
+	if wasUp {
+		glog.Warningf("Redis server %s is unavailable, stored data will be fetched from the backends for the next %v: %v", c.address, c.retryInterval, err)
+	}
+}
+
+func (c *Client) getConn(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+		return c.dial(ctx)
+	}
+}
+
+func (c *Client) putConn(cn *conn) {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+
+	if closed {
+		cn.netConn.Close()
+		return
+	}
+
+	select {
+	case c.idle <- cn:
+	default:
+		cn.netConn.Close()
+	}
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.address)
+		tlsConn := tls.Client(netConn, &tls.Config{ServerName: host})
+		tlsConn.SetDeadline(time.Now().Add(c.dialTimeout))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+		netConn = tlsConn
+	}
+
+	cn := &conn{
+		netConn: netConn,
+		r:       bufio.NewReader(netConn),
+		w:       bufio.NewWriter(netConn),
+	}
+
+	var handshake [][][]byte
+	if c.password != "" {
+		handshake = append(handshake, [][]byte{[]byte("AUTH"), []byte(c.password)})
+	}
+	if c.database != 0 {
+		handshake = append(handshake, [][]byte{[]byte("SELECT"), []byte(strconv.Itoa(c.database))})
+	}
+	if len(handshake) > 0 {
+		if _, err := cn.pipeline(ctx, c.dialTimeout, handshake); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+// pipeline writes all the commands before reading their replies. All the replies are read
+// even if the server replies with errors, so the connection can be reused.
+func (cn *conn) pipeline(ctx context.Context, timeout time.Duration, commands [][][]byte) ([]reply, error) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := cn.netConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	for _, command := range commands {
+		if err := writeCommand(cn.w, command...); err != nil {
+			return nil, err
+		}
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	replies := make([]reply, len(commands))
+	for i := range replies {
+		r, err := readReply(cn.r)
+		if err != nil {
+			if _, isServerErr := err.(serverError); !isServerErr {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		replies[i] = r
+	}
+	return replies, firstErr
+}
@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// This file implements the subset of the RESP2 protocol (https://redis.io/docs/reference/protocol-spec/)
+// needed by the cache: commands are sent as arrays of bulk strings and the replies of the commands
+// used are simple strings, errors, integers, bulk strings and arrays of bulk strings.
+
+// maxBulkLength bounds the size of a bulk string reply to guard against corrupted streams.
+const maxBulkLength = 512 * 1024 * 1024
+
+// reply is a value sent by the server.
+type reply struct {
+	// str holds simple and bulk strings
+	str []byte
+	// null is true for null bulk strings and arrays, e.g. the missing keys of a MGET
+	null    bool
+	integer int64
+	array   []reply
+}
+
+// serverError is an error reply sent by the server, the connection remains usable.
+type serverError string
+
+func (e serverError) Error() string {
+	return "redis: " + string(e)
+}
+
+var errProtocol = errors.New("redis: protocol error")
+
+func writeCommand(w *bufio.Writer, args ...[]byte) error {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(len(args)))
+	w.WriteString("\r\n")
+	for _, arg := range args {
+		w.WriteByte('$')
+		w.WriteString(strconv.Itoa(len(arg)))
+		w.WriteString("\r\n")
+		w.Write(arg)
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readReply(r *bufio.Reader) (reply, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return reply{}, err
+	}
+	if len(line) == 0 {
+		return reply{}, errProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		// the line is only valid until the next read
+		return reply{str: append([]byte(nil), line[1:]...)}, nil
+	case '-':
+		return reply{}, serverError(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return reply{}, errProtocol
+		}
+		return reply{integer: n}, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || n > maxBulkLength {
+			return reply{}, errProtocol
+		}
+		if n < 0 {
+			return reply{null: true}, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return reply{}, err
+		}
+		if buf[n] != '\r' || buf[n+1] != '\n' {
+			return reply{}, errProtocol
+		}
+		return reply{str: buf[:n]}, nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return reply{}, errProtocol
+		}
+		if n < 0 {
+			return reply{null: true}, nil
+		}
+		array := make([]reply, n)
+		for i := range array {
+			// errors nested in arrays are only sent for transactions, which are not used
+			if array[i], err = readReply(r); err != nil {
+				return reply{}, err
+			}
+		}
+		return reply{array: array}, nil
+	}
+	return reply{}, fmt.Errorf("%w: unexpected reply type %q", errProtocol, line[0])
+}
+
+// readLine returns the next line without its CRLF terminator.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		if err == bufio.ErrBufferFull {
+			return nil, errProtocol
+		}
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	return line[:len(line)-2], nil
+}
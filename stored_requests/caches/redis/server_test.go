@@ -0,0 +1,165 @@
+package redis
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer is an in-process stand-in for a Redis server, implementing the commands used by the cache.
+type testServer struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[int]map[string]testValue
+	commands []string
+	conns    []net.Conn
+}
+
+type testValue struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func newTestServer(t *testing.T, password string) *testServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the test server: %v", err)
+	}
+
+	s := &testServer{
+		listener: listener,
+		password: password,
+		data:     make(map[int]map[string]testValue),
+	}
+	go s.serve()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *testServer) address() string {
+	return s.listener.Addr().String()
+}
+
+// close stops the server and drops the open connections.
+func (s *testServer) close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+}
+
+func (s *testServer) serve() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		go s.handle(c)
+	}
+}
+
+func (s *testServer) handle(c net.Conn) {
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	authenticated := s.password == ""
+	database := 0
+
+	for {
+		command, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		args := make([]string, len(command.array))
+		for i, arg := range command.array {
+			args[i] = string(arg.str)
+		}
+		name := strings.ToUpper(args[0])
+
+		s.mu.Lock()
+		s.commands = append(s.commands, name)
+		db := s.data[database]
+		if db == nil {
+			db = make(map[string]testValue)
+			s.data[database] = db
+		}
+
+		switch {
+		case name == "AUTH":
+			if args[1] == s.password {
+				authenticated = true
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid password\r\n")
+			}
+		case !authenticated:
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		case name == "SELECT":
+			database, _ = strconv.Atoi(args[1])
+			w.WriteString("+OK\r\n")
+		case name == "MGET":
+			w.WriteString("*" + strconv.Itoa(len(args)-1) + "\r\n")
+			for _, key := range args[1:] {
+				v, ok := db[key]
+				if !ok || (!v.expiresAt.IsZero() && time.Now().After(v.expiresAt)) {
+					w.WriteString("$-1\r\n")
+					continue
+				}
+				w.WriteString("$" + strconv.Itoa(len(v.value)) + "\r\n" + string(v.value) + "\r\n")
+			}
+		case name == "SET":
+			v := testValue{value: []byte(args[2])}
+			if len(args) == 5 && strings.ToUpper(args[3]) == "EX" {
+				seconds, _ := strconv.Atoi(args[4])
+				v.expiresAt = time.Now().Add(time.Duration(seconds) * time.Second)
+			}
+			db[args[1]] = v
+			w.WriteString("+OK\r\n")
+		case name == "DEL":
+			deleted := 0
+			for _, key := range args[1:] {
+				if _, ok := db[key]; ok {
+					delete(db, key)
+					deleted++
+				}
+			}
+			w.WriteString(":" + strconv.Itoa(deleted) + "\r\n")
+		default:
+			w.WriteString("-ERR unknown command '" + args[0] + "'\r\n")
+		}
+		s.mu.Unlock()
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *testServer) get(database int, key string) (testValue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[database][key]
+	return v, ok
+}
+
+func (s *testServer) receivedCommands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
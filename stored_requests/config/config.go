@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"maps"
 	"net/http"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/memory"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/nil_cache"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	apiEvents "github.com/prebid/prebid-server/v3/stored_requests/events/api"
 	databaseEvents "github.com/prebid/prebid-server/v3/stored_requests/events/database"
@@ -29,13 +31,14 @@ import (
 //
 // 1. A Fetcher which can be used to get Stored Requests
 // 2. A function which should be called on shutdown for graceful cleanups.
+// 3. The in-memory caches, so they can be inspected from the admin endpoints.
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func(), caches stored_requests.NamedCaches) {
 	// Create database connection if given options for one
 	if cfg.Database.ConnectionInfo.Database != "" {
 		if provider == nil {
@@ -58,18 +61,27 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
 	fetcher = newFetcher(cfg, client, provider)
 
-	var shutdown1 func()
+	var shutdown1, shutdown2 func()
 
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
+		if cfg.RedisCache.Enabled {
+			redisClient := redis.NewClient(cfg.RedisCache)
+			cache = withRedisCache(cfg, cache, redisClient)
+			shutdown2 = redisClient.Close
+		}
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
 		shutdown1 = addListeners(cache, eventProducers)
+		caches = namedCaches(cfg, cache)
 	}
 
 	shutdown = func() {
 		if shutdown1 != nil {
 			shutdown1()
 		}
+		if shutdown2 != nil {
+			shutdown2()
+		}
 
 		if provider == nil {
 			return
@@ -91,6 +103,8 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 // 4. A Fetcher which can be used to get Account data
 // 5. A Fetcher which can be used to get Category Mapping data
 // 6. A Fetcher which can be used to get Stored Requests for /openrtb2/video
+// 7. A Fetcher which can be used to get Stored Responses
+// 8. The in-memory caches of all the stored data, so they can be inspected from the admin endpoints
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
@@ -103,16 +117,17 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	accountsFetcher stored_requests.AccountFetcher,
 	categoriesFetcher stored_requests.CategoryFetcher,
 	videoFetcher stored_requests.Fetcher,
-	storedRespFetcher stored_requests.Fetcher) {
+	storedRespFetcher stored_requests.Fetcher,
+	caches stored_requests.NamedCaches) {
 
 	var provider db_provider.DbProvider
 
-	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
-	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
-	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
-	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher1, shutdown1, caches1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
+	fetcher2, shutdown2, caches2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
+	fetcher3, shutdown3, caches3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
+	fetcher4, shutdown4, caches4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
+	fetcher5, shutdown5, caches5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
+	fetcher6, shutdown6, caches6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
 
 	fetcher = fetcher1.(stored_requests.Fetcher)
 	ampFetcher = fetcher2.(stored_requests.Fetcher)
@@ -121,6 +136,11 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	accountsFetcher = fetcher5.(stored_requests.AccountFetcher)
 	storedRespFetcher = fetcher6.(stored_requests.Fetcher)
 
+	caches = make(stored_requests.NamedCaches)
+	for _, c := range []stored_requests.NamedCaches{caches1, caches2, caches3, caches4, caches5, caches6} {
+		maps.Copy(caches, c)
+	}
+
 	shutdown = func() {
 		shutdown1()
 		shutdown2()
@@ -193,6 +213,50 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	return cache
 }
 
+// withRedisCache adds the Redis caches behind the in-memory caches, or in place of the caches
+// if there is no in-memory cache.
+func withRedisCache(cfg *config.StoredRequests, cache stored_requests.Cache, client *redis.Client) stored_requests.Cache {
+	keyPrefix := cfg.RedisCache.Namespace + ":" + cfg.Section() + ":"
+	layer := func(c stored_requests.CacheJSON, name string, dataType string) stored_requests.CacheJSON {
+		redisCache := redis.NewCache(client, keyPrefix+name+":", cfg.RedisCache.TTL, dataType)
+		if _, isNil := c.(*nil_cache.NilCache); isNil {
+			return redisCache
+		}
+		return stored_requests.TieredCache{Local: c, Shared: redisCache}
+	}
+
+	if cfg.DataType() == config.AccountDataType {
+		cache.Accounts = layer(cache.Accounts, "accounts", "Accounts")
+	} else {
+		cache.Requests = layer(cache.Requests, "requests", "Requests")
+		cache.Imps = layer(cache.Imps, "imps", "Imps")
+		cache.Responses = layer(cache.Responses, "responses", "Responses")
+	}
+	return cache
+}
+
+// namedCaches returns the inspectable caches of a config section, named "<section>.<data>". The caches backed by
+// Redis are inspected through their in-memory cache and invalidated in both.
+func namedCaches(cfg *config.StoredRequests, cache stored_requests.Cache) stored_requests.NamedCaches {
+	caches := make(stored_requests.NamedCaches)
+	for name, c := range map[string]stored_requests.CacheJSON{
+		"requests":  cache.Requests,
+		"imps":      cache.Imps,
+		"responses": cache.Responses,
+		"accounts":  cache.Accounts,
+	} {
+		if tiered, ok := c.(stored_requests.TieredCache); ok {
+			if local, ok := tiered.Local.(stored_requests.InspectableCache); ok {
+				c = stored_requests.InspectableTieredCache{TieredCache: tiered, Inspectable: local}
+			}
+		}
+		if inspectable, ok := c.(stored_requests.InspectableCache); ok {
+			caches[cfg.Section()+"."+name] = inspectable
+		}
+	}
+	return caches
+}
+
 func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
 	if cfg.CacheEvents.Enabled {
 		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,6 +21,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
 	"github.com/stretchr/testify/mock"
@@ -199,6 +202,54 @@ func TestNewInMemoryAccountCache(t *testing.T) {
 	assert.True(t, isEmptyCacheType(cache.Responses), "The newCache method should return an empty Responses cache for Accounts config")
 }
 
+func TestNamedCaches(t *testing.T) {
+	cfg := typedConfig(config.RequestDataType, &config.StoredRequests{
+		InMemoryCache: config.InMemoryCache{
+			TTL:              60,
+			RequestCacheSize: 100,
+			ImpCacheSize:     100,
+			RespCacheSize:    100,
+		},
+	})
+	caches := namedCaches(cfg, newCache(cfg))
+	assert.ElementsMatch(t, []string{"stored_requests.requests", "stored_requests.imps", "stored_requests.responses"}, slices.Collect(maps.Keys(caches)))
+
+	accountsCfg := typedConfig(config.AccountDataType, &config.StoredRequests{InMemoryCache: config.InMemoryCache{Type: "none"}})
+	assert.Empty(t, namedCaches(accountsCfg, newCache(accountsCfg)))
+}
+
+func TestWithRedisCache(t *testing.T) {
+	client := redis.NewClient(config.RedisCache{Address: "127.0.0.1:0", PoolSize: 1, DialTimeout: 10, Timeout: 10})
+	defer client.Close()
+
+	cfg := typedConfig(config.RequestDataType, &config.StoredRequests{
+		InMemoryCache: config.InMemoryCache{
+			TTL:              60,
+			RequestCacheSize: 100,
+			ImpCacheSize:     100,
+			RespCacheSize:    100,
+		},
+		RedisCache: config.RedisCache{Namespace: "pbs", TTL: 60},
+	})
+	cache := withRedisCache(cfg, newCache(cfg), client)
+	assert.IsType(t, stored_requests.TieredCache{}, cache.Requests, "The in-memory Request cache should be backed by the Redis cache")
+	assert.IsType(t, stored_requests.TieredCache{}, cache.Imps, "The in-memory Imp cache should be backed by the Redis cache")
+	assert.IsType(t, stored_requests.TieredCache{}, cache.Responses, "The in-memory Responses cache should be backed by the Redis cache")
+	assert.True(t, isEmptyCacheType(cache.Accounts), "The Account cache should remain empty for StoredRequests config")
+	caches := namedCaches(cfg, cache)
+	assert.Len(t, caches, 3, "The in-memory caches should remain inspectable")
+	assert.IsType(t, stored_requests.InspectableTieredCache{}, caches["stored_requests.requests"], "The invalidations should reach the Redis cache")
+
+	accountsCfg := typedConfig(config.AccountDataType, &config.StoredRequests{
+		InMemoryCache: config.InMemoryCache{Type: "none"},
+		RedisCache:    config.RedisCache{Namespace: "pbs", TTL: 60},
+	})
+	accountsCache := withRedisCache(accountsCfg, newCache(accountsCfg), client)
+	_, isTiered := accountsCache.Accounts.(stored_requests.TieredCache)
+	assert.False(t, isTiered, "The Redis cache should replace the empty Account cache")
+	assert.True(t, isEmptyCacheType(accountsCache.Requests), "The Request cache should remain empty for Accounts config")
+}
+
 func TestNewDatabaseEventProducers(t *testing.T) {
 	metricsMock := &metrics.MetricsEngineMock{}
 	metricsMock.Mock.On("RecordStoredDataFetchTime", mock.Anything, mock.Anything).Return()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/metrics"
 )
@@ -90,6 +91,40 @@ type CacheJSON interface {
 	Save(ctx context.Context, data map[string]json.RawMessage)
 }
 
+// InspectableCache is a CacheJSON which can list its entries and report its lookup statistics.
+// It is used by the admin endpoints to debug the cached data without affecting the cache.
+type InspectableCache interface {
+	CacheJSON
+
+	// Entries returns the IDs, sizes and save times of the values in the cache.
+	Entries() []CacheEntry
+
+	// Peek returns the value saved at the given ID without counting as a lookup.
+	Peek(id string) (CacheEntry, json.RawMessage, bool)
+
+	// Stats returns the lookup statistics of the cache since it was created.
+	Stats() CacheStats
+}
+
+// CacheEntry describes a value held by an InspectableCache.
+type CacheEntry struct {
+	ID      string
+	Size    int
+	SavedAt time.Time
+}
+
+// CacheStats holds the lookup statistics of an InspectableCache.
+type CacheStats struct {
+	Type    string
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+// NamedCaches holds the inspectable caches of the stored data, named after the config section
+// and the data they hold, e.g. "stored_requests.imps" or "accounts.accounts".
+type NamedCaches map[string]InspectableCache
+
 // ComposedCache creates an interface to treat a slice of caches as a single cache
 type ComposedCache []CacheJSON
 
@@ -145,6 +180,64 @@ func (c ComposedCache) Save(ctx context.Context, data map[string]json.RawMessage
 	}
 }
 
+// TieredCache treats a local cache and a shared cache as a single cache. Values missing from the
+// local cache are looked up in the shared cache and saved to the local cache when found there.
+type TieredCache struct {
+	Local  CacheJSON
+	Shared CacheJSON
+}
+
+// Get looks up the ids in the local cache, then the ids it missed in the shared cache
+func (c TieredCache) Get(ctx context.Context, ids []string) (data map[string]json.RawMessage) {
+	data = c.Local.Get(ctx, ids)
+
+	remainingIDs := findLeftovers(ids, data)
+	if len(remainingIDs) == 0 {
+		return
+	}
+
+	sharedData := c.Shared.Get(ctx, remainingIDs)
+	if len(sharedData) > 0 {
+		c.Local.Save(ctx, sharedData)
+		data = mergeData(data, sharedData)
+	}
+	return
+}
+
+// Invalidate will propagate invalidations to both caches
+func (c TieredCache) Invalidate(ctx context.Context, ids []string) {
+	c.Local.Invalidate(ctx, ids)
+	c.Shared.Invalidate(ctx, ids)
+}
+
+// Save will propagate saves to both caches
+func (c TieredCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	c.Local.Save(ctx, data)
+	c.Shared.Save(ctx, data)
+}
+
+// InspectableTieredCache is a TieredCache inspected through its local cache. The values invalidated by the admin
+// endpoints are invalidated in the shared cache too, so the next lookup doesn't read them back from it.
+type InspectableTieredCache struct {
+	TieredCache
+	Inspectable InspectableCache
+}
+
+// Entries returns the entries of the local cache
+func (c InspectableTieredCache) Entries() []CacheEntry {
+	return c.Inspectable.Entries()
+}
+
+// Peek returns the value saved at the given ID in the local cache
+func (c InspectableTieredCache) Peek(id string) (CacheEntry, json.RawMessage, bool) {
+	return c.Inspectable.Peek(id)
+}
+
+// Stats returns the lookup statistics of the local cache
+func (c InspectableTieredCache) Stats() CacheStats {
+	return c.Inspectable.Stats()
+}
+
 type fetcherWithCache struct {
 	fetcher       AllFetcher
 	cache         Cache
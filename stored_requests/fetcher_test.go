@@ -333,6 +333,66 @@ func TestComposedCache(t *testing.T) {
 	assert.JSONEq(t, `{"id": "3"}`, string(respData["3"]), "FetchResponses should fetch the right resp data")
 }
 
+func TestTieredCache(t *testing.T) {
+	local := &mockCache{}
+	shared := &mockCache{}
+	cache := TieredCache{Local: local, Shared: shared}
+	ctx := context.Background()
+
+	local.On("Get", ctx, []string{"1", "2", "3"}).Return(map[string]json.RawMessage{
+		"1": json.RawMessage(`{"id": "1"}`),
+	})
+	shared.On("Get", ctx, []string{"2", "3"}).Return(map[string]json.RawMessage{
+		"2": json.RawMessage(`{"id": "2"}`),
+	})
+	local.On("Save", ctx, map[string]json.RawMessage{"2": json.RawMessage(`{"id": "2"}`)})
+
+	data := cache.Get(ctx, []string{"1", "2", "3"})
+
+	local.AssertExpectations(t)
+	shared.AssertExpectations(t)
+	assert.Equal(t, map[string]json.RawMessage{
+		"1": json.RawMessage(`{"id": "1"}`),
+		"2": json.RawMessage(`{"id": "2"}`),
+	}, data, "Get should merge the local and shared data")
+}
+
+func TestTieredCacheLocalHit(t *testing.T) {
+	local := &mockCache{}
+	shared := &mockCache{}
+	cache := TieredCache{Local: local, Shared: shared}
+	ctx := context.Background()
+
+	local.On("Get", ctx, []string{"1"}).Return(map[string]json.RawMessage{
+		"1": json.RawMessage(`{"id": "1"}`),
+	})
+
+	data := cache.Get(ctx, []string{"1"})
+
+	local.AssertExpectations(t)
+	shared.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	assert.Len(t, data, 1)
+}
+
+func TestTieredCachePropagation(t *testing.T) {
+	local := &mockCache{}
+	shared := &mockCache{}
+	cache := TieredCache{Local: local, Shared: shared}
+	ctx := context.Background()
+	data := map[string]json.RawMessage{"1": json.RawMessage(`{"id": "1"}`)}
+
+	local.On("Save", ctx, data)
+	shared.On("Save", ctx, data)
+	local.On("Invalidate", ctx, []string{"1"})
+	shared.On("Invalidate", ctx, []string{"1"})
+
+	cache.Save(ctx, data)
+	cache.Invalidate(ctx, []string{"1"})
+
+	local.AssertExpectations(t)
+	shared.AssertExpectations(t)
+}
+
 type mockFetcher struct {
 	mock.Mock
 }
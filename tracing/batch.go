@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+type batchOptions struct {
+	batchSize     int
+	queueSize     int
+	flushInterval time.Duration
+	exportTimeout time.Duration
+}
+
+// batchProcessor collects finished spans off the request path and exports them in batches,
+// either when a batch is full or when the flush interval elapses.
+type batchProcessor struct {
+	exporter Exporter
+	opts     batchOptions
+	queue    chan SpanData
+	done     chan struct{}
+	stopped  sync.WaitGroup
+	closed   atomic.Bool
+	dropped  atomic.Int64
+}
+
+func newBatchProcessor(exporter Exporter, opts batchOptions) *batchProcessor {
+	p := &batchProcessor{
+		exporter: exporter,
+		opts:     opts,
+		queue:    make(chan SpanData, opts.queueSize),
+		done:     make(chan struct{}),
+	}
+	p.stopped.Add(1)
+	go p.run()
+	return p
+}
+
+// enqueue never blocks the caller. Spans are dropped when the queue is full.
+func (p *batchProcessor) enqueue(span SpanData) {
+	if p.closed.Load() {
+		return
+	}
+	select {
+	case p.queue <- span:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+func (p *batchProcessor) shutdown() {
+	if p.closed.Swap(true) {
+		return
+	}
+	close(p.done)
+	p.stopped.Wait()
+}
+
+func (p *batchProcessor) run() {
+	defer p.stopped.Done()
+
+	ticker := time.NewTicker(p.opts.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, p.opts.batchSize)
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.opts.batchSize {
+				batch = p.flush(batch)
+			}
+		case <-ticker.C:
+			batch = p.flush(batch)
+		case <-p.done:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+					if len(batch) >= p.opts.batchSize {
+						batch = p.flush(batch)
+					}
+				default:
+					p.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *batchProcessor) flush(batch []SpanData) []SpanData {
+	if dropped := p.dropped.Swap(0); dropped > 0 {
+		glog.Warningf("Tracing queue is full, dropped %d spans", dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.exportTimeout)
+	defer cancel()
+	if err := p.exporter.Export(ctx, batch); err != nil {
+		glog.Warningf("Failed to export %d spans: %v", len(batch), err)
+	}
+	return make([]SpanData, 0, p.opts.batchSize)
+}
@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectorStandIn mimics the OTLP/HTTP receiver of an OpenTelemetry collector.
+type collectorStandIn struct {
+	mu       sync.Mutex
+	requests []otlpExportRequest
+	headers  []http.Header
+	status   int
+}
+
+func (c *collectorStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != otlpTracesPath || r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	var req otlpExportRequest
+	if err := jsonutil.UnmarshalValid(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, r.Header)
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+}
+
+func (c *collectorStandIn) spans() []otlpSpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []otlpSpan
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func TestOTLPExporterEndToEnd(t *testing.T) {
+	collector := &collectorStandIn{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	tracer := NewTracer(config.Tracing{
+		Enabled:      true,
+		ServiceName:  "pbs-test",
+		SamplingRate: 1,
+		OTLP: config.TracingOTLP{
+			Endpoint:        server.URL + "/",
+			Headers:         map[string]string{"X-Api-Key": "secret"},
+			TimeoutMs:       1000,
+			BatchSize:       2,
+			QueueSize:       10,
+			FlushIntervalMs: 60000,
+		},
+	}, server.Client())
+	require.NotNil(t, tracer)
+
+	ctx, root := tracer.StartRootSpan(context.Background(), "openrtb2.auction", http.Header{})
+	_, bidderSpan := StartSpan(ctx, "bidder.appnexus", SpanKindClient,
+		String("pbs.bidder", "appnexus"), Int("pbs.imps", 2), Bool("pbs.debug", true), Float64("pbs.price", 1.5))
+	bidderSpan.SetError(errors.New("timeout"))
+	bidderSpan.End()
+	_, cacheSpan := StartSpan(ctx, "prebid_cache.put", SpanKindClient)
+	cacheSpan.End()
+	root.End()
+	tracer.Shutdown()
+
+	spans := collector.spans()
+	require.Len(t, spans, 3)
+	assert.Len(t, collector.requests, 2, "a full batch and the remainder flushed on shutdown")
+	assert.Equal(t, "secret", collector.headers[0].Get("X-Api-Key"))
+
+	resource := collector.requests[0].ResourceSpans[0].Resource
+	require.Len(t, resource.Attributes, 1)
+	assert.Equal(t, "service.name", resource.Attributes[0].Key)
+	assert.Equal(t, "pbs-test", *resource.Attributes[0].Value.StringValue)
+
+	bidder := spans[0]
+	assert.Equal(t, "bidder.appnexus", bidder.Name)
+	assert.Equal(t, root.SpanContext().TraceID.String(), bidder.TraceID)
+	assert.Equal(t, root.SpanContext().SpanID.String(), bidder.ParentSpanID)
+	assert.Equal(t, int(SpanKindClient), bidder.Kind)
+	assert.Equal(t, int(StatusError), bidder.Status.Code)
+	assert.Equal(t, "timeout", bidder.Status.Message)
+	require.Len(t, bidder.Attributes, 4)
+	assert.Equal(t, "appnexus", *bidder.Attributes[0].Value.StringValue)
+	assert.Equal(t, "2", *bidder.Attributes[1].Value.IntValue)
+	assert.True(t, *bidder.Attributes[2].Value.BoolValue)
+	assert.Equal(t, 1.5, *bidder.Attributes[3].Value.DoubleValue)
+
+	assert.Equal(t, "openrtb2.auction", spans[2].Name)
+	assert.Empty(t, spans[2].ParentSpanID)
+	assert.Equal(t, int(SpanKindServer), spans[2].Kind)
+	assert.NotEmpty(t, spans[2].StartTimeUnixNano)
+	assert.NotEmpty(t, spans[2].EndTimeUnixNano)
+}
+
+func TestOTLPExporterFlushInterval(t *testing.T) {
+	collector := &collectorStandIn{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.Client(), server.URL, nil, "pbs-test")
+	tracer := newTracer(1, exporter, batchOptions{batchSize: 100, queueSize: 100, flushInterval: 10 * time.Millisecond, exportTimeout: time.Second})
+	defer tracer.Shutdown()
+
+	_, span := tracer.StartRootSpan(context.Background(), "root", http.Header{})
+	span.End()
+
+	assert.Eventually(t, func() bool { return len(collector.spans()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestOTLPExporterErrorStatus(t *testing.T) {
+	collector := &collectorStandIn{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.Client(), server.URL, nil, "pbs-test")
+	err := exporter.Export(context.Background(), []SpanData{{Name: "span"}})
+
+	assert.EqualError(t, err, "OTLP collector "+server.URL+"/v1/traces responded with status 503")
+}
+
+func TestBatchProcessorDropsWhenFull(t *testing.T) {
+	blocked := make(chan struct{})
+	exporter := &blockingExporter{release: blocked}
+	p := newBatchProcessor(exporter, batchOptions{batchSize: 1, queueSize: 1, flushInterval: time.Hour, exportTimeout: time.Second})
+
+	for i := 0; i < 10; i++ {
+		p.enqueue(SpanData{Name: "span"})
+	}
+	close(blocked)
+	p.shutdown()
+
+	assert.Less(t, exporter.count, 10)
+	p.enqueue(SpanData{Name: "after_shutdown"})
+}
+
+type blockingExporter struct {
+	release chan struct{}
+	count   int
+}
+
+func (e *blockingExporter) Export(_ context.Context, spans []SpanData) error {
+	<-e.release
+	e.count += len(spans)
+	return nil
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header. See https://www.w3.org/TR/trace-context/
+const TraceparentHeader = "Traceparent"
+
+const (
+	traceparentVersion = "00"
+	flagSampled        = "01"
+	flagNotSampled     = "00"
+)
+
+var errInvalidTraceparent = errors.New("invalid traceparent header")
+
+// Inject writes the traceparent of the span active in ctx to the outgoing request headers.
+// Nothing is written when the request is not traced.
+func Inject(ctx context.Context, header http.Header) {
+	span := SpanFromContext(ctx)
+	if span == nil || header == nil {
+		return
+	}
+	header.Set(TraceparentHeader, FormatTraceparent(span.SpanContext()))
+}
+
+// FormatTraceparent encodes the span context as a version 00 traceparent value.
+func FormatTraceparent(sc SpanContext) string {
+	flags := flagNotSampled
+	if sc.Sampled {
+		flags = flagSampled
+	}
+	return traceparentVersion + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// Extract reads the span context of the caller from the incoming request headers.
+func Extract(header http.Header) (SpanContext, error) {
+	value := header.Get(TraceparentHeader)
+	if value == "" {
+		return SpanContext{}, nil
+	}
+	return ParseTraceparent(value)
+}
+
+// ParseTraceparent decodes a traceparent value. Future versions are accepted as long as they
+// start with the version 00 fields, as required by the specification.
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, errInvalidTraceparent
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == traceparentVersion && len(parts) != 4) {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return SpanContext{}, errInvalidTraceparent
+	}
+
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceID)); err != nil || strings.ToLower(traceID) != traceID {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanID)); err != nil || strings.ToLower(spanID) != spanID {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	flagBytes, err := hex.DecodeString(flags)
+	if err != nil {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, errInvalidTraceparent
+	}
+
+	sc.Sampled = flagBytes[0]&0x01 == 0x01
+	return sc, nil
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	testCases := []struct {
+		description     string
+		value           string
+		expectedContext SpanContext
+		expectedError   error
+	}{
+		{
+			description: "sampled",
+			value:       "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedContext: SpanContext{
+				TraceID: TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+				SpanID:  SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+				Sampled: true,
+			},
+		},
+		{
+			description: "not_sampled",
+			value:       "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expectedContext: SpanContext{
+				TraceID: TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+				SpanID:  SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			},
+		},
+		{
+			description: "future_version_with_extra_fields",
+			value:       "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			expectedContext: SpanContext{
+				TraceID: TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+				SpanID:  SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+				Sampled: true,
+			},
+		},
+		{
+			description:   "version_00_with_extra_fields",
+			value:         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "forbidden_version",
+			value:         "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "zero_trace_id",
+			value:         "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "zero_span_id",
+			value:         "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "uppercase_trace_id",
+			value:         "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "short_trace_id",
+			value:         "00-4bf92f3577b34da6-00f067aa0ba902b7-01",
+			expectedError: errInvalidTraceparent,
+		},
+		{
+			description:   "malformed",
+			value:         "garbage",
+			expectedError: errInvalidTraceparent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			sc, err := ParseTraceparent(tc.value)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedContext, sc)
+		})
+	}
+}
+
+func TestFormatTraceparentRoundTrip(t *testing.T) {
+	sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+
+	parsed, err := ParseTraceparent(FormatTraceparent(sc))
+
+	assert.NoError(t, err)
+	assert.Equal(t, sc, parsed)
+}
+
+func TestInject(t *testing.T) {
+	tracer := newTracer(1, &mockExporter{}, testBatchOptions)
+	defer tracer.Shutdown()
+
+	t.Run("traced", func(t *testing.T) {
+		ctx, span := tracer.StartRootSpan(context.Background(), "root", http.Header{})
+		header := http.Header{}
+
+		Inject(ctx, header)
+
+		assert.Equal(t, FormatTraceparent(span.SpanContext()), header.Get(TraceparentHeader))
+	})
+
+	t.Run("not_traced", func(t *testing.T) {
+		header := http.Header{}
+
+		Inject(context.Background(), header)
+
+		assert.Empty(t, header)
+	})
+}
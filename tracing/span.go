@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies all the spans which belong to one trace.
+type TraceID [16]byte
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a single span within a trace.
+type SpanID [8]byte
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is the part of a span which is propagated across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind describes the relationship between a span and its parent, using the OTLP numbering.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode is the outcome of a span, using the OTLP numbering.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key/value pair describing a span. Values are limited to
+// strings, booleans, int64 and float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is a read-only snapshot of a finished span handed to an Exporter.
+type SpanData struct {
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	Name          string
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span records the timing and outcome of one unit of work. All methods are safe to call
+// on a nil Span, which is what StartSpan returns when the request is not traced.
+type Span struct {
+	tracer       *Tracer
+	spanContext  SpanContext
+	parentSpanID SpanID
+	name         string
+	kind         SpanKind
+	startTime    time.Time
+
+	mu            sync.Mutex
+	ended         bool
+	endTime       time.Time
+	attributes    []Attribute
+	status        StatusCode
+	statusMessage string
+}
+
+// SpanContext returns the propagated identity of the span.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.spanContext
+}
+
+// SetAttributes adds attributes to the span. Attributes set after End are ignored.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil || !s.spanContext.Sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.attributes = append(s.attributes, attrs...)
+	}
+}
+
+// SetError marks the span as failed. A nil error leaves the status untouched.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.status = StatusError
+		s.statusMessage = err.Error()
+	}
+}
+
+// End finishes the span and hands it to the tracer for export if it was sampled.
+// Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.endTime = time.Now()
+	s.mu.Unlock()
+
+	if s.spanContext.Sampled && s.tracer != nil {
+		s.tracer.export(s.snapshot())
+	}
+}
+
+func (s *Span) snapshot() SpanData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SpanData{
+		SpanContext:   s.spanContext,
+		ParentSpanID:  s.parentSpanID,
+		Name:          s.name,
+		Kind:          s.kind,
+		StartTime:     s.startTime,
+		EndTime:       s.endTime,
+		Attributes:    s.attributes,
+		Status:        s.status,
+		StatusMessage: s.statusMessage,
+	}
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying the span as the parent for new spans.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the active span of ctx or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts a child of the span active in ctx. When ctx carries no span the request is
+// not traced, so ctx is returned unchanged along with a nil span.
+func StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil || parent.tracer == nil {
+		return ctx, nil
+	}
+
+	span := parent.tracer.newSpan(parent.spanContext, name, kind)
+	span.SetAttributes(attrs...)
+	return ContextWithSpan(ctx, span), span
+}
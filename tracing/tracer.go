@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// Tracer creates the root span of each traced request and hands finished spans to the
+// batch processor. A nil Tracer is valid and traces nothing.
+type Tracer struct {
+	samplingRate float64
+	processor    *batchProcessor
+}
+
+// NewTracer builds a Tracer exporting spans to the configured OTLP collector.
+// It returns nil if tracing is disabled.
+func NewTracer(cfg config.Tracing, client *http.Client) *Tracer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	exporter := NewOTLPExporter(client, cfg.OTLP.Endpoint, cfg.OTLP.Headers, cfg.ServiceName)
+	return newTracer(cfg.SamplingRate, exporter, batchOptions{
+		batchSize:     cfg.OTLP.BatchSize,
+		queueSize:     cfg.OTLP.QueueSize,
+		flushInterval: cfg.OTLP.FlushInterval(),
+		exportTimeout: cfg.OTLP.Timeout(),
+	})
+}
+
+func newTracer(samplingRate float64, exporter Exporter, opts batchOptions) *Tracer {
+	return &Tracer{
+		samplingRate: samplingRate,
+		processor:    newBatchProcessor(exporter, opts),
+	}
+}
+
+// StartRootSpan starts the server span of an incoming request. If the request headers carry a
+// valid W3C traceparent, the span joins that trace and follows the caller's sampling decision.
+func (t *Tracer) StartRootSpan(ctx context.Context, name string, header http.Header) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent, _ := Extract(header)
+	span := t.newSpan(parent, name, SpanKindServer)
+	return ContextWithSpan(ctx, span), span
+}
+
+// Shutdown exports all spans which are still queued. Spans ended afterwards are dropped.
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	t.processor.shutdown()
+}
+
+// WrapHandle runs handle inside a server span named after the endpoint. The span is available
+// from the request context to everything the handler calls.
+func WrapHandle(t *Tracer, name string, handle httprouter.Handle) httprouter.Handle {
+	if t == nil {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		ctx, span := t.StartRootSpan(r.Context(), name, r.Header)
+		defer span.End()
+
+		span.SetAttributes(String("http.method", r.Method), String("http.route", name))
+		handle(w, r.WithContext(ctx), params)
+	}
+}
+
+func (t *Tracer) newSpan(parent SpanContext, name string, kind SpanKind) *Span {
+	span := &Span{
+		tracer:    t,
+		name:      name,
+		kind:      kind,
+		startTime: time.Now(),
+	}
+
+	if parent.IsValid() {
+		span.spanContext.TraceID = parent.TraceID
+		span.spanContext.Sampled = parent.Sampled
+		span.parentSpanID = parent.SpanID
+	} else {
+		span.spanContext.TraceID = newTraceID()
+		span.spanContext.Sampled = t.shouldSample(span.spanContext.TraceID)
+	}
+	span.spanContext.SpanID = newSpanID()
+
+	return span
+}
+
+// shouldSample derives the decision from the trace id, so that every service using the same
+// ratio based sampling makes the same decision for a trace.
+func (t *Tracer) shouldSample(traceID TraceID) bool {
+	if t.samplingRate >= 1 {
+		return true
+	}
+	if t.samplingRate <= 0 {
+		return false
+	}
+	threshold := uint64(t.samplingRate * math.MaxUint64)
+	return binary.BigEndian.Uint64(traceID[8:]) < threshold
+}
+
+func (t *Tracer) export(span SpanData) {
+	t.processor.enqueue(span)
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
@@ -0,0 +1,230 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBatchOptions = batchOptions{
+	batchSize:     10,
+	queueSize:     100,
+	flushInterval: time.Hour,
+	exportTimeout: time.Second,
+}
+
+type mockExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *mockExporter) Export(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *mockExporter) exported() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.spans
+}
+
+func TestNewTracerDisabled(t *testing.T) {
+	tracer := NewTracer(config.Tracing{Enabled: false}, http.DefaultClient)
+
+	assert.Nil(t, tracer)
+
+	ctx, span := tracer.StartRootSpan(context.Background(), "root", http.Header{})
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+	tracer.Shutdown()
+}
+
+func TestSpanHierarchy(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := newTracer(1, exporter, testBatchOptions)
+
+	ctx, root := tracer.StartRootSpan(context.Background(), "root", http.Header{})
+	childCtx, child := StartSpan(ctx, "child", SpanKindClient, String("pbs.bidder", "appnexus"))
+	_, grandChild := StartSpan(childCtx, "grandchild", SpanKindInternal)
+	grandChild.SetError(errors.New("failed"))
+	grandChild.End()
+	child.End()
+	root.End()
+	tracer.Shutdown()
+
+	spans := exporter.exported()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "grandchild", spans[0].Name)
+	assert.Equal(t, StatusError, spans[0].Status)
+	assert.Equal(t, "failed", spans[0].StatusMessage)
+	assert.Equal(t, child.SpanContext().SpanID, spans[0].ParentSpanID)
+
+	assert.Equal(t, "child", spans[1].Name)
+	assert.Equal(t, SpanKindClient, spans[1].Kind)
+	assert.Equal(t, []Attribute{String("pbs.bidder", "appnexus")}, spans[1].Attributes)
+	assert.Equal(t, root.SpanContext().SpanID, spans[1].ParentSpanID)
+
+	assert.Equal(t, "root", spans[2].Name)
+	assert.Equal(t, SpanKindServer, spans[2].Kind)
+	assert.False(t, spans[2].ParentSpanID.IsValid())
+
+	for _, span := range spans {
+		assert.Equal(t, root.SpanContext().TraceID, span.SpanContext.TraceID)
+	}
+}
+
+func TestStartSpanWithoutParent(t *testing.T) {
+	ctx := context.Background()
+
+	newCtx, span := StartSpan(ctx, "orphan", SpanKindInternal)
+
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, newCtx)
+
+	// all span methods must be safe on a nil span
+	span.SetAttributes(String("key", "value"))
+	span.SetError(errors.New("error"))
+	span.End()
+}
+
+func TestSpanEndOnlyOnce(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := newTracer(1, exporter, testBatchOptions)
+
+	_, span := tracer.StartRootSpan(context.Background(), "root", http.Header{})
+	span.End()
+	span.End()
+	span.SetAttributes(String("late", "attribute"))
+	tracer.Shutdown()
+
+	spans := exporter.exported()
+	require.Len(t, spans, 1)
+	assert.Empty(t, spans[0].Attributes)
+}
+
+func TestSampling(t *testing.T) {
+	testCases := []struct {
+		description     string
+		samplingRate    float64
+		traceparent     string
+		expectedSampled bool
+		expectedTraceID string
+	}{
+		{
+			description:     "always",
+			samplingRate:    1,
+			expectedSampled: true,
+		},
+		{
+			description:     "never",
+			samplingRate:    0,
+			expectedSampled: false,
+		},
+		{
+			description:     "parent_sampled_overrides_rate",
+			samplingRate:    0,
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedSampled: true,
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			description:     "parent_not_sampled_overrides_rate",
+			samplingRate:    1,
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expectedSampled: false,
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			description:     "invalid_parent_ignored",
+			samplingRate:    1,
+			traceparent:     "invalid",
+			expectedSampled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			exporter := &mockExporter{}
+			tracer := newTracer(tc.samplingRate, exporter, testBatchOptions)
+			header := http.Header{}
+			if tc.traceparent != "" {
+				header.Set(TraceparentHeader, tc.traceparent)
+			}
+
+			_, span := tracer.StartRootSpan(context.Background(), "root", header)
+			span.End()
+			tracer.Shutdown()
+
+			assert.Equal(t, tc.expectedSampled, span.SpanContext().Sampled)
+			if tc.expectedTraceID != "" {
+				assert.Equal(t, tc.expectedTraceID, span.SpanContext().TraceID.String())
+			}
+			if tc.expectedSampled {
+				assert.Len(t, exporter.exported(), 1)
+			} else {
+				assert.Empty(t, exporter.exported())
+			}
+		})
+	}
+}
+
+func TestSamplingRatio(t *testing.T) {
+	tracer := newTracer(0.25, &mockExporter{}, testBatchOptions)
+	defer tracer.Shutdown()
+
+	sampled := 0
+	for i := 0; i < 10000; i++ {
+		if tracer.shouldSample(newTraceID()) {
+			sampled++
+		}
+	}
+
+	assert.InDelta(t, 2500, sampled, 300)
+}
+
+func TestWrapHandle(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := newTracer(1, exporter, testBatchOptions)
+
+	var handlerSpan *Span
+	handle := WrapHandle(tracer, "openrtb2.auction", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		handlerSpan = SpanFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handle(httptest.NewRecorder(), req, nil)
+	tracer.Shutdown()
+
+	require.NotNil(t, handlerSpan)
+	spans := exporter.exported()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "openrtb2.auction", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID.String())
+}
+
+func TestWrapHandleDisabled(t *testing.T) {
+	called := false
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		called = true
+		assert.Nil(t, SpanFromContext(r.Context()))
+	}
+
+	WrapHandle(nil, "openrtb2.auction", handle)(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil), nil)
+
+	assert.True(t, called)
+}
@@ -0,0 +1,166 @@
+package usersync
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+
+	"github.com/golang/glog"
+)
+
+// Codec encodes and decodes the uids cookie
+type Codec interface {
+	Encoder
+	Decoder
+}
+
+// Base64Codec keeps the UIDs in the uids cookie itself
+type Base64Codec struct {
+	Base64Encoder
+	Base64Decoder
+}
+
+// NewCodec returns the Codec of the uids cookie, protecting the UIDs held by the cookie with the key ring of the host
+// cookie and keeping the UIDs in the store if any.
+func NewCodec(hostCookie *config.HostCookie, cfg *config.UIDStore, store UIDStore, me metrics.MetricsEngine) (Codec, error) {
+	cookieCodec, err := NewKeyRingCodec(&hostCookie.KeyRing, me)
+	if err != nil || store == nil {
+		return cookieCodec, err
+	}
+	return NewStoreCodec(store, cfg.SigningKey, hostCookie.TTLDuration(), time.Duration(cfg.TimeoutMs)*time.Millisecond, cookieCodec, me), nil
+}
+
+// NewStoreCodec returns a Codec keeping the UIDs in the store, the uids cookie only carrying the PBS user key signed
+// with the signing key. The UIDs are kept for the ttl, and each store call is bounded by the timeout.
+//
+// The cookies holding the UIDs, read with the cookieCodec, are migrated to the store the first time they're decoded,
+// under a user key derived from the cookie so decoding the same cookie again finds the migrated UIDs until the cookie
+// is written back. The opted out cookies are written with the cookieCodec.
+func NewStoreCodec(store UIDStore, signingKey string, ttl, timeout time.Duration, cookieCodec Codec, me metrics.MetricsEngine) Codec {
+	return &storeCodec{
+		store:         store,
+		signingKey:    []byte(signingKey),
+		ttl:           ttl,
+		timeout:       timeout,
+		uuidGenerator: uuidutil.UUIDRandomGenerator{},
+		cookieCodec:   cookieCodec,
+		metrics:       me,
+	}
+}
+
+type storeCodec struct {
+	store         UIDStore
+	signingKey    []byte
+	ttl           time.Duration
+	timeout       time.Duration
+	uuidGenerator uuidutil.UUIDGenerator
+	cookieCodec   Codec
+	metrics       metrics.MetricsEngine
+}
+
+// Encode stores the UIDs of the cookie and returns its signed user key.
+// The opted out cookies don't have UIDs, so they are encoded as is once the stored UIDs are cleared.
+func (c *storeCodec) Encode(cookie *Cookie) (string, error) {
+	if !cookie.AllowSyncs() {
+		c.clear(cookie)
+		return c.cookieCodec.Encode(cookie)
+	}
+	if cookie.storeErr != nil {
+		return "", cookie.storeErr
+	}
+
+	key := cookie.userKey
+	if key == "" {
+		var err error
+		if key, err = c.uuidGenerator.Generate(); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.store.Set(ctx, key, cookie.uids, c.ttl); err != nil {
+		return "", err
+	}
+	cookie.userKey = key
+	cookie.migrated = false
+	return key + "." + sign(c.signingKey, key), nil
+}
+
+// Decode returns the cookie of the user key, with the UIDs of the store. The cookies with an invalid signature are ignored.
+func (c *storeCodec) Decode(encodedValue string) *Cookie {
+	// the user keys are the only cookies made of two parts
+	if strings.Count(encodedValue, ".") != 1 {
+		return c.migrate(encodedValue)
+	}
+	key, signature, _ := strings.Cut(encodedValue, ".")
+	if !hmac.Equal([]byte(signature), []byte(sign(c.signingKey, key))) {
+		c.metrics.RecordUIDCookieVerificationFailure(metrics.UIDCookieInvalid)
+		return NewCookie()
+	}
+	return c.load(key)
+}
+
+func (c *storeCodec) load(key string) *Cookie {
+	cookie := NewCookie()
+	cookie.userKey = key
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	uids, found, err := c.store.Get(ctx, key)
+	if err != nil {
+		glog.Warningf("Unable to read the UIDs of a user: %v", err)
+		// the cookie can't be written back without losing the UIDs which couldn't be read
+		cookie.storeErr = errors.New("the UIDs of the user are unavailable")
+		return cookie
+	}
+	if found {
+		cookie.uids = uids
+	}
+	return cookie
+}
+
+// migrate moves the UIDs of a cookie holding them to the store
+func (c *storeCodec) migrate(encodedValue string) *Cookie {
+	cookie := c.cookieCodec.Decode(encodedValue)
+	if !cookie.AllowSyncs() || len(cookie.uids) == 0 {
+		return cookie
+	}
+
+	key := sign(c.signingKey, "legacy:"+encodedValue)
+	stored := c.load(key)
+	if stored.storeErr != nil {
+		return cookie
+	}
+	if len(stored.uids) > 0 {
+		stored.migrated = true
+		return stored
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.store.Set(ctx, key, cookie.uids, c.ttl); err != nil {
+		glog.Warningf("Unable to migrate the UIDs of a user: %v", err)
+		return cookie
+	}
+	cookie.userKey = key
+	cookie.migrated = true
+	return cookie
+}
+
+func (c *storeCodec) clear(cookie *Cookie) {
+	if cookie == nil || cookie.userKey == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.store.Set(ctx, cookie.userKey, map[string]UIDEntry{}, c.ttl); err != nil {
+		glog.Warningf("Unable to clear the UIDs of an opted out user: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUUIDGenerator struct {
+	id  string
+	err error
+}
+
+func (g fakeUUIDGenerator) Generate() (string, error) {
+	return g.id, g.err
+}
+
+// failingUIDStore fails every read and write
+type failingUIDStore struct{}
+
+func (failingUIDStore) Get(context.Context, string) (map[string]UIDEntry, bool, error) {
+	return nil, false, errors.New("store unavailable")
+}
+
+func (failingUIDStore) Set(context.Context, string, map[string]UIDEntry, time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func (failingUIDStore) Shutdown() {}
+
+func newTestStoreCodec(store UIDStore) *storeCodec {
+	codec := NewStoreCodec(store, "secret", time.Hour, time.Second, Base64Codec{}, &metricsConf.NilMetricsEngine{}).(*storeCodec)
+	codec.uuidGenerator = fakeUUIDGenerator{id: "user-key"}
+	return codec
+}
+
+func TestNewCodec(t *testing.T) {
+	me := &metricsConf.NilMetricsEngine{}
+
+	codec, err := NewCodec(&config.HostCookie{}, &config.UIDStore{}, nil, me)
+	assert.NoError(t, err)
+	assert.Equal(t, Base64Codec{}, codec)
+
+	keyRing := config.CookieKeyRing{Mode: config.CookieProtectionSign, Keys: []config.CookieKey{{ID: "key", Secret: "MDEyMzQ1Njc4OWFiY2RlZg=="}}}
+	codec, err = NewCodec(&config.HostCookie{KeyRing: keyRing}, &config.UIDStore{}, nil, me)
+	assert.NoError(t, err)
+	assert.IsType(t, &keyRingCodec{}, codec)
+
+	codec, err = NewCodec(&config.HostCookie{TTL: 90, KeyRing: keyRing}, &config.UIDStore{SigningKey: "secret", TimeoutMs: 50}, NewMemoryUIDStore(10), me)
+	assert.NoError(t, err)
+	if assert.IsType(t, &storeCodec{}, codec) {
+		assert.IsType(t, &keyRingCodec{}, codec.(*storeCodec).cookieCodec, "the cookies holding UIDs should be read with the key ring")
+	}
+
+	_, err = NewCodec(&config.HostCookie{KeyRing: config.CookieKeyRing{Mode: config.CookieProtectionEncrypt}}, &config.UIDStore{}, nil, me)
+	assert.EqualError(t, err, "no key to encrypt the uids cookie")
+}
+
+func TestStoreCodecEncodeDecode(t *testing.T) {
+	store := NewMemoryUIDStore(10)
+	codec := newTestStoreCodec(store)
+
+	cookie := NewCookie()
+	assert.NoError(t, cookie.Sync("adnxs", "123"))
+	encoded, err := codec.Encode(cookie)
+	assert.NoError(t, err)
+
+	key, _, _ := strings.Cut(encoded, ".")
+	assert.Equal(t, "user-key", key, "the cookie should only carry the user key")
+	stored, found, _ := store.Get(context.Background(), "user-key")
+	assert.True(t, found)
+	assert.Equal(t, "123", stored["adnxs"].UID)
+
+	decoded := codec.Decode(encoded)
+	uid, _, isActive := decoded.GetUID("adnxs")
+	assert.Equal(t, "123", uid)
+	assert.True(t, isActive)
+
+	assert.NoError(t, decoded.Sync("rubicon", "456"))
+	reencoded, err := codec.Encode(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, reencoded, "the user key should be kept")
+	assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, codec.Decode(reencoded).GetUIDs())
+}
+
+func TestStoreCodecDecodeInvalidSignature(t *testing.T) {
+	store := NewMemoryUIDStore(10)
+	assert.NoError(t, store.Set(context.Background(), "user-key", testUIDs, time.Hour))
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordUIDCookieVerificationFailure", metrics.UIDCookieInvalid)
+	codec := newTestStoreCodec(store)
+	codec.metrics = me
+
+	tampered := "user-key." + sign([]byte("secret"), "other-key")
+	assert.Empty(t, codec.Decode(tampered).GetUIDs())
+	assert.Empty(t, codec.Decode("user-key."+sign([]byte("other-secret"), "user-key")).GetUIDs())
+	me.AssertNumberOfCalls(t, "RecordUIDCookieVerificationFailure", 2)
+}
+
+func TestStoreCodecMigration(t *testing.T) {
+	store := NewMemoryUIDStore(10)
+	codec := newTestStoreCodec(store)
+
+	legacy := NewCookie()
+	assert.NoError(t, legacy.Sync("adnxs", "123"))
+	legacyValue, err := Base64Encoder{}.Encode(legacy)
+	assert.NoError(t, err)
+
+	migrated := codec.Decode(legacyValue)
+	assert.True(t, migrated.Migrated())
+	assert.Equal(t, map[string]string{"adnxs": "123"}, migrated.GetUIDs())
+
+	// the cookie can be seen again before it's written back
+	assert.NoError(t, migrated.Sync("rubicon", "456"))
+	encoded, err := codec.Encode(migrated)
+	assert.NoError(t, err)
+	assert.False(t, migrated.Migrated())
+
+	again := codec.Decode(legacyValue)
+	assert.True(t, again.Migrated())
+	assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, again.GetUIDs(), "the migrated UIDs should be found under the same user key")
+	assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, codec.Decode(encoded).GetUIDs())
+}
+
+func TestStoreCodecOptOut(t *testing.T) {
+	store := NewMemoryUIDStore(10)
+	codec := newTestStoreCodec(store)
+
+	cookie := NewCookie()
+	assert.NoError(t, cookie.Sync("adnxs", "123"))
+	encoded, err := codec.Encode(cookie)
+	assert.NoError(t, err)
+
+	decoded := codec.Decode(encoded)
+	decoded.SetOptOut(true)
+	optOutValue, err := codec.Encode(decoded)
+	assert.NoError(t, err)
+	assert.False(t, codec.Decode(optOutValue).AllowSyncs())
+
+	stored, _, _ := store.Get(context.Background(), "user-key")
+	assert.Empty(t, stored, "the stored UIDs of the user should be cleared")
+}
+
+func TestStoreCodecStoreErrors(t *testing.T) {
+	codec := newTestStoreCodec(failingUIDStore{})
+
+	cookie := NewCookie()
+	assert.NoError(t, cookie.Sync("adnxs", "123"))
+	_, err := codec.Encode(cookie)
+	assert.EqualError(t, err, "store unavailable")
+
+	decoded := codec.Decode("user-key." + sign([]byte("secret"), "user-key"))
+	assert.Empty(t, decoded.GetUIDs())
+	assert.NoError(t, decoded.Sync("adnxs", "123"))
+	_, err = codec.Encode(decoded)
+	assert.EqualError(t, err, "the UIDs of the user are unavailable", "the UIDs which couldn't be read should not be overwritten")
+
+	legacyValue, _ := Base64Encoder{}.Encode(cookie)
+	legacy := codec.Decode(legacyValue)
+	assert.False(t, legacy.Migrated())
+	assert.Equal(t, map[string]string{"adnxs": "123"}, legacy.GetUIDs(), "the UIDs of the cookie should be used until they can be migrated")
+}
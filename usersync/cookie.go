@@ -23,6 +23,13 @@ const uidTTL = 14 * 24 * time.Hour
 type Cookie struct {
 	uids   map[string]UIDEntry
 	optOut bool
+
+	// userKey is the PBS user key of the UIDs kept in the UID store
+	userKey string
+	// migrated is true if the UIDs were just moved from the cookie to the UID store
+	migrated bool
+	// storeErr is set if the UIDs couldn't be read from the UID store
+	storeErr error
 }
 
 // UIDEntry bundles the UID with an Expiration date.
@@ -61,7 +68,7 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 	for len(cookie.uids) > 0 {
 		encodedCookie, err := encoder.Encode(cookie)
 		if err != nil {
-			return encodedCookie, nil
+			return "", err
 		}
 
 		// Convert to HTTP Cookie to Get Size
@@ -157,6 +164,12 @@ func (cookie *Cookie) AllowSyncs() bool {
 	return cookie != nil && !cookie.optOut
 }
 
+// Migrated is true if the UIDs of the cookie were just moved to the UID store, so the cookie should be written back
+// with the user key of the UIDs.
+func (cookie *Cookie) Migrated() bool {
+	return cookie != nil && cookie.migrated
+}
+
 // SetOptOut is used to change whether or not we're allowed to sync cookies for this user.
 func (cookie *Cookie) SetOptOut(optOut bool) {
 	cookie.optOut = optOut
@@ -0,0 +1,161 @@
+package usersync
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	encryptedCookieVersion = "e1"
+	signedCookieVersion    = "s1"
+)
+
+// NewKeyRingCodec returns a Codec protecting the UIDs of the uids cookie with the keys of the ring. The cookies are
+// written with the first key and read with any of them, the cookies failing their verification being recorded and
+// read as empty ones.
+//
+// The encrypted cookies are "e1.<key id>.<nonce and AES-256-GCM ciphertext>", and the signed cookies are
+// "s1.<key id>.<cookie JSON>.<HMAC-SHA256>", all base64url encoded. The unprotected cookies have no dots.
+func NewKeyRingCodec(cfg *config.CookieKeyRing, me metrics.MetricsEngine) (Codec, error) {
+	if cfg.Mode == "" || cfg.Mode == config.CookieProtectionNone {
+		return Base64Codec{}, nil
+	}
+
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("no key to %s the uids cookie", cfg.Mode)
+	}
+
+	codec := &keyRingCodec{
+		activeKey:       cfg.Keys[0].ID,
+		acceptPlaintext: cfg.AcceptPlaintext,
+		metrics:         me,
+		aeads:           make(map[string]cipher.AEAD, len(cfg.Keys)),
+		secrets:         make(map[string][]byte, len(cfg.Keys)),
+	}
+	for _, key := range cfg.Keys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret of the uids cookie key %s: %v", key.ID, err)
+		}
+		if cfg.Mode == config.CookieProtectionEncrypt {
+			block, err := aes.NewCipher(secret)
+			if err != nil {
+				return nil, fmt.Errorf("invalid secret of the uids cookie key %s: %v", key.ID, err)
+			}
+			if codec.aeads[key.ID], err = cipher.NewGCM(block); err != nil {
+				return nil, err
+			}
+		} else {
+			codec.secrets[key.ID] = secret
+		}
+	}
+	return codec, nil
+}
+
+type keyRingCodec struct {
+	activeKey       string
+	acceptPlaintext bool
+	metrics         metrics.MetricsEngine
+	// aeads holds the keys to encrypt, secrets the keys to sign
+	aeads   map[string]cipher.AEAD
+	secrets map[string][]byte
+	legacy  Base64Codec
+}
+
+func (c *keyRingCodec) Encode(cookie *Cookie) (string, error) {
+	data, err := jsonutil.Marshal(cookie)
+	if err != nil {
+		return "", err
+	}
+
+	if aead, ok := c.aeads[c.activeKey]; ok {
+		header := encryptedCookieVersion + "." + c.activeKey
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		sealed := aead.Seal(nonce, nonce, data, []byte(header))
+		return header + "." + base64.RawURLEncoding.EncodeToString(sealed), nil
+	}
+
+	signed := signedCookieVersion + "." + c.activeKey + "." + base64.RawURLEncoding.EncodeToString(data)
+	return signed + "." + sign(c.secrets[c.activeKey], signed), nil
+}
+
+func (c *keyRingCodec) Decode(encodedValue string) *Cookie {
+	if !strings.Contains(encodedValue, ".") {
+		if c.acceptPlaintext {
+			return c.legacy.Decode(encodedValue)
+		}
+		c.metrics.RecordUIDCookieVerificationFailure(metrics.UIDCookiePlaintextRejected)
+		return NewCookie()
+	}
+
+	data, failure := c.open(encodedValue)
+	if failure != "" {
+		c.metrics.RecordUIDCookieVerificationFailure(failure)
+		return NewCookie()
+	}
+
+	var cookie Cookie
+	if err := jsonutil.UnmarshalValid(data, &cookie); err != nil {
+		c.metrics.RecordUIDCookieVerificationFailure(metrics.UIDCookieMalformed)
+		return NewCookie()
+	}
+	return &cookie
+}
+
+// open returns the cookie JSON once decrypted or verified
+func (c *keyRingCodec) open(encodedValue string) ([]byte, metrics.UIDCookieFailure) {
+	parts := strings.Split(encodedValue, ".")
+	switch {
+	case len(parts) == 3 && parts[0] == encryptedCookieVersion && len(c.aeads) > 0:
+		aead, ok := c.aeads[parts[1]]
+		if !ok {
+			return nil, metrics.UIDCookieUnknownKey
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, metrics.UIDCookieMalformed
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		data, err := aead.Open(nil, nonce, ciphertext, []byte(parts[0]+"."+parts[1]))
+		if err != nil {
+			return nil, metrics.UIDCookieInvalid
+		}
+		return data, ""
+	case len(parts) == 4 && parts[0] == signedCookieVersion && len(c.secrets) > 0:
+		secret, ok := c.secrets[parts[1]]
+		if !ok {
+			return nil, metrics.UIDCookieUnknownKey
+		}
+		signed := strings.Join(parts[:3], ".")
+		if !hmac.Equal([]byte(parts[3]), []byte(sign(secret, signed))) {
+			return nil, metrics.UIDCookieInvalid
+		}
+		data, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, metrics.UIDCookieMalformed
+		}
+		return data, ""
+	default:
+		return nil, metrics.UIDCookieMalformed
+	}
+}
+
+// sign returns the base64url encoded HMAC-SHA256 of the value
+func sign(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
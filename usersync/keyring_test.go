@@ -0,0 +1,180 @@
+package usersync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testSecretA = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testSecretB = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+func newMetricsMockForKeyRing() *metrics.MetricsEngineMock {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordUIDCookieVerificationFailure", mock.Anything)
+	return me
+}
+
+func newTestKeyRingCodec(t *testing.T, mode string, acceptPlaintext bool, me metrics.MetricsEngine, keys ...config.CookieKey) Codec {
+	codec, err := NewKeyRingCodec(&config.CookieKeyRing{Mode: mode, Keys: keys, AcceptPlaintext: acceptPlaintext}, me)
+	assert.NoError(t, err)
+	return codec
+}
+
+func newTestCookie(t *testing.T) *Cookie {
+	cookie := NewCookie()
+	assert.NoError(t, cookie.Sync("adnxs", "123"))
+	return cookie
+}
+
+func TestNewKeyRingCodec(t *testing.T) {
+	codec, err := NewKeyRingCodec(&config.CookieKeyRing{Mode: config.CookieProtectionNone}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Base64Codec{}, codec)
+
+	_, err = NewKeyRingCodec(&config.CookieKeyRing{Mode: config.CookieProtectionEncrypt, Keys: []config.CookieKey{{ID: "key", Secret: "MDEyMzQ1Njc="}}}, nil)
+	assert.EqualError(t, err, "invalid secret of the uids cookie key key: crypto/aes: invalid key size 8")
+
+	_, err = NewKeyRingCodec(&config.CookieKeyRing{Mode: config.CookieProtectionSign, Keys: []config.CookieKey{{ID: "key", Secret: "invalid"}}}, nil)
+	assert.Error(t, err)
+}
+
+func TestKeyRingCodecEncodeDecode(t *testing.T) {
+	for _, mode := range []string{config.CookieProtectionEncrypt, config.CookieProtectionSign} {
+		t.Run(mode, func(t *testing.T) {
+			me := newMetricsMockForKeyRing()
+			codec := newTestKeyRingCodec(t, mode, false, me, config.CookieKey{ID: "a", Secret: testSecretA})
+
+			encoded, err := codec.Encode(newTestCookie(t))
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, map[string]string{config.CookieProtectionEncrypt: "e1.a.", config.CookieProtectionSign: "s1.a."}[mode]))
+
+			decoded := codec.Decode(encoded)
+			assert.Equal(t, map[string]string{"adnxs": "123"}, decoded.GetUIDs())
+			me.AssertNotCalled(t, "RecordUIDCookieVerificationFailure", mock.Anything)
+		})
+	}
+}
+
+func TestKeyRingCodecEncryptionHidesUIDs(t *testing.T) {
+	codec := newTestKeyRingCodec(t, config.CookieProtectionEncrypt, false, newMetricsMockForKeyRing(), config.CookieKey{ID: "a", Secret: testSecretA})
+
+	first, err := codec.Encode(newTestCookie(t))
+	assert.NoError(t, err)
+	second, err := codec.Encode(newTestCookie(t))
+	assert.NoError(t, err)
+
+	assert.NotContains(t, Base64Decoder{}.Decode(strings.Split(first, ".")[2]).GetUIDs(), "adnxs")
+	assert.NotEqual(t, first, second, "each cookie should be encrypted with its own nonce")
+}
+
+func TestKeyRingCodecRotation(t *testing.T) {
+	for _, mode := range []string{config.CookieProtectionEncrypt, config.CookieProtectionSign} {
+		t.Run(mode, func(t *testing.T) {
+			me := newMetricsMockForKeyRing()
+			oldCodec := newTestKeyRingCodec(t, mode, false, me, config.CookieKey{ID: "a", Secret: testSecretA})
+			rotatedCodec := newTestKeyRingCodec(t, mode, false, me, config.CookieKey{ID: "b", Secret: testSecretB}, config.CookieKey{ID: "a", Secret: testSecretA})
+			newCodec := newTestKeyRingCodec(t, mode, false, me, config.CookieKey{ID: "b", Secret: testSecretB})
+
+			encoded, err := oldCodec.Encode(newTestCookie(t))
+			assert.NoError(t, err)
+			decoded := rotatedCodec.Decode(encoded)
+			assert.Equal(t, map[string]string{"adnxs": "123"}, decoded.GetUIDs(), "the cookies of the old key should be accepted")
+
+			reencoded, err := rotatedCodec.Encode(decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{"adnxs": "123"}, newCodec.Decode(reencoded).GetUIDs(), "the cookies should be written with the new key")
+			me.AssertNotCalled(t, "RecordUIDCookieVerificationFailure", mock.Anything)
+
+			assert.Empty(t, newCodec.Decode(encoded).GetUIDs(), "the cookies of a removed key should be ignored")
+			me.AssertCalled(t, "RecordUIDCookieVerificationFailure", metrics.UIDCookieUnknownKey)
+		})
+	}
+}
+
+func TestKeyRingCodecVerificationFailures(t *testing.T) {
+	signCodec := newTestKeyRingCodec(t, config.CookieProtectionSign, true, nil, config.CookieKey{ID: "a", Secret: testSecretA})
+	signed, _ := signCodec.Encode(newTestCookie(t))
+	signedParts := strings.Split(signed, ".")
+	forgedUIDs, _ := Base64Encoder{}.Encode(newTestCookie(t))
+
+	encryptCodec := newTestKeyRingCodec(t, config.CookieProtectionEncrypt, true, nil, config.CookieKey{ID: "a", Secret: testSecretA})
+	encrypted, _ := encryptCodec.Encode(newTestCookie(t))
+	otherKeyCodec := newTestKeyRingCodec(t, config.CookieProtectionEncrypt, true, nil, config.CookieKey{ID: "a", Secret: testSecretB})
+	otherKeyEncrypted, _ := otherKeyCodec.Encode(newTestCookie(t))
+
+	testCases := []struct {
+		description     string
+		codec           Codec
+		givenValue      string
+		expectedFailure metrics.UIDCookieFailure
+	}{
+		{
+			description:     "forged_uids",
+			codec:           signCodec,
+			givenValue:      strings.Join([]string{signedParts[0], signedParts[1], strings.TrimRight(forgedUIDs, "="), signedParts[3]}, "."),
+			expectedFailure: metrics.UIDCookieInvalid,
+		},
+		{
+			description:     "invalid_signature",
+			codec:           signCodec,
+			givenValue:      strings.Join(signedParts[:3], ".") + ".invalid",
+			expectedFailure: metrics.UIDCookieInvalid,
+		},
+		{
+			description:     "encrypted_with_another_secret",
+			codec:           encryptCodec,
+			givenValue:      otherKeyEncrypted,
+			expectedFailure: metrics.UIDCookieInvalid,
+		},
+		{
+			description:     "encrypted_for_the_sign_mode",
+			codec:           signCodec,
+			givenValue:      encrypted,
+			expectedFailure: metrics.UIDCookieMalformed,
+		},
+		{
+			description:     "truncated",
+			codec:           encryptCodec,
+			givenValue:      "e1.a.AAAA",
+			expectedFailure: metrics.UIDCookieMalformed,
+		},
+		{
+			description:     "unknown_key",
+			codec:           encryptCodec,
+			givenValue:      strings.Replace(encrypted, "e1.a.", "e1.z.", 1),
+			expectedFailure: metrics.UIDCookieUnknownKey,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			me := newMetricsMockForKeyRing()
+			test.codec.(*keyRingCodec).metrics = me
+
+			cookie := test.codec.Decode(test.givenValue)
+			assert.Empty(t, cookie.GetUIDs())
+			assert.True(t, cookie.AllowSyncs())
+			me.AssertCalled(t, "RecordUIDCookieVerificationFailure", test.expectedFailure)
+		})
+	}
+}
+
+func TestKeyRingCodecPlaintext(t *testing.T) {
+	plaintext, _ := Base64Encoder{}.Encode(newTestCookie(t))
+
+	me := newMetricsMockForKeyRing()
+	codec := newTestKeyRingCodec(t, config.CookieProtectionSign, true, me, config.CookieKey{ID: "a", Secret: testSecretA})
+	assert.Equal(t, map[string]string{"adnxs": "123"}, codec.Decode(plaintext).GetUIDs(), "the plaintext cookies should be accepted during the migration")
+	me.AssertNotCalled(t, "RecordUIDCookieVerificationFailure", mock.Anything)
+
+	codec = newTestKeyRingCodec(t, config.CookieProtectionSign, false, me, config.CookieKey{ID: "a", Secret: testSecretA})
+	assert.Empty(t, codec.Decode(plaintext).GetUIDs())
+	me.AssertCalled(t, "RecordUIDCookieVerificationFailure", metrics.UIDCookiePlaintextRejected)
+}
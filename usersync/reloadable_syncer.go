@@ -0,0 +1,63 @@
+package usersync
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/prebid/prebid-server/v3/macros"
+)
+
+var errSyncerDisabled = errors.New("syncer is disabled")
+
+// ReloadableSyncer is a Syncer whose implementation can be swapped while it's in use, so the bidder infos
+// can be reloaded without a restart. Its key never changes since it's stored in the users' cookies.
+type ReloadableSyncer struct {
+	key     string
+	current atomic.Pointer[Syncer]
+}
+
+// NewReloadableSyncer returns a ReloadableSyncer delegating to the given syncer.
+func NewReloadableSyncer(syncer Syncer) *ReloadableSyncer {
+	s := &ReloadableSyncer{key: syncer.Key()}
+	s.Swap(syncer)
+	return s
+}
+
+// Swap replaces the syncer delegated to. A nil syncer disables the syncs, e.g. when the bidder is disabled.
+func (s *ReloadableSyncer) Swap(syncer Syncer) {
+	if syncer == nil {
+		s.current.Store(nil)
+		return
+	}
+	s.current.Store(&syncer)
+}
+
+// Enabled returns true if the syncs aren't disabled.
+func (s *ReloadableSyncer) Enabled() bool {
+	return s.current.Load() != nil
+}
+
+func (s *ReloadableSyncer) Key() string {
+	return s.key
+}
+
+func (s *ReloadableSyncer) DefaultResponseFormat() SyncType {
+	if syncer := s.current.Load(); syncer != nil {
+		return (*syncer).DefaultResponseFormat()
+	}
+	return SyncTypeUnknown
+}
+
+func (s *ReloadableSyncer) SupportsType(syncTypes []SyncType) bool {
+	if syncer := s.current.Load(); syncer != nil {
+		return (*syncer).SupportsType(syncTypes)
+	}
+	return false
+}
+
+func (s *ReloadableSyncer) GetSync(syncTypes []SyncType, userSyncMacros macros.UserSyncPrivacy) (Sync, error) {
+	if syncer := s.current.Load(); syncer != nil {
+		return (*syncer).GetSync(syncTypes, userSyncMacros)
+	}
+	return Sync{}, errSyncerDisabled
+}
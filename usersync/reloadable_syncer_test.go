@@ -0,0 +1,52 @@
+package usersync
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadableSyncer(t *testing.T) {
+	redirect := standardSyncer{
+		key:             "a",
+		defaultSyncType: SyncTypeRedirect,
+		redirect:        template.Must(template.New("test").Parse("redirect")),
+		supportCORS:     true,
+	}
+	iframe := standardSyncer{
+		key:             "a",
+		defaultSyncType: SyncTypeIFrame,
+		iframe:          template.Must(template.New("test").Parse("iframe")),
+	}
+
+	syncer := NewReloadableSyncer(redirect)
+
+	assert.True(t, syncer.Enabled())
+	assert.Equal(t, "a", syncer.Key())
+	assert.Equal(t, SyncTypeRedirect, syncer.DefaultResponseFormat())
+	assert.True(t, syncer.SupportsType([]SyncType{SyncTypeRedirect}))
+	sync, err := syncer.GetSync([]SyncType{SyncTypeRedirect}, macros.UserSyncPrivacy{})
+	assert.NoError(t, err)
+	assert.Equal(t, Sync{URL: "redirect", Type: SyncTypeRedirect, SupportCORS: true}, sync)
+
+	syncer.Swap(iframe)
+
+	assert.True(t, syncer.Enabled())
+	assert.Equal(t, SyncTypeIFrame, syncer.DefaultResponseFormat())
+	assert.False(t, syncer.SupportsType([]SyncType{SyncTypeRedirect}))
+	sync, err = syncer.GetSync([]SyncType{SyncTypeIFrame}, macros.UserSyncPrivacy{})
+	assert.NoError(t, err)
+	assert.Equal(t, Sync{URL: "iframe", Type: SyncTypeIFrame}, sync)
+
+	syncer.Swap(nil)
+
+	assert.False(t, syncer.Enabled())
+	assert.Equal(t, "a", syncer.Key(), "the key is kept when disabled")
+	assert.Equal(t, SyncTypeUnknown, syncer.DefaultResponseFormat())
+	assert.False(t, syncer.SupportsType([]SyncType{SyncTypeIFrame, SyncTypeRedirect}))
+	sync, err = syncer.GetSync([]SyncType{SyncTypeIFrame}, macros.UserSyncPrivacy{})
+	assert.EqualError(t, err, "syncer is disabled")
+	assert.Equal(t, Sync{}, sync)
+}
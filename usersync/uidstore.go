@@ -0,0 +1,274 @@
+package usersync
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
+	"github.com/prebid/prebid-server/v3/util/fileutil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+
+	"github.com/golang/glog"
+)
+
+// UIDStore keeps the bidder UIDs of the users server-side, keyed by the PBS user key carried by the uids cookie.
+type UIDStore interface {
+	// Get returns the UIDs of the user, false if the user is unknown or expired.
+	Get(ctx context.Context, key string) (map[string]UIDEntry, bool, error)
+	// Set replaces the UIDs of the user, kept for the ttl.
+	Set(ctx context.Context, key string, uids map[string]UIDEntry, ttl time.Duration) error
+	// Shutdown releases the resources of the store.
+	Shutdown()
+}
+
+// NewUIDStore returns the store configured, nil if the UIDs are kept in the uids cookie.
+func NewUIDStore(cfg *config.UIDStore) (UIDStore, error) {
+	switch cfg.Type {
+	case config.UIDStoreMemory:
+		glog.Infof("Storing the user UIDs in memory, up to %d users", cfg.Memory.MaxEntries)
+		return NewMemoryUIDStore(cfg.Memory.MaxEntries), nil
+	case config.UIDStoreFile:
+		glog.Infof("Storing the user UIDs in the directory %s", cfg.File.Path)
+		return NewFileUIDStore(cfg.File.Path)
+	case config.UIDStoreRedis:
+		glog.Infof("Storing the user UIDs in the Redis server %s", cfg.Redis.Address)
+		return NewRedisUIDStore(redis.NewClient(cfg.Redis), cfg.Redis.Namespace+":uids:"), nil
+	default:
+		return nil, nil
+	}
+}
+
+type memoryUIDEntry struct {
+	uids      map[string]UIDEntry
+	expiresAt time.Time
+}
+
+// uidExpiration is the time the UIDs of a user of the memory store expire at
+type uidExpiration struct {
+	key       string
+	expiresAt time.Time
+}
+
+// uidExpirationQueue orders the expirations of the memory store, the earliest first
+type uidExpirationQueue []uidExpiration
+
+func (q uidExpirationQueue) Len() int {
+	return len(q)
+}
+
+func (q uidExpirationQueue) Less(i, j int) bool {
+	return q[i].expiresAt.Before(q[j].expiresAt)
+}
+
+func (q uidExpirationQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *uidExpirationQueue) Push(element interface{}) {
+	*q = append(*q, element.(uidExpiration))
+}
+
+func (q *uidExpirationQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	element := old[n-1]
+	*q = old[0 : n-1]
+	return element
+}
+
+// NewMemoryUIDStore returns a UIDStore keeping up to maxEntries users in memory.
+// When full, the expired users are evicted, then the users expiring first.
+func NewMemoryUIDStore(maxEntries int) UIDStore {
+	return &memoryUIDStore{
+		entries:    make(map[string]memoryUIDEntry),
+		maxEntries: maxEntries,
+		time:       &timeutil.RealTime{},
+	}
+}
+
+type memoryUIDStore struct {
+	mutex   sync.RWMutex
+	entries map[string]memoryUIDEntry
+	// expirations holds the expiration of every user stored, including the users updated since, so the users
+	// are evicted without scanning all the entries. It is rebuilt from the entries once twice as long.
+	expirations uidExpirationQueue
+	maxEntries  int
+	time        timeutil.Time
+}
+
+func (s *memoryUIDStore) Get(_ context.Context, key string) (map[string]UIDEntry, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !s.time.Now().Before(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return copyUIDs(entry.uids), true, nil
+}
+
+func (s *memoryUIDStore) Set(_ context.Context, key string, uids map[string]UIDEntry, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.time.Now()
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= s.maxEntries {
+		s.evict(now)
+	}
+	expiresAt := now.Add(ttl)
+	s.entries[key] = memoryUIDEntry{uids: copyUIDs(uids), expiresAt: expiresAt}
+	heap.Push(&s.expirations, uidExpiration{key: key, expiresAt: expiresAt})
+	if len(s.expirations) > 2*len(s.entries) {
+		s.compactExpirations()
+	}
+	return nil
+}
+
+// evict removes the expired users, then the users expiring first until a user can be added. The expiration
+// of a user updated since is dropped without removing the user.
+func (s *memoryUIDStore) evict(now time.Time) {
+	for len(s.expirations) > 0 && (len(s.entries) >= s.maxEntries || !now.Before(s.expirations[0].expiresAt)) {
+		expired := heap.Pop(&s.expirations).(uidExpiration)
+		if entry, ok := s.entries[expired.key]; ok && entry.expiresAt.Equal(expired.expiresAt) {
+			delete(s.entries, expired.key)
+		}
+	}
+}
+
+// compactExpirations drops the expirations of the users updated since, which would otherwise pile up
+// while the store isn't full.
+func (s *memoryUIDStore) compactExpirations() {
+	s.expirations = make(uidExpirationQueue, 0, len(s.entries))
+	for key, entry := range s.entries {
+		s.expirations = append(s.expirations, uidExpiration{key: key, expiresAt: entry.expiresAt})
+	}
+	heap.Init(&s.expirations)
+}
+
+func (s *memoryUIDStore) Shutdown() {}
+
+func copyUIDs(uids map[string]UIDEntry) map[string]UIDEntry {
+	copied := make(map[string]UIDEntry, len(uids))
+	for key, entry := range uids {
+		copied[key] = entry
+	}
+	return copied
+}
+
+// storedUIDs is the format the UIDs are written in by the file and Redis stores
+type storedUIDs struct {
+	UIDs    map[string]UIDEntry `json:"uids"`
+	Expires time.Time           `json:"expires"`
+}
+
+// validUserKey prevents the keys from escaping the directory of the file store
+var validUserKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// NewFileUIDStore returns a UIDStore keeping the UIDs of each user in a file of the directory.
+// The expired files are left for the host to clean up, and read as unknown users.
+func NewFileUIDStore(path string) (UIDStore, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create the directory of the UID store: %v", err)
+	}
+	return &fileUIDStore{path: path, time: &timeutil.RealTime{}}, nil
+}
+
+type fileUIDStore struct {
+	path string
+	time timeutil.Time
+}
+
+func (s *fileUIDStore) Get(_ context.Context, key string) (map[string]UIDEntry, bool, error) {
+	if !validUserKey.MatchString(key) {
+		return nil, false, fmt.Errorf("invalid user key %q", key)
+	}
+
+	data, err := os.ReadFile(s.filename(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var stored storedUIDs
+	if err := jsonutil.UnmarshalValid(data, &stored); err != nil {
+		return nil, false, fmt.Errorf("invalid UIDs of the user %s: %v", key, err)
+	}
+	if !s.time.Now().Before(stored.Expires) {
+		return nil, false, nil
+	}
+	return stored.UIDs, true, nil
+}
+
+func (s *fileUIDStore) Set(_ context.Context, key string, uids map[string]UIDEntry, ttl time.Duration) error {
+	if !validUserKey.MatchString(key) {
+		return fmt.Errorf("invalid user key %q", key)
+	}
+
+	data, err := jsonutil.Marshal(storedUIDs{UIDs: uids, Expires: s.time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomically(s.filename(key), data)
+}
+
+func (s *fileUIDStore) filename(key string) string {
+	return filepath.Join(s.path, key+".json")
+}
+
+func (s *fileUIDStore) Shutdown() {}
+
+// NewRedisUIDStore returns a UIDStore keeping the UIDs in a Redis-compatible server, under the keys prefixed with keyPrefix.
+// The UIDs are shared by all the PBS instances using the server.
+func NewRedisUIDStore(client *redis.Client, keyPrefix string) UIDStore {
+	return &redisUIDStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+type redisUIDStore struct {
+	client    redis.KeyValueClient
+	keyPrefix string
+}
+
+func (s *redisUIDStore) Get(ctx context.Context, key string) (map[string]UIDEntry, bool, error) {
+	values, err := s.client.Get(ctx, []string{s.keyPrefix + key})
+	if err != nil {
+		return nil, false, err
+	}
+	if values[0] == nil {
+		return nil, false, nil
+	}
+
+	var stored storedUIDs
+	if err := jsonutil.UnmarshalValid(values[0], &stored); err != nil {
+		return nil, false, fmt.Errorf("invalid UIDs of the user %s: %v", key, err)
+	}
+	return stored.UIDs, true, nil
+}
+
+func (s *redisUIDStore) Set(ctx context.Context, key string, uids map[string]UIDEntry, ttl time.Duration) error {
+	data, err := jsonutil.Marshal(storedUIDs{UIDs: uids, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, []redis.KeyValue{{
+		Key:        s.keyPrefix + key,
+		Value:      data,
+		TTLSeconds: int(ttl.Seconds()),
+	}})
+}
+
+func (s *redisUIDStore) Shutdown() {
+	s.client.Close()
+}
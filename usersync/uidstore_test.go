@@ -0,0 +1,203 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (ft *fakeTime) Now() time.Time {
+	return ft.time
+}
+
+var testUIDs = map[string]UIDEntry{
+	"adnxs": {UID: "123", Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestNewUIDStore(t *testing.T) {
+	store, err := NewUIDStore(&config.UIDStore{Type: config.UIDStoreCookie})
+	assert.NoError(t, err)
+	assert.Nil(t, store, "the UIDs should be kept in the cookie")
+
+	store, err = NewUIDStore(&config.UIDStore{Type: config.UIDStoreMemory, Memory: config.MemoryUIDStore{MaxEntries: 10}})
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryUIDStore{}, store)
+
+	path := filepath.Join(t.TempDir(), "uids")
+	store, err = NewUIDStore(&config.UIDStore{Type: config.UIDStoreFile, File: config.FileUIDStore{Path: path}})
+	assert.NoError(t, err)
+	assert.IsType(t, &fileUIDStore{}, store)
+	assert.DirExists(t, path)
+}
+
+func TestMemoryUIDStore(t *testing.T) {
+	ctx := context.Background()
+	now := &fakeTime{time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryUIDStore(2).(*memoryUIDStore)
+	store.time = now
+
+	_, found, err := store.Get(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	uids := map[string]UIDEntry{"adnxs": testUIDs["adnxs"]}
+	assert.NoError(t, store.Set(ctx, "one", uids, time.Hour))
+	uids["rubicon"] = UIDEntry{UID: "456"}
+
+	stored, found, err := store.Get(ctx, "one")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testUIDs, stored, "the stored UIDs should not change with the UIDs set")
+
+	now.time = now.time.Add(2 * time.Hour)
+	_, found, _ = store.Get(ctx, "one")
+	assert.False(t, found, "the UIDs should expire after the ttl")
+
+	assert.NoError(t, store.Set(ctx, "two", testUIDs, time.Hour))
+	assert.NoError(t, store.Set(ctx, "three", testUIDs, time.Hour))
+	assert.Len(t, store.entries, 2, "the expired user should be evicted first")
+	_, found, _ = store.Get(ctx, "three")
+	assert.True(t, found)
+
+	assert.NoError(t, store.Set(ctx, "four", testUIDs, time.Hour))
+	assert.Len(t, store.entries, 2)
+	_, found, _ = store.Get(ctx, "four")
+	assert.True(t, found)
+}
+
+func TestMemoryUIDStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	now := &fakeTime{time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryUIDStore(2).(*memoryUIDStore)
+	store.time = now
+
+	assert.NoError(t, store.Set(ctx, "one", testUIDs, time.Hour))
+	assert.NoError(t, store.Set(ctx, "two", testUIDs, 2*time.Hour))
+	assert.NoError(t, store.Set(ctx, "one", testUIDs, 3*time.Hour))
+	assert.NoError(t, store.Set(ctx, "three", testUIDs, time.Hour))
+
+	_, found, _ := store.Get(ctx, "one")
+	assert.True(t, found, "the user updated should be kept, despite its former expiration")
+	_, found, _ = store.Get(ctx, "two")
+	assert.False(t, found, "the user expiring first should be evicted")
+	_, found, _ = store.Get(ctx, "three")
+	assert.True(t, found)
+}
+
+func TestMemoryUIDStoreCompactExpirations(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryUIDStore(10).(*memoryUIDStore)
+
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, store.Set(ctx, "one", testUIDs, time.Hour))
+	}
+	assert.LessOrEqual(t, len(store.expirations), 2, "the expirations of the user updated should be dropped")
+}
+
+func TestFileUIDStore(t *testing.T) {
+	ctx := context.Background()
+	now := &fakeTime{time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	path := t.TempDir()
+	store, err := NewFileUIDStore(path)
+	assert.NoError(t, err)
+	store.(*fileUIDStore).time = now
+
+	_, found, err := store.Get(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set(ctx, "user-key", testUIDs, time.Hour))
+	assert.FileExists(t, filepath.Join(path, "user-key.json"))
+
+	stored, found, err := store.Get(ctx, "user-key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testUIDs, stored)
+
+	now.time = now.time.Add(2 * time.Hour)
+	_, found, err = store.Get(ctx, "user-key")
+	assert.NoError(t, err)
+	assert.False(t, found, "the UIDs should expire after the ttl")
+
+	assert.EqualError(t, store.Set(ctx, "../user-key", testUIDs, time.Hour), `invalid user key "../user-key"`)
+	_, _, err = store.Get(ctx, "../user-key")
+	assert.EqualError(t, err, `invalid user key "../user-key"`)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "invalid.json"), []byte("invalid"), 0644))
+	_, _, err = store.Get(ctx, "invalid")
+	assert.Error(t, err)
+}
+
+type mockRedisClient struct {
+	values map[string]redis.KeyValue
+	err    error
+	closed bool
+}
+
+func (c *mockRedisClient) Set(_ context.Context, values []redis.KeyValue) error {
+	if c.err != nil {
+		return c.err
+	}
+	for _, value := range values {
+		c.values[value.Key] = value
+	}
+	return nil
+}
+
+func (c *mockRedisClient) Get(_ context.Context, keys []string) ([][]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if value, ok := c.values[key]; ok {
+			values[i] = value.Value
+		}
+	}
+	return values, nil
+}
+
+func (c *mockRedisClient) Close() {
+	c.closed = true
+}
+
+func TestRedisUIDStore(t *testing.T) {
+	ctx := context.Background()
+	client := &mockRedisClient{values: make(map[string]redis.KeyValue)}
+	store := &redisUIDStore{client: client, keyPrefix: "pbs:uids:"}
+
+	_, found, err := store.Get(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set(ctx, "user-key", testUIDs, time.Hour))
+	assert.Equal(t, 3600, client.values["pbs:uids:user-key"].TTLSeconds)
+
+	stored, found, err := store.Get(ctx, "user-key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testUIDs, stored)
+
+	client.values["pbs:uids:invalid"] = redis.KeyValue{Value: []byte("invalid")}
+	_, _, err = store.Get(ctx, "invalid")
+	assert.Error(t, err)
+
+	client.err = errors.New("redis: server marked as unavailable")
+	_, _, err = store.Get(ctx, "user-key")
+	assert.Equal(t, client.err, err)
+	assert.Equal(t, client.err, store.Set(ctx, "user-key", testUIDs, time.Hour))
+
+	store.Shutdown()
+	assert.True(t, client.closed)
+}
@@ -0,0 +1,34 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.json")
+
+	assert.NoError(t, WriteFileAtomically(path, []byte("first")))
+	assert.NoError(t, WriteFileAtomically(path, []byte("second")))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "the temporary files should be removed")
+}
+
+func TestWriteFileAtomicallyMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "file.json")
+
+	assert.Error(t, WriteFileAtomically(path, []byte("content")))
+
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}